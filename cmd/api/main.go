@@ -15,9 +15,11 @@ import (
 	"student-portal/internal/config"
 	"student-portal/internal/handler"
 	kafka "student-portal/internal/kafka"
+	"student-portal/internal/mail"
 	"student-portal/internal/repository"
 	"student-portal/internal/routes"
 	"student-portal/internal/service"
+	"student-portal/internal/storage"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap" // For structured error logging
@@ -70,16 +72,89 @@ func main() {
 		}
 	}()
 
-	// 5. Dependency Injection
+	// 5. Initialize Blob Storage
+	blobStorage, err := storage.NewStorage(cfg)
+	if err != nil {
+		logger.Logger.Fatal(fmt.Sprintf("Failed to initialize %s storage: %v", cfg.StorageBackend, err))
+	}
+	mailer := mail.NewSender(cfg)
+
+	// 6. Dependency Injection
 	userRepo := repository.NewUserRepository(dbPool)
-	userService := service.NewUserService(userRepo, cfg, kafkaProducer)
+	profileRepo := repository.NewProfileRepository(dbPool)
+	fileRepo := repository.NewFileRepository(dbPool)
+	privacyRepo := repository.NewPrivacyRepository(dbPool)
+	historyRepo := repository.NewHistoryRepository(dbPool)
+	mergeRepo := repository.NewMergeRepository(dbPool)
+	guardianRepo := repository.NewGuardianRepository(dbPool)
+	courseRepo := repository.NewCourseRepository(dbPool)
+	enrollmentRepo := repository.NewEnrollmentRepository(dbPool)
+	completionRepo := repository.NewCompletionRepository(dbPool)
+	termRepo := repository.NewTermRepository(dbPool)
+	offeringRepo := repository.NewOfferingRepository(dbPool)
+	sectionRepo := repository.NewSectionRepository(dbPool)
+	calendarRepo := repository.NewCalendarRepository(dbPool)
+	attendanceRepo := repository.NewAttendanceRepository(dbPool)
+	checkInRepo := repository.NewCheckInRepository(dbPool)
+	assignmentRepo := repository.NewAssignmentRepository(dbPool)
+	gradebookRepo := repository.NewGradebookRepository(dbPool)
+	transcriptRepo := repository.NewTranscriptRepository(dbPool)
+	quizRepo := repository.NewQuizRepository(dbPool)
+	rubricRepo := repository.NewRubricRepository(dbPool)
+	peerReviewRepo := repository.NewPeerReviewRepository(dbPool)
+	transactor := repository.NewTransactor(dbPool)
+	// Virus-scan hooks (e.g. a ClamAV client implementing storage.Scanner) can be passed here.
+	fileService := service.NewFileService(fileRepo, blobStorage, cfg)
+	historyService := service.NewHistoryService(historyRepo)
+	userService := service.NewUserService(userRepo, profileRepo, transactor, fileService, historyService, cfg, kafkaProducer)
+	privacyService := service.NewPrivacyService(privacyRepo, transactor, fileService, historyService, cfg, kafkaProducer)
+	mergeService := service.NewMergeService(mergeRepo, userRepo, profileRepo, transactor, historyService, cfg, kafkaProducer)
+	guardianService := service.NewGuardianService(guardianRepo, userRepo, profileRepo, transactor, historyService, cfg, kafkaProducer, mailer)
+	enrollmentService := service.NewEnrollmentService(enrollmentRepo, courseRepo, termRepo, offeringRepo, sectionRepo, completionRepo, userRepo, transactor, historyService, cfg, kafkaProducer)
+	courseService := service.NewCourseService(courseRepo, transactor, historyService, cfg, kafkaProducer)
+	termService := service.NewTermService(termRepo, transactor, historyService, cfg)
+	offeringService := service.NewOfferingService(offeringRepo, courseRepo, termRepo, transactor, enrollmentService, historyService, cfg)
+	sectionService := service.NewSectionService(sectionRepo, offeringRepo, termRepo, userRepo, transactor, historyService, cfg)
+	calendarService := service.NewCalendarService(calendarRepo, userRepo, termRepo, sectionRepo, assignmentRepo, cfg)
+	attendanceService := service.NewAttendanceService(attendanceRepo, sectionRepo, termRepo, userRepo, transactor, historyService, cfg, kafkaProducer)
+	checkInService := service.NewCheckInService(checkInRepo, attendanceRepo, sectionRepo, transactor, historyService, cfg)
+	gradebookService := service.NewGradebookService(gradebookRepo, assignmentRepo, sectionRepo, courseRepo, termRepo, userRepo, transactor, historyService, kafkaProducer)
+	transcriptService := service.NewTranscriptService(transcriptRepo, userRepo, transactor, fileService, historyService, cfg)
+	assignmentService := service.NewAssignmentService(assignmentRepo, sectionRepo, termRepo, userRepo, gradebookRepo, transactor, fileService, gradebookService, historyService, cfg)
+	quizService := service.NewQuizService(quizRepo, assignmentRepo, sectionRepo, termRepo, userRepo, transactor, historyService, cfg)
+	rubricService := service.NewRubricService(rubricRepo, assignmentRepo, sectionRepo, gradebookRepo, transactor, gradebookService, historyService)
+	peerReviewService := service.NewPeerReviewService(peerReviewRepo, rubricRepo, assignmentRepo, sectionRepo, transactor, fileService, gradebookService, historyService)
+	// Exports interrupted by the previous shutdown would otherwise block their users.
+	if err := privacyService.ExpireStaleExports(ctx); err != nil {
+		logger.Logger.Error("Failed to expire abandoned data exports", zap.Error(err))
+	}
+
 	authHandler := handler.NewAuthHandler(userService, cfg)
 	userHandler := handler.NewUserHandler(userService, cfg)
-
-	// 6. Setup Router
-	r := routes.SetupRouter(cfg, authHandler, userHandler)
-
-	// 7. Start Server
+	fileHandler := handler.NewFileHandler(fileService, cfg)
+	privacyHandler := handler.NewPrivacyHandler(privacyService, cfg)
+	historyHandler := handler.NewHistoryHandler(historyService, cfg)
+	mergeHandler := handler.NewMergeHandler(mergeService, cfg)
+	guardianHandler := handler.NewGuardianHandler(guardianService, cfg)
+	courseHandler := handler.NewCourseHandler(courseService, cfg)
+	enrollmentHandler := handler.NewEnrollmentHandler(enrollmentService, cfg)
+	termHandler := handler.NewTermHandler(termService, cfg)
+	offeringHandler := handler.NewOfferingHandler(offeringService, cfg)
+	sectionHandler := handler.NewSectionHandler(sectionService, cfg)
+	calendarHandler := handler.NewCalendarHandler(calendarService, cfg)
+	attendanceHandler := handler.NewAttendanceHandler(attendanceService, cfg)
+	checkInHandler := handler.NewCheckInHandler(checkInService, cfg)
+	assignmentHandler := handler.NewAssignmentHandler(assignmentService, cfg)
+	gradebookHandler := handler.NewGradebookHandler(gradebookService, cfg)
+	transcriptHandler := handler.NewTranscriptHandler(transcriptService, cfg)
+	quizHandler := handler.NewQuizHandler(quizService, cfg)
+	rubricHandler := handler.NewRubricHandler(rubricService, cfg)
+	peerReviewHandler := handler.NewPeerReviewHandler(peerReviewService, cfg)
+
+	// 7. Setup Router
+	r := routes.SetupRouter(cfg, authHandler, userHandler, fileHandler, privacyHandler, historyHandler, mergeHandler, guardianHandler, courseHandler, enrollmentHandler, termHandler, offeringHandler, sectionHandler, calendarHandler, attendanceHandler, checkInHandler, assignmentHandler, gradebookHandler, transcriptHandler, quizHandler, rubricHandler, peerReviewHandler, guardianService, userService)
+
+	// 8. Start Server
 	server := &http.Server{
 		Addr:    ":" + cfg.ServerPort,
 		Handler: r,
@@ -97,7 +172,7 @@ func main() {
 
 	logger.Logger.Info("Server shutting down...")
 
-	// 8. Shutdown gracefully
+	// 9. Shutdown gracefully
 	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
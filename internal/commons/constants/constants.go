@@ -23,5 +23,20 @@ const (
 	// Topic for user authentication and lifecycle events (registration, login)
 	TopicUserEvents = "user-auth-events"
 
-	// Add other topics here as features grow (e.g., TopicCourseEnrollments = "course-enroll-events")
+	// Topic for personal data export and erasure events (GDPR/FERPA)
+	TopicPrivacyEvents = "user-privacy-events"
+
+	// Topic for course catalog lifecycle events (created, updated, deleted)
+	TopicCourseEvents = "course-events"
+
+	// Topic for enrollment and waitlist events (enrolled, dropped, promoted from the waitlist)
+	TopicCourseEnrollments = "course-enroll-events"
+
+	// Topic for attendance alerts (a student's attendance rate dropping below the threshold)
+	TopicAttendanceEvents = "attendance-events"
+
+	// Topic for grade events (assignment grades released, final course grades posted)
+	TopicGradeEvents = "grade-events"
+
+	// Add other topics here as features grow
 )
@@ -0,0 +1,12 @@
+package enums
+
+// AccountStatus represents the lifecycle state of a user account
+type AccountStatus string
+
+const (
+	AccountStatusPending   AccountStatus = "pending"   // Created but not yet activated
+	AccountStatusActive    AccountStatus = "active"    // The only status that can log in
+	AccountStatusSuspended AccountStatus = "suspended" // Temporarily blocked, e.g. disciplinary or unpaid fees
+	AccountStatusGraduated AccountStatus = "graduated" // Finished their studies
+	AccountStatusArchived  AccountStatus = "archived"  // Closed; kept for records
+)
@@ -0,0 +1,11 @@
+package enums
+
+// AttendanceStatus records how a student attended a class session
+type AttendanceStatus string
+
+const (
+	AttendanceStatusPresent AttendanceStatus = "present"
+	AttendanceStatusAbsent  AttendanceStatus = "absent"
+	AttendanceStatusLate    AttendanceStatus = "late"    // Counts as attended
+	AttendanceStatusExcused AttendanceStatus = "excused" // Left out of the attendance rate
+)
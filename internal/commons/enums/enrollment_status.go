@@ -0,0 +1,10 @@
+package enums
+
+// EnrollmentStatus represents a student's place in a course
+type EnrollmentStatus string
+
+const (
+	EnrollmentStatusEnrolled   EnrollmentStatus = "enrolled"   // Holds a seat
+	EnrollmentStatusWaitlisted EnrollmentStatus = "waitlisted" // Waiting for a seat, promoted in order
+	EnrollmentStatusDropped    EnrollmentStatus = "dropped"    // Left the course or the waitlist
+)
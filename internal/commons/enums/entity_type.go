@@ -0,0 +1,37 @@
+package enums
+
+// EntityType identifies the kind of entity a change history entry belongs to
+type EntityType string
+
+const (
+	EntityUser             EntityType = "user"
+	EntityGuardianLink     EntityType = "guardian_link"
+	EntityCourse           EntityType = "course"
+	EntityEnrollment       EntityType = "enrollment"
+	EntityCourseCompletion EntityType = "course_completion"
+	EntityTerm             EntityType = "term"
+	EntityCourseOffering   EntityType = "course_offering"
+	EntitySection          EntityType = "section"
+	EntityAssignment       EntityType = "assignment"
+	EntityGradePointScale  EntityType = "grade_point_scale"
+	EntityQuestionBank     EntityType = "question_bank"
+	EntityQuiz             EntityType = "quiz"
+	EntityRubric           EntityType = "rubric"
+	EntityPeerReview       EntityType = "peer_review"       // Peer review settings, keyed by assignment
+	EntityClassSession     EntityType = "class_session"     // Attendance of a class session
+	EntityGradeScale       EntityType = "grade_scale"       // Letter grade scale, keyed by course
+	EntityGradeCategories  EntityType = "grade_categories"  // Grade categories, keyed by section
+	EntityAssignmentGrades EntityType = "assignment_grades" // Students' grades, keyed by assignment
+	EntityFinalGrades      EntityType = "final_grades"      // Posted final grades, keyed by section
+)
+
+// ChangeAction describes what a mutating call did to an entity
+type ChangeAction string
+
+const (
+	ActionCreate ChangeAction = "create"
+	ActionUpdate ChangeAction = "update"
+	ActionDelete ChangeAction = "delete"
+	ActionErase  ChangeAction = "erase"
+	ActionMerge  ChangeAction = "merge"
+)
@@ -0,0 +1,13 @@
+package enums
+
+// FilePurpose describes what an uploaded file is used for.
+// It determines the size limit, accepted content types and post-processing.
+type FilePurpose string
+
+const (
+	FilePurposeAvatar     FilePurpose = "avatar"
+	FilePurposeDocument   FilePurpose = "document"
+	FilePurposeExport     FilePurpose = "export"     // Server-generated personal data exports
+	FilePurposeSubmission FilePurpose = "submission" // Files handed in for assignments
+	FilePurposeTranscript FilePurpose = "transcript" // Server-generated official transcripts
+)
@@ -0,0 +1,12 @@
+package enums
+
+// GuardianRelationship describes how a guardian is related to a student
+type GuardianRelationship string
+
+const (
+	RelationshipParent        GuardianRelationship = "parent"
+	RelationshipLegalGuardian GuardianRelationship = "legal_guardian"
+	RelationshipGrandparent   GuardianRelationship = "grandparent"
+	RelationshipSibling       GuardianRelationship = "sibling"
+	RelationshipOther         GuardianRelationship = "other"
+)
@@ -0,0 +1,10 @@
+package enums
+
+// LatePolicy decides what happens to work handed in after an assignment's due date
+type LatePolicy string
+
+const (
+	LatePolicyReject  LatePolicy = "reject"
+	LatePolicyAccept  LatePolicy = "accept"  // Accepted and flagged as late
+	LatePolicyPenalty LatePolicy = "penalty" // Accepted with a deduction per started day late
+)
@@ -0,0 +1,21 @@
+package enums
+
+// QuestionType decides how a quiz question is answered and graded
+type QuestionType string
+
+const (
+	QuestionTypeMultipleChoice QuestionType = "multiple_choice" // Exactly one correct option
+	QuestionTypeMultiSelect    QuestionType = "multi_select"    // One or more correct options, with partial credit
+	QuestionTypeTrueFalse      QuestionType = "true_false"
+	QuestionTypeNumeric        QuestionType = "numeric"      // Correct within a tolerance
+	QuestionTypeShortAnswer    QuestionType = "short_answer" // Free text, graded by the teacher
+)
+
+// QuizAttemptStatus tracks a quiz attempt from start to final score
+type QuizAttemptStatus string
+
+const (
+	QuizAttemptInProgress QuizAttemptStatus = "in_progress"
+	QuizAttemptSubmitted  QuizAttemptStatus = "submitted" // Waiting for free-text answers to be graded
+	QuizAttemptGraded     QuizAttemptStatus = "graded"
+)
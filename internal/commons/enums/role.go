@@ -4,6 +4,8 @@ package enums
 type Role string
 
 const (
-	RoleStudent Role = "student"
-	RoleAdmin   Role = "admin"
+	RoleStudent  Role = "student"
+	RoleAdmin    Role = "admin"
+	RoleGuardian Role = "guardian" // Parent or guardian with read-only access to linked students
+	RoleTeacher  Role = "teacher"  // Instructor of course sections
 )
@@ -8,14 +8,21 @@ import (
 
 // AppError is a custom error type for centralized error handling.
 type AppError struct {
-	Code    int    `json:"-"` // HTTP status code
-	Message string `json:"error"`
+	Code    int         `json:"-"` // HTTP status code
+	Message string      `json:"error"`
+	Details interface{} `json:"details,omitempty"` // Optional structured information for the client
 }
 
 func (e *AppError) Error() string {
 	return e.Message
 }
 
+// WithDetails returns a copy of the error carrying structured details, leaving the
+// predefined error untouched.
+func (e *AppError) WithDetails(details interface{}) *AppError {
+	return &AppError{Code: e.Code, Message: e.Message, Details: details}
+}
+
 // New creates a new AppError with a specific code and message.
 func New(code int, format string, args ...interface{}) *AppError {
 	return &AppError{
@@ -34,4 +41,63 @@ var (
 	ErrInternalServerError = New(http.StatusInternalServerError, "An unexpected error occurred")
 	ErrInvalidCredentials  = New(http.StatusUnauthorized, "Invalid email or password")
 	ErrEmailExists         = New(http.StatusConflict, "Email already exists")
+	ErrStudentNumberExists = New(http.StatusConflict, "Student number already exists")
+	ErrProfileFieldExists  = New(http.StatusConflict, "Profile field already exists")
+	ErrFileTooLarge        = New(http.StatusRequestEntityTooLarge, "File exceeds the maximum allowed size")
+	ErrUnsupportedMedia    = New(http.StatusUnsupportedMediaType, "File type is not allowed")
+	ErrFileRejected        = New(http.StatusUnprocessableEntity, "File was rejected by the virus scan")
+	ErrInvalidSignature    = New(http.StatusForbidden, "Download link is invalid or has expired")
+	ErrPreconditionFailed  = New(http.StatusPreconditionFailed, "Resource has been modified by someone else; reload and retry")
+	ErrIfMatchRequired     = New(http.StatusPreconditionRequired, "If-Match header is required for this update")
+	ErrUserAlreadyErased   = New(http.StatusConflict, "User has already been erased")
+	ErrExportInProgress    = New(http.StatusConflict, "A data export is already in progress")
+	ErrUserErased          = New(http.StatusConflict, "User has been erased")
+	ErrAccountPending      = New(http.StatusForbidden, "Account has not been activated yet")
+	ErrAccountSuspended    = New(http.StatusForbidden, "Account is suspended")
+	ErrAccountGraduated    = New(http.StatusForbidden, "Account is closed because the student has graduated")
+	ErrAccountArchived     = New(http.StatusForbidden, "Account is archived")
+	ErrGuardianLinkExists  = New(http.StatusConflict, "Guardian is already linked to this student")
+	ErrInvitationInvalid   = New(http.StatusGone, "Invitation is invalid, expired or already used")
+	ErrCourseCodeExists    = New(http.StatusConflict, "Course code already exists")
+	ErrCourseInUse         = New(http.StatusConflict, "Course is still referenced; deactivate it instead")
+	ErrCourseNotOpen       = New(http.StatusConflict, "Course is not open for enrollment")
+	ErrAlreadyEnrolled     = New(http.StatusConflict, "Student is already enrolled or waitlisted in this course")
+	ErrNotEnrolled         = New(http.StatusNotFound, "Student is not enrolled or waitlisted in this course")
+	ErrDropDeadlinePassed  = New(http.StatusConflict, "The drop deadline for this course has passed")
+	ErrRequirementsNotMet  = New(http.StatusUnprocessableEntity, "Course requirements are not met")
+	ErrTermCodeExists      = New(http.StatusConflict, "Term code already exists")
+	ErrTermInUse           = New(http.StatusConflict, "Term still has course offerings or enrollments")
+	ErrTermOverlaps        = New(http.StatusConflict, "Term dates overlap another term")
+	ErrNoCurrentTerm       = New(http.StatusNotFound, "No term is currently running")
+	ErrAddDeadlinePassed   = New(http.StatusConflict, "The add deadline for this term has passed")
+	ErrOfferingExists      = New(http.StatusConflict, "Course is already offered in this term")
+	ErrOfferingInUse       = New(http.StatusConflict, "Offering has enrollments; deactivate it instead")
+	ErrOfferingNotFound    = New(http.StatusNotFound, "Course is not offered in this term")
+	ErrSectionCodeExists   = New(http.StatusConflict, "Section code already exists for this offering")
+	ErrSectionInUse        = New(http.StatusConflict, "Section still has enrollments or submitted coursework")
+	ErrSectionRequired     = New(http.StatusBadRequest, "Choose one of the course's sections")
+	ErrTimetableClash      = New(http.StatusConflict, "Section clashes with the student's timetable")
+	ErrNoCalendarFeed      = New(http.StatusNotFound, "No calendar feed has been set up")
+	ErrCheckInWindowOpen   = New(http.StatusConflict, "A check-in window is already open for this session")
+	ErrNoCheckInWindow     = New(http.StatusNotFound, "No check-in window is open for this session")
+	ErrInvalidCheckInCode  = New(http.StatusBadRequest, "Check-in code is invalid or has expired")
+	ErrAlreadyCheckedIn    = New(http.StatusConflict, "Attendance has already been recorded for this session")
+	ErrTooManyCheckIns     = New(http.StatusTooManyRequests, "Too many failed check-in attempts")
+	ErrAssignmentInUse     = New(http.StatusConflict, "Assignment already has submissions or grades")
+	ErrSubmissionClosed    = New(http.StatusConflict, "This assignment no longer accepts submissions")
+	ErrGradeCategoryExists = New(http.StatusConflict, "Section already has a grade category with this name")
+	ErrScaleNameExists     = New(http.StatusConflict, "A grade-point scale with this name already exists")
+	ErrInvalidTranscript   = New(http.StatusNotFound, "No transcript was issued with this verification code")
+	ErrQuestionBankInUse   = New(http.StatusConflict, "Question bank has questions that quizzes use")
+	ErrQuestionInUse       = New(http.StatusConflict, "Question is used by a quiz")
+	ErrQuizInUse           = New(http.StatusConflict, "Quiz has already been attempted")
+	ErrQuizNotOpen         = New(http.StatusConflict, "This quiz is not open")
+	ErrNoAttemptsLeft      = New(http.StatusConflict, "No attempts are left for this quiz")
+	ErrQuizTimeUp          = New(http.StatusConflict, "Time is up for this attempt")
+	ErrAttemptSubmitted    = New(http.StatusConflict, "This attempt has already been submitted")
+	ErrInvalidQTIPackage   = New(http.StatusBadRequest, "File is not a QTI package or item")
+	ErrRubricInUse         = New(http.StatusConflict, "Rubric is attached to an assignment or has been scored with")
+	ErrAssignmentScored    = New(http.StatusConflict, "Assignment already has rubric scores or peer reviews")
+	ErrPeerReviewStarted   = New(http.StatusConflict, "Peer reviews have already been handed in for this assignment")
+	ErrReviewClosed        = New(http.StatusConflict, "The review deadline has passed")
 )
@@ -1,9 +1,14 @@
 package config
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/hex"
 	"log"
 	"os"
+	"strconv"
 	"time"
+	_ "time/tzdata" // Time zone names must resolve on hosts without a zoneinfo database
 
 	"github.com/joho/godotenv"
 )
@@ -26,6 +31,65 @@ type Config struct {
 	// Kafka configuration
 	KafkaBrokers string
 	KafkaTopic   string
+
+	// File storage configuration
+	StorageBackend  string // "local" or "s3"
+	StorageLocalDir string
+	S3Endpoint      string
+	S3Region        string
+	S3Bucket        string
+	S3AccessKey     string
+	S3SecretKey     string
+	S3UsePathStyle  bool
+
+	PublicBaseURL      string // Base URL used to build signed download links for the local backend
+	FileSigningKey     string // Derived from JWTSecret unless set
+	SignedURLTTL       time.Duration
+	MaxUploadSize      int64 // Bytes
+	AvatarMaxSize      int64 // Bytes
+	AvatarMaxDimension int   // Pixels
+	AvatarMaxPixels    int64 // Width times height; larger images are rejected before decoding
+
+	// Email addresses are always trimmed and matched case-insensitively; this controls
+	// whether the local part is also stored in lower case.
+	EmailLowercaseLocalPart bool
+
+	GuardianInviteTTL time.Duration
+	GuardianInviteURL string // Client page that lets the invited parent accept; receives ?token=
+
+	// Outgoing email; with no SMTPHost, emails are only logged (without their body).
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+
+	// What happens when a student enrolls in a section that clashes with their timetable:
+	// "reject" refuses the enrollment, "warn" accepts it and reports the clashes.
+	TimetableClashPolicy string
+
+	// Time zone the class times are given in; calendar feeds place meetings and deadlines in it.
+	CalendarLocation *time.Location
+
+	// Students whose attendance rate in a section drops below AttendanceAlertThreshold percent
+	// trigger an alert, once at least AttendanceAlertMinSessions sessions count towards the rate.
+	AttendanceAlertThreshold   float64
+	AttendanceAlertMinSessions int
+
+	// Self check-in codes are derived from CheckInSigningKey and rotate every CheckInCodeTTL.
+	CheckInSigningKey  string // Derived from JWTSecret unless set
+	CheckInCodeTTL     time.Duration
+	CheckInMaxAttempts int // Failed attempts allowed per student and check-in window
+
+	// Files a student can attach to one assignment submission; each is limited to MaxUploadSize.
+	MaxSubmissionFiles int
+
+	// Name printed at the top of official transcripts.
+	InstitutionName string
+
+	// Quiz answers arriving up to QuizGracePeriod after an attempt's deadline still count, to
+	// allow for network delays; anything later is refused.
+	QuizGracePeriod time.Duration
 }
 
 // LoadConfig reads configuration from .env file and environment variables.
@@ -43,7 +107,9 @@ func LoadConfig() *Config {
 		expiryDuration = 24 * time.Hour
 	}
 
-	return &Config{
+	jwtSecret := getEnv("JWT_SECRET", "super-secret-key")
+
+	cfg := &Config{
 		DBHost:     getEnv("DB_HOST", "localhost"),
 		DBPort:     getEnv("DB_PORT", "5432"),
 		DBUser:     getEnv("DB_USER", "postgres"),
@@ -53,13 +119,72 @@ func LoadConfig() *Config {
 
 		ServerPort: getEnv("SERVER_PORT", "8080"), // Changed default back to 8080 for consistency
 
-		JWTSecret: getEnv("JWT_SECRET", "super-secret-key"),
+		JWTSecret: jwtSecret,
 		JWTExpiry: expiryDuration,
 		AppEnv:    getEnv("APP_ENV", "development"),
 
 		// Kafka defaults
 		KafkaBrokers: getEnv("KAFKA_BROKER", "localhost:9092"),
+
+		// Storage defaults (local filesystem; use STORAGE_BACKEND=s3 with MinIO for an S3 stand-in)
+		StorageBackend:  getEnv("STORAGE_BACKEND", "local"),
+		StorageLocalDir: getEnv("STORAGE_LOCAL_DIR", "./data/uploads"),
+		S3Endpoint:      getEnv("S3_ENDPOINT", "http://localhost:9000"),
+		S3Region:        getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:        getEnv("S3_BUCKET", "student-portal"),
+		S3AccessKey:     getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:     getEnv("S3_SECRET_KEY", ""),
+		S3UsePathStyle:  getEnvBool("S3_USE_PATH_STYLE", true),
+
+		PublicBaseURL:      getEnv("PUBLIC_BASE_URL", "http://localhost:8080"),
+		FileSigningKey:     getEnvSigningKey("FILE_SIGNING_KEY", jwtSecret, "file signing"),
+		SignedURLTTL:       getEnvDuration("SIGNED_URL_TTL", 15*time.Minute),
+		MaxUploadSize:      getEnvInt64("MAX_UPLOAD_SIZE", 20<<20),
+		AvatarMaxSize:      getEnvInt64("AVATAR_MAX_SIZE", 5<<20),
+		AvatarMaxDimension: int(getEnvInt64("AVATAR_MAX_DIMENSION", 256)),
+		AvatarMaxPixels:    getEnvInt64("AVATAR_MAX_PIXELS", 25_000_000),
+
+		EmailLowercaseLocalPart: getEnvBool("EMAIL_LOWERCASE_LOCAL_PART", true),
+
+		GuardianInviteTTL: getEnvDuration("GUARDIAN_INVITE_TTL", 7*24*time.Hour),
+		GuardianInviteURL: getEnv("GUARDIAN_INVITE_URL", "http://localhost:3000/guardian/accept"),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		MailFrom:     getEnv("MAIL_FROM", "Student Portal <no-reply@localhost>"),
+
+		TimetableClashPolicy: getEnv("TIMETABLE_CLASH_POLICY", "reject"),
+
+		CalendarLocation: getEnvLocation("CALENDAR_TIMEZONE", time.UTC),
+
+		AttendanceAlertThreshold:   getEnvFloat("ATTENDANCE_ALERT_THRESHOLD", 80),
+		AttendanceAlertMinSessions: int(getEnvInt64("ATTENDANCE_ALERT_MIN_SESSIONS", 3)),
+
+		CheckInSigningKey:  getEnvSigningKey("CHECK_IN_SIGNING_KEY", jwtSecret, "check-in codes"),
+		CheckInCodeTTL:     getEnvDuration("CHECK_IN_CODE_TTL", 30*time.Second),
+		CheckInMaxAttempts: int(getEnvInt64("CHECK_IN_MAX_ATTEMPTS", 5)),
+
+		MaxSubmissionFiles: int(getEnvInt64("MAX_SUBMISSION_FILES", 10)),
+
+		InstitutionName: getEnv("INSTITUTION_NAME", "Student Portal"),
+
+		QuizGracePeriod: getEnvDuration("QUIZ_GRACE_PERIOD", 30*time.Second),
+	}
+
+	if cfg.CheckInSigningKey == cfg.FileSigningKey {
+		log.Printf("Warning: CHECK_IN_SIGNING_KEY must differ from FILE_SIGNING_KEY. Deriving it from JWT_SECRET.")
+		cfg.CheckInSigningKey = deriveKey(jwtSecret, "check-in codes")
+	}
+
+	// Check-in codes rotate in whole seconds
+	if cfg.CheckInCodeTTL < time.Second {
+		log.Printf("Warning: CHECK_IN_CODE_TTL '%s' is shorter than 1s. Defaulting to 30s.", cfg.CheckInCodeTTL)
+		cfg.CheckInCodeTTL = 30 * time.Second
 	}
+
+	return cfg
 }
 
 func getEnv(key string, defaultValue string) string {
@@ -69,6 +194,95 @@ func getEnv(key string, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvSigningKey returns the key configured for one signing purpose. Without one, or when it
+// repeats JWT_SECRET, the key is derived from JWT_SECRET so that no two purposes share a key.
+func getEnvSigningKey(key, jwtSecret, purpose string) string {
+	value := getEnv(key, "")
+	if value == "" {
+		return deriveKey(jwtSecret, purpose)
+	}
+	if value == jwtSecret {
+		log.Printf("Warning: %s must differ from JWT_SECRET. Deriving it from JWT_SECRET.", key)
+		return deriveKey(jwtSecret, purpose)
+	}
+	return value
+}
+
+// deriveKey derives an independent key for the given purpose from a master secret with
+// HKDF-SHA256.
+func deriveKey(secret, purpose string) string {
+	key, err := hkdf.Key(sha256.New, []byte(secret), nil, "student-portal "+purpose, 32)
+	if err != nil {
+		log.Fatalf("Failed to derive the %s key: %v", purpose, err)
+	}
+	return hex.EncodeToString(key)
+}
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		log.Printf("Warning: Failed to parse %s '%s'. Defaulting to %d.", key, valueStr, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		log.Printf("Warning: Failed to parse %s '%s'. Defaulting to %g.", key, valueStr, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		log.Printf("Warning: Failed to parse %s '%s'. Defaulting to %t.", key, valueStr, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := time.ParseDuration(valueStr)
+	if err != nil {
+		log.Printf("Warning: Failed to parse %s '%s'. Defaulting to %s.", key, valueStr, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvLocation(key string, defaultValue *time.Location) *time.Location {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := time.LoadLocation(valueStr)
+	if err != nil {
+		log.Printf("Warning: Failed to load time zone %s '%s'. Defaulting to %s.", key, valueStr, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
 // DatabaseURL constructs the PostgreSQL connection URL.
 func (c *Config) DatabaseURL() string {
 	return "postgresql://" + c.DBUser + ":" + c.DBPassword + "@" + c.DBHost + ":" + c.DBPort + "/" + c.DBName + "?sslmode=" + c.DBSSLMode
@@ -0,0 +1,380 @@
+// internal/handler/assignment_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AssignmentHandler handles HTTP requests for assignments and submissions.
+type AssignmentHandler struct {
+	svc service.AssignmentService
+	cfg *config.Config
+}
+
+// NewAssignmentHandler creates a new AssignmentHandler.
+func NewAssignmentHandler(svc service.AssignmentService, cfg *config.Config) *AssignmentHandler {
+	return &AssignmentHandler{svc: svc, cfg: cfg}
+}
+
+// CreateAssignment sets a new assignment for the section identified by the {id} URL parameter
+// (Instructor or Admin).
+func (h *AssignmentHandler) CreateAssignment(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sectionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.CreateAssignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	assignment, err := h.svc.CreateAssignment(r.Context(), sectionID, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, assignment)
+}
+
+// ListAssignments lists the assignments of a section by due date (Instructor or Admin).
+func (h *AssignmentHandler) ListAssignments(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sectionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	assignments, err := h.svc.ListAssignments(r.Context(), sectionID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, assignments)
+}
+
+// GetAssignment returns the assignment identified by the {id} URL parameter to its instructor,
+// admins and the students of its section.
+func (h *AssignmentHandler) GetAssignment(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	assignment, err := h.svc.GetAssignment(r.Context(), id, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, assignment)
+}
+
+// UpdateAssignment updates an assignment (Instructor or Admin).
+func (h *AssignmentHandler) UpdateAssignment(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.UpdateAssignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	assignment, err := h.svc.UpdateAssignment(r.Context(), id, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, assignment)
+}
+
+// DeleteAssignment deletes an assignment nobody has submitted to yet (Instructor or Admin).
+func (h *AssignmentHandler) DeleteAssignment(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteAssignment(r.Context(), id, claims.UserID, isAdminRequest(r)); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// Submit hands in a new version of the authenticated student's work for the assignment
+// (multipart form with one or more "file" fields and an optional "comment").
+func (h *AssignmentHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	assignmentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	headers, err := readUploadedFiles(w, r, h.cfg.MaxUploadSize, h.cfg.MaxSubmissionFiles)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+	files := make([]service.UploadedFile, 0, len(headers))
+	for _, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			utils.SendError(w, appErrors.ErrBadRequest)
+			return
+		}
+		defer file.Close()
+		files = append(files, service.UploadedFile{Filename: header.Filename, Content: file})
+	}
+
+	submission, err := h.svc.Submit(r.Context(), assignmentID, claims.UserID, r.FormValue("comment"), files)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, submission)
+}
+
+// ListSubmissions returns every student of the assignment's section with their latest
+// submission (Instructor or Admin).
+func (h *AssignmentHandler) ListSubmissions(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	assignmentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	submissions, err := h.svc.ListSubmissions(r.Context(), assignmentID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, submissions)
+}
+
+// ListStudentVersions returns every version the student identified by the {studentId} URL
+// parameter handed in for the assignment (Instructor or Admin).
+func (h *AssignmentHandler) ListStudentVersions(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	assignmentID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	studentID, err := strconv.ParseInt(chi.URLParam(r, "studentId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	versions, err := h.svc.ListVersions(r.Context(), assignmentID, studentID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, versions)
+}
+
+// ListOwnVersions returns every version the authenticated student handed in for the assignment.
+func (h *AssignmentHandler) ListOwnVersions(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	assignmentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	versions, err := h.svc.ListVersions(r.Context(), assignmentID, claims.UserID, claims.UserID, false)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, versions)
+}
+
+// GetSubmission returns a submission with its files to the student who handed it in, the
+// section's instructor and admins.
+func (h *AssignmentHandler) GetSubmission(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	submission, err := h.svc.GetSubmission(r.Context(), id, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, submission)
+}
+
+// GetSubmissionFileURL returns a signed download URL for the {fileId} file of a submission.
+func (h *AssignmentHandler) GetSubmissionFileURL(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	submissionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	fileID, err := strconv.ParseInt(chi.URLParam(r, "fileId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	resp, err := h.svc.GetSubmissionFileURL(r.Context(), submissionID, fileID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, resp)
+}
+
+// GetOwnAssignments returns the authenticated student's assignments in the ?term_id= term
+// (default: current) with their latest submissions.
+func (h *AssignmentHandler) GetOwnAssignments(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	assignments, err := h.svc.GetStudentAssignments(r.Context(), claims.UserID, termID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, assignments)
+}
+
+// GetStudentAssignments returns the assignments of the student identified by the {id} URL parameter.
+func (h *AssignmentHandler) GetStudentAssignments(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	studentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	assignments, err := h.svc.GetStudentAssignments(r.Context(), studentID, termID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, assignments)
+}
@@ -0,0 +1,201 @@
+// internal/handler/attendance_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AttendanceHandler handles HTTP requests for class sessions and attendance.
+type AttendanceHandler struct {
+	svc service.AttendanceService
+	cfg *config.Config
+}
+
+// NewAttendanceHandler creates a new AttendanceHandler.
+func NewAttendanceHandler(svc service.AttendanceService, cfg *config.Config) *AttendanceHandler {
+	return &AttendanceHandler{svc: svc, cfg: cfg}
+}
+
+// GenerateSessions creates the class sessions of the section identified by the {id} URL
+// parameter from its weekly meetings (Instructor or Admin).
+func (h *AttendanceHandler) GenerateSessions(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sectionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	sessions, err := h.svc.GenerateSessions(r.Context(), sectionID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, sessions)
+}
+
+// ListSessions lists the class sessions of a section with their attendance counts (Instructor or Admin).
+func (h *AttendanceHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sectionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	sessions, err := h.svc.ListSessions(r.Context(), sectionID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, sessions)
+}
+
+// ListSectionAttendance returns the attendance rate of every student in a section (Instructor or Admin).
+func (h *AttendanceHandler) ListSectionAttendance(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sectionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	summaries, err := h.svc.ListSectionSummaries(r.Context(), sectionID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, summaries)
+}
+
+// GetSessionAttendance returns the roster of a class session with everyone's attendance (Instructor or Admin).
+func (h *AttendanceHandler) GetSessionAttendance(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sessionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	attendance, err := h.svc.GetSessionAttendance(r.Context(), sessionID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, attendance)
+}
+
+// MarkAttendance marks the attendance of several students of a class session at once (Instructor or Admin).
+func (h *AttendanceHandler) MarkAttendance(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sessionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.MarkAttendanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	attendance, err := h.svc.MarkAttendance(r.Context(), sessionID, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, attendance)
+}
+
+// GetOwnAttendance returns the authenticated student's attendance in the ?term_id= term (default: current).
+func (h *AttendanceHandler) GetOwnAttendance(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	attendance, err := h.svc.GetStudentAttendance(r.Context(), claims.UserID, termID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, attendance)
+}
+
+// GetStudentAttendance returns the attendance of the student identified by the {id} URL parameter.
+func (h *AttendanceHandler) GetStudentAttendance(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	studentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	attendance, err := h.svc.GetStudentAttendance(r.Context(), studentID, termID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, attendance)
+}
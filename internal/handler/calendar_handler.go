@@ -0,0 +1,160 @@
+// internal/handler/calendar_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CalendarHandler handles HTTP requests for iCalendar subscription feeds.
+type CalendarHandler struct {
+	svc service.CalendarService
+	cfg *config.Config
+}
+
+// NewCalendarHandler creates a new CalendarHandler.
+func NewCalendarHandler(svc service.CalendarService, cfg *config.Config) *CalendarHandler {
+	return &CalendarHandler{svc: svc, cfg: cfg}
+}
+
+// Subscribe serves the .ics feed identified by the secret {token} URL parameter. Calendar
+// apps fetch it without credentials, so the token is the only authentication.
+func (h *CalendarHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSuffix(chi.URLParam(r, "token"), ".ics")
+	if token == "" {
+		utils.SendError(w, appErrors.ErrNoCalendarFeed)
+		return
+	}
+
+	body, err := h.svc.RenderFeed(r.Context(), token)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Header().Set("Content-Disposition", `inline; filename="timetable.ics"`)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// GetOwnFeed returns whether the authenticated user has a feed and when it was last fetched.
+func (h *CalendarHandler) GetOwnFeed(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	feed, err := h.svc.GetFeed(r.Context(), claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, feed)
+}
+
+// CreateOwnFeed generates a new feed URL for the authenticated user, replacing any previous one.
+func (h *CalendarHandler) CreateOwnFeed(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	feed, err := h.svc.CreateFeed(r.Context(), claims.UserID, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, feed)
+}
+
+// RevokeOwnFeed stops the authenticated user's feed URL from working.
+func (h *CalendarHandler) RevokeOwnFeed(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	if err := h.svc.RevokeFeed(r.Context(), claims.UserID); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// GetFeed returns the feed of the user identified by the {id} URL parameter (Admin Only).
+func (h *CalendarHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	feed, err := h.svc.GetFeed(r.Context(), userID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, feed)
+}
+
+// RegenerateFeed issues a new feed URL for the user, e.g. after the old one leaked (Admin Only).
+func (h *CalendarHandler) RegenerateFeed(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	feed, err := h.svc.CreateFeed(r.Context(), userID, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, feed)
+}
+
+// RevokeFeed stops the user's feed URL from working (Admin Only).
+func (h *CalendarHandler) RevokeFeed(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.RevokeFeed(r.Context(), userID); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
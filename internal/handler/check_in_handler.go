@@ -0,0 +1,142 @@
+// internal/handler/check_in_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CheckInHandler handles HTTP requests for attendance self check-in.
+type CheckInHandler struct {
+	svc service.CheckInService
+	cfg *config.Config
+}
+
+// NewCheckInHandler creates a new CheckInHandler.
+func NewCheckInHandler(svc service.CheckInService, cfg *config.Config) *CheckInHandler {
+	return &CheckInHandler{svc: svc, cfg: cfg}
+}
+
+// clientIP returns the address the request came from. Forwarding headers are deliberately
+// ignored, since clients could forge them to get around network restrictions.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// OpenWindow opens self check-in for the class session identified by the {id} URL parameter
+// and returns its first code (Instructor or Admin).
+func (h *CheckInHandler) OpenWindow(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sessionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.OpenCheckInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	code, err := h.svc.OpenWindow(r.Context(), sessionID, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, code)
+}
+
+// GetCode returns the current check-in code of a class session; displays poll it to rotate
+// the code (Instructor or Admin).
+func (h *CheckInHandler) GetCode(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sessionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	code, err := h.svc.GetCode(r.Context(), sessionID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	utils.SendJSON(w, http.StatusOK, code)
+}
+
+// CloseWindow ends self check-in for a class session (Instructor or Admin).
+func (h *CheckInHandler) CloseWindow(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sessionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.CloseWindow(r.Context(), sessionID, claims.UserID, isAdminRequest(r)); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// CheckIn marks the authenticated student present with a code shown in class.
+func (h *CheckInHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	var req models.CheckInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	record, err := h.svc.CheckIn(r.Context(), claims.UserID, &req, clientIP(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, record)
+}
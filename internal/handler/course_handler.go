@@ -0,0 +1,185 @@
+// internal/handler/course_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// CourseHandler handles HTTP requests for the course catalog.
+type CourseHandler struct {
+	svc service.CourseService
+	cfg *config.Config
+}
+
+// NewCourseHandler creates a new CourseHandler.
+func NewCourseHandler(svc service.CourseService, cfg *config.Config) *CourseHandler {
+	return &CourseHandler{svc: svc, cfg: cfg}
+}
+
+// isAdminRequest reports whether the (optionally) authenticated caller is an admin.
+func isAdminRequest(r *http.Request) bool {
+	claims := middleware.OptionalUserClaims(r.Context())
+	return claims != nil && claims.Role == string(enums.RoleAdmin)
+}
+
+// ListCourses searches the course catalog. Supports the q and department query
+// parameters; admins can pass include_inactive=true to see inactive courses.
+func (h *CourseHandler) ListCourses(w http.ResponseWriter, r *http.Request) {
+	query := utils.NewPaginationQuery(r)
+
+	filter := models.CourseFilter{
+		Search:     r.URL.Query().Get("q"),
+		Department: r.URL.Query().Get("department"),
+	}
+	if includeStr := r.URL.Query().Get("include_inactive"); includeStr != "" {
+		include, err := strconv.ParseBool(includeStr)
+		if err != nil {
+			utils.SendError(w, appErrors.ErrBadRequest)
+			return
+		}
+		if include && !isAdminRequest(r) {
+			utils.SendError(w, appErrors.ErrForbidden)
+			return
+		}
+		filter.IncludeInactive = include
+	}
+
+	courses, totalCount, err := h.svc.ListCourses(r.Context(), filter, query.Limit, query.Offset)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	resp := utils.NewPaginationResponse(courses, query, totalCount)
+	utils.SendJSON(w, http.StatusOK, resp)
+}
+
+// GetCourse returns a course. Inactive courses are only visible to admins.
+func (h *CourseHandler) GetCourse(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	course, err := h.svc.GetCourse(r.Context(), id, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, course)
+}
+
+// CreateCourse adds a course to the catalog (Admin Only).
+func (h *CourseHandler) CreateCourse(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	var req models.CreateCourseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	course, err := h.svc.CreateCourse(r.Context(), &req, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, course)
+}
+
+// UpdateCourse changes a course, including activating or deactivating it (Admin Only).
+func (h *CourseHandler) UpdateCourse(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.UpdateCourseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	course, err := h.svc.UpdateCourse(r.Context(), id, &req, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, course)
+}
+
+// DeleteCourse removes a course that nothing references yet (Admin Only).
+func (h *CourseHandler) DeleteCourse(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteCourse(r.Context(), id, claims.UserID); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// SetRequirements replaces a course's prerequisites and co-requisites (Admin Only).
+func (h *CourseHandler) SetRequirements(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.CourseRequirements
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	course, err := h.svc.SetRequirements(r.Context(), id, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, course)
+}
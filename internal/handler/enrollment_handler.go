@@ -0,0 +1,390 @@
+// internal/handler/enrollment_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// EnrollmentHandler handles HTTP requests for course enrollments and waitlists.
+type EnrollmentHandler struct {
+	svc service.EnrollmentService
+	cfg *config.Config
+}
+
+// NewEnrollmentHandler creates a new EnrollmentHandler.
+func NewEnrollmentHandler(svc service.EnrollmentService, cfg *config.Config) *EnrollmentHandler {
+	return &EnrollmentHandler{svc: svc, cfg: cfg}
+}
+
+// Enroll enrolls the authenticated student in the course, or puts them on its waitlist when it is full.
+// The term is taken from ?term_id= and defaults to the current term; courses split into sections
+// need a ?section_id=.
+func (h *EnrollmentHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	courseID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	sectionID, err := sectionIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	enrollment, err := h.svc.Enroll(r.Context(), courseID, termID, sectionID, claims.UserID, claims.UserID, nil, true)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, enrollment)
+}
+
+// ChangeSection moves the authenticated student to another section of the course, keeping their seat.
+func (h *EnrollmentHandler) ChangeSection(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	courseID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.ChangeSectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	enrollment, err := h.svc.ChangeSection(r.Context(), courseID, termID, req.SectionID, claims.UserID, true)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, enrollment)
+}
+
+// Drop removes the authenticated student from the course or its waitlist, subject to the drop deadline.
+func (h *EnrollmentHandler) Drop(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	courseID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	enrollment, err := h.svc.Drop(r.Context(), courseID, termID, claims.UserID, claims.UserID, true)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, enrollment)
+}
+
+// ListOwnEnrollments lists the authenticated student's enrollments, including waitlist places.
+func (h *EnrollmentHandler) ListOwnEnrollments(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	enrollments, err := h.svc.ListStudentEnrollments(r.Context(), claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, enrollments)
+}
+
+// ListStudentEnrollments lists the enrollments of the student identified by the {id} URL parameter.
+func (h *EnrollmentHandler) ListStudentEnrollments(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	studentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	enrollments, err := h.svc.ListStudentEnrollments(r.Context(), studentID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, enrollments)
+}
+
+// ListCourseEnrollments lists a course's roster and waitlist for the ?term_id= term (default: current),
+// optionally filtered by ?status= (Admin Only).
+func (h *EnrollmentHandler) ListCourseEnrollments(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	courseID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	enrollments, err := h.svc.ListCourseEnrollments(r.Context(), courseID, termID, r.URL.Query().Get("status"))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, enrollments)
+}
+
+// AdminEnroll enrolls a student in a course on their behalf (Admin Only).
+// Capacity still applies; a full course puts the student on the waitlist. Unmet
+// requirements can be overridden with a justification, and the add deadline does not apply.
+func (h *EnrollmentHandler) AdminEnroll(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	courseID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.AdminEnrollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var override *models.RequirementOverride
+	if req.OverrideRequirements {
+		override = &models.RequirementOverride{By: claims.UserID, Justification: req.Justification}
+	}
+
+	var sectionID int64
+	if req.SectionID != nil {
+		sectionID = *req.SectionID
+	}
+
+	enrollment, err := h.svc.Enroll(r.Context(), courseID, termID, sectionID, req.StudentID, claims.UserID, override, false)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, enrollment)
+}
+
+// AdminDrop removes a student from a course or its waitlist, regardless of the drop deadline (Admin Only).
+func (h *EnrollmentHandler) AdminDrop(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	courseID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	studentID, err := strconv.ParseInt(chi.URLParam(r, "studentId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	enrollment, err := h.svc.Drop(r.Context(), courseID, termID, studentID, claims.UserID, false)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, enrollment)
+}
+
+// CheckEligibility explains whether the authenticated student meets the course's
+// prerequisites and co-requisites for the ?term_id= term (default: current).
+func (h *EnrollmentHandler) CheckEligibility(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	courseID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	eligibility, err := h.svc.CheckEligibility(r.Context(), courseID, termID, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, eligibility)
+}
+
+// ListOwnCompletions lists the courses the authenticated student has completed.
+func (h *EnrollmentHandler) ListOwnCompletions(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	completions, err := h.svc.ListCompletions(r.Context(), claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, completions)
+}
+
+// ListCompletions lists the courses a student has completed (Admin Only).
+func (h *EnrollmentHandler) ListCompletions(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	studentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	completions, err := h.svc.ListCompletions(r.Context(), studentID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, completions)
+}
+
+// RecordCompletion records that a student completed a course with a final grade (Admin Only).
+func (h *EnrollmentHandler) RecordCompletion(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	studentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.CreateCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	completion, err := h.svc.RecordCompletion(r.Context(), studentID, &req, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, completion)
+}
+
+// DeleteCompletion removes a wrongly recorded completion (Admin Only).
+func (h *EnrollmentHandler) DeleteCompletion(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	completionID, err := strconv.ParseInt(chi.URLParam(r, "completionId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteCompletion(r.Context(), studentID, completionID); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
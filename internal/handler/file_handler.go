@@ -0,0 +1,121 @@
+// internal/handler/file_handler.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// uploadFormField is the multipart form field that carries uploaded files.
+const uploadFormField = "file"
+
+// FileHandler handles HTTP requests for stored files.
+type FileHandler struct {
+	svc service.FileService
+	cfg *config.Config
+}
+
+// NewFileHandler creates a new FileHandler.
+func NewFileHandler(svc service.FileService, cfg *config.Config) *FileHandler {
+	return &FileHandler{svc: svc, cfg: cfg}
+}
+
+// Download streams a file referenced by a signed download link (public, signature checked).
+func (h *FileHandler) Download(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	file, body, err := h.svc.OpenSigned(r.Context(), q.Get("key"), q.Get("expires"), q.Get("signature"))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(file.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", file.Filename))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	// nolint:errcheck
+	io.Copy(w, body)
+}
+
+// GetFileURL returns a signed download URL for a file owned by the caller (or any file for admins).
+func (h *FileHandler) GetFileURL(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	file, err := h.svc.GetFile(r.Context(), id)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+	if claims.Role != string(enums.RoleAdmin) && (file.OwnerID == nil || *file.OwnerID != claims.UserID) {
+		utils.SendError(w, appErrors.ErrForbidden)
+		return
+	}
+
+	resp, err := h.svc.SignedURL(r.Context(), file)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, resp)
+}
+
+// readUploadedFile extracts the uploaded file from a multipart request, capping the body size.
+// The caller must close the returned file.
+func readUploadedFile(w http.ResponseWriter, r *http.Request, maxSize int64) (multipart.File, *multipart.FileHeader, error) {
+	// Allow some headroom for the multipart envelope; the service enforces the exact limit.
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, nil, appErrors.ErrFileTooLarge
+		}
+		return nil, nil, appErrors.ErrBadRequest
+	}
+
+	file, header, err := r.FormFile(uploadFormField)
+	if err != nil {
+		return nil, nil, appErrors.ErrBadRequest
+	}
+	return file, header, nil
+}
+
+// readUploadedFiles parses a multipart request carrying up to maxFiles files, each of at most
+// maxSize bytes, and returns the headers of the files uploaded under the form field.
+func readUploadedFiles(w http.ResponseWriter, r *http.Request, maxSize int64, maxFiles int) ([]*multipart.FileHeader, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize*int64(maxFiles)+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, appErrors.ErrFileTooLarge
+		}
+		return nil, appErrors.ErrBadRequest
+	}
+	return r.MultipartForm.File[uploadFormField], nil
+}
@@ -0,0 +1,333 @@
+// internal/handler/gradebook_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GradebookHandler handles HTTP requests for grade scales, categories and grades.
+type GradebookHandler struct {
+	svc service.GradebookService
+	cfg *config.Config
+}
+
+// NewGradebookHandler creates a new GradebookHandler.
+func NewGradebookHandler(svc service.GradebookService, cfg *config.Config) *GradebookHandler {
+	return &GradebookHandler{svc: svc, cfg: cfg}
+}
+
+// GetGradeScale returns the grade scale of the course identified by the {id} URL parameter.
+func (h *GradebookHandler) GetGradeScale(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	courseID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	scale, err := h.svc.GetGradeScale(r.Context(), courseID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, scale)
+}
+
+// SetGradeScale replaces the grade scale of the course identified by the {id} URL parameter (Admin only).
+func (h *GradebookHandler) SetGradeScale(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	courseID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.SetGradeScaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	scale, err := h.svc.SetGradeScale(r.Context(), courseID, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, scale)
+}
+
+// ListCategories lists the grade categories of the section identified by the {id} URL
+// parameter (Instructor or Admin).
+func (h *GradebookHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sectionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	categories, err := h.svc.ListCategories(r.Context(), sectionID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, categories)
+}
+
+// SetCategories replaces the grade categories of the section identified by the {id} URL
+// parameter (Instructor or Admin).
+func (h *GradebookHandler) SetCategories(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sectionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.SetGradeCategoriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	categories, err := h.svc.SetCategories(r.Context(), sectionID, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, categories)
+}
+
+// ListGrades lists the grades of the assignment identified by the {id} URL parameter
+// (Instructor or Admin).
+func (h *GradebookHandler) ListGrades(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	assignmentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	grades, err := h.svc.ListGrades(r.Context(), assignmentID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, grades)
+}
+
+// SetGrades grades students on the assignment identified by the {id} URL parameter
+// (Instructor or Admin).
+func (h *GradebookHandler) SetGrades(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	assignmentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.SetGradesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	grades, err := h.svc.SetGrades(r.Context(), assignmentID, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, grades)
+}
+
+// ReleaseGrades releases the grades of the assignment identified by the {id} URL parameter to
+// its students and returns the grades that were newly released (Instructor or Admin).
+func (h *GradebookHandler) ReleaseGrades(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	assignmentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	released, err := h.svc.ReleaseGrades(r.Context(), assignmentID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, released)
+}
+
+// GetGradebook returns the gradebook of the section identified by the {id} URL parameter
+// (Instructor or Admin).
+func (h *GradebookHandler) GetGradebook(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sectionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	gradebook, err := h.svc.GetGradebook(r.Context(), sectionID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, gradebook)
+}
+
+// GetStudentBreakdown explains the grade of the student identified by the {studentId} URL
+// parameter in the section (Instructor or Admin).
+func (h *GradebookHandler) GetStudentBreakdown(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	sectionID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	studentID, err := strconv.ParseInt(chi.URLParam(r, "studentId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	breakdown, err := h.svc.GetStudentBreakdown(r.Context(), sectionID, studentID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, breakdown)
+}
+
+// PostFinalGrades posts the final grades of the section identified by the {id} URL parameter
+// as course completions (Instructor or Admin).
+func (h *GradebookHandler) PostFinalGrades(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sectionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	grades, err := h.svc.PostFinalGrades(r.Context(), sectionID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, grades)
+}
+
+// GetOwnGrades returns the authenticated student's released grades in the ?term_id= term
+// (default: current).
+func (h *GradebookHandler) GetOwnGrades(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	grades, err := h.svc.GetStudentGrades(r.Context(), claims.UserID, termID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, grades)
+}
+
+// GetStudentGrades returns the released grades of the student identified by the {id} URL parameter.
+func (h *GradebookHandler) GetStudentGrades(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	studentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	grades, err := h.svc.GetStudentGrades(r.Context(), studentID, termID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, grades)
+}
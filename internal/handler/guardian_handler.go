@@ -0,0 +1,192 @@
+// internal/handler/guardian_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GuardianHandler handles HTTP requests for guardian links, invitations and guardian access.
+type GuardianHandler struct {
+	svc service.GuardianService
+	cfg *config.Config
+}
+
+// NewGuardianHandler creates a new GuardianHandler.
+func NewGuardianHandler(svc service.GuardianService, cfg *config.Config) *GuardianHandler {
+	return &GuardianHandler{svc: svc, cfg: cfg}
+}
+
+// CreateLink links a guardian account to a student (Admin Only).
+func (h *GuardianHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	var req models.CreateGuardianLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	link, err := h.svc.CreateLink(r.Context(), &req, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, link)
+}
+
+// UpdateLink changes a link's relationship or consent flags (Admin Only).
+func (h *GuardianHandler) UpdateLink(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.UpdateGuardianLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	link, err := h.svc.UpdateLink(r.Context(), id, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, link)
+}
+
+// DeleteLink removes a student–guardian link (Admin Only).
+func (h *GuardianHandler) DeleteLink(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteLink(r.Context(), id); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// ListGuardians lists the guardians linked to a student (Admin Only).
+func (h *GuardianHandler) ListGuardians(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	links, err := h.svc.ListGuardians(r.Context(), id)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, links)
+}
+
+// CreateInvitation invites a parent by email to become a student's guardian (Admin Only).
+func (h *GuardianHandler) CreateInvitation(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	var req models.CreateGuardianInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	invitation, err := h.svc.CreateInvitation(r.Context(), &req, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, invitation)
+}
+
+// AcceptInvitation accepts a guardian invitation, creating the guardian account if needed.
+// The invitation token is the credential, so this route is public.
+func (h *GuardianHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	var req models.AcceptGuardianInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	link, err := h.svc.AcceptInvitation(r.Context(), &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, link)
+}
+
+// ListLinkedStudents lists the students linked to the authenticated guardian.
+func (h *GuardianHandler) ListLinkedStudents(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	students, err := h.svc.ListLinkedStudents(r.Context(), claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, students)
+}
+
+// GetLinkedStudent returns a linked student's profile to the authenticated guardian (read-only).
+func (h *GuardianHandler) GetLinkedStudent(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	student, err := h.svc.GetLinkedStudent(r.Context(), claims.UserID, id)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, student)
+}
@@ -0,0 +1,49 @@
+// internal/handler/history_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HistoryHandler handles HTTP requests for entity change history.
+type HistoryHandler struct {
+	svc service.HistoryService
+	cfg *config.Config
+}
+
+// NewHistoryHandler creates a new HistoryHandler.
+func NewHistoryHandler(svc service.HistoryService, cfg *config.Config) *HistoryHandler {
+	return &HistoryHandler{svc: svc, cfg: cfg}
+}
+
+// ListHistory returns a handler that lists the paginated change history, newest first,
+// of the entity of the given type identified by the {id} URL parameter.
+func (h *HistoryHandler) ListHistory(entityType enums.EntityType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil {
+			utils.SendError(w, appErrors.ErrBadRequest)
+			return
+		}
+
+		query := utils.NewPaginationQuery(r)
+
+		entries, totalCount, err := h.svc.ListHistory(r.Context(), entityType, id, query.Limit, query.Offset)
+		if err != nil {
+			utils.SendError(w, err)
+			return
+		}
+
+		resp := utils.NewPaginationResponse(entries, query, totalCount)
+		utils.SendJSON(w, http.StatusOK, resp)
+	}
+}
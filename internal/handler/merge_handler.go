@@ -0,0 +1,91 @@
+// internal/handler/merge_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+)
+
+// MergeHandler handles HTTP requests for duplicate account detection and merging.
+type MergeHandler struct {
+	svc service.MergeService
+	cfg *config.Config
+}
+
+// NewMergeHandler creates a new MergeHandler.
+func NewMergeHandler(svc service.MergeService, cfg *config.Config) *MergeHandler {
+	return &MergeHandler{svc: svc, cfg: cfg}
+}
+
+// ListDuplicates lists likely duplicate account pairs, most likely first (Admin Only).
+// The optional 'min_score' query parameter (0..1) overrides the default threshold.
+func (h *MergeHandler) ListDuplicates(w http.ResponseWriter, r *http.Request) {
+	minScore := service.DefaultDuplicateMinScore
+	if minScoreStr := r.URL.Query().Get("min_score"); minScoreStr != "" {
+		value, err := strconv.ParseFloat(minScoreStr, 64)
+		if err != nil || value < 0 || value > 1 {
+			utils.SendError(w, appErrors.New(http.StatusBadRequest, "min_score must be a number between 0 and 1"))
+			return
+		}
+		minScore = value
+	}
+
+	query := utils.NewPaginationQuery(r)
+
+	candidates, totalCount, err := h.svc.FindDuplicates(r.Context(), minScore, query.Limit, query.Offset)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	resp := utils.NewPaginationResponse(candidates, query, totalCount)
+	utils.SendJSON(w, http.StatusOK, resp)
+}
+
+// PreviewMerge shows what merging the source account into the target would do (Admin Only).
+func (h *MergeHandler) PreviewMerge(w http.ResponseWriter, r *http.Request) {
+	var req models.MergeUsersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	preview, err := h.svc.PreviewMerge(r.Context(), &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, preview)
+}
+
+// MergeUsers merges the source account into the target account (Admin Only).
+func (h *MergeHandler) MergeUsers(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	var req models.MergeUsersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	merge, err := h.svc.MergeUsers(r.Context(), &req, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, merge)
+}
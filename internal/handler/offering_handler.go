@@ -0,0 +1,138 @@
+// internal/handler/offering_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// OfferingHandler handles HTTP requests for the courses offered in a term.
+type OfferingHandler struct {
+	svc service.OfferingService
+	cfg *config.Config
+}
+
+// NewOfferingHandler creates a new OfferingHandler.
+func NewOfferingHandler(svc service.OfferingService, cfg *config.Config) *OfferingHandler {
+	return &OfferingHandler{svc: svc, cfg: cfg}
+}
+
+// ListOfferings lists the courses offered in a term with their seat usage. Admins can pass
+// include_inactive=true to see withdrawn offerings.
+func (h *OfferingHandler) ListOfferings(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	termID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	includeInactive := false
+	if includeStr := r.URL.Query().Get("include_inactive"); includeStr != "" {
+		if includeInactive, err = strconv.ParseBool(includeStr); err != nil {
+			utils.SendError(w, appErrors.ErrBadRequest)
+			return
+		}
+		if includeInactive && !isAdminRequest(r) {
+			utils.SendError(w, appErrors.ErrForbidden)
+			return
+		}
+	}
+
+	offerings, err := h.svc.ListOfferings(r.Context(), termID, includeInactive)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, offerings)
+}
+
+// CreateOffering offers a course in a term (Admin Only).
+func (h *OfferingHandler) CreateOffering(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	termID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.CreateOfferingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	offering, err := h.svc.CreateOffering(r.Context(), termID, &req, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, offering)
+}
+
+// UpdateOffering changes an offering's capacity, drop deadline or availability (Admin Only).
+func (h *OfferingHandler) UpdateOffering(w http.ResponseWriter, r *http.Request) {
+	termID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	courseID, err := strconv.ParseInt(chi.URLParam(r, "courseId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.UpdateOfferingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	offering, err := h.svc.UpdateOffering(r.Context(), termID, courseID, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, offering)
+}
+
+// DeleteOffering withdraws a course from a term before anyone enrolled (Admin Only).
+func (h *OfferingHandler) DeleteOffering(w http.ResponseWriter, r *http.Request) {
+	termID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	courseID, err := strconv.ParseInt(chi.URLParam(r, "courseId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteOffering(r.Context(), termID, courseID); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,325 @@
+// internal/handler/peer_review_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// PeerReviewHandler handles HTTP requests for anonymous peer review of assignments.
+type PeerReviewHandler struct {
+	svc service.PeerReviewService
+	cfg *config.Config
+}
+
+// NewPeerReviewHandler creates a new PeerReviewHandler.
+func NewPeerReviewHandler(svc service.PeerReviewService, cfg *config.Config) *PeerReviewHandler {
+	return &PeerReviewHandler{svc: svc, cfg: cfg}
+}
+
+// GetSettings returns the peer review settings of the assignment identified by the {id} URL
+// parameter (Instructor or Admin).
+func (h *PeerReviewHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	settings, err := h.svc.GetSettings(r.Context(), id, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, settings)
+}
+
+// SetSettings turns on or changes peer review of the assignment identified by the {id} URL
+// parameter (Instructor or Admin).
+func (h *PeerReviewHandler) SetSettings(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.PeerReviewSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	settings, err := h.svc.SetSettings(r.Context(), id, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, settings)
+}
+
+// DisablePeerReview turns off peer review of the assignment identified by the {id} URL
+// parameter (Instructor or Admin).
+func (h *PeerReviewHandler) DisablePeerReview(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.DisablePeerReview(r.Context(), id, claims.UserID, isAdminRequest(r)); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// AllocateReviews assigns the submissions of the assignment identified by the {id} URL
+// parameter to reviewers (Instructor or Admin).
+func (h *PeerReviewHandler) AllocateReviews(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	overview, err := h.svc.AllocateReviews(r.Context(), id, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, overview)
+}
+
+// GetOverview returns the peer reviews and aggregated scores of the assignment identified by
+// the {id} URL parameter (Instructor or Admin).
+func (h *PeerReviewHandler) GetOverview(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	overview, err := h.svc.GetOverview(r.Context(), id, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, overview)
+}
+
+// AcceptScores grades students of the assignment identified by the {id} URL parameter with
+// their aggregated peer scores (Instructor or Admin).
+func (h *PeerReviewHandler) AcceptScores(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.AcceptPeerScoresRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	grades, err := h.svc.AcceptScores(r.Context(), id, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, grades)
+}
+
+// ListOwnReviews lists the peer reviews assigned to the authenticated student.
+func (h *PeerReviewHandler) ListOwnReviews(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	tasks, err := h.svc.ListOwnReviews(r.Context(), claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, tasks)
+}
+
+// GetOwnReview returns the peer review identified by the {id} URL parameter with the work
+// to review (Reviewer only).
+func (h *PeerReviewHandler) GetOwnReview(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	task, err := h.svc.GetOwnReview(r.Context(), id, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, task)
+}
+
+// SubmitOwnReview hands in the peer review identified by the {id} URL parameter (Reviewer only).
+func (h *PeerReviewHandler) SubmitOwnReview(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.SubmitPeerReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	task, err := h.svc.SubmitOwnReview(r.Context(), id, claims.UserID, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, task)
+}
+
+// DownloadReviewFile streams the file at the {position} URL parameter of the submission under
+// the peer review identified by the {id} URL parameter (Reviewer only). Files are streamed
+// rather than signed because storage keys carry the owner's ID.
+func (h *PeerReviewHandler) DownloadReviewFile(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	position, err := strconv.Atoi(chi.URLParam(r, "position"))
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	file, body, err := h.svc.OpenReviewFile(r.Context(), id, position, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(file.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Filename))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	// nolint:errcheck
+	io.Copy(w, body)
+}
+
+// GetOwnPeerFeedback returns the peer reviews of the authenticated student's submission to the
+// assignment identified by the {id} URL parameter, once the review deadline has passed.
+func (h *PeerReviewHandler) GetOwnPeerFeedback(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	feedback, err := h.svc.GetOwnPeerFeedback(r.Context(), id, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, feedback)
+}
@@ -0,0 +1,116 @@
+// internal/handler/privacy_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// PrivacyHandler handles HTTP requests for personal data export and erasure.
+type PrivacyHandler struct {
+	svc service.PrivacyService
+	cfg *config.Config
+}
+
+// NewPrivacyHandler creates a new PrivacyHandler.
+func NewPrivacyHandler(svc service.PrivacyService, cfg *config.Config) *PrivacyHandler {
+	return &PrivacyHandler{svc: svc, cfg: cfg}
+}
+
+// RequestExport starts a personal data export for the authenticated user.
+func (h *PrivacyHandler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	job, err := h.svc.RequestExport(r.Context(), claims.UserID, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusAccepted, job)
+}
+
+// ListExports lists the authenticated user's data exports.
+func (h *PrivacyHandler) ListExports(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	jobs, err := h.svc.ListExports(r.Context(), claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, jobs)
+}
+
+// GetExport returns the status of an export and, once completed, a signed download URL.
+func (h *PrivacyHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	job, err := h.svc.GetExport(r.Context(), claims.UserID, id)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, job)
+}
+
+// EraseUser anonymizes a user's personal data (Admin Only).
+func (h *PrivacyHandler) EraseUser(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.EraseUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	erasure, err := h.svc.EraseUser(r.Context(), id, claims.UserID, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, erasure)
+}
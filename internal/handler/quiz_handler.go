@@ -0,0 +1,760 @@
+// internal/handler/quiz_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// QuizHandler handles HTTP requests for question banks, quizzes and attempts.
+type QuizHandler struct {
+	svc service.QuizService
+	cfg *config.Config
+}
+
+// NewQuizHandler creates a new QuizHandler.
+func NewQuizHandler(svc service.QuizService, cfg *config.Config) *QuizHandler {
+	return &QuizHandler{svc: svc, cfg: cfg}
+}
+
+// ListBanks lists the authenticated teacher's question banks; admins see every bank.
+func (h *QuizHandler) ListBanks(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	banks, err := h.svc.ListBanks(r.Context(), claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, banks)
+}
+
+// CreateBank creates a question bank owned by the authenticated teacher.
+func (h *QuizHandler) CreateBank(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	var req models.QuestionBankRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	bank, err := h.svc.CreateBank(r.Context(), &req, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, bank)
+}
+
+// GetBank returns the question bank identified by the {id} URL parameter with its questions
+// (Owner or Admin).
+func (h *QuizHandler) GetBank(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	bank, err := h.svc.GetBank(r.Context(), id, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, bank)
+}
+
+// UpdateBank renames the question bank identified by the {id} URL parameter (Owner or Admin).
+func (h *QuizHandler) UpdateBank(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.QuestionBankRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	bank, err := h.svc.UpdateBank(r.Context(), id, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, bank)
+}
+
+// DeleteBank removes the question bank identified by the {id} URL parameter (Owner or Admin).
+func (h *QuizHandler) DeleteBank(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteBank(r.Context(), id, claims.UserID, isAdminRequest(r)); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// AddQuestion adds a question to the bank identified by the {id} URL parameter (Owner or Admin).
+func (h *QuizHandler) AddQuestion(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	bankID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.QuestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	question, err := h.svc.AddQuestion(r.Context(), bankID, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, question)
+}
+
+// UpdateQuestion replaces the question identified by the {questionId} URL parameter in the
+// bank identified by {id} (Owner or Admin).
+func (h *QuizHandler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	bankID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	questionID, err := strconv.ParseInt(chi.URLParam(r, "questionId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.QuestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	question, err := h.svc.UpdateQuestion(r.Context(), bankID, questionID, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, question)
+}
+
+// DeleteQuestion removes the question identified by the {questionId} URL parameter from the
+// bank identified by {id} (Owner or Admin).
+func (h *QuizHandler) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	bankID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	questionID, err := strconv.ParseInt(chi.URLParam(r, "questionId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteQuestion(r.Context(), bankID, questionID, claims.UserID, isAdminRequest(r)); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// ExportBank downloads the bank identified by the {id} URL parameter as an IMS QTI package;
+// ?version= picks QTI 2.1 (the default) or 3.0 (Owner or Admin).
+func (h *QuizHandler) ExportBank(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	filename, body, err := h.svc.ExportBankQTI(r.Context(), id, r.URL.Query().Get("version"), claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// ImportQTI imports the QTI package or item file uploaded as the "file" form field into the
+// bank identified by the {id} URL parameter (Owner or Admin). Without one, a new bank is
+// created, named by the optional "name" form field and filed under the optional "course_id".
+// The response reports the items that were imported and those that were skipped.
+func (h *QuizHandler) ImportQTI(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	var bankID int64
+	if idStr := chi.URLParam(r, "id"); idStr != "" {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			utils.SendError(w, appErrors.ErrBadRequest)
+			return
+		}
+		bankID = id
+	}
+
+	file, header, err := readUploadedFile(w, r, h.cfg.MaxUploadSize)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+	defer file.Close()
+
+	req := models.QTIImportRequest{Name: r.FormValue("name")}
+	if courseStr := r.FormValue("course_id"); courseStr != "" {
+		courseID, err := strconv.ParseInt(courseStr, 10, 64)
+		if err != nil {
+			utils.SendError(w, appErrors.ErrBadRequest)
+			return
+		}
+		req.CourseID = &courseID
+	}
+
+	upload := service.UploadedFile{Filename: header.Filename, Content: file}
+	report, err := h.svc.ImportQTI(r.Context(), bankID, &req, upload, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if bankID == 0 && report.Bank != nil {
+		status = http.StatusCreated
+	}
+	utils.SendJSON(w, status, report)
+}
+
+// CreateQuiz sets a new quiz for the section identified by the {id} URL parameter
+// (Instructor or Admin).
+func (h *QuizHandler) CreateQuiz(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sectionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.CreateQuizRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	quiz, err := h.svc.CreateQuiz(r.Context(), sectionID, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, quiz)
+}
+
+// ListQuizzes lists the quizzes of the section identified by the {id} URL parameter by
+// opening time (Instructor or Admin).
+func (h *QuizHandler) ListQuizzes(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	sectionID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	quizzes, err := h.svc.ListQuizzes(r.Context(), sectionID, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, quizzes)
+}
+
+// GetQuiz returns the quiz identified by the {id} URL parameter.
+func (h *QuizHandler) GetQuiz(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	quiz, err := h.svc.GetQuiz(r.Context(), id, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, quiz)
+}
+
+// UpdateQuiz updates the quiz identified by the {id} URL parameter (Instructor or Admin).
+func (h *QuizHandler) UpdateQuiz(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.UpdateQuizRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	quiz, err := h.svc.UpdateQuiz(r.Context(), id, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, quiz)
+}
+
+// DeleteQuiz removes the quiz identified by the {id} URL parameter (Instructor or Admin).
+func (h *QuizHandler) DeleteQuiz(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteQuiz(r.Context(), id, claims.UserID, isAdminRequest(r)); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// GetQuizQuestions lists the questions of the quiz identified by the {id} URL parameter with
+// their answer keys (Instructor or Admin).
+func (h *QuizHandler) GetQuizQuestions(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	questions, err := h.svc.GetQuizQuestions(r.Context(), id, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, questions)
+}
+
+// SetQuizQuestions replaces the questions of the quiz identified by the {id} URL parameter
+// (Instructor or Admin).
+func (h *QuizHandler) SetQuizQuestions(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.SetQuizQuestionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	questions, err := h.svc.SetQuizQuestions(r.Context(), id, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, questions)
+}
+
+// ListAttempts lists every attempt at the quiz identified by the {id} URL parameter
+// (Instructor or Admin).
+func (h *QuizHandler) ListAttempts(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	attempts, err := h.svc.ListAttempts(r.Context(), id, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, attempts)
+}
+
+// GradingQueue lists the free-text answers to the quiz identified by the {id} URL parameter
+// that wait to be graded (Instructor or Admin).
+func (h *QuizHandler) GradingQueue(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	queue, err := h.svc.GradingQueue(r.Context(), id, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, queue)
+}
+
+// StartAttempt starts an attempt at the quiz identified by the {id} URL parameter for the
+// authenticated student, or returns the attempt they already have in progress.
+func (h *QuizHandler) StartAttempt(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	detail, started, err := h.svc.StartAttempt(r.Context(), id, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if started {
+		status = http.StatusCreated
+	}
+	utils.SendJSON(w, status, detail)
+}
+
+// GetAttempt returns the quiz attempt identified by the {id} URL parameter to its student,
+// the section's instructor or an admin.
+func (h *QuizHandler) GetAttempt(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	detail, err := h.svc.GetAttempt(r.Context(), id, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, detail)
+}
+
+// SaveAnswers stores answers of the authenticated student's attempt identified by the {id}
+// URL parameter.
+func (h *QuizHandler) SaveAnswers(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.SaveAnswersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	detail, err := h.svc.SaveAnswers(r.Context(), id, claims.UserID, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, detail)
+}
+
+// SubmitAttempt submits the authenticated student's attempt identified by the {id} URL
+// parameter. The body with final answers is optional.
+func (h *QuizHandler) SubmitAttempt(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.SaveAnswersRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.SendError(w, appErrors.ErrBadRequest)
+			return
+		}
+	}
+
+	detail, err := h.svc.SubmitAttempt(r.Context(), id, claims.UserID, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, detail)
+}
+
+// GradeResponse grades the answer to the question identified by the {questionId} URL
+// parameter in the attempt identified by {id} (Instructor or Admin).
+func (h *QuizHandler) GradeResponse(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	attemptID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	questionID, err := strconv.ParseInt(chi.URLParam(r, "questionId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.GradeResponseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	detail, err := h.svc.GradeResponse(r.Context(), attemptID, questionID, &req, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, detail)
+}
+
+// GetOwnQuizzes returns the authenticated student's quizzes in the ?term_id= term
+// (default: current) with their attempts.
+func (h *QuizHandler) GetOwnQuizzes(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	quizzes, err := h.svc.GetStudentQuizzes(r.Context(), claims.UserID, termID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, quizzes)
+}
+
+// GetStudentQuizzes returns the quizzes of the student identified by the {id} URL parameter.
+func (h *QuizHandler) GetStudentQuizzes(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	studentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	quizzes, err := h.svc.GetStudentQuizzes(r.Context(), studentID, termID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, quizzes)
+}
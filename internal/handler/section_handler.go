@@ -0,0 +1,212 @@
+// internal/handler/section_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SectionHandler handles HTTP requests for course sections, timetables and clash reports.
+type SectionHandler struct {
+	svc service.SectionService
+	cfg *config.Config
+}
+
+// NewSectionHandler creates a new SectionHandler.
+func NewSectionHandler(svc service.SectionService, cfg *config.Config) *SectionHandler {
+	return &SectionHandler{svc: svc, cfg: cfg}
+}
+
+// sectionIDParam parses the optional ?section_id= query parameter; zero means none.
+func sectionIDParam(r *http.Request) (int64, error) {
+	sectionStr := r.URL.Query().Get("section_id")
+	if sectionStr == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(sectionStr, 10, 64)
+}
+
+// ListSections lists the sections of a course in a term with their meetings.
+func (h *SectionHandler) ListSections(w http.ResponseWriter, r *http.Request) {
+	termID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	courseID, err := strconv.ParseInt(chi.URLParam(r, "courseId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	sections, err := h.svc.ListSections(r.Context(), termID, courseID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, sections)
+}
+
+// CreateSection adds a section to a course offering (Admin Only).
+func (h *SectionHandler) CreateSection(w http.ResponseWriter, r *http.Request) {
+	termID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	courseID, err := strconv.ParseInt(chi.URLParam(r, "courseId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.CreateSectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	section, err := h.svc.CreateSection(r.Context(), termID, courseID, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, section)
+}
+
+// GetSection returns a section with its meetings.
+func (h *SectionHandler) GetSection(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	section, err := h.svc.GetSection(r.Context(), id)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, section)
+}
+
+// UpdateSection changes a section's code, instructor or meetings (Admin Only).
+func (h *SectionHandler) UpdateSection(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.UpdateSectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	section, err := h.svc.UpdateSection(r.Context(), id, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, section)
+}
+
+// DeleteSection removes a section nobody is enrolled in (Admin Only).
+func (h *SectionHandler) DeleteSection(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteSection(r.Context(), id); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// GetOwnTimetable returns the authenticated student's or teacher's weekly timetable for the
+// ?term_id= term (default: current).
+func (h *SectionHandler) GetOwnTimetable(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	timetable, err := h.svc.GetTimetable(r.Context(), claims.UserID, termID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, timetable)
+}
+
+// GetTimetable returns the weekly timetable of the user identified by the {id} URL parameter.
+func (h *SectionHandler) GetTimetable(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	termID, err := termIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	timetable, err := h.svc.GetTimetable(r.Context(), userID, termID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, timetable)
+}
+
+// ListClashes reports double-booked rooms and instructors in a term, optionally only of the
+// ?type= room or instructor (Admin Only).
+func (h *SectionHandler) ListClashes(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	termID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	clashes, err := h.svc.ListClashes(r.Context(), termID, r.URL.Query().Get("type"))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, clashes)
+}
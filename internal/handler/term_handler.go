@@ -0,0 +1,189 @@
+// internal/handler/term_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TermHandler handles HTTP requests for the academic calendar.
+type TermHandler struct {
+	svc service.TermService
+	cfg *config.Config
+}
+
+// NewTermHandler creates a new TermHandler.
+func NewTermHandler(svc service.TermService, cfg *config.Config) *TermHandler {
+	return &TermHandler{svc: svc, cfg: cfg}
+}
+
+// termIDParam parses the optional ?term_id= query parameter; zero means the current term.
+func termIDParam(r *http.Request) (int64, error) {
+	termStr := r.URL.Query().Get("term_id")
+	if termStr == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(termStr, 10, 64)
+}
+
+// ListTerms lists the academic terms, latest first.
+func (h *TermHandler) ListTerms(w http.ResponseWriter, r *http.Request) {
+	query := utils.NewPaginationQuery(r)
+
+	terms, totalCount, err := h.svc.ListTerms(r.Context(), query.Limit, query.Offset)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	resp := utils.NewPaginationResponse(terms, query, totalCount)
+	utils.SendJSON(w, http.StatusOK, resp)
+}
+
+// GetCurrentTerm returns the term running today, with its holidays.
+func (h *TermHandler) GetCurrentTerm(w http.ResponseWriter, r *http.Request) {
+	term, err := h.svc.GetCurrentTerm(r.Context())
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, term)
+}
+
+// GetTerm returns a term with its holidays.
+func (h *TermHandler) GetTerm(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	term, err := h.svc.GetTerm(r.Context(), id)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, term)
+}
+
+// CreateTerm adds a term to the academic calendar (Admin Only).
+func (h *TermHandler) CreateTerm(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	var req models.CreateTermRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	term, err := h.svc.CreateTerm(r.Context(), &req, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, term)
+}
+
+// UpdateTerm changes a term's dates, deadlines or exam period (Admin Only).
+func (h *TermHandler) UpdateTerm(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.UpdateTermRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	term, err := h.svc.UpdateTerm(r.Context(), id, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, term)
+}
+
+// DeleteTerm removes a term that has no offerings or enrollments (Admin Only).
+func (h *TermHandler) DeleteTerm(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteTerm(r.Context(), id); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// AddHoliday adds a holiday to a term (Admin Only).
+func (h *TermHandler) AddHoliday(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	termID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.CreateHolidayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	holiday, err := h.svc.AddHoliday(r.Context(), termID, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, holiday)
+}
+
+// DeleteHoliday removes a holiday from a term (Admin Only).
+func (h *TermHandler) DeleteHoliday(w http.ResponseWriter, r *http.Request) {
+	termID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	holidayID, err := strconv.ParseInt(chi.URLParam(r, "holidayId"), 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteHoliday(r.Context(), termID, holidayID); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,260 @@
+// internal/handler/transcript_handler.go
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/middleware"
+	"student-portal/internal/models"
+	"student-portal/internal/service"
+	"student-portal/internal/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TranscriptHandler handles HTTP requests for grade-point scales and transcripts.
+type TranscriptHandler struct {
+	svc service.TranscriptService
+	cfg *config.Config
+}
+
+// NewTranscriptHandler creates a new TranscriptHandler.
+func NewTranscriptHandler(svc service.TranscriptService, cfg *config.Config) *TranscriptHandler {
+	return &TranscriptHandler{svc: svc, cfg: cfg}
+}
+
+// scaleIDParam reads the optional ?scale_id= query parameter; zero means the default scale.
+func scaleIDParam(r *http.Request) (int64, error) {
+	scaleStr := r.URL.Query().Get("scale_id")
+	if scaleStr == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(scaleStr, 10, 64)
+}
+
+// ListScales lists the grade-point scales.
+func (h *TranscriptHandler) ListScales(w http.ResponseWriter, r *http.Request) {
+	scales, err := h.svc.ListScales(r.Context())
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, scales)
+}
+
+// CreateScale adds a grade-point scale (Admin Only).
+func (h *TranscriptHandler) CreateScale(w http.ResponseWriter, r *http.Request) {
+	var req models.GradePointScaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	scale, err := h.svc.CreateScale(r.Context(), &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, scale)
+}
+
+// UpdateScale replaces the grade-point scale identified by the {id} URL parameter (Admin Only).
+func (h *TranscriptHandler) UpdateScale(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.GradePointScaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	scale, err := h.svc.UpdateScale(r.Context(), id, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, scale)
+}
+
+// DeleteScale removes the grade-point scale identified by the {id} URL parameter (Admin Only).
+func (h *TranscriptHandler) DeleteScale(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	if err := h.svc.DeleteScale(r.Context(), id); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// GetOwnTranscript returns the authenticated student's unofficial transcript on the
+// ?scale_id= scale (default: the default scale).
+func (h *TranscriptHandler) GetOwnTranscript(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	scaleID, err := scaleIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	transcript, err := h.svc.GetTranscript(r.Context(), claims.UserID, scaleID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, transcript)
+}
+
+// GetTranscript returns the unofficial transcript of the student identified by the {id} URL parameter.
+func (h *TranscriptHandler) GetTranscript(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	studentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+	scaleID, err := scaleIDParam(r)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	transcript, err := h.svc.GetTranscript(r.Context(), studentID, scaleID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, transcript)
+}
+
+// IssueTranscript issues an official PDF transcript for the student identified by the {id}
+// URL parameter (Admin Only).
+func (h *TranscriptHandler) IssueTranscript(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	studentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.IssueTranscriptRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.SendError(w, appErrors.ErrBadRequest)
+			return
+		}
+	}
+
+	issued, err := h.svc.IssueTranscript(r.Context(), studentID, &req, claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, issued)
+}
+
+// ListIssued lists the official transcripts issued for the student identified by the {id}
+// URL parameter, newest first.
+func (h *TranscriptHandler) ListIssued(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	studentID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	transcripts, err := h.svc.ListIssued(r.Context(), studentID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, transcripts)
+}
+
+// ListOwnIssued lists the official transcripts issued for the authenticated student.
+func (h *TranscriptHandler) ListOwnIssued(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	transcripts, err := h.svc.ListIssued(r.Context(), claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, transcripts)
+}
+
+// GetIssuedFileURL returns a signed download URL for the PDF of the issued transcript
+// identified by the {id} URL parameter.
+func (h *TranscriptHandler) GetIssuedFileURL(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	resp, err := h.svc.GetIssuedFileURL(r.Context(), id, claims.UserID, isAdminRequest(r))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, resp)
+}
+
+// Verify confirms the transcript identified by the {code} URL parameter. It is public, so it
+// only reveals who the transcript was issued to, when, and the checksum of the PDF.
+func (h *TranscriptHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	verification, err := h.svc.Verify(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	utils.SendJSON(w, http.StatusOK, verification)
+}
@@ -41,10 +41,12 @@ func (h *UserHandler) GetOwnProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	utils.SetETag(w, userResp.Version)
 	utils.SendJSON(w, http.StatusOK, userResp)
 }
 
 // UpdateOwnProfile updates the authenticated user's profile.
+// If-Match is optional here; when present the update is conditional on the version.
 func (h *UserHandler) UpdateOwnProfile(w http.ResponseWriter, r *http.Request) {
 	claims := middleware.GetUserClaims(r.Context())
 	if claims == nil {
@@ -52,18 +54,57 @@ func (h *UserHandler) UpdateOwnProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ifMatch, err := utils.ParseIfMatch(r)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
 	var req models.UpdateProfileRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.SendError(w, appErrors.ErrBadRequest)
 		return
 	}
 
-	userResp, err := h.svc.UpdateProfile(r.Context(), claims.UserID, &req)
+	userResp, err := h.svc.UpdateProfile(r.Context(), claims.UserID, &req, ifMatch)
 	if err != nil {
 		utils.SendError(w, err)
 		return
 	}
 
+	utils.SetETag(w, userResp.Version)
+	utils.SendJSON(w, http.StatusOK, userResp)
+}
+
+// PatchOwnProfile partially updates the authenticated user's profile.
+// Accepts application/merge-patch+json (RFC 7396) or application/json-patch+json (RFC 6902);
+// If-Match is optional, as for UpdateOwnProfile.
+func (h *UserHandler) PatchOwnProfile(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	ifMatch, err := utils.ParseIfMatch(r)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	patch, err := utils.ParsePatch(r)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	userResp, err := h.svc.PatchProfile(r.Context(), claims.UserID, patch, ifMatch)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SetETag(w, userResp.Version)
 	utils.SendJSON(w, http.StatusOK, userResp)
 }
 
@@ -96,10 +137,12 @@ func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	utils.SetETag(w, userResp.Version)
 	utils.SendJSON(w, http.StatusOK, userResp)
 }
 
 // UpdateUser updates a user by ID (Admin Only).
+// Requires If-Match with the ETag from GetUserByID so concurrent edits are not lost.
 func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -108,18 +151,57 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ifMatch, err := utils.RequireIfMatch(r)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
 	var req models.UpdateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.SendError(w, appErrors.ErrBadRequest)
 		return
 	}
 
-	userResp, err := h.svc.UpdateUser(r.Context(), id, &req)
+	userResp, err := h.svc.UpdateUser(r.Context(), id, &req, ifMatch)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SetETag(w, userResp.Version)
+	utils.SendJSON(w, http.StatusOK, userResp)
+}
+
+// PatchUser partially updates a user by ID (Admin Only).
+// Accepts the same patch formats as PatchOwnProfile and, like UpdateUser, requires If-Match.
+func (h *UserHandler) PatchUser(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	ifMatch, err := utils.RequireIfMatch(r)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	patch, err := utils.ParsePatch(r)
 	if err != nil {
 		utils.SendError(w, err)
 		return
 	}
 
+	userResp, err := h.svc.PatchUser(r.Context(), id, patch, ifMatch)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SetETag(w, userResp.Version)
 	utils.SendJSON(w, http.StatusOK, userResp)
 }
 
@@ -139,3 +221,149 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 
 	utils.SendJSON(w, http.StatusNoContent, nil)
 }
+
+// ListProfileFields lists the admin-defined custom profile fields.
+func (h *UserHandler) ListProfileFields(w http.ResponseWriter, r *http.Request) {
+	defs, err := h.svc.ListProfileFields(r.Context())
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, defs)
+}
+
+// CreateProfileField defines a new custom profile field (Admin Only).
+func (h *UserHandler) CreateProfileField(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateProfileFieldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	def, err := h.svc.CreateProfileField(r.Context(), &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, def)
+}
+
+// DeleteProfileField removes a custom profile field definition by key (Admin Only).
+func (h *UserHandler) DeleteProfileField(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+
+	if err := h.svc.DeleteProfileField(r.Context(), key); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// UploadAvatar replaces the authenticated user's avatar (multipart form field "file").
+func (h *UserHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	file, header, err := readUploadedFile(w, r, h.cfg.AvatarMaxSize)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+	defer file.Close()
+
+	resp, err := h.svc.UploadAvatar(r.Context(), claims.UserID, header.Filename, file)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusCreated, resp)
+}
+
+// GetAvatar returns a signed download URL for the authenticated user's avatar.
+func (h *UserHandler) GetAvatar(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	resp, err := h.svc.GetAvatarURL(r.Context(), claims.UserID)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, resp)
+}
+
+// DeleteAvatar removes the authenticated user's avatar.
+func (h *UserHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	if err := h.svc.DeleteAvatar(r.Context(), claims.UserID); err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusNoContent, nil)
+}
+
+// ChangeStatus moves a user to another account status with a reason (Admin Only).
+func (h *UserHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r.Context())
+	if claims == nil {
+		utils.SendError(w, appErrors.ErrUnauthorized)
+		return
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	var req models.ChangeStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	userResp, err := h.svc.ChangeStatus(r.Context(), id, claims.UserID, &req)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SetETag(w, userResp.Version)
+	utils.SendJSON(w, http.StatusOK, userResp)
+}
+
+// ListStatusChanges lists a user's account status transitions, newest first (Admin Only).
+func (h *UserHandler) ListStatusChanges(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		utils.SendError(w, appErrors.ErrBadRequest)
+		return
+	}
+
+	changes, err := h.svc.ListStatusChanges(r.Context(), id)
+	if err != nil {
+		utils.SendError(w, err)
+		return
+	}
+
+	utils.SendJSON(w, http.StatusOK, changes)
+}
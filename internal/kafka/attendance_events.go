@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"student-portal/internal/commons/constants"
+)
+
+// AttendanceEvent represents attendance alerts
+type AttendanceEvent struct {
+	EventType string    `json:"event_type"`
+	StudentID int64     `json:"student_id"`
+	CourseID  int64     `json:"course_id"`
+	TermID    int64     `json:"term_id"`
+	SectionID int64     `json:"section_id"`
+	Rate      float64   `json:"rate"`      // Percentage of sessions attended
+	Threshold float64   `json:"threshold"` // Configured minimum percentage
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// PublishAttendanceBelowThresholdEvent publishes a student's attendance rate in a section
+// dropping below the configured threshold to Kafka. Events are keyed by the student ID.
+func (p *KafkaProducer) PublishAttendanceBelowThresholdEvent(ctx context.Context, studentID, courseID, termID, sectionID int64, rate, threshold float64) error {
+	event := AttendanceEvent{
+		EventType: "attendance_below_threshold",
+		StudentID: studentID,
+		CourseID:  courseID,
+		TermID:    termID,
+		SectionID: sectionID,
+		Rate:      rate,
+		Threshold: threshold,
+		Timestamp: time.Now(),
+	}
+	return p.PublishMessage(ctx, constants.TopicAttendanceEvents, strconv.FormatInt(studentID, 10), event)
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"student-portal/internal/commons/constants"
+)
+
+// CourseEvent represents course catalog lifecycle events
+type CourseEvent struct {
+	EventType   string    `json:"event_type"`
+	CourseID    int64     `json:"course_id"`
+	Code        string    `json:"code"`
+	Title       string    `json:"title"`
+	Credits     float64   `json:"credits"`
+	IsActive    bool      `json:"is_active"`
+	PerformedBy int64     `json:"performed_by,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// publishCourseEvent publishes a course event keyed by the course ID, so that the events
+// of one course stay in order
+func (p *KafkaProducer) publishCourseEvent(ctx context.Context, event CourseEvent) error {
+	event.Timestamp = time.Now()
+	return p.PublishMessage(ctx, constants.TopicCourseEvents, strconv.FormatInt(event.CourseID, 10), event)
+}
+
+// PublishCourseCreatedEvent publishes a course creation to Kafka
+func (p *KafkaProducer) PublishCourseCreatedEvent(ctx context.Context, courseID int64, code, title string, credits float64, isActive bool, performedBy int64) error {
+	return p.publishCourseEvent(ctx, CourseEvent{
+		EventType: "course_created", CourseID: courseID, Code: code, Title: title, Credits: credits, IsActive: isActive, PerformedBy: performedBy,
+	})
+}
+
+// PublishCourseUpdatedEvent publishes a course update to Kafka
+func (p *KafkaProducer) PublishCourseUpdatedEvent(ctx context.Context, courseID int64, code, title string, credits float64, isActive bool, performedBy int64) error {
+	return p.publishCourseEvent(ctx, CourseEvent{
+		EventType: "course_updated", CourseID: courseID, Code: code, Title: title, Credits: credits, IsActive: isActive, PerformedBy: performedBy,
+	})
+}
+
+// PublishCourseDeletedEvent publishes a course deletion to Kafka
+func (p *KafkaProducer) PublishCourseDeletedEvent(ctx context.Context, courseID int64, code string, performedBy int64) error {
+	return p.publishCourseEvent(ctx, CourseEvent{
+		EventType: "course_deleted", CourseID: courseID, Code: code, PerformedBy: performedBy,
+	})
+}
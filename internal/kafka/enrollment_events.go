@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"student-portal/internal/commons/constants"
+)
+
+// EnrollmentEvent represents enrollment and waitlist events
+type EnrollmentEvent struct {
+	EventType   string    `json:"event_type"`
+	CourseID    int64     `json:"course_id"`
+	TermID      int64     `json:"term_id"`
+	StudentID   int64     `json:"student_id"`
+	Status      string    `json:"status"`
+	PerformedBy int64     `json:"performed_by,omitempty"` // Zero for automatic promotions
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// publishEnrollmentEvent publishes an enrollment event keyed by the course ID, so that the
+// events of one course's roster stay in order
+func (p *KafkaProducer) publishEnrollmentEvent(ctx context.Context, event EnrollmentEvent) error {
+	event.Timestamp = time.Now()
+	return p.PublishMessage(ctx, constants.TopicCourseEnrollments, strconv.FormatInt(event.CourseID, 10), event)
+}
+
+// PublishCourseEnrolledEvent publishes a new enrollment to Kafka. The status tells whether
+// the student got a seat or joined the waitlist.
+func (p *KafkaProducer) PublishCourseEnrolledEvent(ctx context.Context, courseID, termID, studentID int64, status string, performedBy int64) error {
+	return p.publishEnrollmentEvent(ctx, EnrollmentEvent{
+		EventType: "course_enrolled", CourseID: courseID, TermID: termID, StudentID: studentID, Status: status, PerformedBy: performedBy,
+	})
+}
+
+// PublishCourseDroppedEvent publishes a student leaving a course or its waitlist to Kafka
+func (p *KafkaProducer) PublishCourseDroppedEvent(ctx context.Context, courseID, termID, studentID, performedBy int64) error {
+	return p.publishEnrollmentEvent(ctx, EnrollmentEvent{
+		EventType: "course_dropped", CourseID: courseID, TermID: termID, StudentID: studentID, Status: "dropped", PerformedBy: performedBy,
+	})
+}
+
+// PublishWaitlistPromotedEvent publishes a student being moved from the waitlist into a freed seat to Kafka
+func (p *KafkaProducer) PublishWaitlistPromotedEvent(ctx context.Context, courseID, termID, studentID int64) error {
+	return p.publishEnrollmentEvent(ctx, EnrollmentEvent{
+		EventType: "waitlist_promoted", CourseID: courseID, TermID: termID, StudentID: studentID, Status: "enrolled",
+	})
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"student-portal/internal/commons/constants"
+)
+
+// GradeEvent represents a grade becoming visible to a student
+type GradeEvent struct {
+	EventType    string    `json:"event_type"`
+	StudentID    int64     `json:"student_id"`
+	CourseID     int64     `json:"course_id"`
+	TermID       int64     `json:"term_id"`
+	SectionID    int64     `json:"section_id"`
+	AssignmentID *int64    `json:"assignment_id,omitempty"` // Set for assignment grades
+	Grade        string    `json:"grade"`                   // Points for assignments, the letter for courses
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// PublishGradePostedEvent publishes an assignment grade being released, or a final course
+// grade being posted when assignmentID is nil, to Kafka. Events are keyed by the student ID.
+func (p *KafkaProducer) PublishGradePostedEvent(ctx context.Context, studentID, courseID, termID, sectionID int64, assignmentID *int64, grade string) error {
+	event := GradeEvent{
+		EventType:    "grade_posted",
+		StudentID:    studentID,
+		CourseID:     courseID,
+		TermID:       termID,
+		SectionID:    sectionID,
+		AssignmentID: assignmentID,
+		Grade:        grade,
+		Timestamp:    time.Now(),
+	}
+	return p.PublishMessage(ctx, constants.TopicGradeEvents, strconv.FormatInt(studentID, 10), event)
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"student-portal/internal/commons/constants"
+)
+
+// GuardianEvent represents guardian invitation and student–guardian link events
+type GuardianEvent struct {
+	EventType    string    `json:"event_type"`
+	StudentID    int64     `json:"student_id"`
+	GuardianID   int64     `json:"guardian_id,omitempty"`
+	LinkID       int64     `json:"link_id,omitempty"`
+	InvitationID int64     `json:"invitation_id,omitempty"`
+	Relationship string    `json:"relationship,omitempty"`
+	Email        string    `json:"email,omitempty"` // Invited address
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// PublishGuardianInvitedEvent publishes a guardian invitation to Kafka. The accept link is
+// emailed directly and never published.
+func (p *KafkaProducer) PublishGuardianInvitedEvent(ctx context.Context, studentID, invitationID int64, email string) error {
+	event := GuardianEvent{
+		EventType:    "guardian_invited",
+		StudentID:    studentID,
+		InvitationID: invitationID,
+		Email:        email,
+		Timestamp:    time.Now(),
+	}
+
+	return p.PublishMessage(ctx, constants.TopicUserEvents, strconv.FormatInt(studentID, 10), event)
+}
+
+// PublishGuardianLinkedEvent publishes a new student–guardian link to Kafka
+func (p *KafkaProducer) PublishGuardianLinkedEvent(ctx context.Context, studentID, guardianID, linkID int64, relationship string) error {
+	event := GuardianEvent{
+		EventType:    "guardian_linked",
+		StudentID:    studentID,
+		GuardianID:   guardianID,
+		LinkID:       linkID,
+		Relationship: relationship,
+		Timestamp:    time.Now(),
+	}
+
+	return p.PublishMessage(ctx, constants.TopicUserEvents, strconv.FormatInt(studentID, 10), event)
+}
+
+// PublishGuardianUnlinkedEvent publishes a removed student–guardian link to Kafka
+func (p *KafkaProducer) PublishGuardianUnlinkedEvent(ctx context.Context, studentID, guardianID, linkID int64) error {
+	event := GuardianEvent{
+		EventType:  "guardian_unlinked",
+		StudentID:  studentID,
+		GuardianID: guardianID,
+		LinkID:     linkID,
+		Timestamp:  time.Now(),
+	}
+
+	return p.PublishMessage(ctx, constants.TopicUserEvents, strconv.FormatInt(studentID, 10), event)
+}
@@ -32,7 +32,32 @@ func CreateTopics(brokers []string) error {
 			NumPartitions:     1, // Start with 1, scale up later
 			ReplicationFactor: 1, // 1 for local docker setup
 		},
-		// Add other topics here (e.g., constants.TopicCourseEnrollments)
+		{
+			Topic:             constants.TopicPrivacyEvents,
+			NumPartitions:     1,
+			ReplicationFactor: 1,
+		},
+		{
+			Topic:             constants.TopicCourseEvents,
+			NumPartitions:     1,
+			ReplicationFactor: 1,
+		},
+		{
+			Topic:             constants.TopicCourseEnrollments,
+			NumPartitions:     1,
+			ReplicationFactor: 1,
+		},
+		{
+			Topic:             constants.TopicAttendanceEvents,
+			NumPartitions:     1,
+			ReplicationFactor: 1,
+		},
+		{
+			Topic:             constants.TopicGradeEvents,
+			NumPartitions:     1,
+			ReplicationFactor: 1,
+		},
+		// Add other topics here
 	}
 
 	// 3. Create the topics
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"student-portal/internal/commons/constants"
+)
+
+// UserMergedEvent represents a duplicate account being merged into a surviving account
+type UserMergedEvent struct {
+	EventType    string    `json:"event_type"`
+	SourceUserID int64     `json:"source_user_id"` // The deleted account
+	TargetUserID int64     `json:"target_user_id"` // The surviving account
+	PerformedBy  int64     `json:"performed_by"`
+	MergeID      int64     `json:"merge_id"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// PublishUserMergedEvent publishes a user merge event to Kafka
+func (p *KafkaProducer) PublishUserMergedEvent(ctx context.Context, sourceUserID, targetUserID, performedBy, mergeID int64) error {
+	event := UserMergedEvent{
+		EventType:    "user_merged",
+		SourceUserID: sourceUserID,
+		TargetUserID: targetUserID,
+		PerformedBy:  performedBy,
+		MergeID:      mergeID,
+		Timestamp:    time.Now(),
+	}
+
+	return p.PublishMessage(ctx, constants.TopicUserEvents, strconv.FormatInt(targetUserID, 10), event)
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"student-portal/internal/commons/constants"
+)
+
+// PrivacyEvent represents personal data export and erasure events
+type PrivacyEvent struct {
+	EventType   string    `json:"event_type"`
+	UserID      int64     `json:"user_id"`
+	RequestedBy int64     `json:"requested_by"`
+	JobID       int64     `json:"job_id,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// PublishDataExportedEvent publishes a completed personal data export to Kafka
+func (p *KafkaProducer) PublishDataExportedEvent(ctx context.Context, userID, requestedBy, jobID int64) error {
+	event := PrivacyEvent{
+		EventType:   "user_data_exported",
+		UserID:      userID,
+		RequestedBy: requestedBy,
+		JobID:       jobID,
+		Timestamp:   time.Now(),
+	}
+
+	return p.PublishMessage(ctx, constants.TopicPrivacyEvents, strconv.FormatInt(userID, 10), event)
+}
+
+// PublishUserErasedEvent publishes a personal data erasure to Kafka
+func (p *KafkaProducer) PublishUserErasedEvent(ctx context.Context, userID, performedBy int64, reason string) error {
+	event := PrivacyEvent{
+		EventType:   "user_erased",
+		UserID:      userID,
+		RequestedBy: performedBy,
+		Reason:      reason,
+		Timestamp:   time.Now(),
+	}
+
+	return p.PublishMessage(ctx, constants.TopicPrivacyEvents, strconv.FormatInt(userID, 10), event)
+}
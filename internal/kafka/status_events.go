@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"student-portal/internal/commons/constants"
+)
+
+// StatusChangedEvent represents an account moving between lifecycle statuses
+type StatusChangedEvent struct {
+	EventType  string    `json:"event_type"`
+	UserID     int64     `json:"user_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	ChangedBy  int64     `json:"changed_by"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// PublishStatusChangedEvent publishes an account status transition to Kafka
+func (p *KafkaProducer) PublishStatusChangedEvent(ctx context.Context, userID int64, fromStatus, toStatus, reason string, changedBy int64) error {
+	event := StatusChangedEvent{
+		EventType:  "user_status_changed",
+		UserID:     userID,
+		FromStatus: fromStatus,
+		ToStatus:   toStatus,
+		Reason:     reason,
+		ChangedBy:  changedBy,
+		Timestamp:  time.Now(),
+	}
+
+	return p.PublishMessage(ctx, constants.TopicUserEvents, strconv.FormatInt(userID, 10), event)
+}
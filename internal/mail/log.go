@@ -0,0 +1,26 @@
+// internal/mail/log.go
+package mail
+
+import (
+	"context"
+
+	"student-portal/internal/commons/logger"
+
+	"go.uber.org/zap"
+)
+
+type logSender struct{}
+
+// NewLogSender creates a Sender for development that logs the recipient and subject of each
+// message instead of delivering it. The body is not logged, as it may carry secrets such as
+// invitation links.
+func NewLogSender() Sender {
+	return logSender{}
+}
+
+func (logSender) Send(ctx context.Context, msg Message) error {
+	logger.Logger.Info("Email not sent, SMTP is not configured",
+		zap.String("to", msg.To), zap.String("subject", msg.Subject),
+	)
+	return nil
+}
@@ -0,0 +1,36 @@
+// internal/mail/mail.go
+package mail
+
+import (
+	"context"
+
+	"student-portal/internal/config"
+)
+
+// Message is a plain-text email to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender is a pluggable email transport.
+type Sender interface {
+	// Send delivers the message, returning once the transport has accepted it.
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewSender creates the sender selected by the configuration: SMTP when SMTP_HOST is set,
+// otherwise a sender that only logs the recipient and subject of each message.
+func NewSender(cfg *config.Config) Sender {
+	if cfg.SMTPHost == "" {
+		return NewLogSender()
+	}
+	return NewSMTPSender(SMTPOptions{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.MailFrom,
+	})
+}
@@ -0,0 +1,71 @@
+// internal/mail/smtp.go
+package mail
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPOptions configures the SMTP sender.
+type SMTPOptions struct {
+	Host     string
+	Port     string
+	Username string // Leave empty for servers that accept mail without authentication
+	Password string
+	From     string // Address, optionally with a display name: "Name <address>"
+}
+
+type smtpSender struct {
+	opts SMTPOptions
+}
+
+// NewSMTPSender creates a Sender that delivers through an SMTP server. The connection is
+// upgraded with STARTTLS when the server offers it.
+func NewSMTPSender(opts SMTPOptions) Sender {
+	return &smtpSender{opts: opts}
+}
+
+func (s *smtpSender) Send(ctx context.Context, msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return fmt.Errorf("mail: header value contains a line break")
+	}
+
+	from, err := netmail.ParseAddress(s.opts.From)
+	if err != nil {
+		return fmt.Errorf("mail: invalid sender address: %w", err)
+	}
+
+	var auth smtp.Auth
+	if s.opts.Username != "" {
+		auth = smtp.PlainAuth("", s.opts.Username, s.opts.Password, s.opts.Host)
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", from.String())
+	fmt.Fprintf(&body, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&body, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&body, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	body.WriteString("\r\n")
+	body.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	// net/smtp has no context support; run the delivery so that a cancelled request
+	// returns promptly.
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(s.opts.Host, s.opts.Port), auth, from.Address, []string{msg.To}, []byte(body.String()))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -14,8 +14,16 @@ import (
 	"student-portal/internal/utils"
 )
 
-// AuthMiddleware validates the JWT token and sets user claims in the context.
-func AuthMiddleware(cfg *config.Config) func(next http.Handler) http.Handler {
+// AccountStatusChecker reports whether a user's account may still use the API.
+// It is implemented by service.UserService.
+type AccountStatusChecker interface {
+	CheckAccountActive(ctx context.Context, userID int64) error
+}
+
+// AuthMiddleware validates the JWT token and sets user claims in the context. The account's
+// status is checked on every request, so tokens issued before an account was suspended,
+// graduated or archived stop working right away.
+func AuthMiddleware(cfg *config.Config, checker AccountStatusChecker) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -37,6 +45,14 @@ func AuthMiddleware(cfg *config.Config) func(next http.Handler) http.Handler {
 				return
 			}
 
+			if err := checker.CheckAccountActive(r.Context(), claims.UserID); err != nil {
+				if err == appErrors.ErrNotFound {
+					err = appErrors.ErrUnauthorized
+				}
+				handleError(w, err)
+				return
+			}
+
 			// Store claims in context
 			ctx := context.WithValue(r.Context(), constants.UserClaimsKey, claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
@@ -44,6 +60,22 @@ func AuthMiddleware(cfg *config.Config) func(next http.Handler) http.Handler {
 	}
 }
 
+// OptionalAuthMiddleware sets user claims in the context when a valid JWT token is sent.
+// Requests without an Authorization header pass through anonymously; an invalid token is still rejected.
+func OptionalAuthMiddleware(cfg *config.Config, checker AccountStatusChecker) func(next http.Handler) http.Handler {
+	required := AuthMiddleware(cfg, checker)
+	return func(next http.Handler) http.Handler {
+		withAuth := required(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			withAuth.ServeHTTP(w, r)
+		})
+	}
+}
+
 // RoleMiddleware checks if the authenticated user has one of the required roles.
 func RoleMiddleware(requiredRoles ...string) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -84,6 +116,13 @@ func GetUserClaims(ctx context.Context) *utils.UserClaims {
 	return claims
 }
 
+// OptionalUserClaims retrieves the UserClaims from the request context, or nil for anonymous
+// requests on routes behind OptionalAuthMiddleware.
+func OptionalUserClaims(ctx context.Context) *utils.UserClaims {
+	claims, _ := ctx.Value(constants.UserClaimsKey).(*utils.UserClaims)
+	return claims
+}
+
 func handleError(w http.ResponseWriter, err error) {
 	appErr, ok := err.(*appErrors.AppError)
 	if !ok {
@@ -7,6 +7,7 @@ import (
 
 	"student-portal/internal/commons/logger"
 
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
 	"go.uber.org/zap"
 )
 
@@ -38,6 +39,7 @@ func RequestLogger(next http.Handler) http.Handler {
 			zap.Int("status", recorder.status),
 			zap.Duration("duration", time.Since(start)),
 			zap.String("remote_addr", r.RemoteAddr),
+			zap.String("request_id", chiMiddleware.GetReqID(r.Context())),
 		)
 	})
 }
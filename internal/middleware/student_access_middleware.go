@@ -0,0 +1,61 @@
+// internal/middleware/student_access_middleware.go
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GuardianAccessChecker reports whether a guardian may see a student's data.
+// It is implemented by service.GuardianService.
+type GuardianAccessChecker interface {
+	CheckAccess(ctx context.Context, guardianID, studentID int64, academic bool) (*models.GuardianLink, error)
+}
+
+// StudentAccessMiddleware guards routes for the student identified by the given URL parameter.
+// Admins and the student themself pass. Guardians pass for read-only requests if they are
+// linked to the student and the student consented to share profile (or, when academic is
+// set, academic) information. Everyone else is rejected.
+func StudentAccessMiddleware(checker GuardianAccessChecker, param string, academic bool) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := GetUserClaims(r.Context())
+			if claims == nil {
+				handleError(w, appErrors.ErrUnauthorized)
+				return
+			}
+
+			studentID, err := strconv.ParseInt(chi.URLParam(r, param), 10, 64)
+			if err != nil {
+				handleError(w, appErrors.ErrBadRequest)
+				return
+			}
+
+			switch {
+			case claims.Role == string(enums.RoleAdmin), claims.UserID == studentID:
+				// Full access
+			case claims.Role == string(enums.RoleGuardian):
+				if r.Method != http.MethodGet && r.Method != http.MethodHead {
+					handleError(w, appErrors.New(http.StatusForbidden, "Guardians have read-only access"))
+					return
+				}
+				if _, err := checker.CheckAccess(r.Context(), claims.UserID, studentID, academic); err != nil {
+					handleError(w, err)
+					return
+				}
+			default:
+				handleError(w, appErrors.ErrForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
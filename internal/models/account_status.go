@@ -0,0 +1,23 @@
+// internal/models/account_status.go
+package models
+
+import (
+	"time"
+)
+
+// ChangeStatusRequest is the structure for the admin change account status request body.
+type ChangeStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending active suspended graduated archived"`
+	Reason string `json:"reason" validate:"required"`
+}
+
+// UserStatusChange represents the structure of the user_status_changes table in the database.
+type UserStatusChange struct {
+	ID         int64     `json:"id"`
+	UserID     int64     `json:"user_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	ChangedBy  *int64    `json:"changed_by"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
@@ -0,0 +1,102 @@
+// internal/models/assignment.go
+package models
+
+import (
+	"time"
+)
+
+// Assignment represents the structure of the assignments table in the database: coursework
+// set for a section.
+type Assignment struct {
+	ID                 int64      `json:"id"`
+	SectionID          int64      `json:"section_id"`
+	SectionCode        string     `json:"section_code"`
+	CourseID           int64      `json:"course_id"`
+	CourseCode         string     `json:"course_code"`
+	TermID             int64      `json:"term_id"`
+	Title              string     `json:"title"`
+	Instructions       *string    `json:"instructions"`
+	DueAt              time.Time  `json:"due_at"`
+	LatePolicy         string     `json:"late_policy"`          // See enums.LatePolicy
+	LatePenaltyPercent float64    `json:"late_penalty_percent"` // Per started day late, with the 'penalty' policy
+	LateUntil          *time.Time `json:"late_until"`           // No late work is accepted after this
+	MaxPoints          float64    `json:"max_points"`
+	CategoryID         *int64     `json:"category_id"`  // Grade category of the section
+	ExtraCredit        bool       `json:"extra_credit"` // Points add to the category without adding to what can be earned
+	RubricID           *int64     `json:"rubric_id"`    // Rubric the assignment is graded against
+	CreatedBy          *int64     `json:"created_by"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// CreateAssignmentRequest is the structure for the create assignment request body.
+type CreateAssignmentRequest struct {
+	Title              string     `json:"title" validate:"required"`
+	Instructions       string     `json:"instructions"`
+	DueAt              time.Time  `json:"due_at" validate:"required"`
+	LatePolicy         string     `json:"late_policy"` // Defaults to 'accept'
+	LatePenaltyPercent float64    `json:"late_penalty_percent"`
+	LateUntil          *time.Time `json:"late_until"`
+	MaxPoints          float64    `json:"max_points" validate:"required"`
+	CategoryID         *int64     `json:"category_id"`
+	ExtraCredit        bool       `json:"extra_credit"`
+}
+
+// UpdateAssignmentRequest is the structure for the update assignment request body.
+// Omitted fields are left unchanged.
+type UpdateAssignmentRequest struct {
+	Title              *string    `json:"title"`
+	Instructions       *string    `json:"instructions"`
+	DueAt              *time.Time `json:"due_at"`
+	LatePolicy         *string    `json:"late_policy"`
+	LatePenaltyPercent *float64   `json:"late_penalty_percent"`
+	LateUntil          *time.Time `json:"late_until"`
+	ClearLateUntil     bool       `json:"clear_late_until"`
+	MaxPoints          *float64   `json:"max_points"`
+	CategoryID         *int64     `json:"category_id"`
+	ClearCategory      bool       `json:"clear_category"`
+	ExtraCredit        *bool      `json:"extra_credit"`
+}
+
+// Submission represents the structure of the assignment_submissions table in the database:
+// one version of a student's work with its attached files.
+type Submission struct {
+	ID           int64     `json:"id"`
+	AssignmentID int64     `json:"assignment_id"`
+	StudentID    int64     `json:"student_id"`
+	StudentName  string    `json:"student_name"`
+	Version      int       `json:"version"`
+	Comment      *string   `json:"comment"`
+	SubmittedAt  time.Time `json:"submitted_at"` // Set by the server
+	Late         bool      `json:"late"`
+	Files        []File    `json:"files"`
+}
+
+// SubmissionStatus is a student of the section with their latest submission, if any.
+type SubmissionStatus struct {
+	StudentID   int64       `json:"student_id"`
+	StudentName string      `json:"student_name"`
+	Versions    int         `json:"versions"`
+	Latest      *Submission `json:"latest"`
+}
+
+// AssignmentSubmissions lists the submissions of an assignment for its teacher.
+type AssignmentSubmissions struct {
+	Assignment Assignment         `json:"assignment"`
+	Submitted  int                `json:"submitted"`
+	Late       int                `json:"late"`
+	Students   []SubmissionStatus `json:"students"`
+}
+
+// StudentAssignment is an assignment as seen by a student of its section.
+type StudentAssignment struct {
+	Assignment
+	Versions int         `json:"versions"`
+	Latest   *Submission `json:"latest"`
+}
+
+// StudentAssignments lists a student's assignments in a term.
+type StudentAssignments struct {
+	Term        *Term               `json:"term"`
+	Assignments []StudentAssignment `json:"assignments"`
+}
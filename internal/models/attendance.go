@@ -0,0 +1,107 @@
+// internal/models/attendance.go
+package models
+
+import (
+	"time"
+)
+
+// ClassSession represents the structure of the class_sessions table in the database: one
+// occurrence of a section's weekly meeting. Times are formatted as HH:MM.
+type ClassSession struct {
+	ID        int64            `json:"id"`
+	SectionID int64            `json:"section_id"`
+	MeetingID *int64           `json:"meeting_id"`
+	Date      time.Time        `json:"date"`
+	StartTime string           `json:"start_time"`
+	EndTime   string           `json:"end_time"`
+	Room      *string          `json:"room"`
+	Counts    AttendanceCounts `json:"counts"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// AttendanceCounts tallies attendance records by status.
+type AttendanceCounts struct {
+	Present int `json:"present"`
+	Absent  int `json:"absent"`
+	Late    int `json:"late"`
+	Excused int `json:"excused"`
+}
+
+// AttendanceRecord represents the structure of the attendance_records table in the database.
+type AttendanceRecord struct {
+	ID        int64     `json:"id"`
+	SessionID int64     `json:"session_id"`
+	StudentID int64     `json:"student_id"`
+	Status    string    `json:"status"` // See enums.AttendanceStatus
+	Note      *string   `json:"note"`
+	MarkedBy  *int64    `json:"marked_by"`
+	MarkedAt  time.Time `json:"marked_at"`
+}
+
+// AttendanceRosterEntry is a student on the roster of a class session. Status is nil until
+// the student has been marked.
+type AttendanceRosterEntry struct {
+	StudentID   int64      `json:"student_id"`
+	StudentName string     `json:"student_name"`
+	Status      *string    `json:"status"`
+	Note        *string    `json:"note"`
+	MarkedBy    *int64     `json:"marked_by"`
+	MarkedAt    *time.Time `json:"marked_at"`
+}
+
+// SessionAttendance is a class session with its roster.
+type SessionAttendance struct {
+	Session ClassSession            `json:"session"`
+	Roster  []AttendanceRosterEntry `json:"roster"`
+}
+
+// AttendanceMark sets the status of one student in the bulk attendance request body.
+type AttendanceMark struct {
+	StudentID int64  `json:"student_id" validate:"required"`
+	Status    string `json:"status" validate:"required,oneof=present absent late excused"`
+	Note      string `json:"note"`
+}
+
+// MarkAttendanceRequest is the structure for the bulk attendance request body. Unmarked
+// students on the roster who are not listed get MarkRemaining, if given.
+type MarkAttendanceRequest struct {
+	Records       []AttendanceMark `json:"records"`
+	MarkRemaining *string          `json:"mark_remaining" validate:"omitempty,oneof=present absent late excused"`
+}
+
+// AttendanceSummary is a student's attendance in one section. Rate is the percentage of
+// sessions attended (present or late), leaving out excused ones; it is nil until a countable
+// session has been marked.
+type AttendanceSummary struct {
+	StudentID      int64            `json:"student_id"`
+	StudentName    string           `json:"student_name"`
+	CourseID       int64            `json:"course_id"`
+	CourseCode     string           `json:"course_code"`
+	TermID         int64            `json:"term_id"`
+	SectionID      int64            `json:"section_id"`
+	SectionCode    string           `json:"section_code"`
+	Counts         AttendanceCounts `json:"counts"`
+	Rate           *float64         `json:"rate"`
+	BelowThreshold bool             `json:"below_threshold"`
+}
+
+// StudentAttendanceEntry is one marked class session on a student's attendance record.
+type StudentAttendanceEntry struct {
+	SessionID   int64     `json:"session_id"`
+	CourseID    int64     `json:"course_id"`
+	CourseCode  string    `json:"course_code"`
+	SectionCode string    `json:"section_code"`
+	Date        time.Time `json:"date"`
+	StartTime   string    `json:"start_time"`
+	EndTime     string    `json:"end_time"`
+	Status      string    `json:"status"`
+	Note        *string   `json:"note"`
+}
+
+// StudentAttendance is a student's attendance in a term: a summary per section and every
+// marked session.
+type StudentAttendance struct {
+	Term      *Term                    `json:"term"`
+	Summaries []AttendanceSummary      `json:"summaries"`
+	Records   []StudentAttendanceEntry `json:"records"`
+}
@@ -0,0 +1,23 @@
+// internal/models/calendar.go
+package models
+
+import (
+	"time"
+)
+
+// CalendarFeed represents the structure of the calendar_feeds table in the database.
+type CalendarFeed struct {
+	UserID         int64      `json:"user_id"`
+	TokenHash      string     `json:"-"`
+	CreatedBy      *int64     `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at"`
+}
+
+// CalendarFeedResponse is returned when a feed token is generated. The token and the
+// subscription URL are only ever shown in this response.
+type CalendarFeedResponse struct {
+	CalendarFeed
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
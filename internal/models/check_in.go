@@ -0,0 +1,58 @@
+// internal/models/check_in.go
+package models
+
+import (
+	"time"
+)
+
+// CheckInWindow represents the structure of the check_in_windows table in the database.
+type CheckInWindow struct {
+	ID              int64     `json:"id"`
+	SessionID       int64     `json:"session_id"`
+	SectionID       int64     `json:"section_id"`
+	OpenedBy        *int64    `json:"opened_by"`
+	OpenedAt        time.Time `json:"opened_at"`
+	ClosesAt        time.Time `json:"closes_at"`
+	AllowedNetworks []string  `json:"allowed_networks"` // CIDR ranges; empty allows any address
+	Latitude        *float64  `json:"latitude"`
+	Longitude       *float64  `json:"longitude"`
+	RadiusMeters    *int      `json:"radius_meters"`
+}
+
+// CheckInAttempt represents the structure of the check_in_attempts table in the database.
+type CheckInAttempt struct {
+	ID        int64     `json:"id"`
+	WindowID  int64     `json:"window_id"`
+	StudentID int64     `json:"student_id"`
+	Succeeded bool      `json:"succeeded"`
+	Reason    *string   `json:"reason"`
+	IPAddress *string   `json:"ip_address"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OpenCheckInRequest is the structure for the open check-in window request body.
+// DurationMinutes defaults to 15; the geofence fields must be given together.
+type OpenCheckInRequest struct {
+	DurationMinutes int      `json:"duration_minutes"`
+	AllowedNetworks []string `json:"allowed_networks"`
+	Latitude        *float64 `json:"latitude"`
+	Longitude       *float64 `json:"longitude"`
+	RadiusMeters    *int     `json:"radius_meters"`
+}
+
+// CheckInCode is the code of an open check-in window for the instructor to display. Code
+// is short enough to type; Payload is meant for a QR code. Both rotate at ValidUntil.
+type CheckInCode struct {
+	Window     CheckInWindow `json:"window"`
+	Code       string        `json:"code"`
+	Payload    string        `json:"payload"`
+	ValidUntil time.Time     `json:"valid_until"`
+}
+
+// CheckInRequest is the structure for the student check-in request body. Code is either the
+// typed code or the scanned QR payload; the location is required by geofenced windows.
+type CheckInRequest struct {
+	Code      string   `json:"code" validate:"required"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
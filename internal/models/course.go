@@ -0,0 +1,54 @@
+// internal/models/course.go
+package models
+
+import (
+	"time"
+)
+
+// Course represents the structure of the courses table in the database.
+type Course struct {
+	ID             int64            `json:"id"`
+	Code           string           `json:"code"`
+	Title          string           `json:"title"`
+	Description    string           `json:"description"`
+	Credits        float64          `json:"credits"`
+	Department     *string          `json:"department"`
+	IsActive       bool             `json:"is_active"`
+	Capacity       *int             `json:"capacity"` // Default seat limit of new offerings; nil means unlimited
+	Prerequisites  *RequirementNode `json:"prerequisites"`
+	CorequisiteIDs []int64          `json:"corequisite_ids"`
+	CreatedBy      *int64           `json:"created_by,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+// CreateCourseRequest is the structure for the admin create course request body.
+// IsActive defaults to true when omitted.
+type CreateCourseRequest struct {
+	Code        string  `json:"code" validate:"required"`
+	Title       string  `json:"title" validate:"required"`
+	Description string  `json:"description"`
+	Credits     float64 `json:"credits" validate:"gte=0"`
+	Department  *string `json:"department"`
+	IsActive    *bool   `json:"is_active"`
+	Capacity    *int    `json:"capacity" validate:"omitempty,gt=0"`
+}
+
+// UpdateCourseRequest is the structure for the admin update course request body.
+// Omitted fields are left unchanged.
+type UpdateCourseRequest struct {
+	Code        *string  `json:"code"`
+	Title       *string  `json:"title"`
+	Description *string  `json:"description"`
+	Credits     *float64 `json:"credits" validate:"omitempty,gte=0"`
+	Department  *string  `json:"department"` // An empty string clears the department
+	IsActive    *bool    `json:"is_active"`
+	Capacity    *int     `json:"capacity" validate:"omitempty,gte=0"` // Zero removes the seat limit
+}
+
+// CourseFilter narrows down a course listing.
+type CourseFilter struct {
+	Search          string // Full-text search over code, title and description
+	Department      string
+	IncludeInactive bool
+}
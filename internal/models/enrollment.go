@@ -0,0 +1,49 @@
+// internal/models/enrollment.go
+package models
+
+import (
+	"time"
+)
+
+// Enrollment represents the structure of the enrollments table in the database,
+// together with the course, term and student it links.
+type Enrollment struct {
+	ID                    int64      `json:"id"`
+	CourseID              int64      `json:"course_id"`
+	CourseCode            string     `json:"course_code"`
+	CourseTitle           string     `json:"course_title"`
+	TermID                int64      `json:"term_id"`
+	TermCode              string     `json:"term_code"`
+	SectionID             *int64     `json:"section_id"`
+	SectionCode           *string    `json:"section_code"`
+	StudentID             int64      `json:"student_id"`
+	StudentName           string     `json:"student_name"`
+	Status                string     `json:"status"`
+	WaitlistPosition      *int       `json:"waitlist_position,omitempty"` // 1-based; only set while waitlisted
+	EnrolledAt            *time.Time `json:"enrolled_at"`
+	WaitlistedAt          *time.Time `json:"waitlisted_at"`
+	DroppedAt             *time.Time `json:"dropped_at"`
+	DroppedBy             *int64     `json:"dropped_by"`
+	OverrideBy            *int64     `json:"override_by,omitempty"` // Admin who waived unmet requirements
+	OverrideJustification *string    `json:"override_justification,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+
+	// Clashes lists timetable clashes that were accepted with the enrollment; not stored.
+	Clashes []ScheduleClash `json:"clashes,omitempty"`
+}
+
+// AdminEnrollRequest is the structure for the admin enroll student request body.
+// OverrideRequirements waives unmet prerequisites and co-requisites and needs a Justification.
+type AdminEnrollRequest struct {
+	StudentID            int64  `json:"student_id" validate:"required"`
+	SectionID            *int64 `json:"section_id"`
+	OverrideRequirements bool   `json:"override_requirements"`
+	Justification        string `json:"justification"`
+}
+
+// RequirementOverride records an admin waiving a course's requirements for an enrollment.
+type RequirementOverride struct {
+	By            int64
+	Justification string
+}
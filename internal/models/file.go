@@ -0,0 +1,26 @@
+// internal/models/file.go
+package models
+
+import (
+	"time"
+)
+
+// File represents the structure of the files table in the database.
+type File struct {
+	ID          int64     `json:"id"`
+	OwnerID     *int64    `json:"owner_id"`
+	StorageKey  string    `json:"-"` // Internal object key, never exposed
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	Checksum    string    `json:"checksum"`
+	Purpose     string    `json:"purpose"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// FileURLResponse contains a time-limited signed download URL for a file.
+type FileURLResponse struct {
+	File      File      `json:"file"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
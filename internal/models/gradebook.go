@@ -0,0 +1,157 @@
+// internal/models/gradebook.go
+package models
+
+import (
+	"time"
+)
+
+// GradeScaleEntry is one letter of a grade scale: percentages of at least MinPercent earn it.
+type GradeScaleEntry struct {
+	Letter     string  `json:"letter"`
+	MinPercent float64 `json:"min_percent"`
+}
+
+// GradeScale is the letter-grade scale of a course, best letter first.
+type GradeScale struct {
+	CourseID int64             `json:"course_id"`
+	Default  bool              `json:"default"` // The course has no scale of its own
+	Entries  []GradeScaleEntry `json:"entries"`
+}
+
+// SetGradeScaleRequest is the structure for the set grade scale request body. An empty list
+// reverts the course to the default scale.
+type SetGradeScaleRequest struct {
+	Entries []GradeScaleEntry `json:"entries"`
+}
+
+// GradeCategory represents the structure of the grade_categories table in the database.
+type GradeCategory struct {
+	ID         int64   `json:"id"`
+	SectionID  int64   `json:"section_id"`
+	Name       string  `json:"name"`
+	Weight     float64 `json:"weight"`      // Percent of the course grade
+	DropLowest int     `json:"drop_lowest"` // Number of lowest assignments left out
+	Position   int     `json:"position"`
+}
+
+// GradeCategoryRequest describes a category in the set grade categories request body.
+// Categories with an ID update that category; others are created.
+type GradeCategoryRequest struct {
+	ID         *int64  `json:"id"`
+	Name       string  `json:"name"`
+	Weight     float64 `json:"weight"`
+	DropLowest int     `json:"drop_lowest"`
+}
+
+// SetGradeCategoriesRequest is the structure for the set grade categories request body. It
+// replaces all categories of the section; assignments of removed categories become uncategorized.
+type SetGradeCategoriesRequest struct {
+	Categories []GradeCategoryRequest `json:"categories"`
+}
+
+// AssignmentGrade represents the structure of the assignment_grades table in the database.
+type AssignmentGrade struct {
+	ID           int64      `json:"id"`
+	AssignmentID int64      `json:"assignment_id"`
+	StudentID    int64      `json:"student_id"`
+	StudentName  string     `json:"student_name"`
+	SubmissionID *int64     `json:"submission_id"` // The version that was graded
+	Points       *float64   `json:"points"`        // Before the late penalty; nil when excused
+	LatePenalty  float64    `json:"late_penalty"`  // Points deducted for lateness
+	Excused      bool       `json:"excused"`
+	Feedback     *string    `json:"feedback"`
+	GradedBy     *int64     `json:"graded_by"`
+	GradedAt     time.Time  `json:"graded_at"`
+	ReleasedAt   *time.Time `json:"released_at"` // Students only see released grades
+}
+
+// GradeEntry describes one student's grade in the set grades request body.
+type GradeEntry struct {
+	StudentID int64    `json:"student_id"`
+	Points    *float64 `json:"points"`
+	Excused   bool     `json:"excused"`
+	Feedback  string   `json:"feedback"`
+}
+
+// SetGradesRequest is the structure for the set grades request body. With Release, the
+// grades are released to the students right away.
+type SetGradesRequest struct {
+	Grades  []GradeEntry `json:"grades"`
+	Release bool         `json:"release"`
+}
+
+// GradeItem is an assignment in a student's grade breakdown.
+type GradeItem struct {
+	AssignmentID int64      `json:"assignment_id"`
+	Title        string     `json:"title"`
+	DueAt        time.Time  `json:"due_at"`
+	MaxPoints    float64    `json:"max_points"`
+	ExtraCredit  bool       `json:"extra_credit"`
+	Points       *float64   `json:"points"`
+	LatePenalty  float64    `json:"late_penalty"`
+	Score        *float64   `json:"score"` // Points after the late penalty; nil when not graded
+	Excused      bool       `json:"excused"`
+	Dropped      bool       `json:"dropped"` // Left out by the category's drop-lowest rule
+	Feedback     *string    `json:"feedback"`
+	ReleasedAt   *time.Time `json:"released_at"`
+}
+
+// CategoryGrade is a category in a student's grade breakdown.
+type CategoryGrade struct {
+	CategoryID *int64      `json:"category_id"` // Nil for the implicit categories
+	Name       string      `json:"name"`
+	Weight     float64     `json:"weight"`
+	DropLowest int         `json:"drop_lowest"`
+	Counted    bool        `json:"counted"` // Uncategorized work does not count once a section uses categories
+	Earned     float64     `json:"earned"`
+	Possible   float64     `json:"possible"`
+	Percent    *float64    `json:"percent"` // Nil until something in the category is graded
+	Items      []GradeItem `json:"items"`
+}
+
+// GradeBreakdown explains a student's grade in a section. The categories show the running
+// grade, which only counts graded work; the final grade also counts missing work as zero.
+type GradeBreakdown struct {
+	StudentID      int64           `json:"student_id"`
+	SectionID      int64           `json:"section_id"`
+	SectionCode    string          `json:"section_code"`
+	CourseID       int64           `json:"course_id"`
+	CourseCode     string          `json:"course_code"`
+	TermID         int64           `json:"term_id"`
+	RunningPercent *float64        `json:"running_percent"`
+	RunningLetter  *string         `json:"running_letter"`
+	FinalPercent   *float64        `json:"final_percent"`
+	FinalLetter    *string         `json:"final_letter"`
+	Categories     []CategoryGrade `json:"categories"`
+}
+
+// StudentGrades lists a student's grades in the sections they are enrolled in during a term.
+type StudentGrades struct {
+	Term   *Term            `json:"term"`
+	Grades []GradeBreakdown `json:"grades"`
+}
+
+// SectionGrade represents the structure of the section_grades table in the database.
+type SectionGrade struct {
+	SectionID      int64      `json:"section_id"`
+	StudentID      int64      `json:"student_id"`
+	StudentName    string     `json:"student_name"`
+	RunningPercent *float64   `json:"running_percent"`
+	RunningLetter  *string    `json:"running_letter"`
+	FinalPercent   *float64   `json:"final_percent"`
+	FinalLetter    *string    `json:"final_letter"`
+	ComputedAt     *time.Time `json:"computed_at"`
+	PostedLetter   *string    `json:"posted_letter"`
+	PostedAt       *time.Time `json:"posted_at"`
+	PostedBy       *int64     `json:"posted_by"`
+	CompletionID   *int64     `json:"completion_id"`
+}
+
+// Gradebook is a section's gradebook for its teacher.
+type Gradebook struct {
+	SectionID   int64           `json:"section_id"`
+	Scale       GradeScale      `json:"scale"`
+	Categories  []GradeCategory `json:"categories"`
+	Assignments []Assignment    `json:"assignments"`
+	Students    []SectionGrade  `json:"students"`
+}
@@ -0,0 +1,80 @@
+// internal/models/guardian.go
+package models
+
+import (
+	"time"
+)
+
+// GuardianLink represents the structure of the student_guardians table in the database.
+type GuardianLink struct {
+	ID              int64     `json:"id"`
+	StudentID       int64     `json:"student_id"`
+	GuardianID      int64     `json:"guardian_id"`
+	Relationship    string    `json:"relationship"`
+	ConsentProfile  bool      `json:"consent_profile"`  // Guardian may view the student's profile
+	ConsentAcademic bool      `json:"consent_academic"` // Guardian may view academic records
+	CreatedBy       *int64    `json:"created_by"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// CreateGuardianLinkRequest is the structure for the admin link guardian request body.
+// ConsentProfile defaults to true when omitted.
+type CreateGuardianLinkRequest struct {
+	StudentID       int64  `json:"student_id" validate:"required"`
+	GuardianID      int64  `json:"guardian_id" validate:"required"`
+	Relationship    string `json:"relationship" validate:"required,oneof=parent legal_guardian grandparent sibling other"`
+	ConsentProfile  *bool  `json:"consent_profile"`
+	ConsentAcademic bool   `json:"consent_academic"`
+}
+
+// UpdateGuardianLinkRequest is the structure for the admin update guardian link request body.
+type UpdateGuardianLinkRequest struct {
+	Relationship    *string `json:"relationship" validate:"omitempty,oneof=parent legal_guardian grandparent sibling other"`
+	ConsentProfile  *bool   `json:"consent_profile"`
+	ConsentAcademic *bool   `json:"consent_academic"`
+}
+
+// GuardianInvitation represents the structure of the guardian_invitations table in the database.
+type GuardianInvitation struct {
+	ID              int64      `json:"id"`
+	StudentID       int64      `json:"student_id"`
+	Email           string     `json:"email"`
+	Relationship    string     `json:"relationship"`
+	ConsentProfile  bool       `json:"consent_profile"`
+	ConsentAcademic bool       `json:"consent_academic"`
+	TokenHash       string     `json:"-"`
+	InvitedBy       *int64     `json:"invited_by"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	AcceptedAt      *time.Time `json:"accepted_at"`
+	AcceptedBy      *int64     `json:"accepted_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// CreateGuardianInvitationRequest is the structure for the admin invite guardian request body.
+type CreateGuardianInvitationRequest struct {
+	StudentID       int64  `json:"student_id" validate:"required"`
+	Email           string `json:"email" validate:"required,email"`
+	Relationship    string `json:"relationship" validate:"required,oneof=parent legal_guardian grandparent sibling other"`
+	ConsentProfile  *bool  `json:"consent_profile"`
+	ConsentAcademic bool   `json:"consent_academic"`
+}
+
+// AcceptGuardianInvitationRequest is the structure for the accept invitation request body.
+// Name is only used when a new guardian account is created; for an existing account the
+// password must match it.
+type AcceptGuardianInvitationRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Name     string `json:"name"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+// LinkedStudent is a student as seen by one of their guardians.
+type LinkedStudent struct {
+	LinkID          int64  `json:"link_id"`
+	StudentID       int64  `json:"student_id"`
+	Name            string `json:"name"`
+	Relationship    string `json:"relationship"`
+	ConsentProfile  bool   `json:"consent_profile"`
+	ConsentAcademic bool   `json:"consent_academic"`
+}
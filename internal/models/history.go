@@ -0,0 +1,25 @@
+// internal/models/history.go
+package models
+
+import (
+	"time"
+)
+
+// FieldChange holds the before and after values of a single field.
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// ChangeHistoryEntry represents the structure of the change_history table in the database.
+type ChangeHistoryEntry struct {
+	ID         int64                  `json:"id"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   int64                  `json:"entity_id"`
+	Action     string                 `json:"action"`
+	ActorID    *int64                 `json:"actor_id"`
+	ActorRole  *string                `json:"actor_role"`
+	RequestID  *string                `json:"request_id"`
+	Changes    map[string]FieldChange `json:"changes"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
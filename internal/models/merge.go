@@ -0,0 +1,54 @@
+// internal/models/merge.go
+package models
+
+import (
+	"time"
+)
+
+// DuplicateUser is the subset of a user's data used for duplicate detection.
+type DuplicateUser struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	Role          string    `json:"role"`
+	StudentNumber *string   `json:"student_number"`
+	Phone         *string   `json:"phone"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DuplicateCandidate is a pair of accounts that likely belong to the same person.
+type DuplicateCandidate struct {
+	Users   [2]DuplicateUser `json:"users"`
+	Score   float64          `json:"score"`   // 0..1, higher is more likely a duplicate
+	Reasons []string         `json:"reasons"` // e.g. "same_normalized_email", "similar_name"
+}
+
+// MergeUsersRequest is the structure for the admin merge preview and merge request bodies.
+// The source account is merged into the target account, which survives.
+type MergeUsersRequest struct {
+	SourceUserID int64  `json:"source_user_id" validate:"required"`
+	TargetUserID int64  `json:"target_user_id" validate:"required"`
+	Reason       string `json:"reason"`
+}
+
+// MergePreview describes what a merge would do without changing anything.
+type MergePreview struct {
+	Source    UserResponse     `json:"source"`
+	Target    UserResponse     `json:"target"`
+	Result    UserResponse     `json:"result"`    // The surviving account after the merge
+	Records   map[string]int64 `json:"records"`   // Records that would move, per table and column
+	Conflicts []string         `json:"conflicts"` // Profile fields set on both accounts; the target's value is kept
+}
+
+// UserMerge represents the structure of the user_merges table in the database.
+type UserMerge struct {
+	ID           int64            `json:"id"`
+	SourceUserID int64            `json:"source_user_id"`
+	SourceName   string           `json:"source_name"`
+	SourceEmail  *string          `json:"source_email"`
+	TargetUserID int64            `json:"target_user_id"`
+	PerformedBy  *int64           `json:"performed_by"`
+	Reason       string           `json:"reason"`
+	Records      map[string]int64 `json:"records"`
+	MergedAt     time.Time        `json:"merged_at"`
+}
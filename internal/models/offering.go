@@ -0,0 +1,42 @@
+// internal/models/offering.go
+package models
+
+import (
+	"time"
+)
+
+// CourseOffering represents the structure of the course_offerings table in the database:
+// a course taught in a term, together with its seat usage.
+type CourseOffering struct {
+	ID            int64      `json:"id"`
+	CourseID      int64      `json:"course_id"`
+	CourseCode    string     `json:"course_code"`
+	CourseTitle   string     `json:"course_title"`
+	TermID        int64      `json:"term_id"`
+	TermCode      string     `json:"term_code"`
+	Capacity      *int       `json:"capacity"`      // Seat limit; nil means unlimited
+	DropDeadline  *time.Time `json:"drop_deadline"` // Overrides the term's drop deadline when set
+	IsActive      bool       `json:"is_active"`
+	EnrolledCount int        `json:"enrolled_count"`
+	WaitlistCount int        `json:"waitlist_count"`
+	CreatedBy     *int64     `json:"created_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// CreateOfferingRequest is the structure for the admin offer course request body.
+// Capacity defaults to the course's capacity.
+type CreateOfferingRequest struct {
+	CourseID     int64      `json:"course_id" validate:"required"`
+	Capacity     *int       `json:"capacity" validate:"omitempty,gt=0"`
+	DropDeadline *time.Time `json:"drop_deadline"`
+}
+
+// UpdateOfferingRequest is the structure for the admin update offering request body.
+// Omitted fields are left unchanged.
+type UpdateOfferingRequest struct {
+	Capacity          *int       `json:"capacity" validate:"omitempty,gte=0"` // Zero removes the seat limit
+	DropDeadline      *time.Time `json:"drop_deadline"`
+	ClearDropDeadline bool       `json:"clear_drop_deadline"`
+	IsActive          *bool      `json:"is_active"`
+}
@@ -0,0 +1,130 @@
+// internal/models/peer_review.go
+package models
+
+import (
+	"time"
+)
+
+// PeerReviewSettings represents the structure of the peer_review_settings table in the
+// database: anonymous peer review of an assignment's submissions.
+type PeerReviewSettings struct {
+	AssignmentID           int64      `json:"assignment_id"`
+	ReviewersPerSubmission int        `json:"reviewers_per_submission"`
+	ReviewDueAt            time.Time  `json:"review_due_at"`
+	AllocatedAt            *time.Time `json:"allocated_at"` // Nil until reviews have been assigned
+	CreatedBy              *int64     `json:"created_by"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+}
+
+// PeerReviewSettingsRequest is the structure for the set peer review settings request body.
+type PeerReviewSettingsRequest struct {
+	ReviewersPerSubmission int       `json:"reviewers_per_submission" validate:"required"`
+	ReviewDueAt            time.Time `json:"review_due_at" validate:"required"`
+}
+
+// PeerReviewScore represents the structure of the peer_review_scores table in the database.
+type PeerReviewScore struct {
+	CriterionID int64   `json:"criterion_id"`
+	LevelID     *int64  `json:"level_id"`
+	Points      float64 `json:"points"`
+	Comment     *string `json:"comment"`
+}
+
+// PeerReview represents the structure of the peer_reviews table in the database: one student
+// reviewing another's submission. Only teachers see who reviewed whom.
+type PeerReview struct {
+	ID           int64             `json:"id"`
+	AssignmentID int64             `json:"assignment_id"`
+	SubmissionID int64             `json:"submission_id"`
+	AuthorID     int64             `json:"author_id"`
+	AuthorName   string            `json:"author_name"`
+	ReviewerID   int64             `json:"reviewer_id"`
+	ReviewerName string            `json:"reviewer_name"`
+	Points       *float64          `json:"points"` // Rubric total, or points out of the assignment's without a rubric
+	Comment      *string           `json:"comment"`
+	Scores       []PeerReviewScore `json:"scores"`
+	AssignedAt   time.Time         `json:"assigned_at"`
+	SubmittedAt  *time.Time        `json:"submitted_at"` // Nil until the review is handed in
+}
+
+// SubmitPeerReviewRequest is the structure for the submit peer review request body. With a
+// rubric every criterion is scored; without one a comment is required and points are optional.
+type SubmitPeerReviewRequest struct {
+	Scores  []CriterionScoreEntry `json:"scores"`
+	Points  *float64              `json:"points"`
+	Comment string                `json:"comment"`
+}
+
+// ReviewFile is a file of the submission under review, named without its author's filename.
+type ReviewFile struct {
+	Position    int    `json:"position"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
+}
+
+// ReviewTask is a peer review as its reviewer sees it: the submission without its author.
+type ReviewTask struct {
+	ID              int64             `json:"id"`
+	AssignmentID    int64             `json:"assignment_id"`
+	AssignmentTitle string            `json:"assignment_title"`
+	CourseCode      string            `json:"course_code"`
+	ReviewDueAt     time.Time         `json:"review_due_at"`
+	Rubric          *Rubric           `json:"rubric,omitempty"`
+	SubmissionNote  *string           `json:"submission_note"` // The author's comment on the submission
+	Files           []ReviewFile      `json:"files,omitempty"`
+	Points          *float64          `json:"points"`
+	Comment         *string           `json:"comment"`
+	Scores          []PeerReviewScore `json:"scores"`
+	SubmittedAt     *time.Time        `json:"submitted_at"`
+}
+
+// ReceivedReview is a peer review as its author sees it, without the reviewer.
+type ReceivedReview struct {
+	Points      *float64          `json:"points"`
+	Comment     *string           `json:"comment"`
+	Scores      []PeerReviewScore `json:"scores"`
+	SubmittedAt time.Time         `json:"submitted_at"`
+}
+
+// PeerFeedback is the peer reviews a student's submission received.
+type PeerFeedback struct {
+	AssignmentID  int64            `json:"assignment_id"`
+	Rubric        *Rubric          `json:"rubric,omitempty"`
+	Reviews       []ReceivedReview `json:"reviews"`
+	AveragePoints *float64         `json:"average_points"`
+}
+
+// CriterionAverage is the mean score of a rubric criterion over the handed-in reviews.
+type CriterionAverage struct {
+	CriterionID int64   `json:"criterion_id"`
+	Average     float64 `json:"average"`
+}
+
+// PeerReviewSummary aggregates the reviews of one student's submission for the teacher.
+type PeerReviewSummary struct {
+	AuthorID          int64              `json:"author_id"`
+	AuthorName        string             `json:"author_name"`
+	SubmissionID      int64              `json:"submission_id"`
+	Assigned          int                `json:"assigned"`
+	Completed         int                `json:"completed"`
+	AveragePoints     *float64           `json:"average_points"` // Mean of the reviews that gave points
+	CriterionAverages []CriterionAverage `json:"criterion_averages,omitempty"`
+	SuggestedGrade    *float64           `json:"suggested_grade"` // The average scaled to the assignment's points
+	Reviews           []PeerReview       `json:"reviews"`
+}
+
+// PeerReviewOverview is the state of an assignment's peer review for the teacher.
+type PeerReviewOverview struct {
+	Settings  *PeerReviewSettings `json:"settings"`
+	Rubric    *Rubric             `json:"rubric,omitempty"`
+	Summaries []PeerReviewSummary `json:"summaries"`
+}
+
+// AcceptPeerScoresRequest is the structure for the accept peer scores request body. Without
+// student IDs, every student whose submission has handed-in reviews with points is graded.
+type AcceptPeerScoresRequest struct {
+	StudentIDs []int64 `json:"student_ids"`
+	Release    bool    `json:"release"`
+}
@@ -0,0 +1,39 @@
+// internal/models/privacy.go
+package models
+
+import (
+	"time"
+)
+
+// DataExportJob represents the structure of the data_export_jobs table in the database.
+type DataExportJob struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	RequestedBy *int64     `json:"requested_by"`
+	Status      string     `json:"status"`
+	FileID      *int64     `json:"-"`
+	Error       *string    `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+// DataExportJobResponse is the response structure for an export job.
+// Download is only set once the job has completed.
+type DataExportJobResponse struct {
+	DataExportJob
+	Download *FileURLResponse `json:"download,omitempty"`
+}
+
+// EraseUserRequest is the structure for the admin erase user request body.
+type EraseUserRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// UserErasure represents the structure of the user_erasures table in the database.
+type UserErasure struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	PerformedBy *int64    `json:"performed_by"`
+	Reason      string    `json:"reason"`
+	ErasedAt    time.Time `json:"erased_at"`
+}
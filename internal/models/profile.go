@@ -0,0 +1,91 @@
+// internal/models/profile.go
+package models
+
+import (
+	"time"
+)
+
+// DateLayout is the layout used for calendar dates (e.g. date of birth) in requests and responses.
+const DateLayout = "2006-01-02"
+
+// Profile represents the structure of the user_profiles table in the database.
+type Profile struct {
+	UserID         int64                  `json:"user_id"`
+	Phone          *string                `json:"phone"`
+	DateOfBirth    *time.Time             `json:"date_of_birth"`
+	Address        *string                `json:"address"`
+	StudentNumber  *string                `json:"student_number"`
+	Program        *string                `json:"program"`
+	EnrollmentYear *int                   `json:"enrollment_year"`
+	CustomFields   map[string]interface{} `json:"custom_fields"`
+	AvatarFileID   *int64                 `json:"avatar_file_id"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
+
+// ProfileFieldDefinition represents an admin-defined custom profile field.
+type ProfileFieldDefinition struct {
+	ID              int64     `json:"id"`
+	Key             string    `json:"key"`
+	Label           string    `json:"label"`
+	FieldType       string    `json:"field_type"`
+	Options         []string  `json:"options"`
+	Required        bool      `json:"required"`
+	StudentEditable bool      `json:"student_editable"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// CreateProfileFieldRequest is the structure for the admin create custom field request body.
+type CreateProfileFieldRequest struct {
+	Key             string   `json:"key" validate:"required"`
+	Label           string   `json:"label" validate:"required"`
+	FieldType       string   `json:"field_type" validate:"required,oneof=string number boolean date select"`
+	Options         []string `json:"options"`
+	Required        bool     `json:"required"`
+	StudentEditable bool     `json:"student_editable"`
+}
+
+// ProfileResponse is the response structure for a user's profile.
+type ProfileResponse struct {
+	Phone          *string                `json:"phone"`
+	DateOfBirth    *string                `json:"date_of_birth"` // Formatted as YYYY-MM-DD
+	Address        *string                `json:"address"`
+	StudentNumber  *string                `json:"student_number"`
+	Program        *string                `json:"program"`
+	EnrollmentYear *int                   `json:"enrollment_year"`
+	CustomFields   map[string]interface{} `json:"custom_fields"`
+	AvatarFileID   *int64                 `json:"avatar_file_id"` // Use GET /api/profile/avatar for a download URL
+}
+
+// NewProfile returns an empty profile for the given user.
+func NewProfile(userID int64) *Profile {
+	return &Profile{
+		UserID:       userID,
+		CustomFields: map[string]interface{}{},
+	}
+}
+
+// ToResponse converts a Profile model to a ProfileResponse DTO.
+func (p *Profile) ToResponse() ProfileResponse {
+	var dob *string
+	if p.DateOfBirth != nil {
+		formatted := p.DateOfBirth.Format(DateLayout)
+		dob = &formatted
+	}
+
+	customFields := p.CustomFields
+	if customFields == nil {
+		customFields = map[string]interface{}{}
+	}
+
+	return ProfileResponse{
+		Phone:          p.Phone,
+		DateOfBirth:    dob,
+		Address:        p.Address,
+		StudentNumber:  p.StudentNumber,
+		Program:        p.Program,
+		EnrollmentYear: p.EnrollmentYear,
+		CustomFields:   customFields,
+		AvatarFileID:   p.AvatarFileID,
+	}
+}
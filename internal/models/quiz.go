@@ -0,0 +1,251 @@
+// internal/models/quiz.go
+package models
+
+import (
+	"time"
+)
+
+// QuestionBank represents the structure of the question_banks table in the database:
+// reusable quiz questions owned by a teacher.
+type QuestionBank struct {
+	ID            int64      `json:"id"`
+	Name          string     `json:"name"`
+	Description   *string    `json:"description"`
+	CourseID      *int64     `json:"course_id"`
+	OwnerID       *int64     `json:"owner_id"`
+	QuestionCount int        `json:"question_count"`
+	Questions     []Question `json:"questions,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// QuestionBankRequest is the structure for the create and update question bank request body.
+type QuestionBankRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+	CourseID    *int64 `json:"course_id"`
+}
+
+// QuestionOption is one choice of a multiple-choice or multi-select question.
+type QuestionOption struct {
+	ID   int64  `json:"id"`
+	Text string `json:"text"`
+}
+
+// Question represents the structure of the questions table in the database. The answer key
+// (CorrectOptions, CorrectBool, NumericAnswer and Tolerance) is only shown to teachers.
+type Question struct {
+	ID             int64            `json:"id"`
+	BankID         int64            `json:"bank_id"`
+	Type           string           `json:"type"` // See enums.QuestionType
+	Prompt         string           `json:"prompt"`
+	Points         float64          `json:"points"`
+	Options        []QuestionOption `json:"options"`
+	CorrectOptions []int64          `json:"correct_options"`
+	CorrectBool    *bool            `json:"correct_bool"`
+	NumericAnswer  *float64         `json:"numeric_answer"`
+	Tolerance      float64          `json:"tolerance"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+// QuestionRequest is the structure for the create and update question request body. Options
+// are given in order and numbered from 1; CorrectOptions refers to those numbers.
+type QuestionRequest struct {
+	Type           string   `json:"type" validate:"required"`
+	Prompt         string   `json:"prompt" validate:"required"`
+	Points         float64  `json:"points" validate:"required"`
+	Options        []string `json:"options"`
+	CorrectOptions []int64  `json:"correct_options"`
+	CorrectBool    *bool    `json:"correct_bool"`
+	NumericAnswer  *float64 `json:"numeric_answer"`
+	Tolerance      float64  `json:"tolerance"`
+}
+
+// Quiz represents the structure of the quizzes table in the database: a quiz of a section
+// made of questions from question banks.
+type Quiz struct {
+	ID               int64     `json:"id"`
+	SectionID        int64     `json:"section_id"`
+	SectionCode      string    `json:"section_code"`
+	CourseID         int64     `json:"course_id"`
+	CourseCode       string    `json:"course_code"`
+	TermID           int64     `json:"term_id"`
+	Title            string    `json:"title"`
+	Instructions     *string   `json:"instructions"`
+	OpensAt          time.Time `json:"opens_at"`
+	ClosesAt         time.Time `json:"closes_at"`
+	TimeLimitMinutes *int      `json:"time_limit_minutes"` // No limit when nil
+	MaxAttempts      int       `json:"max_attempts"`
+	ShuffleQuestions bool      `json:"shuffle_questions"`
+	ShuffleOptions   bool      `json:"shuffle_options"`
+	QuestionCount    int       `json:"question_count"`
+	MaxScore         float64   `json:"max_score"`
+	CreatedBy        *int64    `json:"created_by"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// CreateQuizRequest is the structure for the create quiz request body.
+type CreateQuizRequest struct {
+	Title            string    `json:"title" validate:"required"`
+	Instructions     string    `json:"instructions"`
+	OpensAt          time.Time `json:"opens_at" validate:"required"`
+	ClosesAt         time.Time `json:"closes_at" validate:"required"`
+	TimeLimitMinutes *int      `json:"time_limit_minutes"`
+	MaxAttempts      int       `json:"max_attempts"`      // Defaults to 1
+	ShuffleQuestions *bool     `json:"shuffle_questions"` // Defaults to true
+	ShuffleOptions   *bool     `json:"shuffle_options"`   // Defaults to true
+}
+
+// UpdateQuizRequest is the structure for the update quiz request body. Omitted fields are
+// left unchanged; changes apply to attempts started afterwards.
+type UpdateQuizRequest struct {
+	Title            *string    `json:"title"`
+	Instructions     *string    `json:"instructions"`
+	OpensAt          *time.Time `json:"opens_at"`
+	ClosesAt         *time.Time `json:"closes_at"`
+	TimeLimitMinutes *int       `json:"time_limit_minutes"`
+	ClearTimeLimit   bool       `json:"clear_time_limit"`
+	MaxAttempts      *int       `json:"max_attempts"`
+	ShuffleQuestions *bool      `json:"shuffle_questions"`
+	ShuffleOptions   *bool      `json:"shuffle_options"`
+}
+
+// SetQuizQuestionsRequest is the structure for the set quiz questions request body, in the
+// order the questions are shown when not shuffled.
+type SetQuizQuestionsRequest struct {
+	QuestionIDs []int64 `json:"question_ids"`
+}
+
+// AttemptLayoutItem is a question of an attempt with its options in the order shown.
+type AttemptLayoutItem struct {
+	QuestionID int64   `json:"question_id"`
+	Options    []int64 `json:"options,omitempty"`
+}
+
+// QuizAttempt represents the structure of the quiz_attempts table in the database.
+type QuizAttempt struct {
+	ID            int64               `json:"id"`
+	QuizID        int64               `json:"quiz_id"`
+	StudentID     int64               `json:"student_id"`
+	StudentName   string              `json:"student_name"`
+	AttemptNumber int                 `json:"attempt_number"`
+	Status        string              `json:"status"` // See enums.QuizAttemptStatus
+	StartedAt     time.Time           `json:"started_at"`
+	DeadlineAt    time.Time           `json:"deadline_at"` // Set by the server
+	SubmittedAt   *time.Time          `json:"submitted_at"`
+	Score         *float64            `json:"score"` // Final once the status is 'graded'
+	MaxScore      float64             `json:"max_score"`
+	Layout        []AttemptLayoutItem `json:"-"`
+}
+
+// QuizAnswer is a student's answer to one question. Only the field matching the question's
+// type is used: Selected for option questions, Value for true/false, Number for numeric and
+// Text for short answers.
+type QuizAnswer struct {
+	QuestionID int64    `json:"question_id"`
+	Selected   []int64  `json:"selected"`
+	Value      *bool    `json:"value"`
+	Number     *float64 `json:"number"`
+	Text       *string  `json:"text"`
+}
+
+// SaveAnswersRequest is the structure for the save answers and submit attempt request bodies.
+type SaveAnswersRequest struct {
+	Answers []QuizAnswer `json:"answers"`
+}
+
+// QuizResponse represents the structure of the quiz_responses table in the database.
+type QuizResponse struct {
+	AttemptID int64 `json:"attempt_id"`
+	QuizAnswer
+	AnsweredAt *time.Time `json:"answered_at"`
+	Points     *float64   `json:"points"` // Nil until graded
+	AutoGraded bool       `json:"auto_graded"`
+	Feedback   *string    `json:"feedback"`
+	GradedBy   *int64     `json:"graded_by"`
+	GradedAt   *time.Time `json:"graded_at"`
+}
+
+// GradeResponseRequest is the structure for the grade response request body.
+type GradeResponseRequest struct {
+	Points   float64 `json:"points"`
+	Feedback string  `json:"feedback"`
+}
+
+// AttemptQuestion is a question of an attempt as laid out for the student, with their answer.
+// Points and feedback are shown once the attempt is graded; Key only to teachers.
+type AttemptQuestion struct {
+	Number        int              `json:"number"`
+	QuestionID    int64            `json:"question_id"`
+	Type          string           `json:"type"`
+	Prompt        string           `json:"prompt"`
+	Points        float64          `json:"points"`
+	Options       []QuestionOption `json:"options"`
+	Answer        *QuizAnswer      `json:"answer"`
+	AwardedPoints *float64         `json:"awarded_points"`
+	Feedback      *string          `json:"feedback"`
+	Key           *Question        `json:"key,omitempty"`
+}
+
+// QuizAttemptDetail is an attempt with its questions.
+type QuizAttemptDetail struct {
+	Quiz             Quiz              `json:"quiz"`
+	Attempt          QuizAttempt       `json:"attempt"`
+	SecondsRemaining *int64            `json:"seconds_remaining"` // While in progress
+	Questions        []AttemptQuestion `json:"questions"`
+}
+
+// QueuedResponse is a free-text answer waiting to be graded by the teacher.
+type QueuedResponse struct {
+	AttemptID     int64     `json:"attempt_id"`
+	StudentID     int64     `json:"student_id"`
+	StudentName   string    `json:"student_name"`
+	AttemptNumber int       `json:"attempt_number"`
+	QuestionID    int64     `json:"question_id"`
+	Prompt        string    `json:"prompt"`
+	Points        float64   `json:"points"` // What the question is worth
+	Text          *string   `json:"text"`
+	SubmittedAt   time.Time `json:"submitted_at"`
+}
+
+// StudentQuiz is a quiz as seen by a student of its section, with their attempts.
+type StudentQuiz struct {
+	Quiz
+	AttemptsLeft int           `json:"attempts_left"`
+	Attempts     []QuizAttempt `json:"attempts"`
+}
+
+// StudentQuizzes lists a student's quizzes in a term.
+type StudentQuizzes struct {
+	Term    *Term         `json:"term"`
+	Quizzes []StudentQuiz `json:"quizzes"`
+}
+
+// QTIImportRequest holds the form fields of a QTI import into a new question bank. The name
+// defaults to the title of the package, or its file name.
+type QTIImportRequest struct {
+	Name     string `json:"name"`
+	CourseID *int64 `json:"course_id"`
+}
+
+// QTIItemResult is what became of one item of an imported QTI package.
+type QTIItemResult struct {
+	File       string   `json:"file"`
+	Identifier string   `json:"identifier"`
+	Title      string   `json:"title"`
+	Version    string   `json:"version"` // QTI version of the item, e.g. "2.1"
+	QuestionID *int64   `json:"question_id,omitempty"`
+	Type       string   `json:"type,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"` // What was lost or changed in conversion
+	Reason     string   `json:"reason,omitempty"`   // Why the item was skipped
+}
+
+// QTIImportReport lists the items of a QTI package that were imported into the bank and the
+// ones that could not be converted.
+type QTIImportReport struct {
+	Bank     *QuestionBank   `json:"bank"` // Nil when nothing could be imported into a new bank
+	Imported []QTIItemResult `json:"imported"`
+	Skipped  []QTIItemResult `json:"skipped"`
+}
@@ -0,0 +1,68 @@
+// internal/models/requirement.go
+package models
+
+import (
+	"time"
+)
+
+// RequirementNode is a node of a prerequisite expression. Exactly one of All, Any or
+// CourseID is set: All and Any group other nodes, CourseID requires the course to be
+// completed with at least MinGrade (any passing grade when empty).
+type RequirementNode struct {
+	All      []RequirementNode `json:"all,omitempty"`
+	Any      []RequirementNode `json:"any,omitempty"`
+	CourseID int64             `json:"course_id,omitempty"`
+	MinGrade string            `json:"min_grade,omitempty"`
+}
+
+// CourseRequirements is the structure for the admin set course requirements request body.
+// It replaces both the prerequisites (nil removes them) and the co-requisites.
+type CourseRequirements struct {
+	Prerequisites  *RequirementNode `json:"prerequisites"`
+	CorequisiteIDs []int64          `json:"corequisite_ids"`
+}
+
+// RequirementCheck explains how a student fares against one node of a course's requirements.
+type RequirementCheck struct {
+	Type         string             `json:"type"` // all, any, course or corequisite
+	Met          bool               `json:"met"`
+	CourseID     int64              `json:"course_id,omitempty"`
+	CourseCode   string             `json:"course_code,omitempty"`
+	MinGrade     string             `json:"min_grade,omitempty"`
+	BestGrade    string             `json:"best_grade,omitempty"` // The student's best grade in the course, if any
+	Reason       string             `json:"reason,omitempty"`     // Why an unmet course requirement fails
+	Requirements []RequirementCheck `json:"requirements,omitempty"`
+}
+
+// EnrollmentEligibility is the result of checking a student against a course's requirements.
+type EnrollmentEligibility struct {
+	CourseID      int64              `json:"course_id"`
+	Eligible      bool               `json:"eligible"`
+	Prerequisites *RequirementCheck  `json:"prerequisites,omitempty"`
+	Corequisites  []RequirementCheck `json:"corequisites,omitempty"`
+	Unmet         []RequirementCheck `json:"unmet"` // Flat list of the unmet course requirements
+}
+
+// CourseCompletion represents the structure of the course_completions table in the database.
+type CourseCompletion struct {
+	ID          int64     `json:"id"`
+	StudentID   int64     `json:"student_id"`
+	CourseID    int64     `json:"course_id"`
+	CourseCode  string    `json:"course_code"`
+	CourseTitle string    `json:"course_title"`
+	TermID      *int64    `json:"term_id"` // Unknown for completions recorded before terms existed
+	TermCode    *string   `json:"term_code"`
+	Grade       string    `json:"grade"`
+	CompletedAt time.Time `json:"completed_at"`
+	RecordedBy  *int64    `json:"recorded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CreateCompletionRequest is the structure for the admin record completion request body.
+// CompletedAt defaults to today; TermID is the term the course was taken in, if known.
+type CreateCompletionRequest struct {
+	CourseID    int64      `json:"course_id" validate:"required"`
+	TermID      *int64     `json:"term_id"`
+	Grade       string     `json:"grade" validate:"required"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
@@ -0,0 +1,102 @@
+// internal/models/rubric.go
+package models
+
+import (
+	"time"
+)
+
+// Rubric represents the structure of the rubrics table in the database: reusable grading
+// criteria owned by a teacher.
+type Rubric struct {
+	ID          int64             `json:"id"`
+	Title       string            `json:"title"`
+	Description *string           `json:"description"`
+	OwnerID     *int64            `json:"owner_id"`
+	MaxPoints   float64           `json:"max_points"` // Sum of the best level of each criterion
+	Criteria    []RubricCriterion `json:"criteria"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// RubricCriterion represents the structure of the rubric_criteria table in the database.
+type RubricCriterion struct {
+	ID          int64         `json:"id"`
+	Title       string        `json:"title"`
+	Description *string       `json:"description"`
+	MaxPoints   float64       `json:"max_points"` // Points of its best level
+	Levels      []RubricLevel `json:"levels"`
+}
+
+// RubricLevel represents the structure of the rubric_levels table in the database: one
+// performance level of a criterion.
+type RubricLevel struct {
+	ID          int64   `json:"id"`
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+	Points      float64 `json:"points"`
+}
+
+// RubricLevelRequest describes a performance level in the rubric request body.
+type RubricLevelRequest struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Points      float64 `json:"points"`
+}
+
+// RubricCriterionRequest describes a criterion in the rubric request body.
+type RubricCriterionRequest struct {
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Levels      []RubricLevelRequest `json:"levels"`
+}
+
+// RubricRequest is the structure for the create and update rubric request body. Criteria and
+// levels are given in order and replace the existing ones.
+type RubricRequest struct {
+	Title       string                   `json:"title" validate:"required"`
+	Description string                   `json:"description"`
+	Criteria    []RubricCriterionRequest `json:"criteria"`
+}
+
+// SetAssignmentRubricRequest is the structure for the set assignment rubric request body.
+// A nil RubricID detaches the rubric.
+type SetAssignmentRubricRequest struct {
+	RubricID *int64 `json:"rubric_id"`
+}
+
+// CriterionScoreEntry describes the score of one criterion in the score rubric request body:
+// either a level of the criterion or points given directly.
+type CriterionScoreEntry struct {
+	CriterionID int64    `json:"criterion_id"`
+	LevelID     *int64   `json:"level_id"`
+	Points      *float64 `json:"points"`
+	Comment     string   `json:"comment"`
+}
+
+// ScoreRubricRequest is the structure for the score rubric request body. Every criterion of
+// the rubric must be scored; the total becomes the student's assignment grade.
+type ScoreRubricRequest struct {
+	Scores   []CriterionScoreEntry `json:"scores"`
+	Feedback string                `json:"feedback"`
+	Release  bool                  `json:"release"`
+}
+
+// RubricScore represents the structure of the rubric_scores table in the database.
+type RubricScore struct {
+	CriterionID int64     `json:"criterion_id"`
+	LevelID     *int64    `json:"level_id"`
+	Points      float64   `json:"points"`
+	Comment     *string   `json:"comment"`
+	ScoredBy    *int64    `json:"scored_by"`
+	ScoredAt    time.Time `json:"scored_at"`
+}
+
+// RubricAssessment is a student's work on an assignment scored against its rubric.
+type RubricAssessment struct {
+	AssignmentID int64            `json:"assignment_id"`
+	StudentID    int64            `json:"student_id"`
+	Rubric       *Rubric          `json:"rubric"`
+	Scores       []RubricScore    `json:"scores"`
+	Total        float64          `json:"total"` // Rubric points, before scaling to the assignment
+	Grade        *AssignmentGrade `json:"grade"`
+}
@@ -0,0 +1,93 @@
+// internal/models/section.go
+package models
+
+import (
+	"time"
+)
+
+// Section represents the structure of the course_sections table in the database: one
+// group of a course offering with its own instructor and weekly meetings.
+type Section struct {
+	ID             int64            `json:"id"`
+	CourseID       int64            `json:"course_id"`
+	CourseCode     string           `json:"course_code"`
+	TermID         int64            `json:"term_id"`
+	TermCode       string           `json:"term_code"`
+	Code           string           `json:"code"`
+	InstructorID   *int64           `json:"instructor_id"`
+	InstructorName *string          `json:"instructor_name"`
+	Meetings       []SectionMeeting `json:"meetings"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+// SectionMeeting represents the structure of the section_meetings table in the database.
+// Times are formatted as HH:MM.
+type SectionMeeting struct {
+	ID        int64   `json:"id"`
+	SectionID int64   `json:"section_id"`
+	DayOfWeek int     `json:"day_of_week"` // ISO 8601: 1 is Monday, 7 is Sunday
+	StartTime string  `json:"start_time"`
+	EndTime   string  `json:"end_time"`
+	Room      *string `json:"room"`
+}
+
+// MeetingRequest describes a weekly meeting in the section request bodies.
+type MeetingRequest struct {
+	DayOfWeek int    `json:"day_of_week" validate:"required,min=1,max=7"`
+	StartTime string `json:"start_time" validate:"required"`
+	EndTime   string `json:"end_time" validate:"required"`
+	Room      string `json:"room"`
+}
+
+// CreateSectionRequest is the structure for the admin create section request body.
+type CreateSectionRequest struct {
+	Code         string           `json:"code" validate:"required"`
+	InstructorID *int64           `json:"instructor_id"`
+	Meetings     []MeetingRequest `json:"meetings"`
+}
+
+// UpdateSectionRequest is the structure for the admin update section request body.
+// Omitted fields are left unchanged; Meetings, when given, replaces all meetings.
+type UpdateSectionRequest struct {
+	Code            *string           `json:"code"`
+	InstructorID    *int64            `json:"instructor_id"`
+	ClearInstructor bool              `json:"clear_instructor"`
+	Meetings        *[]MeetingRequest `json:"meetings"`
+}
+
+// ChangeSectionRequest is the structure for the student change section request body.
+type ChangeSectionRequest struct {
+	SectionID int64 `json:"section_id" validate:"required"`
+}
+
+// TimetableEntry is one weekly meeting on a timetable.
+type TimetableEntry struct {
+	CourseID       int64   `json:"course_id"`
+	CourseCode     string  `json:"course_code"`
+	CourseTitle    string  `json:"course_title"`
+	SectionID      int64   `json:"section_id"`
+	SectionCode    string  `json:"section_code"`
+	MeetingID      int64   `json:"meeting_id"`
+	DayOfWeek      int     `json:"day_of_week"`
+	StartTime      string  `json:"start_time"`
+	EndTime        string  `json:"end_time"`
+	Room           *string `json:"room"`
+	InstructorID   *int64  `json:"instructor_id"`
+	InstructorName *string `json:"instructor_name"`
+	Status         string  `json:"status,omitempty"` // Enrollment status on student timetables
+}
+
+// Timetable is a user's weekly schedule in a term: the meetings of the sections they
+// attend, or teach.
+type Timetable struct {
+	Term    *Term            `json:"term"`
+	Entries []TimetableEntry `json:"entries"`
+}
+
+// ScheduleClash reports two meetings that overlap in time and share a student, room or instructor.
+type ScheduleClash struct {
+	Type   string         `json:"type"` // "student", "room" or "instructor"
+	First  TimetableEntry `json:"first"`
+	Second TimetableEntry `json:"second"`
+}
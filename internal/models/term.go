@@ -0,0 +1,68 @@
+// internal/models/term.go
+package models
+
+import (
+	"time"
+)
+
+// Term represents the structure of the terms table in the database.
+type Term struct {
+	ID           int64         `json:"id"`
+	Code         string        `json:"code"`
+	Name         string        `json:"name"`
+	StartDate    time.Time     `json:"start_date"`
+	EndDate      time.Time     `json:"end_date"`
+	AddDeadline  *time.Time    `json:"add_deadline"`  // Students cannot enroll themselves after this
+	DropDeadline *time.Time    `json:"drop_deadline"` // Students cannot give up a seat themselves after this
+	ExamStart    *time.Time    `json:"exam_start"`
+	ExamEnd      *time.Time    `json:"exam_end"`
+	Holidays     []TermHoliday `json:"holidays"`
+	CreatedBy    *int64        `json:"created_by,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// TermHoliday represents the structure of the term_holidays table in the database.
+type TermHoliday struct {
+	ID        int64     `json:"id"`
+	TermID    int64     `json:"term_id"`
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+// CreateTermRequest is the structure for the admin create term request body.
+// Dates are formatted as YYYY-MM-DD.
+type CreateTermRequest struct {
+	Code         string     `json:"code" validate:"required"`
+	Name         string     `json:"name" validate:"required"`
+	StartDate    string     `json:"start_date" validate:"required"`
+	EndDate      string     `json:"end_date" validate:"required"`
+	AddDeadline  *time.Time `json:"add_deadline"`
+	DropDeadline *time.Time `json:"drop_deadline"`
+	ExamStart    *string    `json:"exam_start"`
+	ExamEnd      *string    `json:"exam_end"`
+}
+
+// UpdateTermRequest is the structure for the admin update term request body.
+// Omitted fields are left unchanged; an empty exam date clears the exam period.
+type UpdateTermRequest struct {
+	Code              *string    `json:"code"`
+	Name              *string    `json:"name"`
+	StartDate         *string    `json:"start_date"`
+	EndDate           *string    `json:"end_date"`
+	AddDeadline       *time.Time `json:"add_deadline"`
+	ClearAddDeadline  bool       `json:"clear_add_deadline"`
+	DropDeadline      *time.Time `json:"drop_deadline"`
+	ClearDropDeadline bool       `json:"clear_drop_deadline"`
+	ExamStart         *string    `json:"exam_start"`
+	ExamEnd           *string    `json:"exam_end"`
+}
+
+// CreateHolidayRequest is the structure for the admin add holiday request body.
+// Dates are formatted as YYYY-MM-DD; EndDate defaults to StartDate.
+type CreateHolidayRequest struct {
+	Name      string `json:"name" validate:"required"`
+	StartDate string `json:"start_date" validate:"required"`
+	EndDate   string `json:"end_date"`
+}
@@ -0,0 +1,103 @@
+// internal/models/transcript.go
+package models
+
+import (
+	"time"
+)
+
+// GradePointScale represents the structure of the grade_point_scales table in the database,
+// with the points of each letter grade.
+type GradePointScale struct {
+	ID        int64              `json:"id"`
+	Name      string             `json:"name"`
+	IsDefault bool               `json:"is_default"`
+	Points    map[string]float64 `json:"points"` // By letter grade
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// GradePointScaleRequest is the structure for the create and update grade-point scale request
+// bodies. Points must be given for every letter grade.
+type GradePointScaleRequest struct {
+	Name      string             `json:"name" validate:"required"`
+	IsDefault bool               `json:"is_default"`
+	Points    map[string]float64 `json:"points" validate:"required"`
+}
+
+// TranscriptCourse is a completed course on a transcript.
+type TranscriptCourse struct {
+	CompletionID int64     `json:"completion_id"`
+	CourseID     int64     `json:"course_id"`
+	CourseCode   string    `json:"course_code"`
+	CourseTitle  string    `json:"course_title"`
+	Credits      float64   `json:"credits"`
+	Grade        string    `json:"grade"`
+	GradePoints  float64   `json:"grade_points"`
+	CompletedAt  time.Time `json:"completed_at"`
+	Repeated     bool      `json:"repeated"` // A later attempt replaces this one in the cumulative GPA
+}
+
+// TranscriptTerm groups the courses completed in a term. Completions recorded without a term
+// are grouped first, with a nil TermID.
+type TranscriptTerm struct {
+	TermID           *int64             `json:"term_id"`
+	TermCode         *string            `json:"term_code"`
+	TermName         string             `json:"term_name"`
+	Courses          []TranscriptCourse `json:"courses"`
+	AttemptedCredits float64            `json:"attempted_credits"`
+	EarnedCredits    float64            `json:"earned_credits"` // Credits of passed courses
+	GPA              *float64           `json:"gpa"`            // Nil without graded credits
+	CumulativeGPA    *float64           `json:"cumulative_gpa"` // Up to and including this term
+}
+
+// Transcript is a student's academic record across terms.
+type Transcript struct {
+	StudentID        int64            `json:"student_id"`
+	StudentName      string           `json:"student_name"`
+	StudentEmail     string           `json:"student_email"`
+	Scale            string           `json:"scale"` // Name of the grade-point scale used
+	Terms            []TranscriptTerm `json:"terms"`
+	AttemptedCredits float64          `json:"attempted_credits"`
+	EarnedCredits    float64          `json:"earned_credits"`
+	CumulativeGPA    *float64         `json:"cumulative_gpa"`
+	GeneratedAt      time.Time        `json:"generated_at"`
+}
+
+// IssuedTranscript represents the structure of the transcripts table in the database: an
+// official transcript issued as PDF.
+type IssuedTranscript struct {
+	ID               int64     `json:"id"`
+	StudentID        int64     `json:"student_id"`
+	VerificationCode string    `json:"verification_code"`
+	ScaleName        string    `json:"scale_name"`
+	CumulativeGPA    *float64  `json:"cumulative_gpa"`
+	EarnedCredits    float64   `json:"earned_credits"`
+	FileID           int64     `json:"file_id"`
+	IssuedBy         *int64    `json:"issued_by"`
+	IssuedAt         time.Time `json:"issued_at"`
+}
+
+// IssueTranscriptRequest is the structure for the issue transcript request body. The default
+// grade-point scale is used when ScaleID is omitted.
+type IssueTranscriptRequest struct {
+	ScaleID *int64 `json:"scale_id"`
+}
+
+// TranscriptVerification is the public answer to a verification code. It only confirms the
+// document: who it was issued to, when, and the SHA-256 checksum of the issued PDF.
+type TranscriptVerification struct {
+	Valid       bool      `json:"valid"`
+	StudentName string    `json:"student_name"`
+	IssuedAt    time.Time `json:"issued_at"`
+	Checksum    string    `json:"checksum"`
+}
+
+// TranscriptCompletion is a completed course with the term it was taken in, as transcripts
+// are built from.
+type TranscriptCompletion struct {
+	TranscriptCourse
+	TermID    *int64     `json:"term_id"`
+	TermCode  *string    `json:"term_code"`
+	TermName  *string    `json:"term_name"`
+	TermStart *time.Time `json:"term_start"`
+}
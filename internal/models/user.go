@@ -12,6 +12,8 @@ type User struct {
 	Email     string    `json:"email"`
 	Password  string    `json:"-"` // Omit from JSON response
 	Role      string    `json:"role"`
+	Status    string    `json:"status"`  // Account lifecycle status, see enums.AccountStatus
+	Version   int64     `json:"version"` // Incremented on every update; exposed as the ETag
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -31,16 +33,32 @@ type LoginRequest struct {
 }
 
 // UpdateProfileRequest is the structure for the update profile request body.
+// Which fields a user may change on their own profile depends on their role;
+// e.g. students cannot change their own student number.
 type UpdateProfileRequest struct {
-	Name  *string `json:"name"`
-	Email *string `json:"email" validate:"omitempty,email"`
+	Name           *string                `json:"name"`
+	Email          *string                `json:"email" validate:"omitempty,email"`
+	Phone          *string                `json:"phone"`
+	DateOfBirth    *string                `json:"date_of_birth"` // YYYY-MM-DD
+	Address        *string                `json:"address"`
+	StudentNumber  *string                `json:"student_number"`
+	Program        *string                `json:"program"`
+	EnrollmentYear *int                   `json:"enrollment_year"`
+	CustomFields   map[string]interface{} `json:"custom_fields"` // A null value removes the field
 }
 
 // UpdateUserRequest is the structure for the admin update user request body.
 type UpdateUserRequest struct {
-	Name  *string `json:"name"`
-	Email *string `json:"email" validate:"omitempty,email"`
-	Role  *string `json:"role" validate:"omitempty,oneof=student admin"`
+	Name           *string                `json:"name"`
+	Email          *string                `json:"email" validate:"omitempty,email"`
+	Role           *string                `json:"role" validate:"omitempty,oneof=student admin guardian teacher"`
+	Phone          *string                `json:"phone"`
+	DateOfBirth    *string                `json:"date_of_birth"` // YYYY-MM-DD
+	Address        *string                `json:"address"`
+	StudentNumber  *string                `json:"student_number"`
+	Program        *string                `json:"program"`
+	EnrollmentYear *int                   `json:"enrollment_year"`
+	CustomFields   map[string]interface{} `json:"custom_fields"` // A null value removes the field
 }
 
 // UserResponse is the standard response structure for a User, omitting the password.
@@ -49,8 +67,12 @@ type UserResponse struct {
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
 	Role      string    `json:"role"`
+	Status    string    `json:"status"`
+	Version   int64     `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	Profile *ProfileResponse `json:"profile,omitempty"`
 }
 
 // LoginResponse contains the JWT token and user info.
@@ -66,6 +88,8 @@ func (u *User) ToResponse() UserResponse {
 		Name:      u.Name,
 		Email:     u.Email,
 		Role:      u.Role,
+		Status:    u.Status,
+		Version:   u.Version,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
 	}
@@ -0,0 +1,423 @@
+// internal/repository/assignment_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AssignmentRepository defines the methods for assignments and their submissions.
+type AssignmentRepository interface {
+	CreateAssignment(ctx context.Context, assignment *models.Assignment) error
+	GetAssignment(ctx context.Context, id int64) (*models.Assignment, error)
+	UpdateAssignment(ctx context.Context, assignment *models.Assignment) error
+	DeleteAssignment(ctx context.Context, id int64) error
+	ListForSection(ctx context.Context, sectionID int64) ([]models.Assignment, error)
+	// ListForStudent returns the assignments of the sections the student is enrolled in during the term.
+	ListForStudent(ctx context.Context, studentID, termID int64) ([]models.Assignment, error)
+	// ListForInstructor returns the assignments of the sections the instructor teaches during the term.
+	ListForInstructor(ctx context.Context, instructorID, termID int64) ([]models.Assignment, error)
+	IsEnrolled(ctx context.Context, sectionID, studentID int64) (bool, error)
+
+	// CreateSubmission adds the next version of the student's work with the given files
+	// attached. The submission time and whether it is late are decided by the database, which
+	// also fails with ErrSubmissionClosed if the late policy no longer accepts work at that time.
+	CreateSubmission(ctx context.Context, submission *models.Submission, fileIDs []int64) error
+	GetSubmission(ctx context.Context, id int64) (*models.Submission, error)
+	// ListSubmissions returns every version the student handed in for the assignment, newest first.
+	ListSubmissions(ctx context.Context, assignmentID, studentID int64) ([]models.Submission, error)
+	// LatestSubmissions returns the student's latest submission for each assignment in the term.
+	LatestSubmissions(ctx context.Context, studentID, termID int64) ([]models.Submission, error)
+	// SubmissionStatuses returns the enrolled students of the assignment's section, and anyone
+	// else who submitted, with their latest submission.
+	SubmissionStatuses(ctx context.Context, assignment *models.Assignment) ([]models.SubmissionStatus, error)
+}
+
+type assignmentRepository struct {
+	db *txPool
+}
+
+// NewAssignmentRepository creates a new AssignmentRepository instance.
+func NewAssignmentRepository(db *pgxpool.Pool) AssignmentRepository {
+	return &assignmentRepository{db: newTxPool(db)}
+}
+
+// assignmentColumns selects from assignments a joined with course_sections s and courses c.
+const assignmentColumns = `a.id, a.section_id, s.code, s.course_id, c.code, s.term_id, a.title, a.instructions, a.due_at,
+	a.late_policy, a.late_penalty_percent, a.late_until, a.max_points, a.category_id, a.extra_credit, a.rubric_id,
+	a.created_by, a.created_at, a.updated_at`
+
+const assignmentJoins = ` a JOIN course_sections s ON s.id = a.section_id JOIN courses c ON c.id = s.course_id`
+
+func scanAssignment(row pgx.Row) (*models.Assignment, error) {
+	assignment := &models.Assignment{}
+	err := row.Scan(
+		&assignment.ID, &assignment.SectionID, &assignment.SectionCode, &assignment.CourseID, &assignment.CourseCode,
+		&assignment.TermID, &assignment.Title, &assignment.Instructions, &assignment.DueAt, &assignment.LatePolicy,
+		&assignment.LatePenaltyPercent, &assignment.LateUntil, &assignment.MaxPoints, &assignment.CategoryID,
+		&assignment.ExtraCredit, &assignment.RubricID, &assignment.CreatedBy, &assignment.CreatedAt, &assignment.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return assignment, nil
+}
+
+// submissionColumns selects from assignment_submissions sub joined with the student u.
+const submissionColumns = `sub.id, sub.assignment_id, sub.student_id, u.name, sub.version, sub.comment, sub.submitted_at, sub.late`
+
+const submissionJoins = ` sub JOIN users u ON u.id = sub.student_id`
+
+func scanSubmission(row pgx.Row) (*models.Submission, error) {
+	submission := &models.Submission{}
+	err := row.Scan(
+		&submission.ID, &submission.AssignmentID, &submission.StudentID, &submission.StudentName,
+		&submission.Version, &submission.Comment, &submission.SubmittedAt, &submission.Late,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	submission.Files = make([]models.File, 0)
+	return submission, nil
+}
+
+func (r *assignmentRepository) CreateAssignment(ctx context.Context, assignment *models.Assignment) error {
+	query := `
+		WITH created AS (
+			INSERT INTO assignments (section_id, title, instructions, due_at, late_policy, late_penalty_percent, late_until,
+			                         max_points, category_id, extra_credit, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING *
+		)
+		SELECT ` + assignmentColumns + ` FROM created` + assignmentJoins
+	created, err := scanAssignment(r.db.QueryRow(ctx, query,
+		assignment.SectionID, assignment.Title, assignment.Instructions, assignment.DueAt, assignment.LatePolicy,
+		assignment.LatePenaltyPercent, assignment.LateUntil, assignment.MaxPoints, assignment.CategoryID,
+		assignment.ExtraCredit, assignment.CreatedBy,
+	))
+	if err != nil {
+		return err
+	}
+	*assignment = *created
+	return nil
+}
+
+func (r *assignmentRepository) GetAssignment(ctx context.Context, id int64) (*models.Assignment, error) {
+	return scanAssignment(r.db.QueryRow(ctx, "SELECT "+assignmentColumns+" FROM assignments"+assignmentJoins+" WHERE a.id = $1", id))
+}
+
+func (r *assignmentRepository) UpdateAssignment(ctx context.Context, assignment *models.Assignment) error {
+	query := `
+		WITH updated AS (
+			UPDATE assignments
+			SET title = $2, instructions = $3, due_at = $4, late_policy = $5, late_penalty_percent = $6,
+			    late_until = $7, max_points = $8, category_id = $9, extra_credit = $10, updated_at = NOW()
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + assignmentColumns + ` FROM updated` + assignmentJoins
+	updated, err := scanAssignment(r.db.QueryRow(ctx, query,
+		assignment.ID, assignment.Title, assignment.Instructions, assignment.DueAt, assignment.LatePolicy,
+		assignment.LatePenaltyPercent, assignment.LateUntil, assignment.MaxPoints, assignment.CategoryID,
+		assignment.ExtraCredit,
+	))
+	if err != nil {
+		return err
+	}
+	*assignment = *updated
+	return nil
+}
+
+func (r *assignmentRepository) DeleteAssignment(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM assignments WHERE id = $1", id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+			return appErrors.ErrAssignmentInUse
+		}
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *assignmentRepository) queryAssignments(ctx context.Context, query string, args ...interface{}) ([]models.Assignment, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	assignments := make([]models.Assignment, 0)
+	for rows.Next() {
+		assignment, err := scanAssignment(rows)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, *assignment)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return assignments, nil
+}
+
+func (r *assignmentRepository) ListForSection(ctx context.Context, sectionID int64) ([]models.Assignment, error) {
+	query := "SELECT " + assignmentColumns + " FROM assignments" + assignmentJoins + " WHERE a.section_id = $1 ORDER BY a.due_at, a.id"
+	return r.queryAssignments(ctx, query, sectionID)
+}
+
+func (r *assignmentRepository) ListForStudent(ctx context.Context, studentID, termID int64) ([]models.Assignment, error) {
+	query := "SELECT " + assignmentColumns + " FROM assignments" + assignmentJoins + `
+		JOIN enrollments e ON e.section_id = s.id
+		WHERE e.student_id = $1 AND e.status = 'enrolled' AND s.term_id = $2
+		ORDER BY a.due_at, a.id`
+	return r.queryAssignments(ctx, query, studentID, termID)
+}
+
+func (r *assignmentRepository) ListForInstructor(ctx context.Context, instructorID, termID int64) ([]models.Assignment, error) {
+	query := "SELECT " + assignmentColumns + " FROM assignments" + assignmentJoins + `
+		WHERE s.instructor_id = $1 AND s.term_id = $2
+		ORDER BY a.due_at, a.id`
+	return r.queryAssignments(ctx, query, instructorID, termID)
+}
+
+func (r *assignmentRepository) IsEnrolled(ctx context.Context, sectionID, studentID int64) (bool, error) {
+	var enrolled bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM enrollments WHERE section_id = $1 AND student_id = $2 AND status = 'enrolled')
+	`, sectionID, studentID).Scan(&enrolled)
+	if err != nil {
+		return false, appErrors.ErrInternalServerError
+	}
+	return enrolled, nil
+}
+
+// loadFiles fills in the attached files of the given submissions.
+func (r *assignmentRepository) loadFiles(ctx context.Context, submissions ...*models.Submission) error {
+	if len(submissions) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*models.Submission, len(submissions))
+	ids := make([]int64, 0, len(submissions))
+	for _, submission := range submissions {
+		byID[submission.ID] = submission
+		ids = append(ids, submission.ID)
+	}
+
+	query := `
+		SELECT sf.submission_id, f.id, f.owner_id, f.storage_key, f.filename, f.content_type, f.size_bytes,
+		       f.checksum, f.purpose, f.created_at
+		FROM submission_files sf
+		JOIN files f ON f.id = sf.file_id
+		WHERE sf.submission_id = ANY($1)
+		ORDER BY sf.submission_id, sf.position`
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var submissionID int64
+		var file models.File
+		err := rows.Scan(
+			&submissionID, &file.ID, &file.OwnerID, &file.StorageKey, &file.Filename, &file.ContentType,
+			&file.SizeBytes, &file.Checksum, &file.Purpose, &file.CreatedAt,
+		)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+		submission := byID[submissionID]
+		submission.Files = append(submission.Files, file)
+	}
+
+	if rows.Err() != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *assignmentRepository) CreateSubmission(ctx context.Context, submission *models.Submission, fileIDs []int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	// Lock the assignment so that simultaneous submissions get consecutive versions, and check
+	// against the same clock that stamps the submission that it is still open
+	var open bool
+	err = tx.QueryRow(ctx, `
+		SELECT CURRENT_TIMESTAMP <= due_at
+		       OR (late_policy <> 'reject' AND (late_until IS NULL OR CURRENT_TIMESTAMP <= late_until))
+		FROM assignments
+		WHERE id = $1
+		FOR UPDATE
+	`, submission.AssignmentID).Scan(&open)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErrors.ErrNotFound
+	}
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if !open {
+		return appErrors.ErrSubmissionClosed
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO assignment_submissions (assignment_id, student_id, version, comment, late)
+		SELECT a.id, $2,
+		       COALESCE((SELECT MAX(version) FROM assignment_submissions WHERE assignment_id = a.id AND student_id = $2), 0) + 1,
+		       $3, CURRENT_TIMESTAMP > a.due_at
+		FROM assignments a
+		WHERE a.id = $1
+		RETURNING id, version, submitted_at, late
+	`, submission.AssignmentID, submission.StudentID, submission.Comment).Scan(
+		&submission.ID, &submission.Version, &submission.SubmittedAt, &submission.Late,
+	)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	for i, fileID := range fileIDs {
+		_, err := tx.Exec(ctx, "INSERT INTO submission_files (submission_id, file_id, position) VALUES ($1, $2, $3)", submission.ID, fileID, i+1)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *assignmentRepository) GetSubmission(ctx context.Context, id int64) (*models.Submission, error) {
+	submission, err := scanSubmission(r.db.QueryRow(ctx, "SELECT "+submissionColumns+" FROM assignment_submissions"+submissionJoins+" WHERE sub.id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadFiles(ctx, submission); err != nil {
+		return nil, err
+	}
+	return submission, nil
+}
+
+func (r *assignmentRepository) querySubmissions(ctx context.Context, query string, args ...interface{}) ([]models.Submission, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	submissions := make([]models.Submission, 0)
+	for rows.Next() {
+		submission, err := scanSubmission(rows)
+		if err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, *submission)
+	}
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	pointers := make([]*models.Submission, len(submissions))
+	for i := range submissions {
+		pointers[i] = &submissions[i]
+	}
+	if err := r.loadFiles(ctx, pointers...); err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+func (r *assignmentRepository) ListSubmissions(ctx context.Context, assignmentID, studentID int64) ([]models.Submission, error) {
+	query := "SELECT " + submissionColumns + " FROM assignment_submissions" + submissionJoins + `
+		WHERE sub.assignment_id = $1 AND sub.student_id = $2
+		ORDER BY sub.version DESC`
+	return r.querySubmissions(ctx, query, assignmentID, studentID)
+}
+
+func (r *assignmentRepository) LatestSubmissions(ctx context.Context, studentID, termID int64) ([]models.Submission, error) {
+	query := "SELECT DISTINCT ON (sub.assignment_id) " + submissionColumns + " FROM assignment_submissions" + submissionJoins + `
+		JOIN assignments a ON a.id = sub.assignment_id
+		JOIN course_sections s ON s.id = a.section_id
+		WHERE sub.student_id = $1 AND s.term_id = $2
+		ORDER BY sub.assignment_id, sub.version DESC`
+	return r.querySubmissions(ctx, query, studentID, termID)
+}
+
+func (r *assignmentRepository) SubmissionStatuses(ctx context.Context, assignment *models.Assignment) ([]models.SubmissionStatus, error) {
+	query := `
+		SELECT u.id, u.name, sub.id, sub.version, sub.comment, sub.submitted_at, sub.late
+		FROM (
+			SELECT student_id FROM enrollments WHERE section_id = $2 AND status = 'enrolled'
+			UNION
+			SELECT student_id FROM assignment_submissions WHERE assignment_id = $1
+		) roster
+		JOIN users u ON u.id = roster.student_id
+		LEFT JOIN LATERAL (
+			SELECT id, version, comment, submitted_at, late
+			FROM assignment_submissions
+			WHERE assignment_id = $1 AND student_id = u.id
+			ORDER BY version DESC
+			LIMIT 1
+		) sub ON TRUE
+		ORDER BY u.name, u.id`
+	rows, err := r.db.Query(ctx, query, assignment.ID, assignment.SectionID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	statuses := make([]models.SubmissionStatus, 0)
+	latest := make([]*models.Submission, 0)
+	for rows.Next() {
+		var status models.SubmissionStatus
+		var id *int64
+		var version *int
+		var comment *string
+		var submittedAt *time.Time
+		var late *bool
+		if err := rows.Scan(&status.StudentID, &status.StudentName, &id, &version, &comment, &submittedAt, &late); err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		if id != nil {
+			status.Versions = *version
+			status.Latest = &models.Submission{
+				ID: *id, AssignmentID: assignment.ID, StudentID: status.StudentID, StudentName: status.StudentName,
+				Version: *version, Comment: comment, SubmittedAt: *submittedAt, Late: *late, Files: make([]models.File, 0),
+			}
+			latest = append(latest, status.Latest)
+		}
+		statuses = append(statuses, status)
+	}
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	if err := r.loadFiles(ctx, latest...); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
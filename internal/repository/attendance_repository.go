@@ -0,0 +1,278 @@
+// internal/repository/attendance_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AttendanceRepository defines the methods for class sessions and attendance records.
+type AttendanceRepository interface {
+	// ReplaceSessions brings the section's sessions in line with the given ones. Sessions
+	// that already have attendance records are always kept.
+	ReplaceSessions(ctx context.Context, sectionID int64, sessions []models.ClassSession) error
+	GetSession(ctx context.Context, id int64) (*models.ClassSession, error)
+	ListSessions(ctx context.Context, sectionID int64) ([]models.ClassSession, error)
+	// ListRoster returns the students enrolled in the session's section, plus anyone else
+	// already marked for the session, with their attendance.
+	ListRoster(ctx context.Context, session *models.ClassSession) ([]models.AttendanceRosterEntry, error)
+	// MarkAttendance creates or overwrites the given records in one transaction.
+	MarkAttendance(ctx context.Context, records []models.AttendanceRecord) error
+	SectionSummaries(ctx context.Context, sectionID int64) ([]models.AttendanceSummary, error)
+	StudentSummaries(ctx context.Context, studentID, termID int64) ([]models.AttendanceSummary, error)
+	StudentRecords(ctx context.Context, studentID, termID int64) ([]models.StudentAttendanceEntry, error)
+}
+
+type attendanceRepository struct {
+	db *txPool
+}
+
+// NewAttendanceRepository creates a new AttendanceRepository instance.
+func NewAttendanceRepository(db *pgxpool.Pool) AttendanceRepository {
+	return &attendanceRepository{db: newTxPool(db)}
+}
+
+// attendanceCounts tallies the attendance records ar by status.
+const attendanceCounts = `COUNT(ar.id) FILTER (WHERE ar.status = 'present'), COUNT(ar.id) FILTER (WHERE ar.status = 'absent'),
+	COUNT(ar.id) FILTER (WHERE ar.status = 'late'), COUNT(ar.id) FILTER (WHERE ar.status = 'excused')`
+
+// sessionColumns selects from class_sessions cs joined with its attendance records ar and
+// grouped by session.
+const sessionColumns = `cs.id, cs.section_id, cs.meeting_id, cs.session_date, to_char(cs.start_time, 'HH24:MI'),
+	to_char(cs.end_time, 'HH24:MI'), cs.room, cs.created_at, ` + attendanceCounts
+
+const sessionJoins = ` cs LEFT JOIN attendance_records ar ON ar.session_id = cs.id`
+
+func scanSession(row pgx.Row) (*models.ClassSession, error) {
+	session := &models.ClassSession{}
+	err := row.Scan(
+		&session.ID, &session.SectionID, &session.MeetingID, &session.Date, &session.StartTime, &session.EndTime,
+		&session.Room, &session.CreatedAt,
+		&session.Counts.Present, &session.Counts.Absent, &session.Counts.Late, &session.Counts.Excused,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return session, nil
+}
+
+// summaryQuery builds the attendance summary of the student and section pairs selected by
+// the given subquery (student_id, section_id).
+func summaryQuery(pairs, orderBy string) string {
+	return `
+		SELECT u.id, u.name, s.course_id, c.code, s.term_id, s.id, s.code, ` + attendanceCounts + `
+		FROM (` + pairs + `) p
+		JOIN users u ON u.id = p.student_id
+		JOIN course_sections s ON s.id = p.section_id
+		JOIN courses c ON c.id = s.course_id
+		LEFT JOIN class_sessions cs ON cs.section_id = s.id
+		LEFT JOIN attendance_records ar ON ar.session_id = cs.id AND ar.student_id = u.id
+		GROUP BY u.id, u.name, s.course_id, c.code, s.term_id, s.id, s.code
+		ORDER BY ` + orderBy
+}
+
+func (r *attendanceRepository) ReplaceSessions(ctx context.Context, sectionID int64, sessions []models.ClassSession) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM class_sessions cs
+		WHERE cs.section_id = $1 AND NOT EXISTS (SELECT 1 FROM attendance_records ar WHERE ar.session_id = cs.id)
+	`, sectionID)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	for _, session := range sessions {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO class_sessions (section_id, meeting_id, session_date, start_time, end_time, room)
+			VALUES ($1, $2, $3, $4::time, $5::time, $6)
+			ON CONFLICT (section_id, session_date, start_time) DO NOTHING
+		`, sectionID, session.MeetingID, session.Date, session.StartTime, session.EndTime, session.Room)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *attendanceRepository) GetSession(ctx context.Context, id int64) (*models.ClassSession, error) {
+	query := "SELECT " + sessionColumns + " FROM class_sessions" + sessionJoins + " WHERE cs.id = $1 GROUP BY cs.id"
+	return scanSession(r.db.QueryRow(ctx, query, id))
+}
+
+func (r *attendanceRepository) ListSessions(ctx context.Context, sectionID int64) ([]models.ClassSession, error) {
+	query := "SELECT " + sessionColumns + " FROM class_sessions" + sessionJoins + `
+		WHERE cs.section_id = $1 GROUP BY cs.id ORDER BY cs.session_date, cs.start_time`
+	rows, err := r.db.Query(ctx, query, sectionID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	sessions := make([]models.ClassSession, 0)
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return sessions, nil
+}
+
+func (r *attendanceRepository) ListRoster(ctx context.Context, session *models.ClassSession) ([]models.AttendanceRosterEntry, error) {
+	query := `
+		SELECT u.id, u.name, ar.status, ar.note, ar.marked_by, ar.marked_at
+		FROM (
+			SELECT student_id FROM enrollments WHERE section_id = $2 AND status = 'enrolled'
+			UNION SELECT student_id FROM attendance_records WHERE session_id = $1
+		) p
+		JOIN users u ON u.id = p.student_id
+		LEFT JOIN attendance_records ar ON ar.session_id = $1 AND ar.student_id = u.id
+		ORDER BY u.name, u.id
+	`
+	rows, err := r.db.Query(ctx, query, session.ID, session.SectionID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	roster := make([]models.AttendanceRosterEntry, 0)
+	for rows.Next() {
+		var entry models.AttendanceRosterEntry
+		if err := rows.Scan(&entry.StudentID, &entry.StudentName, &entry.Status, &entry.Note, &entry.MarkedBy, &entry.MarkedAt); err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		roster = append(roster, entry)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return roster, nil
+}
+
+func (r *attendanceRepository) MarkAttendance(ctx context.Context, records []models.AttendanceRecord) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	for _, record := range records {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO attendance_records (session_id, student_id, status, note, marked_by)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (session_id, student_id) DO UPDATE
+			SET status = EXCLUDED.status, note = EXCLUDED.note, marked_by = EXCLUDED.marked_by, marked_at = NOW()
+		`, record.SessionID, record.StudentID, record.Status, record.Note, record.MarkedBy)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+// querySummaries runs a summary query built by summaryQuery.
+func (r *attendanceRepository) querySummaries(ctx context.Context, query string, args ...interface{}) ([]models.AttendanceSummary, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	summaries := make([]models.AttendanceSummary, 0)
+	for rows.Next() {
+		var s models.AttendanceSummary
+		err := rows.Scan(
+			&s.StudentID, &s.StudentName, &s.CourseID, &s.CourseCode, &s.TermID, &s.SectionID, &s.SectionCode,
+			&s.Counts.Present, &s.Counts.Absent, &s.Counts.Late, &s.Counts.Excused,
+		)
+		if err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		summaries = append(summaries, s)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return summaries, nil
+}
+
+func (r *attendanceRepository) SectionSummaries(ctx context.Context, sectionID int64) ([]models.AttendanceSummary, error) {
+	pairs := `
+		SELECT student_id, section_id FROM enrollments WHERE section_id = $1 AND status = 'enrolled'
+		UNION SELECT ar.student_id, cs.section_id FROM attendance_records ar
+		JOIN class_sessions cs ON cs.id = ar.session_id WHERE cs.section_id = $1`
+	return r.querySummaries(ctx, summaryQuery(pairs, "u.name, u.id"), sectionID)
+}
+
+func (r *attendanceRepository) StudentSummaries(ctx context.Context, studentID, termID int64) ([]models.AttendanceSummary, error) {
+	pairs := `
+		SELECT student_id, section_id FROM enrollments
+		WHERE student_id = $1 AND term_id = $2 AND status = 'enrolled' AND section_id IS NOT NULL
+		UNION SELECT ar.student_id, cs.section_id FROM attendance_records ar
+		JOIN class_sessions cs ON cs.id = ar.session_id JOIN course_sections rs ON rs.id = cs.section_id
+		WHERE ar.student_id = $1 AND rs.term_id = $2`
+	return r.querySummaries(ctx, summaryQuery(pairs, "c.code, s.code"), studentID, termID)
+}
+
+func (r *attendanceRepository) StudentRecords(ctx context.Context, studentID, termID int64) ([]models.StudentAttendanceEntry, error) {
+	query := `
+		SELECT cs.id, s.course_id, c.code, s.code, cs.session_date, to_char(cs.start_time, 'HH24:MI'),
+		       to_char(cs.end_time, 'HH24:MI'), ar.status, ar.note
+		FROM attendance_records ar
+		JOIN class_sessions cs ON cs.id = ar.session_id
+		JOIN course_sections s ON s.id = cs.section_id
+		JOIN courses c ON c.id = s.course_id
+		WHERE ar.student_id = $1 AND s.term_id = $2
+		ORDER BY cs.session_date, cs.start_time, c.code
+	`
+	rows, err := r.db.Query(ctx, query, studentID, termID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	entries := make([]models.StudentAttendanceEntry, 0)
+	for rows.Next() {
+		var e models.StudentAttendanceEntry
+		err := rows.Scan(&e.SessionID, &e.CourseID, &e.CourseCode, &e.SectionCode, &e.Date, &e.StartTime, &e.EndTime, &e.Status, &e.Note)
+		if err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		entries = append(entries, e)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return entries, nil
+}
@@ -0,0 +1,82 @@
+// internal/repository/calendar_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CalendarRepository defines the methods for iCalendar subscription feeds.
+type CalendarRepository interface {
+	// SaveFeed creates the user's feed or replaces its token.
+	SaveFeed(ctx context.Context, feed *models.CalendarFeed) error
+	GetFeed(ctx context.Context, userID int64) (*models.CalendarFeed, error)
+	// UseFeed looks a feed up by its token hash and records the access.
+	UseFeed(ctx context.Context, tokenHash string) (*models.CalendarFeed, error)
+	DeleteFeed(ctx context.Context, userID int64) error
+}
+
+type calendarRepository struct {
+	db *txPool
+}
+
+// NewCalendarRepository creates a new CalendarRepository instance.
+func NewCalendarRepository(db *pgxpool.Pool) CalendarRepository {
+	return &calendarRepository{db: newTxPool(db)}
+}
+
+const calendarFeedColumns = `user_id, token_hash, created_by, created_at, last_accessed_at`
+
+func scanCalendarFeed(row pgx.Row) (*models.CalendarFeed, error) {
+	feed := &models.CalendarFeed{}
+	err := row.Scan(&feed.UserID, &feed.TokenHash, &feed.CreatedBy, &feed.CreatedAt, &feed.LastAccessedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return feed, nil
+}
+
+func (r *calendarRepository) SaveFeed(ctx context.Context, feed *models.CalendarFeed) error {
+	query := `
+		INSERT INTO calendar_feeds (user_id, token_hash, created_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET token_hash = EXCLUDED.token_hash, created_by = EXCLUDED.created_by,
+		    created_at = NOW(), last_accessed_at = NULL
+		RETURNING ` + calendarFeedColumns
+	saved, err := scanCalendarFeed(r.db.QueryRow(ctx, query, feed.UserID, feed.TokenHash, feed.CreatedBy))
+	if err != nil {
+		return err
+	}
+	*feed = *saved
+	return nil
+}
+
+func (r *calendarRepository) GetFeed(ctx context.Context, userID int64) (*models.CalendarFeed, error) {
+	return scanCalendarFeed(r.db.QueryRow(ctx, "SELECT "+calendarFeedColumns+" FROM calendar_feeds WHERE user_id = $1", userID))
+}
+
+func (r *calendarRepository) UseFeed(ctx context.Context, tokenHash string) (*models.CalendarFeed, error) {
+	query := "UPDATE calendar_feeds SET last_accessed_at = NOW() WHERE token_hash = $1 RETURNING " + calendarFeedColumns
+	return scanCalendarFeed(r.db.QueryRow(ctx, query, tokenHash))
+}
+
+func (r *calendarRepository) DeleteFeed(ctx context.Context, userID int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM calendar_feeds WHERE user_id = $1", userID)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+// internal/repository/check_in_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CheckInRepository defines the methods for self check-in windows and attempts.
+type CheckInRepository interface {
+	CreateWindow(ctx context.Context, window *models.CheckInWindow) error
+	// GetOpenWindow returns the session's window that has not closed yet.
+	GetOpenWindow(ctx context.Context, sessionID int64) (*models.CheckInWindow, error)
+	CloseWindow(ctx context.Context, sessionID int64) error
+	// ListOpenWindowsForStudent returns the open windows of the sections the student is enrolled in.
+	ListOpenWindowsForStudent(ctx context.Context, studentID int64) ([]models.CheckInWindow, error)
+	CountFailedAttempts(ctx context.Context, windowID, studentID int64) (int, error)
+	RecordAttempt(ctx context.Context, attempt *models.CheckInAttempt) error
+	// CheckIn records the successful attempt and marks the student with the record in one
+	// transaction. It fails if the student already checked in or was already marked.
+	CheckIn(ctx context.Context, attempt *models.CheckInAttempt, record *models.AttendanceRecord) error
+}
+
+type checkInRepository struct {
+	db *txPool
+}
+
+// NewCheckInRepository creates a new CheckInRepository instance.
+func NewCheckInRepository(db *pgxpool.Pool) CheckInRepository {
+	return &checkInRepository{db: newTxPool(db)}
+}
+
+// checkInWindowColumns selects from check_in_windows w joined with class_sessions cs.
+const checkInWindowColumns = `w.id, w.session_id, cs.section_id, w.opened_by, w.opened_at, w.closes_at, w.allowed_networks,
+	w.latitude, w.longitude, w.radius_meters`
+
+const checkInWindowJoins = ` w JOIN class_sessions cs ON cs.id = w.session_id`
+
+func scanCheckInWindow(row pgx.Row) (*models.CheckInWindow, error) {
+	window := &models.CheckInWindow{}
+	err := row.Scan(
+		&window.ID, &window.SessionID, &window.SectionID, &window.OpenedBy, &window.OpenedAt, &window.ClosesAt,
+		&window.AllowedNetworks, &window.Latitude, &window.Longitude, &window.RadiusMeters,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return window, nil
+}
+
+func (r *checkInRepository) CreateWindow(ctx context.Context, window *models.CheckInWindow) error {
+	query := `
+		WITH created AS (
+			INSERT INTO check_in_windows (session_id, opened_by, closes_at, allowed_networks, latitude, longitude, radius_meters)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING *
+		)
+		SELECT ` + checkInWindowColumns + ` FROM created` + checkInWindowJoins
+	created, err := scanCheckInWindow(r.db.QueryRow(ctx, query,
+		window.SessionID, window.OpenedBy, window.ClosesAt, window.AllowedNetworks,
+		window.Latitude, window.Longitude, window.RadiusMeters,
+	))
+	if err != nil {
+		return err
+	}
+	*window = *created
+	return nil
+}
+
+func (r *checkInRepository) GetOpenWindow(ctx context.Context, sessionID int64) (*models.CheckInWindow, error) {
+	query := "SELECT " + checkInWindowColumns + " FROM check_in_windows" + checkInWindowJoins + `
+		WHERE w.session_id = $1 AND w.closes_at > NOW() ORDER BY w.id DESC LIMIT 1`
+	return scanCheckInWindow(r.db.QueryRow(ctx, query, sessionID))
+}
+
+func (r *checkInRepository) CloseWindow(ctx context.Context, sessionID int64) error {
+	cmdTag, err := r.db.Exec(ctx, "UPDATE check_in_windows SET closes_at = NOW() WHERE session_id = $1 AND closes_at > NOW()", sessionID)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *checkInRepository) ListOpenWindowsForStudent(ctx context.Context, studentID int64) ([]models.CheckInWindow, error) {
+	query := "SELECT " + checkInWindowColumns + " FROM check_in_windows" + checkInWindowJoins + `
+		JOIN enrollments e ON e.section_id = cs.section_id
+		WHERE e.student_id = $1 AND e.status = 'enrolled' AND w.closes_at > NOW()
+		ORDER BY w.id`
+	rows, err := r.db.Query(ctx, query, studentID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	windows := make([]models.CheckInWindow, 0)
+	for rows.Next() {
+		window, err := scanCheckInWindow(rows)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, *window)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return windows, nil
+}
+
+func (r *checkInRepository) CountFailedAttempts(ctx context.Context, windowID, studentID int64) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM check_in_attempts WHERE window_id = $1 AND student_id = $2 AND NOT succeeded
+	`, windowID, studentID).Scan(&count)
+	if err != nil {
+		return 0, appErrors.ErrInternalServerError
+	}
+	return count, nil
+}
+
+func (r *checkInRepository) RecordAttempt(ctx context.Context, attempt *models.CheckInAttempt) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO check_in_attempts (window_id, student_id, succeeded, reason, ip_address)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, attempt.WindowID, attempt.StudentID, attempt.Succeeded, attempt.Reason, attempt.IPAddress).Scan(&attempt.ID, &attempt.CreatedAt)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *checkInRepository) CheckIn(ctx context.Context, attempt *models.CheckInAttempt, record *models.AttendanceRecord) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO check_in_attempts (window_id, student_id, succeeded, reason, ip_address)
+		VALUES ($1, $2, TRUE, NULL, $3)
+		RETURNING id, created_at
+	`, attempt.WindowID, attempt.StudentID, attempt.IPAddress).Scan(&attempt.ID, &attempt.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is unique violation
+			return appErrors.ErrAlreadyCheckedIn
+		}
+		return appErrors.ErrInternalServerError
+	}
+
+	// An instructor's mark takes precedence over a self check-in
+	err = tx.QueryRow(ctx, `
+		INSERT INTO attendance_records (session_id, student_id, status, note, marked_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (session_id, student_id) DO NOTHING
+		RETURNING id, marked_at
+	`, record.SessionID, record.StudentID, record.Status, record.Note, record.MarkedBy).Scan(&record.ID, &record.MarkedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErrors.ErrAlreadyCheckedIn
+	}
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// internal/repository/completion_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CompletionRepository defines the methods for the courses students have completed.
+type CompletionRepository interface {
+	CreateCompletion(ctx context.Context, completion *models.CourseCompletion) error
+	DeleteCompletion(ctx context.Context, studentID, id int64) (*models.CourseCompletion, error)
+	ListForStudent(ctx context.Context, studentID int64) ([]models.CourseCompletion, error)
+}
+
+type completionRepository struct {
+	db *txPool
+}
+
+// NewCompletionRepository creates a new CompletionRepository instance.
+func NewCompletionRepository(db *pgxpool.Pool) CompletionRepository {
+	return &completionRepository{db: newTxPool(db)}
+}
+
+// completionColumns selects from course_completions cc joined with courses c and, when the
+// term is known, terms t.
+const completionColumns = `cc.id, cc.student_id, cc.course_id, c.code, c.title, cc.term_id, t.code, cc.grade, cc.completed_at,
+	cc.recorded_by, cc.created_at`
+
+const completionJoins = ` JOIN courses c ON c.id = cc.course_id LEFT JOIN terms t ON t.id = cc.term_id`
+
+func scanCompletion(row pgx.Row) (*models.CourseCompletion, error) {
+	completion := &models.CourseCompletion{}
+	err := row.Scan(
+		&completion.ID, &completion.StudentID, &completion.CourseID, &completion.CourseCode, &completion.CourseTitle,
+		&completion.TermID, &completion.TermCode, &completion.Grade, &completion.CompletedAt, &completion.RecordedBy, &completion.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return completion, nil
+}
+
+func (r *completionRepository) CreateCompletion(ctx context.Context, completion *models.CourseCompletion) error {
+	query := `
+		WITH cc AS (
+			INSERT INTO course_completions (student_id, course_id, term_id, grade, completed_at, recorded_by)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING *
+		)
+		SELECT ` + completionColumns + ` FROM cc` + completionJoins
+	created, err := scanCompletion(r.db.QueryRow(ctx, query,
+		completion.StudentID, completion.CourseID, completion.TermID, completion.Grade, completion.CompletedAt, completion.RecordedBy,
+	))
+	if err != nil {
+		return err
+	}
+	*completion = *created
+	return nil
+}
+
+// DeleteCompletion deletes one of the student's completions and returns it.
+func (r *completionRepository) DeleteCompletion(ctx context.Context, studentID, id int64) (*models.CourseCompletion, error) {
+	query := `
+		WITH cc AS (
+			DELETE FROM course_completions WHERE id = $1 AND student_id = $2
+			RETURNING *
+		)
+		SELECT ` + completionColumns + ` FROM cc` + completionJoins
+	return scanCompletion(r.db.QueryRow(ctx, query, id, studentID))
+}
+
+func (r *completionRepository) ListForStudent(ctx context.Context, studentID int64) ([]models.CourseCompletion, error) {
+	query := "SELECT " + completionColumns + " FROM course_completions cc" + completionJoins + `
+		WHERE cc.student_id = $1 ORDER BY cc.completed_at, cc.id`
+	rows, err := r.db.Query(ctx, query, studentID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	completions := make([]models.CourseCompletion, 0)
+	for rows.Next() {
+		completion, err := scanCompletion(rows)
+		if err != nil {
+			return nil, err
+		}
+		completions = append(completions, *completion)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return completions, nil
+}
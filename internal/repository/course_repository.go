@@ -0,0 +1,221 @@
+// internal/repository/course_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CourseRepository defines the methods for interacting with the course catalog.
+type CourseRepository interface {
+	CreateCourse(ctx context.Context, course *models.Course) error
+	GetCourseByID(ctx context.Context, id int64) (*models.Course, error)
+	UpdateCourse(ctx context.Context, course *models.Course) error
+	DeleteCourse(ctx context.Context, id int64) error
+	ListCourses(ctx context.Context, filter models.CourseFilter, limit, offset int) ([]models.Course, int64, error)
+	GetCourseCodes(ctx context.Context, ids []int64) (map[int64]string, error)
+	SetRequirements(ctx context.Context, courseID int64, requirements *models.CourseRequirements) error
+}
+
+type courseRepository struct {
+	db *txPool
+}
+
+// NewCourseRepository creates a new CourseRepository instance.
+func NewCourseRepository(db *pgxpool.Pool) CourseRepository {
+	return &courseRepository{db: newTxPool(db)}
+}
+
+const courseColumns = `id, code, title, description, credits, department, is_active, capacity,
+	prerequisites, corequisite_ids, created_by, created_at, updated_at`
+
+func scanCourse(row pgx.Row) (*models.Course, error) {
+	course := &models.Course{}
+	err := row.Scan(
+		&course.ID, &course.Code, &course.Title, &course.Description, &course.Credits, &course.Department,
+		&course.IsActive, &course.Capacity, &course.Prerequisites, &course.CorequisiteIDs, &course.CreatedBy, &course.CreatedAt, &course.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return course, nil
+}
+
+// courseWriteError maps constraint violations on course writes to application errors.
+func courseWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is unique violation
+		return appErrors.ErrCourseCodeExists
+	}
+	return appErrors.ErrInternalServerError
+}
+
+func (r *courseRepository) CreateCourse(ctx context.Context, course *models.Course) error {
+	query := `
+		INSERT INTO courses (code, title, description, credits, department, is_active, capacity, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		course.Code, course.Title, course.Description, course.Credits, course.Department, course.IsActive,
+		course.Capacity, course.CreatedBy,
+	).Scan(&course.ID, &course.CreatedAt, &course.UpdatedAt)
+	if err != nil {
+		return courseWriteError(err)
+	}
+	return nil
+}
+
+func (r *courseRepository) GetCourseByID(ctx context.Context, id int64) (*models.Course, error) {
+	return scanCourse(r.db.QueryRow(ctx, "SELECT "+courseColumns+" FROM courses WHERE id = $1", id))
+}
+
+func (r *courseRepository) UpdateCourse(ctx context.Context, course *models.Course) error {
+	query := `
+		UPDATE courses
+		SET code = $2, title = $3, description = $4, credits = $5, department = $6, is_active = $7,
+		    capacity = $8, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		course.ID, course.Code, course.Title, course.Description, course.Credits, course.Department, course.IsActive,
+		course.Capacity,
+	).Scan(&course.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErrors.ErrNotFound
+	}
+	if err != nil {
+		return courseWriteError(err)
+	}
+	return nil
+}
+
+func (r *courseRepository) DeleteCourse(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM courses WHERE id = $1", id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+			return appErrors.ErrCourseInUse
+		}
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+// ListCourses returns a page of courses matching the filter. With a search term the best
+// matches come first, otherwise courses are ordered by code.
+func (r *courseRepository) ListCourses(ctx context.Context, filter models.CourseFilter, limit, offset int) ([]models.Course, int64, error) {
+	conditions := make([]string, 0, 3)
+	args := make([]interface{}, 0, 5)
+	orderBy := "code"
+
+	if !filter.IncludeInactive {
+		conditions = append(conditions, "is_active")
+	}
+	if filter.Department != "" {
+		args = append(args, filter.Department)
+		conditions = append(conditions, fmt.Sprintf("department ILIKE $%d", len(args)))
+	}
+	if filter.Search != "" {
+		args = append(args, filter.Search)
+		n := len(args)
+		conditions = append(conditions, fmt.Sprintf(
+			"(search_vector @@ websearch_to_tsquery('english', $%d) OR code ILIKE $%d || '%%')", n, n,
+		))
+		orderBy = fmt.Sprintf("ts_rank(search_vector, websearch_to_tsquery('english', $%d)) DESC, code", n)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Query to count matching courses
+	var totalCount int64
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM courses"+where, args...).Scan(&totalCount); err != nil {
+		return nil, 0, appErrors.ErrInternalServerError
+	}
+
+	// Query to get the page
+	args = append(args, limit, offset)
+	query := fmt.Sprintf("SELECT %s FROM courses%s ORDER BY %s LIMIT $%d OFFSET $%d",
+		courseColumns, where, orderBy, len(args)-1, len(args))
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	courses := make([]models.Course, 0)
+	for rows.Next() {
+		course, err := scanCourse(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		courses = append(courses, *course)
+	}
+
+	if rows.Err() != nil {
+		return nil, 0, appErrors.ErrInternalServerError
+	}
+	return courses, totalCount, nil
+}
+
+// GetCourseCodes returns the codes of the given courses by ID; unknown IDs are left out.
+func (r *courseRepository) GetCourseCodes(ctx context.Context, ids []int64) (map[int64]string, error) {
+	rows, err := r.db.Query(ctx, "SELECT id, code FROM courses WHERE id = ANY($1)", ids)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	codes := make(map[int64]string, len(ids))
+	for rows.Next() {
+		var (
+			id   int64
+			code string
+		)
+		if err := rows.Scan(&id, &code); err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		codes[id] = code
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return codes, nil
+}
+
+func (r *courseRepository) SetRequirements(ctx context.Context, courseID int64, requirements *models.CourseRequirements) error {
+	corequisiteIDs := requirements.CorequisiteIDs
+	if corequisiteIDs == nil {
+		corequisiteIDs = []int64{}
+	}
+	cmdTag, err := r.db.Exec(ctx, `
+		UPDATE courses SET prerequisites = $2, corequisite_ids = $3, updated_at = NOW() WHERE id = $1
+	`, courseID, requirements.Prerequisites, corequisiteIDs)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+// internal/repository/enrollment_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EnrollmentRepository defines the methods for course enrollments and waitlists.
+//
+// Enrollments belong to a course offering, i.e. a course in a term. Every method that changes
+// who holds a seat locks the offering row first, so enrollments, drops and promotions of one
+// offering are serialized and its capacity cannot be exceeded.
+type EnrollmentRepository interface {
+	Enroll(ctx context.Context, courseID, termID, studentID int64, sectionID *int64, override *models.RequirementOverride) (*models.Enrollment, error)
+	Drop(ctx context.Context, courseID, termID, studentID, droppedBy int64) (*models.Enrollment, []models.Enrollment, error)
+	FillSeats(ctx context.Context, courseID, termID int64) ([]models.Enrollment, error)
+	GetEnrollment(ctx context.Context, courseID, termID, studentID int64) (*models.Enrollment, error)
+	// SetSection moves an enrollment to another section of the same offering; the seat is kept.
+	SetSection(ctx context.Context, id, sectionID int64) (*models.Enrollment, error)
+	ListForStudent(ctx context.Context, studentID int64) ([]models.Enrollment, error)
+	ListForCourse(ctx context.Context, courseID, termID int64, status string) ([]models.Enrollment, error)
+}
+
+type enrollmentRepository struct {
+	db *txPool
+}
+
+// NewEnrollmentRepository creates a new EnrollmentRepository instance.
+func NewEnrollmentRepository(db *pgxpool.Pool) EnrollmentRepository {
+	return &enrollmentRepository{db: newTxPool(db)}
+}
+
+// enrollmentColumns selects from enrollments e joined with courses c, terms t, users u and,
+// when one is chosen, the section sec.
+// The waitlist position counts the waitlisted rows up to and including this one.
+const enrollmentColumns = `e.id, e.course_id, c.code, c.title, e.term_id, t.code, e.section_id, sec.code, e.student_id, u.name, e.status,
+	CASE WHEN e.status = 'waitlisted' THEN (
+		SELECT COUNT(*) FROM enrollments w
+		WHERE w.course_id = e.course_id AND w.term_id = e.term_id AND w.status = 'waitlisted' AND (w.waitlisted_at, w.id) <= (e.waitlisted_at, e.id)
+	) END,
+	e.enrolled_at, e.waitlisted_at, e.dropped_at, e.dropped_by, e.override_by, e.override_justification,
+	e.created_at, e.updated_at`
+
+const enrollmentJoins = ` e JOIN courses c ON c.id = e.course_id JOIN terms t ON t.id = e.term_id JOIN users u ON u.id = e.student_id
+	LEFT JOIN course_sections sec ON sec.id = e.section_id`
+
+func scanEnrollment(row pgx.Row) (*models.Enrollment, error) {
+	enrollment := &models.Enrollment{}
+	err := row.Scan(
+		&enrollment.ID, &enrollment.CourseID, &enrollment.CourseCode, &enrollment.CourseTitle, &enrollment.TermID,
+		&enrollment.TermCode, &enrollment.SectionID, &enrollment.SectionCode, &enrollment.StudentID,
+		&enrollment.StudentName, &enrollment.Status, &enrollment.WaitlistPosition, &enrollment.EnrolledAt,
+		&enrollment.WaitlistedAt, &enrollment.DroppedAt, &enrollment.DroppedBy, &enrollment.OverrideBy,
+		&enrollment.OverrideJustification, &enrollment.CreatedAt, &enrollment.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return enrollment, nil
+}
+
+func scanEnrollments(rows pgx.Rows) ([]models.Enrollment, error) {
+	defer rows.Close()
+
+	enrollments := make([]models.Enrollment, 0)
+	for rows.Next() {
+		enrollment, err := scanEnrollment(rows)
+		if err != nil {
+			return nil, err
+		}
+		enrollments = append(enrollments, *enrollment)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return enrollments, nil
+}
+
+// lockOffering locks the offering row for the rest of the transaction and returns its capacity.
+func lockOffering(ctx context.Context, tx pgx.Tx, courseID, termID int64) (*int, error) {
+	var capacity *int
+	err := tx.QueryRow(ctx, "SELECT capacity FROM course_offerings WHERE course_id = $1 AND term_id = $2 FOR UPDATE", courseID, termID).Scan(&capacity)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return capacity, nil
+}
+
+// Enroll gives the student a seat if one is free and nobody is waiting for it, and puts
+// them at the end of the waitlist otherwise. A previously dropped enrollment is reused.
+// The override, if any, records who waived the course's requirements.
+func (r *enrollmentRepository) Enroll(ctx context.Context, courseID, termID, studentID int64, sectionID *int64, override *models.RequirementOverride) (*models.Enrollment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	// 1. Lock the offering so that concurrent enrollments see each other's seats
+	capacity, err := lockOffering(ctx, tx, courseID, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Reject students who already hold a seat or a waitlist place
+	var active bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM enrollments WHERE course_id = $1 AND term_id = $2 AND student_id = $3 AND status <> 'dropped'
+		)
+	`, courseID, termID, studentID).Scan(&active)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	if active {
+		return nil, appErrors.ErrAlreadyEnrolled
+	}
+
+	// 3. Decide between a seat and the waitlist
+	var enrolled, waiting int
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE status = 'enrolled'), COUNT(*) FILTER (WHERE status = 'waitlisted')
+		FROM enrollments WHERE course_id = $1 AND term_id = $2
+	`, courseID, termID).Scan(&enrolled, &waiting)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	status := "enrolled"
+	if waiting > 0 || (capacity != nil && enrolled >= *capacity) {
+		status = "waitlisted"
+	}
+
+	// 4. Insert or reactivate the enrollment
+	var (
+		overrideBy            *int64
+		overrideJustification *string
+	)
+	if override != nil {
+		overrideBy, overrideJustification = &override.By, &override.Justification
+	}
+	var id int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO enrollments (course_id, term_id, student_id, section_id, status, enrolled_at, waitlisted_at, override_by, override_justification)
+		VALUES ($1, $2, $3, $4, $5::varchar,
+		        CASE WHEN $5::varchar = 'enrolled' THEN NOW() END,
+		        CASE WHEN $5::varchar = 'waitlisted' THEN NOW() END,
+		        $6, $7)
+		ON CONFLICT (course_id, term_id, student_id) DO UPDATE SET
+			section_id = EXCLUDED.section_id,
+			status = EXCLUDED.status,
+			enrolled_at = EXCLUDED.enrolled_at,
+			waitlisted_at = EXCLUDED.waitlisted_at,
+			dropped_at = NULL,
+			dropped_by = NULL,
+			override_by = EXCLUDED.override_by,
+			override_justification = EXCLUDED.override_justification,
+			updated_at = NOW()
+		RETURNING id
+	`, courseID, termID, studentID, sectionID, status, overrideBy, overrideJustification).Scan(&id)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	enrollment, err := scanEnrollment(tx.QueryRow(ctx, "SELECT "+enrollmentColumns+" FROM enrollments"+enrollmentJoins+" WHERE e.id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return enrollment, nil
+}
+
+// Drop removes the student from the offering or its waitlist. A freed seat goes to the
+// first students on the waitlist; the promoted enrollments are returned with the drop.
+func (r *enrollmentRepository) Drop(ctx context.Context, courseID, termID, studentID, droppedBy int64) (*models.Enrollment, []models.Enrollment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	// 1. Lock the offering so that the freed seat cannot be taken twice
+	capacity, err := lockOffering(ctx, tx, courseID, termID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 2. Drop the enrollment, remembering whether it held a seat
+	var (
+		id             int64
+		previousStatus string
+	)
+	err = tx.QueryRow(ctx, `
+		UPDATE enrollments e
+		SET status = 'dropped', dropped_at = NOW(), dropped_by = $4, updated_at = NOW()
+		FROM (SELECT id, status FROM enrollments WHERE course_id = $1 AND term_id = $2 AND student_id = $3 FOR UPDATE) old
+		WHERE e.id = old.id AND old.status <> 'dropped'
+		RETURNING e.id, old.status
+	`, courseID, termID, studentID, droppedBy).Scan(&id, &previousStatus)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, appErrors.ErrNotEnrolled
+	}
+	if err != nil {
+		return nil, nil, appErrors.ErrInternalServerError
+	}
+
+	// 3. Hand the seat to the waitlist
+	promoted := make([]models.Enrollment, 0)
+	if previousStatus == "enrolled" {
+		if promoted, err = fillSeats(ctx, tx, courseID, termID, capacity); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	dropped, err := scanEnrollment(tx.QueryRow(ctx, "SELECT "+enrollmentColumns+" FROM enrollments"+enrollmentJoins+" WHERE e.id = $1", id))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, appErrors.ErrInternalServerError
+	}
+	return dropped, promoted, nil
+}
+
+// FillSeats promotes waitlisted students into any free seats, e.g. after the capacity was raised.
+func (r *enrollmentRepository) FillSeats(ctx context.Context, courseID, termID int64) ([]models.Enrollment, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	capacity, err := lockOffering(ctx, tx, courseID, termID)
+	if err != nil {
+		return nil, err
+	}
+	promoted, err := fillSeats(ctx, tx, courseID, termID, capacity)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return promoted, nil
+}
+
+// fillSeats promotes waitlisted students in waitlist order until the offering is full.
+// The offering row must already be locked by the transaction.
+func fillSeats(ctx context.Context, tx pgx.Tx, courseID, termID int64, capacity *int) ([]models.Enrollment, error) {
+	// A NULL limit promotes the whole waitlist
+	var free *int
+	if capacity != nil {
+		var enrolled int
+		query := "SELECT COUNT(*) FROM enrollments WHERE course_id = $1 AND term_id = $2 AND status = 'enrolled'"
+		if err := tx.QueryRow(ctx, query, courseID, termID).Scan(&enrolled); err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		seats := *capacity - enrolled
+		if seats <= 0 {
+			return []models.Enrollment{}, nil
+		}
+		free = &seats
+	}
+
+	rows, err := tx.Query(ctx, `
+		WITH promoted AS (
+			UPDATE enrollments
+			SET status = 'enrolled', enrolled_at = NOW(), updated_at = NOW()
+			WHERE id IN (
+				SELECT id FROM enrollments
+				WHERE course_id = $1 AND term_id = $2 AND status = 'waitlisted'
+				ORDER BY waitlisted_at, id
+				LIMIT $3
+			)
+			RETURNING *
+		)
+		SELECT `+enrollmentColumns+` FROM promoted`+enrollmentJoins+` ORDER BY e.waitlisted_at, e.id`,
+		courseID, termID, free,
+	)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return scanEnrollments(rows)
+}
+
+func (r *enrollmentRepository) GetEnrollment(ctx context.Context, courseID, termID, studentID int64) (*models.Enrollment, error) {
+	query := "SELECT " + enrollmentColumns + " FROM enrollments" + enrollmentJoins + " WHERE e.course_id = $1 AND e.term_id = $2 AND e.student_id = $3"
+	return scanEnrollment(r.db.QueryRow(ctx, query, courseID, termID, studentID))
+}
+
+func (r *enrollmentRepository) SetSection(ctx context.Context, id, sectionID int64) (*models.Enrollment, error) {
+	query := `
+		WITH updated AS (
+			UPDATE enrollments SET section_id = $2, updated_at = NOW()
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + enrollmentColumns + " FROM updated" + enrollmentJoins
+	return scanEnrollment(r.db.QueryRow(ctx, query, id, sectionID))
+}
+
+func (r *enrollmentRepository) ListForStudent(ctx context.Context, studentID int64) ([]models.Enrollment, error) {
+	query := "SELECT " + enrollmentColumns + " FROM enrollments" + enrollmentJoins + " WHERE e.student_id = $1 ORDER BY t.start_date DESC, c.code"
+	rows, err := r.db.Query(ctx, query, studentID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return scanEnrollments(rows)
+}
+
+// ListForCourse lists the enrollments of a course offering, optionally only those with the
+// given status. Waitlisted students are listed in waitlist order after the enrolled ones.
+func (r *enrollmentRepository) ListForCourse(ctx context.Context, courseID, termID int64, status string) ([]models.Enrollment, error) {
+	query := "SELECT " + enrollmentColumns + " FROM enrollments" + enrollmentJoins + `
+		WHERE e.course_id = $1 AND e.term_id = $2 AND ($3::varchar = '' OR e.status = $3::varchar)
+		ORDER BY CASE e.status WHEN 'enrolled' THEN 0 WHEN 'waitlisted' THEN 1 ELSE 2 END,
+		         e.waitlisted_at, u.name, e.id`
+	rows, err := r.db.Query(ctx, query, courseID, termID, status)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return scanEnrollments(rows)
+}
@@ -0,0 +1,81 @@
+// internal/repository/file_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// FileRepository defines the methods for interacting with the file metadata store.
+type FileRepository interface {
+	CreateFile(ctx context.Context, file *models.File) error
+	GetFileByID(ctx context.Context, id int64) (*models.File, error)
+	GetFileByKey(ctx context.Context, key string) (*models.File, error)
+	DeleteFile(ctx context.Context, id int64) error
+}
+
+type fileRepository struct {
+	db *txPool
+}
+
+// NewFileRepository creates a new FileRepository instance.
+func NewFileRepository(db *pgxpool.Pool) FileRepository {
+	return &fileRepository{db: newTxPool(db)}
+}
+
+const fileColumns = `id, owner_id, storage_key, filename, content_type, size_bytes, checksum, purpose, created_at`
+
+func scanFile(row pgx.Row) (*models.File, error) {
+	file := &models.File{}
+	err := row.Scan(
+		&file.ID, &file.OwnerID, &file.StorageKey, &file.Filename, &file.ContentType,
+		&file.SizeBytes, &file.Checksum, &file.Purpose, &file.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return file, nil
+}
+
+func (r *fileRepository) CreateFile(ctx context.Context, file *models.File) error {
+	query := `
+		INSERT INTO files (owner_id, storage_key, filename, content_type, size_bytes, checksum, purpose)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		file.OwnerID, file.StorageKey, file.Filename, file.ContentType, file.SizeBytes, file.Checksum, file.Purpose,
+	).Scan(&file.ID, &file.CreatedAt)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *fileRepository) GetFileByID(ctx context.Context, id int64) (*models.File, error) {
+	return scanFile(r.db.QueryRow(ctx, "SELECT "+fileColumns+" FROM files WHERE id = $1", id))
+}
+
+func (r *fileRepository) GetFileByKey(ctx context.Context, key string) (*models.File, error) {
+	return scanFile(r.db.QueryRow(ctx, "SELECT "+fileColumns+" FROM files WHERE storage_key = $1", key))
+}
+
+func (r *fileRepository) DeleteFile(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM files WHERE id = $1", id)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,459 @@
+// internal/repository/gradebook_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GradebookRepository defines the methods for grade scales, categories, grades and the
+// computed course grades of sections.
+type GradebookRepository interface {
+	// GetGradeScale returns the course's own scale, best letter first; empty when it uses the default.
+	GetGradeScale(ctx context.Context, courseID int64) ([]models.GradeScaleEntry, error)
+	SetGradeScale(ctx context.Context, courseID int64, entries []models.GradeScaleEntry) error
+	// ListOpenSectionIDs returns the sections of the course in terms that have not ended.
+	ListOpenSectionIDs(ctx context.Context, courseID int64) ([]int64, error)
+
+	ListCategories(ctx context.Context, sectionID int64) ([]models.GradeCategory, error)
+	// ReplaceCategories updates the categories with an ID, creates the others and deletes the
+	// section's remaining categories in one transaction.
+	ReplaceCategories(ctx context.Context, sectionID int64, categories []models.GradeCategory) error
+
+	ListAssignmentGrades(ctx context.Context, assignmentID int64) ([]models.AssignmentGrade, error)
+	// ListSectionGrades returns the grades of every assignment of the section.
+	ListSectionGrades(ctx context.Context, sectionID int64) ([]models.AssignmentGrade, error)
+	ListStudentGrades(ctx context.Context, sectionID, studentID int64) ([]models.AssignmentGrade, error)
+	// SaveGrades creates or replaces the grades in one transaction. With release, they are
+	// released too; grades released earlier stay released.
+	SaveGrades(ctx context.Context, grades []models.AssignmentGrade, release bool) error
+	// ReleaseGrades releases the assignment's unreleased grades and returns them.
+	ReleaseGrades(ctx context.Context, assignmentID int64) ([]models.AssignmentGrade, error)
+
+	// ListCourseGrades returns the enrolled students of the section with their computed course grades.
+	ListCourseGrades(ctx context.Context, sectionID int64) ([]models.SectionGrade, error)
+	// SaveCourseGrades stores the computed course grades of the section. Rows of students no
+	// longer listed are removed unless their grade was posted.
+	SaveCourseGrades(ctx context.Context, sectionID int64, grades []models.SectionGrade) error
+	// PostCourseGrades records the final grades as course completions, replacing the
+	// completions of earlier postings, in one transaction.
+	PostCourseGrades(ctx context.Context, section *models.Section, grades []models.SectionGrade, postedBy int64) error
+	// ListStudentSectionIDs returns the sections the student is enrolled in during the term.
+	ListStudentSectionIDs(ctx context.Context, studentID, termID int64) ([]int64, error)
+}
+
+type gradebookRepository struct {
+	db *txPool
+}
+
+// NewGradebookRepository creates a new GradebookRepository instance.
+func NewGradebookRepository(db *pgxpool.Pool) GradebookRepository {
+	return &gradebookRepository{db: newTxPool(db)}
+}
+
+func (r *gradebookRepository) GetGradeScale(ctx context.Context, courseID int64) ([]models.GradeScaleEntry, error) {
+	rows, err := r.db.Query(ctx, "SELECT letter, min_percent FROM grade_scales WHERE course_id = $1 ORDER BY min_percent DESC", courseID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	entries := make([]models.GradeScaleEntry, 0)
+	for rows.Next() {
+		var entry models.GradeScaleEntry
+		if err := rows.Scan(&entry.Letter, &entry.MinPercent); err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		entries = append(entries, entry)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return entries, nil
+}
+
+func (r *gradebookRepository) SetGradeScale(ctx context.Context, courseID int64, entries []models.GradeScaleEntry) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM grade_scales WHERE course_id = $1", courseID); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	for _, entry := range entries {
+		_, err := tx.Exec(ctx, "INSERT INTO grade_scales (course_id, letter, min_percent) VALUES ($1, $2, $3)", courseID, entry.Letter, entry.MinPercent)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *gradebookRepository) queryIDs(ctx context.Context, query string, args ...interface{}) ([]int64, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		ids = append(ids, id)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return ids, nil
+}
+
+func (r *gradebookRepository) ListOpenSectionIDs(ctx context.Context, courseID int64) ([]int64, error) {
+	return r.queryIDs(ctx, `
+		SELECT s.id FROM course_sections s JOIN terms t ON t.id = s.term_id
+		WHERE s.course_id = $1 AND t.end_date >= CURRENT_DATE
+		ORDER BY s.id
+	`, courseID)
+}
+
+func (r *gradebookRepository) ListStudentSectionIDs(ctx context.Context, studentID, termID int64) ([]int64, error) {
+	return r.queryIDs(ctx, `
+		SELECT e.section_id FROM enrollments e
+		JOIN course_sections s ON s.id = e.section_id
+		JOIN courses c ON c.id = s.course_id
+		WHERE e.student_id = $1 AND e.term_id = $2 AND e.status = 'enrolled'
+		ORDER BY c.code, s.code
+	`, studentID, termID)
+}
+
+const gradeCategoryColumns = `id, section_id, name, weight, drop_lowest, position`
+
+func (r *gradebookRepository) ListCategories(ctx context.Context, sectionID int64) ([]models.GradeCategory, error) {
+	rows, err := r.db.Query(ctx, "SELECT "+gradeCategoryColumns+" FROM grade_categories WHERE section_id = $1 ORDER BY position, id", sectionID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	categories := make([]models.GradeCategory, 0)
+	for rows.Next() {
+		var c models.GradeCategory
+		if err := rows.Scan(&c.ID, &c.SectionID, &c.Name, &c.Weight, &c.DropLowest, &c.Position); err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		categories = append(categories, c)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return categories, nil
+}
+
+func (r *gradebookRepository) ReplaceCategories(ctx context.Context, sectionID int64, categories []models.GradeCategory) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	// Remove the categories that are not kept first, so that their names can be reused
+	kept := make([]int64, 0, len(categories))
+	for _, c := range categories {
+		if c.ID != 0 {
+			kept = append(kept, c.ID)
+		}
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM grade_categories WHERE section_id = $1 AND NOT (id = ANY($2))", sectionID, kept); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// Park the kept names so that categories can swap names
+	if _, err := tx.Exec(ctx, "UPDATE grade_categories SET name = '#' || id WHERE section_id = $1", sectionID); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	for i := range categories {
+		c := &categories[i]
+		c.SectionID = sectionID
+		if c.ID != 0 {
+			cmdTag, err := tx.Exec(ctx, `
+				UPDATE grade_categories SET name = $3, weight = $4, drop_lowest = $5, position = $6
+				WHERE id = $1 AND section_id = $2
+			`, c.ID, sectionID, c.Name, c.Weight, c.DropLowest, c.Position)
+			if err != nil {
+				return categoryWriteError(err)
+			}
+			if cmdTag.RowsAffected() == 0 {
+				return appErrors.New(404, "Grade category %d does not belong to this section", c.ID)
+			}
+			continue
+		}
+		err := tx.QueryRow(ctx, `
+			INSERT INTO grade_categories (section_id, name, weight, drop_lowest, position)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, sectionID, c.Name, c.Weight, c.DropLowest, c.Position).Scan(&c.ID)
+		if err != nil {
+			return categoryWriteError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+// categoryWriteError maps constraint violations on category writes to application errors.
+func categoryWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is unique violation
+		return appErrors.ErrGradeCategoryExists
+	}
+	return appErrors.ErrInternalServerError
+}
+
+// assignmentGradeColumns selects from assignment_grades g joined with the student u.
+const assignmentGradeColumns = `g.id, g.assignment_id, g.student_id, u.name, g.submission_id, g.points, g.late_penalty,
+	g.excused, g.feedback, g.graded_by, g.graded_at, g.released_at`
+
+const assignmentGradeJoins = ` g JOIN users u ON u.id = g.student_id`
+
+func scanAssignmentGrade(row pgx.Row) (*models.AssignmentGrade, error) {
+	grade := &models.AssignmentGrade{}
+	err := row.Scan(
+		&grade.ID, &grade.AssignmentID, &grade.StudentID, &grade.StudentName, &grade.SubmissionID, &grade.Points,
+		&grade.LatePenalty, &grade.Excused, &grade.Feedback, &grade.GradedBy, &grade.GradedAt, &grade.ReleasedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return grade, nil
+}
+
+func (r *gradebookRepository) queryGrades(ctx context.Context, query string, args ...interface{}) ([]models.AssignmentGrade, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	grades := make([]models.AssignmentGrade, 0)
+	for rows.Next() {
+		grade, err := scanAssignmentGrade(rows)
+		if err != nil {
+			return nil, err
+		}
+		grades = append(grades, *grade)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return grades, nil
+}
+
+func (r *gradebookRepository) ListAssignmentGrades(ctx context.Context, assignmentID int64) ([]models.AssignmentGrade, error) {
+	query := "SELECT " + assignmentGradeColumns + " FROM assignment_grades" + assignmentGradeJoins + `
+		WHERE g.assignment_id = $1 ORDER BY u.name, u.id`
+	return r.queryGrades(ctx, query, assignmentID)
+}
+
+func (r *gradebookRepository) ListSectionGrades(ctx context.Context, sectionID int64) ([]models.AssignmentGrade, error) {
+	query := "SELECT " + assignmentGradeColumns + " FROM assignment_grades" + assignmentGradeJoins + `
+		JOIN assignments a ON a.id = g.assignment_id
+		WHERE a.section_id = $1 ORDER BY g.student_id, g.assignment_id`
+	return r.queryGrades(ctx, query, sectionID)
+}
+
+func (r *gradebookRepository) ListStudentGrades(ctx context.Context, sectionID, studentID int64) ([]models.AssignmentGrade, error) {
+	query := "SELECT " + assignmentGradeColumns + " FROM assignment_grades" + assignmentGradeJoins + `
+		JOIN assignments a ON a.id = g.assignment_id
+		WHERE a.section_id = $1 AND g.student_id = $2 ORDER BY g.assignment_id`
+	return r.queryGrades(ctx, query, sectionID, studentID)
+}
+
+func (r *gradebookRepository) SaveGrades(ctx context.Context, grades []models.AssignmentGrade, release bool) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	for i := range grades {
+		grade := &grades[i]
+		err := tx.QueryRow(ctx, `
+			INSERT INTO assignment_grades (assignment_id, student_id, submission_id, points, late_penalty, excused, feedback, graded_by, released_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CASE WHEN $9 THEN NOW() END)
+			ON CONFLICT (assignment_id, student_id) DO UPDATE
+			SET submission_id = EXCLUDED.submission_id, points = EXCLUDED.points, late_penalty = EXCLUDED.late_penalty,
+			    excused = EXCLUDED.excused, feedback = EXCLUDED.feedback, graded_by = EXCLUDED.graded_by, graded_at = NOW(),
+			    released_at = COALESCE(assignment_grades.released_at, EXCLUDED.released_at)
+			RETURNING id, graded_at, released_at
+		`, grade.AssignmentID, grade.StudentID, grade.SubmissionID, grade.Points, grade.LatePenalty, grade.Excused,
+			grade.Feedback, grade.GradedBy, release).Scan(&grade.ID, &grade.GradedAt, &grade.ReleasedAt)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *gradebookRepository) ReleaseGrades(ctx context.Context, assignmentID int64) ([]models.AssignmentGrade, error) {
+	query := `
+		WITH g AS (
+			UPDATE assignment_grades SET released_at = NOW()
+			WHERE assignment_id = $1 AND released_at IS NULL
+			RETURNING *
+		)
+		SELECT ` + assignmentGradeColumns + ` FROM g JOIN users u ON u.id = g.student_id ORDER BY u.name, u.id`
+	return r.queryGrades(ctx, query, assignmentID)
+}
+
+func (r *gradebookRepository) ListCourseGrades(ctx context.Context, sectionID int64) ([]models.SectionGrade, error) {
+	query := `
+		SELECT $1::integer, u.id, u.name, sg.running_percent, sg.running_letter, sg.final_percent, sg.final_letter,
+		       sg.computed_at, sg.posted_letter, sg.posted_at, sg.posted_by, sg.completion_id
+		FROM enrollments e
+		JOIN users u ON u.id = e.student_id
+		LEFT JOIN section_grades sg ON sg.section_id = e.section_id AND sg.student_id = e.student_id
+		WHERE e.section_id = $1 AND e.status = 'enrolled'
+		ORDER BY u.name, u.id`
+	rows, err := r.db.Query(ctx, query, sectionID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	grades := make([]models.SectionGrade, 0)
+	for rows.Next() {
+		var g models.SectionGrade
+		err := rows.Scan(
+			&g.SectionID, &g.StudentID, &g.StudentName, &g.RunningPercent, &g.RunningLetter, &g.FinalPercent, &g.FinalLetter,
+			&g.ComputedAt, &g.PostedLetter, &g.PostedAt, &g.PostedBy, &g.CompletionID,
+		)
+		if err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		grades = append(grades, g)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return grades, nil
+}
+
+func (r *gradebookRepository) SaveCourseGrades(ctx context.Context, sectionID int64, grades []models.SectionGrade) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	studentIDs := make([]int64, 0, len(grades))
+	for _, g := range grades {
+		studentIDs = append(studentIDs, g.StudentID)
+	}
+	_, err = tx.Exec(ctx, `
+		DELETE FROM section_grades WHERE section_id = $1 AND posted_at IS NULL AND NOT (student_id = ANY($2))
+	`, sectionID, studentIDs)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	for i := range grades {
+		g := &grades[i]
+		var computedAt time.Time
+		err := tx.QueryRow(ctx, `
+			INSERT INTO section_grades (section_id, student_id, running_percent, running_letter, final_percent, final_letter)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (section_id, student_id) DO UPDATE
+			SET running_percent = EXCLUDED.running_percent, running_letter = EXCLUDED.running_letter,
+			    final_percent = EXCLUDED.final_percent, final_letter = EXCLUDED.final_letter, computed_at = NOW()
+			RETURNING computed_at
+		`, sectionID, g.StudentID, g.RunningPercent, g.RunningLetter, g.FinalPercent, g.FinalLetter).Scan(&computedAt)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+		g.ComputedAt = &computedAt
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *gradebookRepository) PostCourseGrades(ctx context.Context, section *models.Section, grades []models.SectionGrade, postedBy int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	for i := range grades {
+		g := &grades[i]
+		if g.CompletionID != nil {
+			if _, err := tx.Exec(ctx, "DELETE FROM course_completions WHERE id = $1", *g.CompletionID); err != nil {
+				return appErrors.ErrInternalServerError
+			}
+		}
+
+		var completionID int64
+		err := tx.QueryRow(ctx, `
+			INSERT INTO course_completions (student_id, course_id, term_id, grade, recorded_by)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, g.StudentID, section.CourseID, section.TermID, g.FinalLetter, postedBy).Scan(&completionID)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+
+		err = tx.QueryRow(ctx, `
+			UPDATE section_grades SET posted_letter = final_letter, posted_at = NOW(), posted_by = $3, completion_id = $4
+			WHERE section_id = $1 AND student_id = $2
+			RETURNING posted_letter, posted_at
+		`, section.ID, g.StudentID, postedBy, completionID).Scan(&g.PostedLetter, &g.PostedAt)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+		g.PostedBy = &postedBy
+		g.CompletionID = &completionID
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
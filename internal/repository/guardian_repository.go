@@ -0,0 +1,262 @@
+// internal/repository/guardian_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GuardianRepository defines the methods for student–guardian links and guardian invitations.
+type GuardianRepository interface {
+	CreateLink(ctx context.Context, link *models.GuardianLink) error
+	GetLink(ctx context.Context, id int64) (*models.GuardianLink, error)
+	FindLink(ctx context.Context, studentID, guardianID int64) (*models.GuardianLink, error)
+	UpdateLink(ctx context.Context, link *models.GuardianLink) error
+	DeleteLink(ctx context.Context, id int64) error
+	ListLinksForStudent(ctx context.Context, studentID int64) ([]models.GuardianLink, error)
+	ListLinkedStudents(ctx context.Context, guardianID int64) ([]models.LinkedStudent, error)
+	CreateInvitation(ctx context.Context, invitation *models.GuardianInvitation) error
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*models.GuardianInvitation, error)
+	// DeleteInvitation removes an invitation that has not been accepted.
+	DeleteInvitation(ctx context.Context, id int64) error
+	AcceptInvitation(ctx context.Context, invitationID, guardianID int64) (*models.GuardianLink, error)
+}
+
+type guardianRepository struct {
+	db *txPool
+}
+
+// NewGuardianRepository creates a new GuardianRepository instance.
+func NewGuardianRepository(db *pgxpool.Pool) GuardianRepository {
+	return &guardianRepository{db: newTxPool(db)}
+}
+
+const guardianLinkColumns = `id, student_id, guardian_id, relationship, consent_profile, consent_academic, created_by, created_at, updated_at`
+
+func scanGuardianLink(row pgx.Row) (*models.GuardianLink, error) {
+	link := &models.GuardianLink{}
+	err := row.Scan(
+		&link.ID, &link.StudentID, &link.GuardianID, &link.Relationship, &link.ConsentProfile, &link.ConsentAcademic,
+		&link.CreatedBy, &link.CreatedAt, &link.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return link, nil
+}
+
+const guardianInvitationColumns = `id, student_id, email, relationship, consent_profile, consent_academic, token_hash,
+	invited_by, expires_at, accepted_at, accepted_by, created_at`
+
+func scanGuardianInvitation(row pgx.Row) (*models.GuardianInvitation, error) {
+	inv := &models.GuardianInvitation{}
+	err := row.Scan(
+		&inv.ID, &inv.StudentID, &inv.Email, &inv.Relationship, &inv.ConsentProfile, &inv.ConsentAcademic, &inv.TokenHash,
+		&inv.InvitedBy, &inv.ExpiresAt, &inv.AcceptedAt, &inv.AcceptedBy, &inv.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return inv, nil
+}
+
+func (r *guardianRepository) CreateLink(ctx context.Context, link *models.GuardianLink) error {
+	query := `
+		INSERT INTO student_guardians (student_id, guardian_id, relationship, consent_profile, consent_academic, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		link.StudentID, link.GuardianID, link.Relationship, link.ConsentProfile, link.ConsentAcademic, link.CreatedBy,
+	).Scan(&link.ID, &link.CreatedAt, &link.UpdatedAt)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is unique violation
+			return appErrors.ErrGuardianLinkExists
+		}
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *guardianRepository) GetLink(ctx context.Context, id int64) (*models.GuardianLink, error) {
+	return scanGuardianLink(r.db.QueryRow(ctx, "SELECT "+guardianLinkColumns+" FROM student_guardians WHERE id = $1", id))
+}
+
+func (r *guardianRepository) FindLink(ctx context.Context, studentID, guardianID int64) (*models.GuardianLink, error) {
+	query := "SELECT " + guardianLinkColumns + " FROM student_guardians WHERE student_id = $1 AND guardian_id = $2"
+	return scanGuardianLink(r.db.QueryRow(ctx, query, studentID, guardianID))
+}
+
+func (r *guardianRepository) UpdateLink(ctx context.Context, link *models.GuardianLink) error {
+	query := `
+		UPDATE student_guardians
+		SET relationship = $2, consent_profile = $3, consent_academic = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+	err := r.db.QueryRow(ctx, query, link.ID, link.Relationship, link.ConsentProfile, link.ConsentAcademic).Scan(&link.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErrors.ErrNotFound
+	}
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *guardianRepository) DeleteLink(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM student_guardians WHERE id = $1", id)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *guardianRepository) ListLinksForStudent(ctx context.Context, studentID int64) ([]models.GuardianLink, error) {
+	rows, err := r.db.Query(ctx, "SELECT "+guardianLinkColumns+" FROM student_guardians WHERE student_id = $1 ORDER BY id", studentID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	links := make([]models.GuardianLink, 0)
+	for rows.Next() {
+		link, err := scanGuardianLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *link)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return links, nil
+}
+
+func (r *guardianRepository) ListLinkedStudents(ctx context.Context, guardianID int64) ([]models.LinkedStudent, error) {
+	query := `
+		SELECT g.id, u.id, u.name, g.relationship, g.consent_profile, g.consent_academic
+		FROM student_guardians g
+		JOIN users u ON u.id = g.student_id
+		WHERE g.guardian_id = $1 AND u.erased_at IS NULL
+		ORDER BY u.name, u.id
+	`
+	rows, err := r.db.Query(ctx, query, guardianID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	students := make([]models.LinkedStudent, 0)
+	for rows.Next() {
+		var s models.LinkedStudent
+		if err := rows.Scan(&s.LinkID, &s.StudentID, &s.Name, &s.Relationship, &s.ConsentProfile, &s.ConsentAcademic); err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		students = append(students, s)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return students, nil
+}
+
+func (r *guardianRepository) CreateInvitation(ctx context.Context, invitation *models.GuardianInvitation) error {
+	query := `
+		INSERT INTO guardian_invitations (student_id, email, relationship, consent_profile, consent_academic,
+		                                  token_hash, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + guardianInvitationColumns
+	created, err := scanGuardianInvitation(r.db.QueryRow(ctx, query,
+		invitation.StudentID, invitation.Email, invitation.Relationship, invitation.ConsentProfile,
+		invitation.ConsentAcademic, invitation.TokenHash, invitation.InvitedBy, invitation.ExpiresAt,
+	))
+	if err != nil {
+		return err
+	}
+	*invitation = *created
+	return nil
+}
+
+func (r *guardianRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*models.GuardianInvitation, error) {
+	query := "SELECT " + guardianInvitationColumns + " FROM guardian_invitations WHERE token_hash = $1"
+	return scanGuardianInvitation(r.db.QueryRow(ctx, query, tokenHash))
+}
+
+func (r *guardianRepository) DeleteInvitation(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM guardian_invitations WHERE id = $1 AND accepted_at IS NULL", id)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+// AcceptInvitation marks the invitation as used by the guardian and links the guardian to the
+// student in one transaction. An existing link is updated with the invitation's relationship
+// and consent flags. It returns ErrInvitationInvalid if the invitation expired or was used.
+func (r *guardianRepository) AcceptInvitation(ctx context.Context, invitationID, guardianID int64) (*models.GuardianLink, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	invitation, err := scanGuardianInvitation(tx.QueryRow(ctx, `
+		UPDATE guardian_invitations
+		SET accepted_at = NOW(), accepted_by = $2
+		WHERE id = $1 AND accepted_at IS NULL AND expires_at > NOW()
+		RETURNING `+guardianInvitationColumns,
+		invitationID, guardianID,
+	))
+	if err == appErrors.ErrNotFound {
+		return nil, appErrors.ErrInvitationInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := scanGuardianLink(tx.QueryRow(ctx, `
+		INSERT INTO student_guardians (student_id, guardian_id, relationship, consent_profile, consent_academic, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (student_id, guardian_id) DO UPDATE SET
+			relationship = EXCLUDED.relationship,
+			consent_profile = EXCLUDED.consent_profile,
+			consent_academic = EXCLUDED.consent_academic,
+			updated_at = NOW()
+		RETURNING `+guardianLinkColumns,
+		invitation.StudentID, guardianID, invitation.Relationship, invitation.ConsentProfile,
+		invitation.ConsentAcademic, invitation.InvitedBy,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return link, nil
+}
@@ -0,0 +1,83 @@
+// internal/repository/history_repository.go
+package repository
+
+import (
+	"context"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HistoryRepository defines the methods for interacting with the change history store.
+type HistoryRepository interface {
+	RecordChange(ctx context.Context, entry *models.ChangeHistoryEntry) error
+	ListChanges(ctx context.Context, entityType string, entityID int64, limit, offset int) ([]models.ChangeHistoryEntry, int64, error)
+}
+
+type historyRepository struct {
+	db *txPool
+}
+
+// NewHistoryRepository creates a new HistoryRepository instance.
+func NewHistoryRepository(db *pgxpool.Pool) HistoryRepository {
+	return &historyRepository{db: newTxPool(db)}
+}
+
+func (r *historyRepository) RecordChange(ctx context.Context, entry *models.ChangeHistoryEntry) error {
+	query := `
+		INSERT INTO change_history (entity_type, entity_id, action, actor_id, actor_role, request_id, changes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		entry.EntityType, entry.EntityID, entry.Action, entry.ActorID, entry.ActorRole, entry.RequestID, entry.Changes,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *historyRepository) ListChanges(ctx context.Context, entityType string, entityID int64, limit, offset int) ([]models.ChangeHistoryEntry, int64, error) {
+	// Query to count total entries
+	var totalCount int64
+	countQuery := "SELECT COUNT(*) FROM change_history WHERE entity_type = $1 AND entity_id = $2"
+	if err := r.db.QueryRow(ctx, countQuery, entityType, entityID).Scan(&totalCount); err != nil {
+		return nil, 0, appErrors.ErrInternalServerError
+	}
+
+	// Query to get paginated entries, newest first
+	query := `
+		SELECT id, entity_type, entity_id, action, actor_id, actor_role, request_id, changes, created_at
+		FROM change_history
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.db.Query(ctx, query, entityType, entityID, limit, offset)
+	if err != nil {
+		return nil, 0, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	entries := make([]models.ChangeHistoryEntry, 0)
+	for rows.Next() {
+		entry := models.ChangeHistoryEntry{}
+		err := rows.Scan(
+			&entry.ID, &entry.EntityType, &entry.EntityID, &entry.Action, &entry.ActorID,
+			&entry.ActorRole, &entry.RequestID, &entry.Changes, &entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, appErrors.ErrInternalServerError
+		}
+		entries = append(entries, entry)
+	}
+
+	if rows.Err() != nil {
+		return nil, 0, appErrors.ErrInternalServerError
+	}
+
+	return entries, totalCount, nil
+}
@@ -0,0 +1,404 @@
+// internal/repository/merge_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MergeRepository defines the methods for duplicate account detection and merging.
+type MergeRepository interface {
+	// ListDuplicateCandidates returns up to limit pairs of accounts with the same role that share
+	// a blocking key: the email's local part, the student number, the phone number or the
+	// phonetic (soundex) key of the name. Only these pairs are worth scoring.
+	ListDuplicateCandidates(ctx context.Context, limit int) ([][2]models.DuplicateUser, error)
+	CountMergeRecords(ctx context.Context, sourceUserID int64) (map[string]int64, error)
+	MergeUsers(ctx context.Context, merge *models.UserMerge) error
+}
+
+// mergeTarget is a set of records that a merge moves from the source to the target user.
+type mergeTarget struct {
+	Name  string
+	Count string // Takes the source user ID as $1
+	Move  string // Takes the source user ID as $1 and the target user ID as $2
+}
+
+// reassign moves every row whose column references the source user to the target user.
+func reassign(table, column string) mergeTarget {
+	return mergeTarget{
+		Name:  table + "." + column,
+		Count: "SELECT COUNT(*) FROM " + table + " WHERE " + column + " = $1",
+		Move:  "UPDATE " + table + " SET " + column + " = $2 WHERE " + column + " = $1",
+	}
+}
+
+// mergeTargets lists everything that references a user and has to survive a merge.
+// Features that add tables referencing users must add them here; user_profiles is merged separately.
+var mergeTargets = []mergeTarget{
+	reassign("files", "owner_id"),
+	reassign("data_export_jobs", "user_id"),
+	reassign("data_export_jobs", "requested_by"),
+	{
+		Name:  "change_history.entity_id",
+		Count: `SELECT COUNT(*) FROM change_history WHERE entity_type = 'user' AND entity_id = $1`,
+		Move:  `UPDATE change_history SET entity_id = $2 WHERE entity_type = 'user' AND entity_id = $1`,
+	},
+	reassign("change_history", "actor_id"),
+	reassign("user_erasures", "performed_by"),
+	reassign("user_status_changes", "user_id"),
+	reassign("user_status_changes", "changed_by"),
+	{
+		// A guardian linked to both accounts keeps the target's link; the source's is removed with the source.
+		Name:  "student_guardians.student_id",
+		Count: `SELECT COUNT(*) FROM student_guardians WHERE student_id = $1`,
+		Move: `UPDATE student_guardians SET student_id = $2
+		       WHERE student_id = $1 AND guardian_id NOT IN (SELECT guardian_id FROM student_guardians WHERE student_id = $2)`,
+	},
+	{
+		Name:  "student_guardians.guardian_id",
+		Count: `SELECT COUNT(*) FROM student_guardians WHERE guardian_id = $1`,
+		Move: `UPDATE student_guardians SET guardian_id = $2
+		       WHERE guardian_id = $1 AND student_id NOT IN (SELECT student_id FROM student_guardians WHERE guardian_id = $2)`,
+	},
+	reassign("student_guardians", "created_by"),
+	reassign("guardian_invitations", "student_id"),
+	reassign("guardian_invitations", "invited_by"),
+	reassign("guardian_invitations", "accepted_by"),
+	reassign("courses", "created_by"),
+	{
+		// An offering both accounts are in keeps the target's enrollment; the source's is removed with the source.
+		Name:  "enrollments.student_id",
+		Count: `SELECT COUNT(*) FROM enrollments WHERE student_id = $1`,
+		Move: `UPDATE enrollments SET student_id = $2
+		       WHERE student_id = $1 AND (course_id, term_id) NOT IN (SELECT course_id, term_id FROM enrollments WHERE student_id = $2)`,
+	},
+	reassign("enrollments", "dropped_by"),
+	reassign("enrollments", "override_by"),
+	reassign("course_completions", "student_id"),
+	reassign("course_completions", "recorded_by"),
+	reassign("terms", "created_by"),
+	reassign("course_offerings", "created_by"),
+	reassign("course_sections", "instructor_id"),
+	{
+		// The target keeps its own feed if it has one; the source's is removed with the source.
+		Name:  "calendar_feeds.user_id",
+		Count: `SELECT COUNT(*) FROM calendar_feeds WHERE user_id = $1`,
+		Move: `UPDATE calendar_feeds SET user_id = $2
+		       WHERE user_id = $1 AND NOT EXISTS (SELECT 1 FROM calendar_feeds WHERE user_id = $2)`,
+	},
+	reassign("calendar_feeds", "created_by"),
+	{
+		// A session both accounts were marked in keeps the target's record; the source's is removed with the source.
+		Name:  "attendance_records.student_id",
+		Count: `SELECT COUNT(*) FROM attendance_records WHERE student_id = $1`,
+		Move: `UPDATE attendance_records SET student_id = $2
+		       WHERE student_id = $1 AND session_id NOT IN (SELECT session_id FROM attendance_records WHERE student_id = $2)`,
+	},
+	reassign("attendance_records", "marked_by"),
+	reassign("check_in_windows", "opened_by"),
+	{
+		// A window both accounts checked into keeps the target's success; the source's is removed with the source.
+		Name:  "check_in_attempts.student_id",
+		Count: `SELECT COUNT(*) FROM check_in_attempts WHERE student_id = $1`,
+		Move: `UPDATE check_in_attempts SET student_id = $2
+		       WHERE student_id = $1 AND NOT (succeeded AND window_id IN (SELECT window_id FROM check_in_attempts WHERE student_id = $2 AND succeeded))`,
+	},
+	reassign("assignments", "created_by"),
+	{
+		// Both accounts' versions are kept; the source's are numbered after the target's.
+		Name:  "assignment_submissions.student_id",
+		Count: `SELECT COUNT(*) FROM assignment_submissions WHERE student_id = $1`,
+		Move: `UPDATE assignment_submissions sub
+		       SET student_id = $2, version = sub.version + COALESCE((
+		           SELECT MAX(version) FROM assignment_submissions WHERE assignment_id = sub.assignment_id AND student_id = $2), 0)
+		       WHERE student_id = $1`,
+	},
+	{
+		// A review that would become a self-review, or duplicate one of the target's, is removed with the source.
+		Name:  "peer_reviews.author_id",
+		Count: `SELECT COUNT(*) FROM peer_reviews WHERE author_id = $1`,
+		Move: `UPDATE peer_reviews pr SET author_id = $2
+		       WHERE author_id = $1 AND reviewer_id <> $2
+		             AND NOT EXISTS (SELECT 1 FROM peer_reviews WHERE assignment_id = pr.assignment_id AND author_id = $2 AND reviewer_id = pr.reviewer_id)`,
+	},
+	{
+		Name:  "peer_reviews.reviewer_id",
+		Count: `SELECT COUNT(*) FROM peer_reviews WHERE reviewer_id = $1`,
+		Move: `UPDATE peer_reviews pr SET reviewer_id = $2
+		       WHERE reviewer_id = $1 AND author_id <> $2
+		             AND NOT EXISTS (SELECT 1 FROM peer_reviews WHERE assignment_id = pr.assignment_id AND author_id = pr.author_id AND reviewer_id = $2)`,
+	},
+	reassign("peer_review_settings", "created_by"),
+	{
+		// Rubric scores follow the grade they make up, so this runs before the grades are moved.
+		Name:  "rubric_scores.student_id",
+		Count: `SELECT COUNT(*) FROM rubric_scores WHERE student_id = $1`,
+		Move: `UPDATE rubric_scores SET student_id = $2
+		       WHERE student_id = $1 AND assignment_id NOT IN (SELECT assignment_id FROM assignment_grades WHERE student_id = $2)
+		             AND assignment_id NOT IN (SELECT assignment_id FROM rubric_scores WHERE student_id = $2)`,
+	},
+	reassign("rubric_scores", "scored_by"),
+	{
+		// An assignment both accounts were graded on keeps the target's grade; the source's is removed with the source.
+		Name:  "assignment_grades.student_id",
+		Count: `SELECT COUNT(*) FROM assignment_grades WHERE student_id = $1`,
+		Move: `UPDATE assignment_grades SET student_id = $2
+		       WHERE student_id = $1 AND assignment_id NOT IN (SELECT assignment_id FROM assignment_grades WHERE student_id = $2)`,
+	},
+	reassign("assignment_grades", "graded_by"),
+	{
+		// A section both accounts have a course grade in keeps the target's; the source's is removed with the source.
+		Name:  "section_grades.student_id",
+		Count: `SELECT COUNT(*) FROM section_grades WHERE student_id = $1`,
+		Move: `UPDATE section_grades SET student_id = $2
+		       WHERE student_id = $1 AND section_id NOT IN (SELECT section_id FROM section_grades WHERE student_id = $2)`,
+	},
+	reassign("section_grades", "posted_by"),
+	reassign("transcripts", "student_id"),
+	reassign("transcripts", "issued_by"),
+	reassign("question_banks", "owner_id"),
+	reassign("quizzes", "created_by"),
+	{
+		// Both accounts' attempts are kept; the source's are numbered after the target's. A source
+		// attempt in progress at a quiz the target is also taking is submitted as it stands.
+		Name:  "quiz_attempts.student_id",
+		Count: `SELECT COUNT(*) FROM quiz_attempts WHERE student_id = $1`,
+		Move: `UPDATE quiz_attempts qa
+		       SET student_id = $2, attempt_number = qa.attempt_number + COALESCE((
+		           SELECT MAX(attempt_number) FROM quiz_attempts WHERE quiz_id = qa.quiz_id AND student_id = $2), 0),
+		           status = CASE WHEN clash.id IS NULL THEN qa.status ELSE 'submitted' END,
+		           submitted_at = CASE WHEN clash.id IS NULL THEN qa.submitted_at ELSE LEAST(CURRENT_TIMESTAMP, qa.deadline_at) END
+		       FROM quiz_attempts src
+		       LEFT JOIN quiz_attempts clash ON clash.quiz_id = src.quiz_id AND clash.student_id = $2
+		           AND clash.status = 'in_progress' AND src.status = 'in_progress'
+		       WHERE src.id = qa.id AND qa.student_id = $1`,
+	},
+	reassign("quiz_responses", "graded_by"),
+	reassign("rubrics", "owner_id"),
+	reassign("user_merges", "target_user_id"),
+	reassign("user_merges", "performed_by"),
+}
+
+type mergeRepository struct {
+	db *txPool
+}
+
+// NewMergeRepository creates a new MergeRepository instance.
+func NewMergeRepository(db *pgxpool.Pool) MergeRepository {
+	return &mergeRepository{db: newTxPool(db)}
+}
+
+// duplicateKeysQuery computes the blocking keys of every account. They must stay in line with
+// the normalization used to score the pairs in the service.
+const duplicateKeysQuery = `
+	SELECT u.id, u.role,
+	       NULLIF(regexp_replace(lower(split_part(split_part(u.email, '@', 1), '+', 1)), '[^[:alnum:]]', '', 'g'), '') AS email_key,
+	       NULLIF(lower(trim(p.student_number)), '') AS number_key,
+	       CASE WHEN length(regexp_replace(p.phone, '[^0-9]', '', 'g')) >= 7
+	            THEN right(regexp_replace(p.phone, '[^0-9]', '', 'g'), 9) END AS phone_key,
+	       (SELECT string_agg(soundex(w), ' ' ORDER BY soundex(w))
+	        FROM regexp_split_to_table(lower(u.name), '[^[:alnum:]]+') AS w
+	        WHERE soundex(w) <> '') AS name_key
+	FROM users u
+	LEFT JOIN user_profiles p ON p.user_id = u.id
+	WHERE u.erased_at IS NULL
+`
+
+func (r *mergeRepository) ListDuplicateCandidates(ctx context.Context, limit int) ([][2]models.DuplicateUser, error) {
+	// One equi-join per key keeps the pairing linear in the size of the blocks rather than
+	// quadratic in the number of accounts.
+	query := `
+		WITH keyed AS (` + duplicateKeysQuery + `),
+		pairs AS (
+			SELECT a.id AS first_id, b.id AS second_id FROM keyed a JOIN keyed b ON b.email_key = a.email_key AND b.role = a.role AND b.id > a.id
+			UNION
+			SELECT a.id, b.id FROM keyed a JOIN keyed b ON b.number_key = a.number_key AND b.role = a.role AND b.id > a.id
+			UNION
+			SELECT a.id, b.id FROM keyed a JOIN keyed b ON b.phone_key = a.phone_key AND b.role = a.role AND b.id > a.id
+			UNION
+			SELECT a.id, b.id FROM keyed a JOIN keyed b ON b.name_key = a.name_key AND b.role = a.role AND b.id > a.id
+		)
+		SELECT a.id, a.name, a.email, a.role, pa.student_number, pa.phone, a.created_at,
+		       b.id, b.name, b.email, b.role, pb.student_number, pb.phone, b.created_at
+		FROM pairs
+		JOIN users a ON a.id = pairs.first_id
+		JOIN users b ON b.id = pairs.second_id
+		LEFT JOIN user_profiles pa ON pa.user_id = a.id
+		LEFT JOIN user_profiles pb ON pb.user_id = b.id
+		ORDER BY pairs.first_id, pairs.second_id
+		LIMIT $1
+	`
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	pairs := make([][2]models.DuplicateUser, 0)
+	for rows.Next() {
+		var pair [2]models.DuplicateUser
+		a, b := &pair[0], &pair[1]
+		if err := rows.Scan(
+			&a.ID, &a.Name, &a.Email, &a.Role, &a.StudentNumber, &a.Phone, &a.CreatedAt,
+			&b.ID, &b.Name, &b.Email, &b.Role, &b.StudentNumber, &b.Phone, &b.CreatedAt,
+		); err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		pairs = append(pairs, pair)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return pairs, nil
+}
+
+func (r *mergeRepository) CountMergeRecords(ctx context.Context, sourceUserID int64) (map[string]int64, error) {
+	counts := make(map[string]int64, len(mergeTargets))
+	for _, target := range mergeTargets {
+		var count int64
+		if err := r.db.QueryRow(ctx, target.Count, sourceUserID).Scan(&count); err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		if count > 0 {
+			counts[target.Name] = count
+		}
+	}
+	return counts, nil
+}
+
+// MergeUsers moves all records of the source user to the target user, merges their
+// profiles (values already set on the target win), deletes the source user and records
+// the merge, all in a single transaction. The merge's ID, source details, moved record
+// counts and timestamp are filled in on success.
+func (r *mergeRepository) MergeUsers(ctx context.Context, merge *models.UserMerge) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	// 1. Lock both users in ID order to avoid deadlocks with concurrent merges
+	rows, err := tx.Query(ctx, `
+		SELECT id, name, email, erased_at FROM users WHERE id = ANY($1) ORDER BY id FOR UPDATE
+	`, []int64{merge.SourceUserID, merge.TargetUserID})
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	found := 0
+	for rows.Next() {
+		var (
+			id          int64
+			name, email string
+			erasedAt    *time.Time
+		)
+		if err := rows.Scan(&id, &name, &email, &erasedAt); err != nil {
+			rows.Close()
+			return appErrors.ErrInternalServerError
+		}
+		if erasedAt != nil {
+			rows.Close()
+			return appErrors.ErrUserErased
+		}
+		if id == merge.SourceUserID {
+			merge.SourceName, merge.SourceEmail = name, &email
+		}
+		found++
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if found != 2 {
+		return appErrors.ErrNotFound
+	}
+
+	// 2. Merge the profiles. The student number is unique, so it is taken off the source first.
+	if err := mergeProfiles(ctx, tx, merge.SourceUserID, merge.TargetUserID); err != nil {
+		return err
+	}
+
+	// 3. Move the related records
+	merge.Records = make(map[string]int64, len(mergeTargets))
+	for _, target := range mergeTargets {
+		cmdTag, err := tx.Exec(ctx, target.Move, merge.SourceUserID, merge.TargetUserID)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+		if n := cmdTag.RowsAffected(); n > 0 {
+			merge.Records[target.Name] = n
+		}
+	}
+
+	// 4. Remove the source account and make the target's cached representations stale
+	if _, err := tx.Exec(ctx, "DELETE FROM users WHERE id = $1", merge.SourceUserID); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if _, err := tx.Exec(ctx, "UPDATE users SET version = version + 1, updated_at = NOW() WHERE id = $1", merge.TargetUserID); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	// 5. Record the merge
+	err = tx.QueryRow(ctx, `
+		INSERT INTO user_merges (source_user_id, source_name, source_email, target_user_id, performed_by, reason, records)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, merged_at
+	`, merge.SourceUserID, merge.SourceName, merge.SourceEmail, merge.TargetUserID, merge.PerformedBy, merge.Reason, merge.Records,
+	).Scan(&merge.ID, &merge.MergedAt)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+// mergeProfiles fills the target's empty profile fields from the source profile.
+// Custom field values are combined per key, with the target's values taking precedence.
+func mergeProfiles(ctx context.Context, tx pgx.Tx, sourceUserID, targetUserID int64) error {
+	var studentNumber *string
+	err := tx.QueryRow(ctx, `
+		UPDATE user_profiles s
+		SET student_number = NULL
+		FROM (SELECT student_number FROM user_profiles WHERE user_id = $1) old
+		WHERE s.user_id = $1
+		RETURNING old.student_number
+	`, sourceUserID).Scan(&studentNumber)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil // The source never created a profile
+	}
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_profiles (user_id, phone, date_of_birth, address, student_number, program,
+		                           enrollment_year, custom_fields, avatar_file_id)
+		SELECT $2, phone, date_of_birth, address, $3, program, enrollment_year, custom_fields, avatar_file_id
+		FROM user_profiles WHERE user_id = $1
+		ON CONFLICT (user_id) DO UPDATE SET
+			phone = COALESCE(user_profiles.phone, EXCLUDED.phone),
+			date_of_birth = COALESCE(user_profiles.date_of_birth, EXCLUDED.date_of_birth),
+			address = COALESCE(user_profiles.address, EXCLUDED.address),
+			student_number = COALESCE(user_profiles.student_number, EXCLUDED.student_number),
+			program = COALESCE(user_profiles.program, EXCLUDED.program),
+			enrollment_year = COALESCE(user_profiles.enrollment_year, EXCLUDED.enrollment_year),
+			custom_fields = EXCLUDED.custom_fields || user_profiles.custom_fields,
+			avatar_file_id = COALESCE(user_profiles.avatar_file_id, EXCLUDED.avatar_file_id),
+			updated_at = NOW()
+	`, sourceUserID, targetUserID, studentNumber)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
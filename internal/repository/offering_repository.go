@@ -0,0 +1,143 @@
+// internal/repository/offering_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OfferingRepository defines the methods for courses offered in a term.
+type OfferingRepository interface {
+	CreateOffering(ctx context.Context, offering *models.CourseOffering) error
+	GetOffering(ctx context.Context, courseID, termID int64) (*models.CourseOffering, error)
+	UpdateOffering(ctx context.Context, offering *models.CourseOffering) error
+	DeleteOffering(ctx context.Context, courseID, termID int64) error
+	ListForTerm(ctx context.Context, termID int64, includeInactive bool) ([]models.CourseOffering, error)
+}
+
+type offeringRepository struct {
+	db *txPool
+}
+
+// NewOfferingRepository creates a new OfferingRepository instance.
+func NewOfferingRepository(db *pgxpool.Pool) OfferingRepository {
+	return &offeringRepository{db: newTxPool(db)}
+}
+
+// offeringColumns selects from course_offerings o joined with courses c and terms t.
+const offeringColumns = `o.id, o.course_id, c.code, c.title, o.term_id, t.code, o.capacity, o.drop_deadline, o.is_active,
+	(SELECT COUNT(*) FROM enrollments e WHERE e.course_id = o.course_id AND e.term_id = o.term_id AND e.status = 'enrolled'),
+	(SELECT COUNT(*) FROM enrollments e WHERE e.course_id = o.course_id AND e.term_id = o.term_id AND e.status = 'waitlisted'),
+	o.created_by, o.created_at, o.updated_at`
+
+const offeringJoins = ` o JOIN courses c ON c.id = o.course_id JOIN terms t ON t.id = o.term_id`
+
+func scanOffering(row pgx.Row) (*models.CourseOffering, error) {
+	offering := &models.CourseOffering{}
+	err := row.Scan(
+		&offering.ID, &offering.CourseID, &offering.CourseCode, &offering.CourseTitle, &offering.TermID, &offering.TermCode,
+		&offering.Capacity, &offering.DropDeadline, &offering.IsActive, &offering.EnrolledCount, &offering.WaitlistCount,
+		&offering.CreatedBy, &offering.CreatedAt, &offering.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return offering, nil
+}
+
+func (r *offeringRepository) CreateOffering(ctx context.Context, offering *models.CourseOffering) error {
+	var id int64
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO course_offerings (course_id, term_id, capacity, drop_deadline, is_active, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, offering.CourseID, offering.TermID, offering.Capacity, offering.DropDeadline, offering.IsActive, offering.CreatedBy,
+	).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is unique violation
+			return appErrors.ErrOfferingExists
+		}
+		return appErrors.ErrInternalServerError
+	}
+
+	created, err := scanOffering(r.db.QueryRow(ctx, "SELECT "+offeringColumns+" FROM course_offerings"+offeringJoins+" WHERE o.id = $1", id))
+	if err != nil {
+		return err
+	}
+	*offering = *created
+	return nil
+}
+
+func (r *offeringRepository) GetOffering(ctx context.Context, courseID, termID int64) (*models.CourseOffering, error) {
+	query := "SELECT " + offeringColumns + " FROM course_offerings" + offeringJoins + " WHERE o.course_id = $1 AND o.term_id = $2"
+	return scanOffering(r.db.QueryRow(ctx, query, courseID, termID))
+}
+
+func (r *offeringRepository) UpdateOffering(ctx context.Context, offering *models.CourseOffering) error {
+	err := r.db.QueryRow(ctx, `
+		UPDATE course_offerings
+		SET capacity = $2, drop_deadline = $3, is_active = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, offering.ID, offering.Capacity, offering.DropDeadline, offering.IsActive).Scan(&offering.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErrors.ErrNotFound
+	}
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *offeringRepository) DeleteOffering(ctx context.Context, courseID, termID int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM course_offerings WHERE course_id = $1 AND term_id = $2", courseID, termID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+			return appErrors.ErrOfferingInUse
+		}
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+// ListForTerm lists the courses offered in a term, ordered by course code. Offerings that
+// are inactive, or whose course is, are only included on request.
+func (r *offeringRepository) ListForTerm(ctx context.Context, termID int64, includeInactive bool) ([]models.CourseOffering, error) {
+	query := "SELECT " + offeringColumns + " FROM course_offerings" + offeringJoins + `
+		WHERE o.term_id = $1 AND ($2 OR (o.is_active AND c.is_active))
+		ORDER BY c.code`
+	rows, err := r.db.Query(ctx, query, termID, includeInactive)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	offerings := make([]models.CourseOffering, 0)
+	for rows.Next() {
+		offering, err := scanOffering(rows)
+		if err != nil {
+			return nil, err
+		}
+		offerings = append(offerings, *offering)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return offerings, nil
+}
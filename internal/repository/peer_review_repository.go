@@ -0,0 +1,343 @@
+// internal/repository/peer_review_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PeerReviewRepository defines the methods for peer review of assignments.
+type PeerReviewRepository interface {
+	GetSettings(ctx context.Context, assignmentID int64) (*models.PeerReviewSettings, error)
+	// SaveSettings creates or updates the peer review settings of the assignment. The number
+	// of reviewers per submission cannot change once reviews have been assigned.
+	SaveSettings(ctx context.Context, settings *models.PeerReviewSettings) error
+	// DeleteSettings turns peer review off for the assignment, removing the assigned reviews,
+	// as long as none has been handed in.
+	DeleteSettings(ctx context.Context, assignmentID int64) error
+	// Allocate replaces the assigned reviews of the assignment, as long as none has been
+	// handed in, and marks the settings as allocated.
+	Allocate(ctx context.Context, assignmentID int64, reviews []models.PeerReview) error
+
+	GetReview(ctx context.Context, id int64) (*models.PeerReview, error)
+	ListForAssignment(ctx context.Context, assignmentID int64) ([]models.PeerReview, error)
+	// ListForReviewer returns the reviews assigned to the student, newest first.
+	ListForReviewer(ctx context.Context, reviewerID int64) ([]models.PeerReview, error)
+	// ListReceived returns the handed-in reviews of the student's submission.
+	ListReceived(ctx context.Context, assignmentID, authorID int64) ([]models.PeerReview, error)
+	// SubmitReview stores the points, comment and scores of the review before its deadline,
+	// provided the assignment is still graded against the given rubric.
+	SubmitReview(ctx context.Context, review *models.PeerReview, rubricID *int64) error
+}
+
+type peerReviewRepository struct {
+	db *txPool
+}
+
+// NewPeerReviewRepository creates a new PeerReviewRepository instance.
+func NewPeerReviewRepository(db *pgxpool.Pool) PeerReviewRepository {
+	return &peerReviewRepository{db: newTxPool(db)}
+}
+
+// peerReviewSettingsColumns selects from peer_review_settings ps.
+const peerReviewSettingsColumns = `ps.assignment_id, ps.reviewers_per_submission, ps.review_due_at, ps.allocated_at,
+	ps.created_by, ps.created_at, ps.updated_at`
+
+func scanPeerReviewSettings(row pgx.Row) (*models.PeerReviewSettings, error) {
+	settings := &models.PeerReviewSettings{}
+	err := row.Scan(
+		&settings.AssignmentID, &settings.ReviewersPerSubmission, &settings.ReviewDueAt, &settings.AllocatedAt,
+		&settings.CreatedBy, &settings.CreatedAt, &settings.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return settings, nil
+}
+
+// peerReviewColumns selects from peer_reviews pr joined with the author au and the reviewer ru.
+const peerReviewColumns = `pr.id, pr.assignment_id, pr.submission_id, pr.author_id, au.name, pr.reviewer_id, ru.name,
+	pr.points, pr.comment, pr.assigned_at, pr.submitted_at`
+
+const peerReviewJoins = ` pr JOIN users au ON au.id = pr.author_id JOIN users ru ON ru.id = pr.reviewer_id`
+
+func scanPeerReview(row pgx.Row) (*models.PeerReview, error) {
+	review := &models.PeerReview{}
+	err := row.Scan(
+		&review.ID, &review.AssignmentID, &review.SubmissionID, &review.AuthorID, &review.AuthorName,
+		&review.ReviewerID, &review.ReviewerName, &review.Points, &review.Comment, &review.AssignedAt,
+		&review.SubmittedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	review.Scores = make([]models.PeerReviewScore, 0)
+	return review, nil
+}
+
+func (r *peerReviewRepository) GetSettings(ctx context.Context, assignmentID int64) (*models.PeerReviewSettings, error) {
+	return scanPeerReviewSettings(r.db.QueryRow(ctx, "SELECT "+peerReviewSettingsColumns+" FROM peer_review_settings ps WHERE ps.assignment_id = $1", assignmentID))
+}
+
+func (r *peerReviewRepository) SaveSettings(ctx context.Context, settings *models.PeerReviewSettings) error {
+	query := `
+		WITH saved AS (
+			INSERT INTO peer_review_settings (assignment_id, reviewers_per_submission, review_due_at, created_by)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (assignment_id) DO UPDATE
+			SET reviewers_per_submission = EXCLUDED.reviewers_per_submission, review_due_at = EXCLUDED.review_due_at,
+			    updated_at = NOW()
+			WHERE peer_review_settings.allocated_at IS NULL
+			   OR peer_review_settings.reviewers_per_submission = EXCLUDED.reviewers_per_submission
+			RETURNING *
+		)
+		SELECT ` + peerReviewSettingsColumns + ` FROM saved ps`
+	saved, err := scanPeerReviewSettings(r.db.QueryRow(ctx, query,
+		settings.AssignmentID, settings.ReviewersPerSubmission, settings.ReviewDueAt, settings.CreatedBy,
+	))
+	if errors.Is(err, appErrors.ErrNotFound) {
+		return appErrors.New(http.StatusConflict, "Reviewers per submission cannot change once reviews have been assigned")
+	}
+	if err != nil {
+		return err
+	}
+	*settings = *saved
+	return nil
+}
+
+// lockPeerReview locks the peer review settings of the assignment and fails if a review has
+// been handed in already.
+func lockPeerReview(ctx context.Context, tx pgx.Tx, assignmentID int64) error {
+	var started bool
+	err := tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM peer_reviews WHERE assignment_id = ps.assignment_id AND submitted_at IS NOT NULL)
+		FROM peer_review_settings ps WHERE ps.assignment_id = $1 FOR UPDATE
+	`, assignmentID).Scan(&started)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErrors.ErrNotFound
+	}
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if started {
+		return appErrors.ErrPeerReviewStarted
+	}
+	return nil
+}
+
+func (r *peerReviewRepository) DeleteSettings(ctx context.Context, assignmentID int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	if err := lockPeerReview(ctx, tx, assignmentID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM peer_reviews WHERE assignment_id = $1", assignmentID); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM peer_review_settings WHERE assignment_id = $1", assignmentID); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *peerReviewRepository) Allocate(ctx context.Context, assignmentID int64, reviews []models.PeerReview) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	if err := lockPeerReview(ctx, tx, assignmentID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM peer_reviews WHERE assignment_id = $1", assignmentID); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	for _, review := range reviews {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO peer_reviews (assignment_id, submission_id, author_id, reviewer_id) VALUES ($1, $2, $3, $4)
+		`, assignmentID, review.SubmissionID, review.AuthorID, review.ReviewerID)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+	_, err = tx.Exec(ctx, "UPDATE peer_review_settings SET allocated_at = NOW(), updated_at = NOW() WHERE assignment_id = $1", assignmentID)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+// loadScores fills in the rubric scores of the given reviews.
+func (r *peerReviewRepository) loadScores(ctx context.Context, reviews ...*models.PeerReview) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*models.PeerReview, len(reviews))
+	ids := make([]int64, 0, len(reviews))
+	for _, review := range reviews {
+		byID[review.ID] = review
+		ids = append(ids, review.ID)
+	}
+
+	query := `
+		SELECT ps.review_id, ps.criterion_id, ps.level_id, ps.points, ps.comment
+		FROM peer_review_scores ps
+		JOIN rubric_criteria rc ON rc.id = ps.criterion_id
+		WHERE ps.review_id = ANY($1)
+		ORDER BY ps.review_id, rc.position`
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reviewID int64
+		var score models.PeerReviewScore
+		if err := rows.Scan(&reviewID, &score.CriterionID, &score.LevelID, &score.Points, &score.Comment); err != nil {
+			return appErrors.ErrInternalServerError
+		}
+		review := byID[reviewID]
+		review.Scores = append(review.Scores, score)
+	}
+
+	if rows.Err() != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *peerReviewRepository) queryReviews(ctx context.Context, query string, args ...interface{}) ([]models.PeerReview, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	reviews := make([]models.PeerReview, 0)
+	for rows.Next() {
+		review, err := scanPeerReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, *review)
+	}
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	pointers := make([]*models.PeerReview, len(reviews))
+	for i := range reviews {
+		pointers[i] = &reviews[i]
+	}
+	if err := r.loadScores(ctx, pointers...); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+func (r *peerReviewRepository) GetReview(ctx context.Context, id int64) (*models.PeerReview, error) {
+	review, err := scanPeerReview(r.db.QueryRow(ctx, "SELECT "+peerReviewColumns+" FROM peer_reviews"+peerReviewJoins+" WHERE pr.id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadScores(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (r *peerReviewRepository) ListForAssignment(ctx context.Context, assignmentID int64) ([]models.PeerReview, error) {
+	query := "SELECT " + peerReviewColumns + " FROM peer_reviews" + peerReviewJoins + `
+		WHERE pr.assignment_id = $1 ORDER BY au.name, pr.author_id, pr.id`
+	return r.queryReviews(ctx, query, assignmentID)
+}
+
+func (r *peerReviewRepository) ListForReviewer(ctx context.Context, reviewerID int64) ([]models.PeerReview, error) {
+	query := "SELECT " + peerReviewColumns + " FROM peer_reviews" + peerReviewJoins + `
+		WHERE pr.reviewer_id = $1 ORDER BY pr.assigned_at DESC, pr.id`
+	return r.queryReviews(ctx, query, reviewerID)
+}
+
+func (r *peerReviewRepository) ListReceived(ctx context.Context, assignmentID, authorID int64) ([]models.PeerReview, error) {
+	query := "SELECT " + peerReviewColumns + " FROM peer_reviews" + peerReviewJoins + `
+		WHERE pr.assignment_id = $1 AND pr.author_id = $2 AND pr.submitted_at IS NOT NULL ORDER BY pr.submitted_at, pr.id`
+	return r.queryReviews(ctx, query, assignmentID, authorID)
+}
+
+func (r *peerReviewRepository) SubmitReview(ctx context.Context, review *models.PeerReview, rubricID *int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	// The lock keeps the rubric from being swapped while the scores are written
+	current, err := lockAssignmentRubric(ctx, tx, review.AssignmentID)
+	if err != nil {
+		return err
+	}
+	if (current == nil) != (rubricID == nil) || (current != nil && *current != *rubricID) {
+		return appErrors.New(http.StatusConflict, "The assignment's rubric has changed")
+	}
+
+	// The database clock decides whether the review deadline has passed
+	err = tx.QueryRow(ctx, `
+		UPDATE peer_reviews pr SET points = $2, comment = $3, submitted_at = NOW()
+		FROM peer_review_settings ps
+		WHERE pr.id = $1 AND ps.assignment_id = pr.assignment_id AND ps.review_due_at > NOW()
+		RETURNING pr.submitted_at
+	`, review.ID, review.Points, review.Comment).Scan(&review.SubmittedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErrors.ErrReviewClosed
+	}
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM peer_review_scores WHERE review_id = $1", review.ID); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	for _, score := range review.Scores {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO peer_review_scores (review_id, criterion_id, level_id, points, comment) VALUES ($1, $2, $3, $4, $5)
+		`, review.ID, score.CriterionID, score.LevelID, score.Points, score.Comment)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+// internal/repository/privacy_repository.go
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PrivacyRepository defines the methods for personal data export and erasure.
+type PrivacyRepository interface {
+	CreateExportJob(ctx context.Context, job *models.DataExportJob) error
+	GetExportJob(ctx context.Context, id int64) (*models.DataExportJob, error)
+	ListExportJobs(ctx context.Context, userID int64) ([]models.DataExportJob, error)
+	// ExpireExportJobs marks jobs still pending or running that were requested before the
+	// given time as failed, and returns how many there were.
+	ExpireExportJobs(ctx context.Context, requestedBefore time.Time, message string) (int64, error)
+	UpdateExportJob(ctx context.Context, job *models.DataExportJob) error
+	ExportUserData(ctx context.Context, userID int64) (map[string][]json.RawMessage, error)
+	EraseUser(ctx context.Context, erasure *models.UserErasure, filePurposes []string) ([]models.File, error)
+}
+
+// exportSource is one JSON file of a personal data export.
+type exportSource struct {
+	File  string
+	Query string // Takes the user ID as $1
+}
+
+// exportSources lists everything tied to a user ID that goes into a data export.
+// Features that store personal data must add their tables here.
+var exportSources = []exportSource{
+	{File: "account.json", Query: `SELECT id, name, email, role, version, created_at, updated_at FROM users WHERE id = $1`},
+	{File: "profile.json", Query: `SELECT * FROM user_profiles WHERE user_id = $1`},
+	{File: "files.json", Query: `SELECT id, filename, content_type, size_bytes, checksum, purpose, created_at FROM files WHERE owner_id = $1`},
+	{File: "data_exports.json", Query: `SELECT id, status, created_at, completed_at FROM data_export_jobs WHERE user_id = $1`},
+	{File: "change_history.json", Query: `SELECT id, action, changes, actor_id, created_at FROM change_history WHERE entity_type = 'user' AND entity_id = $1`},
+	{File: "status_changes.json", Query: `SELECT id, from_status, to_status, reason, changed_at FROM user_status_changes WHERE user_id = $1`},
+	{File: "guardians.json", Query: `SELECT id, student_id, guardian_id, relationship, consent_profile, consent_academic, created_at, updated_at FROM student_guardians WHERE student_id = $1 OR guardian_id = $1`},
+	{File: "guardian_invitations.json", Query: `SELECT id, email, relationship, consent_profile, consent_academic, expires_at, accepted_at, created_at FROM guardian_invitations WHERE student_id = $1`},
+	{File: "enrollments.json", Query: `SELECT e.id, c.code, c.title, t.code AS term, s.code AS section, e.status, e.enrolled_at, e.waitlisted_at, e.dropped_at FROM enrollments e JOIN courses c ON c.id = e.course_id JOIN terms t ON t.id = e.term_id LEFT JOIN course_sections s ON s.id = e.section_id WHERE e.student_id = $1`},
+	{File: "course_completions.json", Query: `SELECT cc.id, c.code, c.title, t.code AS term, cc.grade, cc.completed_at FROM course_completions cc JOIN courses c ON c.id = cc.course_id LEFT JOIN terms t ON t.id = cc.term_id WHERE cc.student_id = $1`},
+	{File: "attendance.json", Query: `SELECT c.code, s.code AS section, cs.session_date, cs.start_time, ar.status, ar.note, ar.marked_at FROM attendance_records ar JOIN class_sessions cs ON cs.id = ar.session_id JOIN course_sections s ON s.id = cs.section_id JOIN courses c ON c.id = s.course_id WHERE ar.student_id = $1`},
+	{File: "check_in_attempts.json", Query: `SELECT window_id, succeeded, reason, ip_address, created_at FROM check_in_attempts WHERE student_id = $1`},
+	{File: "assignment_submissions.json", Query: `SELECT c.code, s.code AS section, a.title, sub.version, sub.comment, sub.submitted_at, sub.late, (SELECT json_agg(sf.file_id ORDER BY sf.position) FROM submission_files sf WHERE sf.submission_id = sub.id) AS file_ids FROM assignment_submissions sub JOIN assignments a ON a.id = sub.assignment_id JOIN course_sections s ON s.id = a.section_id JOIN courses c ON c.id = s.course_id WHERE sub.student_id = $1`},
+	{File: "grades.json", Query: `SELECT c.code, s.code AS section, a.title, a.max_points, g.points, g.late_penalty, g.excused, g.feedback, g.released_at FROM assignment_grades g JOIN assignments a ON a.id = g.assignment_id JOIN course_sections s ON s.id = a.section_id JOIN courses c ON c.id = s.course_id WHERE g.student_id = $1 AND g.released_at IS NOT NULL`},
+	{File: "rubric_scores.json", Query: `SELECT c.code, s.code AS section, a.title, rc.title AS criterion, rl.title AS level, rs.points, rs.comment, rs.scored_at FROM rubric_scores rs JOIN rubric_criteria rc ON rc.id = rs.criterion_id LEFT JOIN rubric_levels rl ON rl.id = rs.level_id JOIN assignments a ON a.id = rs.assignment_id JOIN course_sections s ON s.id = a.section_id JOIN courses c ON c.id = s.course_id JOIN assignment_grades g ON g.assignment_id = rs.assignment_id AND g.student_id = rs.student_id WHERE rs.student_id = $1 AND g.released_at IS NOT NULL`},
+	{File: "peer_reviews_given.json", Query: `SELECT c.code, s.code AS section, a.title, pr.points, pr.comment, (SELECT json_agg(json_build_object('criterion', rc.title, 'points', ps.points, 'comment', ps.comment) ORDER BY rc.position) FROM peer_review_scores ps JOIN rubric_criteria rc ON rc.id = ps.criterion_id WHERE ps.review_id = pr.id) AS scores, pr.assigned_at, pr.submitted_at FROM peer_reviews pr JOIN assignments a ON a.id = pr.assignment_id JOIN course_sections s ON s.id = a.section_id JOIN courses c ON c.id = s.course_id WHERE pr.reviewer_id = $1`},
+	{File: "peer_reviews_received.json", Query: `SELECT c.code, s.code AS section, a.title, pr.points, pr.comment, (SELECT json_agg(json_build_object('criterion', rc.title, 'points', ps.points, 'comment', ps.comment) ORDER BY rc.position) FROM peer_review_scores ps JOIN rubric_criteria rc ON rc.id = ps.criterion_id WHERE ps.review_id = pr.id) AS scores, pr.submitted_at FROM peer_reviews pr JOIN assignments a ON a.id = pr.assignment_id JOIN course_sections s ON s.id = a.section_id JOIN courses c ON c.id = s.course_id JOIN peer_review_settings prs ON prs.assignment_id = pr.assignment_id WHERE pr.author_id = $1 AND pr.submitted_at IS NOT NULL AND prs.review_due_at <= NOW()`},
+	{File: "course_grades.json", Query: `SELECT c.code, t.code AS term, s.code AS section, sg.posted_letter, sg.posted_at FROM section_grades sg JOIN course_sections s ON s.id = sg.section_id JOIN courses c ON c.id = s.course_id JOIN terms t ON t.id = s.term_id WHERE sg.student_id = $1 AND sg.posted_at IS NOT NULL`},
+	{File: "quiz_attempts.json", Query: `SELECT c.code, s.code AS section, z.title, qa.attempt_number, qa.status, qa.started_at, qa.submitted_at, qa.score, qa.max_score, (SELECT json_agg(json_build_object('question_id', r.question_id, 'selected_options', r.selected_options, 'bool_answer', r.bool_answer, 'numeric_answer', r.numeric_answer, 'text_answer', r.text_answer, 'points', r.points, 'feedback', r.feedback) ORDER BY r.question_id) FROM quiz_responses r WHERE r.attempt_id = qa.id) AS responses FROM quiz_attempts qa JOIN quizzes z ON z.id = qa.quiz_id JOIN course_sections s ON s.id = z.section_id JOIN courses c ON c.id = s.course_id WHERE qa.student_id = $1`},
+	{File: "transcripts.json", Query: `SELECT id, verification_code, scale_name, cumulative_gpa, earned_credits, file_id, issued_at FROM transcripts WHERE student_id = $1`},
+	{File: "calendar_feed.json", Query: `SELECT created_at, last_accessed_at FROM calendar_feeds WHERE user_id = $1`},
+	{File: "account_merges.json", Query: `SELECT id, source_user_id, source_name, source_email, reason, merged_at FROM user_merges WHERE target_user_id = $1`},
+}
+
+// erasureStatements run inside the erasure transaction after the users row has been anonymized.
+// Records that must be retained by law (academic records) are deliberately not listed.
+var erasureStatements = []string{
+	`UPDATE user_profiles
+	 SET phone = NULL, date_of_birth = NULL, address = NULL, custom_fields = '{}'::jsonb,
+	     avatar_file_id = NULL, updated_at = NOW()
+	 WHERE user_id = $1`,
+	`DELETE FROM data_export_jobs WHERE user_id = $1`,
+	`UPDATE change_history SET changes = '{}'::jsonb WHERE entity_type = 'user' AND entity_id = $1`,
+	`DELETE FROM student_guardians WHERE student_id = $1 OR guardian_id = $1`,
+	`DELETE FROM guardian_invitations WHERE student_id = $1`,
+	`UPDATE enrollments SET status = 'dropped', dropped_at = NOW(), updated_at = NOW() WHERE student_id = $1 AND status = 'waitlisted'`,
+	`DELETE FROM calendar_feeds WHERE user_id = $1`,
+	`DELETE FROM check_in_attempts WHERE student_id = $1`,
+	`UPDATE user_merges SET source_name = 'Erased User', source_email = NULL WHERE target_user_id = $1`,
+}
+
+type privacyRepository struct {
+	db *txPool
+}
+
+// NewPrivacyRepository creates a new PrivacyRepository instance.
+func NewPrivacyRepository(db *pgxpool.Pool) PrivacyRepository {
+	return &privacyRepository{db: newTxPool(db)}
+}
+
+const exportJobColumns = `id, user_id, requested_by, status, file_id, error, created_at, completed_at`
+
+func scanExportJob(row pgx.Row) (*models.DataExportJob, error) {
+	job := &models.DataExportJob{}
+	err := row.Scan(
+		&job.ID, &job.UserID, &job.RequestedBy, &job.Status, &job.FileID, &job.Error, &job.CreatedAt, &job.CompletedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return job, nil
+}
+
+func (r *privacyRepository) CreateExportJob(ctx context.Context, job *models.DataExportJob) error {
+	query := `
+		INSERT INTO data_export_jobs (user_id, requested_by, status)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(ctx, query, job.UserID, job.RequestedBy, job.Status).Scan(&job.ID, &job.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		// 23505 is unique violation: the user already has a pending or running job
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return appErrors.ErrExportInProgress
+		}
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *privacyRepository) GetExportJob(ctx context.Context, id int64) (*models.DataExportJob, error) {
+	return scanExportJob(r.db.QueryRow(ctx, "SELECT "+exportJobColumns+" FROM data_export_jobs WHERE id = $1", id))
+}
+
+func (r *privacyRepository) ListExportJobs(ctx context.Context, userID int64) ([]models.DataExportJob, error) {
+	rows, err := r.db.Query(ctx, "SELECT "+exportJobColumns+" FROM data_export_jobs WHERE user_id = $1 ORDER BY id DESC", userID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	jobs := make([]models.DataExportJob, 0)
+	for rows.Next() {
+		job, err := scanExportJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return jobs, nil
+}
+
+func (r *privacyRepository) ExpireExportJobs(ctx context.Context, requestedBefore time.Time, message string) (int64, error) {
+	query := `
+		UPDATE data_export_jobs
+		SET status = 'failed', error = $2, completed_at = CURRENT_TIMESTAMP
+		WHERE status IN ('pending', 'running') AND created_at < $1
+	`
+	cmdTag, err := r.db.Exec(ctx, query, requestedBefore, message)
+	if err != nil {
+		return 0, appErrors.ErrInternalServerError
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+func (r *privacyRepository) UpdateExportJob(ctx context.Context, job *models.DataExportJob) error {
+	query := `
+		UPDATE data_export_jobs
+		SET status = $2, file_id = $3, error = $4, completed_at = $5
+		WHERE id = $1 AND status IN ('pending', 'running') -- An expired job stays failed
+	`
+	cmdTag, err := r.db.Exec(ctx, query, job.ID, job.Status, job.FileID, job.Error, job.CompletedAt)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+// ExportUserData runs every export source and returns the rows as JSON, keyed by file name.
+func (r *privacyRepository) ExportUserData(ctx context.Context, userID int64) (map[string][]json.RawMessage, error) {
+	result := make(map[string][]json.RawMessage, len(exportSources))
+
+	for _, source := range exportSources {
+		rows, err := r.db.Query(ctx, "SELECT row_to_json(t) FROM ("+source.Query+") t", userID)
+		if err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+
+		records := make([]json.RawMessage, 0)
+		for rows.Next() {
+			var record json.RawMessage
+			if err := rows.Scan(&record); err != nil {
+				rows.Close()
+				return nil, appErrors.ErrInternalServerError
+			}
+			records = append(records, record)
+		}
+		rows.Close()
+		if rows.Err() != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+
+		result[source.File] = records
+	}
+	return result, nil
+}
+
+// EraseUser anonymizes the user and their related personal data in a single transaction.
+// File metadata for the given purposes is deleted; the removed files are returned so that
+// the caller can delete the blobs from storage after the commit.
+func (r *privacyRepository) EraseUser(ctx context.Context, erasure *models.UserErasure, filePurposes []string) ([]models.File, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	// 1. Lock the user row and make sure it has not been erased already
+	var erasedAt *time.Time
+	err = tx.QueryRow(ctx, "SELECT erased_at FROM users WHERE id = $1 FOR UPDATE", erasure.UserID).Scan(&erasedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	if erasedAt != nil {
+		return nil, appErrors.ErrUserAlreadyErased
+	}
+
+	// 2. Anonymize the account. The password can never match a bcrypt hash.
+	_, err = tx.Exec(ctx, `
+		UPDATE users
+		SET name = 'Erased User', email = 'erased-' || id || '@erased.invalid', password = '!',
+		    status = 'archived', erased_at = NOW(), updated_at = NOW(), version = version + 1
+		WHERE id = $1
+	`, erasure.UserID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	// 3. Clear related personal data
+	for _, statement := range erasureStatements {
+		if _, err := tx.Exec(ctx, statement, erasure.UserID); err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+	}
+
+	// 4. Remove file metadata for erasable purposes
+	rows, err := tx.Query(ctx,
+		"DELETE FROM files WHERE owner_id = $1 AND purpose = ANY($2) RETURNING "+fileColumns,
+		erasure.UserID, filePurposes,
+	)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	files := make([]models.File, 0)
+	for rows.Next() {
+		file, err := scanFile(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		files = append(files, *file)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	// 5. Record the erasure
+	err = tx.QueryRow(ctx, `
+		INSERT INTO user_erasures (user_id, performed_by, reason)
+		VALUES ($1, $2, $3)
+		RETURNING id, erased_at
+	`, erasure.UserID, erasure.PerformedBy, erasure.Reason).Scan(&erasure.ID, &erasure.ErasedAt)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return files, nil
+}
@@ -0,0 +1,241 @@
+// internal/repository/profile_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ProfileRepository defines the methods for interacting with the user profiles data store.
+type ProfileRepository interface {
+	GetProfile(ctx context.Context, userID int64) (*models.Profile, error)
+	UpsertProfile(ctx context.Context, profile *models.Profile) error
+	PatchProfile(ctx context.Context, userID int64, fields map[string]interface{}) error
+	// SetAvatar points the profile at a new avatar file, or at none, and returns the file it
+	// replaced. Only avatar_file_id is written, so concurrent profile updates are kept.
+	SetAvatar(ctx context.Context, userID int64, fileID *int64) (*int64, error)
+	ListFieldDefinitions(ctx context.Context) ([]models.ProfileFieldDefinition, error)
+	CreateFieldDefinition(ctx context.Context, def *models.ProfileFieldDefinition) error
+	DeleteFieldDefinition(ctx context.Context, key string) error
+}
+
+type profileRepository struct {
+	db *txPool
+}
+
+// NewProfileRepository creates a new ProfileRepository instance.
+func NewProfileRepository(db *pgxpool.Pool) ProfileRepository {
+	return &profileRepository{db: newTxPool(db)}
+}
+
+func (r *profileRepository) GetProfile(ctx context.Context, userID int64) (*models.Profile, error) {
+	profile := &models.Profile{}
+	query := `
+		SELECT user_id, phone, date_of_birth, address, student_number, program, enrollment_year,
+		       custom_fields, avatar_file_id, created_at, updated_at
+		FROM user_profiles
+		WHERE user_id = $1
+	`
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&profile.UserID, &profile.Phone, &profile.DateOfBirth, &profile.Address, &profile.StudentNumber,
+		&profile.Program, &profile.EnrollmentYear, &profile.CustomFields, &profile.AvatarFileID, &profile.CreatedAt, &profile.UpdatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return profile, nil
+}
+
+func (r *profileRepository) UpsertProfile(ctx context.Context, profile *models.Profile) error {
+	if profile.CustomFields == nil {
+		profile.CustomFields = map[string]interface{}{}
+	}
+
+	query := `
+		INSERT INTO user_profiles (user_id, phone, date_of_birth, address, student_number, program, enrollment_year, custom_fields, avatar_file_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id) DO UPDATE SET
+			phone = EXCLUDED.phone,
+			date_of_birth = EXCLUDED.date_of_birth,
+			address = EXCLUDED.address,
+			student_number = EXCLUDED.student_number,
+			program = EXCLUDED.program,
+			enrollment_year = EXCLUDED.enrollment_year,
+			custom_fields = EXCLUDED.custom_fields,
+			avatar_file_id = EXCLUDED.avatar_file_id,
+			updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		profile.UserID, profile.Phone, profile.DateOfBirth, profile.Address, profile.StudentNumber,
+		profile.Program, profile.EnrollmentYear, profile.CustomFields, profile.AvatarFileID,
+	).Scan(&profile.CreatedAt, &profile.UpdatedAt)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is unique violation
+			return appErrors.ErrStudentNumberExists
+		}
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+// patchableProfileColumns whitelists the user_profiles columns PatchProfile may write.
+var patchableProfileColumns = map[string]bool{
+	"phone": true, "date_of_birth": true, "address": true, "student_number": true,
+	"program": true, "enrollment_year": true, "custom_fields": true,
+}
+
+// PatchProfile writes only the given columns, creating the profile row if needed.
+func (r *profileRepository) PatchProfile(ctx context.Context, userID int64, fields map[string]interface{}) error {
+	columns := make([]string, 0, len(fields))
+	for column := range fields {
+		if !patchableProfileColumns[column] {
+			return appErrors.ErrBadRequest
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	sort.Strings(columns) // Deterministic SQL for the statement cache
+
+	args := []interface{}{userID}
+	placeholders := []string{"$1"}
+	sets := []string{"updated_at = NOW()"}
+	for _, column := range columns {
+		args = append(args, fields[column])
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		sets = append(sets, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
+	}
+
+	query := `
+		INSERT INTO user_profiles (user_id, ` + strings.Join(columns, ", ") + `)
+		VALUES (` + strings.Join(placeholders, ", ") + `)
+		ON CONFLICT (user_id) DO UPDATE SET ` + strings.Join(sets, ", ")
+
+	if _, err := r.db.Exec(ctx, query, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is unique violation
+			return appErrors.ErrStudentNumberExists
+		}
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *profileRepository) SetAvatar(ctx context.Context, userID int64, fileID *int64) (*int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "INSERT INTO user_profiles (user_id) VALUES ($1) ON CONFLICT (user_id) DO NOTHING", userID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+			return nil, appErrors.ErrNotFound
+		}
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	// The locked subquery reads the avatar being replaced
+	query := `
+		UPDATE user_profiles p
+		SET avatar_file_id = $2, updated_at = NOW()
+		FROM (SELECT avatar_file_id FROM user_profiles WHERE user_id = $1 FOR UPDATE) previous
+		WHERE p.user_id = $1
+		RETURNING previous.avatar_file_id
+	`
+	var previous *int64
+	if err := tx.QueryRow(ctx, query, userID, fileID).Scan(&previous); err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return previous, nil
+}
+
+func (r *profileRepository) ListFieldDefinitions(ctx context.Context) ([]models.ProfileFieldDefinition, error) {
+	query := `
+		SELECT id, key, label, field_type, options, required, student_editable, created_at
+		FROM profile_field_definitions
+		ORDER BY id
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	defs := make([]models.ProfileFieldDefinition, 0)
+	for rows.Next() {
+		def := models.ProfileFieldDefinition{}
+		err := rows.Scan(
+			&def.ID, &def.Key, &def.Label, &def.FieldType, &def.Options, &def.Required, &def.StudentEditable, &def.CreatedAt,
+		)
+		if err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		defs = append(defs, def)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	return defs, nil
+}
+
+func (r *profileRepository) CreateFieldDefinition(ctx context.Context, def *models.ProfileFieldDefinition) error {
+	if def.Options == nil {
+		def.Options = []string{}
+	}
+
+	query := `
+		INSERT INTO profile_field_definitions (key, label, field_type, options, required, student_editable)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		def.Key, def.Label, def.FieldType, def.Options, def.Required, def.StudentEditable,
+	).Scan(&def.ID, &def.CreatedAt)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is unique violation
+			return appErrors.ErrProfileFieldExists
+		}
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *profileRepository) DeleteFieldDefinition(ctx context.Context, key string) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM profile_field_definitions WHERE key = $1", key)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
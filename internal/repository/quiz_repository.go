@@ -0,0 +1,912 @@
+// internal/repository/quiz_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// QuizRepository defines the methods for question banks, quizzes and their attempts.
+type QuizRepository interface {
+	CreateBank(ctx context.Context, bank *models.QuestionBank) error
+	GetBank(ctx context.Context, id int64) (*models.QuestionBank, error)
+	UpdateBank(ctx context.Context, bank *models.QuestionBank) error
+	DeleteBank(ctx context.Context, id int64) error
+	// ListBanks returns the banks of the owner, or every bank for nil.
+	ListBanks(ctx context.Context, ownerID *int64) ([]models.QuestionBank, error)
+
+	CreateQuestion(ctx context.Context, question *models.Question) error
+	GetQuestion(ctx context.Context, id int64) (*models.Question, error)
+	UpdateQuestion(ctx context.Context, question *models.Question) error
+	DeleteQuestion(ctx context.Context, id int64) error
+	// ImportQuestions adds the questions to the bank at once, first creating the bank if it
+	// has no ID yet. The IDs of the new questions are set on them.
+	ImportQuestions(ctx context.Context, bank *models.QuestionBank, questions []models.Question) error
+	ListBankQuestions(ctx context.Context, bankID int64) ([]models.Question, error)
+	// GetQuestions returns the questions with the given IDs; missing ones are left out.
+	GetQuestions(ctx context.Context, ids []int64) ([]models.Question, error)
+	// IsQuestionAnswered reports whether the question is on a quiz that has been attempted.
+	IsQuestionAnswered(ctx context.Context, id int64) (bool, error)
+
+	CreateQuiz(ctx context.Context, quiz *models.Quiz) error
+	GetQuiz(ctx context.Context, id int64) (*models.Quiz, error)
+	UpdateQuiz(ctx context.Context, quiz *models.Quiz) error
+	DeleteQuiz(ctx context.Context, id int64) error
+	ListForSection(ctx context.Context, sectionID int64) ([]models.Quiz, error)
+	// ListForStudent returns the quizzes of the sections the student is enrolled in during the term.
+	ListForStudent(ctx context.Context, studentID, termID int64) ([]models.Quiz, error)
+	// ListQuizQuestions returns the questions of the quiz in their set order.
+	ListQuizQuestions(ctx context.Context, quizID int64) ([]models.Question, error)
+	// SetQuizQuestions replaces the questions of a quiz nobody has attempted yet.
+	SetQuizQuestions(ctx context.Context, quizID int64, questionIDs []int64) error
+
+	// StartAttempt adds the student's next attempt with the given layout, its deadline set by
+	// the database. If the student already has an attempt in progress, that attempt is
+	// returned instead and started is false.
+	StartAttempt(ctx context.Context, attempt *models.QuizAttempt) (started bool, err error)
+	GetAttempt(ctx context.Context, id int64) (*models.QuizAttempt, error)
+	ListAttempts(ctx context.Context, quizID int64) ([]models.QuizAttempt, error)
+	// ListStudentAttempts returns the student's attempts at quizzes of the term.
+	ListStudentAttempts(ctx context.Context, studentID, termID int64) ([]models.QuizAttempt, error)
+	// ListUnfinished returns the attempts that ran out of time without being submitted, or were
+	// submitted without being graded, optionally limited to a quiz and a student (zero for any).
+	ListUnfinished(ctx context.Context, quizID, studentID int64, grace time.Duration) ([]int64, error)
+	ListResponses(ctx context.Context, attemptID int64) ([]models.QuizResponse, error)
+	// SaveAnswers stores answers of an attempt in progress, unless it is more than grace past
+	// its deadline.
+	SaveAnswers(ctx context.Context, attemptID int64, answers []models.QuizAnswer, grace time.Duration) error
+	// CloseAttempt submits an attempt in progress; no more answers are accepted afterwards.
+	CloseAttempt(ctx context.Context, attemptID int64) error
+	// RecordAutoGrades stores the points of the graded responses of a submitted attempt and
+	// updates its score.
+	RecordAutoGrades(ctx context.Context, attemptID int64, responses []models.QuizResponse) error
+	// GradeResponse stores the teacher's points and feedback for a response of a submitted
+	// attempt and updates its score.
+	GradeResponse(ctx context.Context, response *models.QuizResponse) error
+	// GradingQueue returns the free-text answers of the quiz waiting to be graded, oldest first.
+	GradingQueue(ctx context.Context, quizID int64) ([]models.QueuedResponse, error)
+}
+
+type quizRepository struct {
+	db *txPool
+}
+
+// NewQuizRepository creates a new QuizRepository instance.
+func NewQuizRepository(db *pgxpool.Pool) QuizRepository {
+	return &quizRepository{db: newTxPool(db)}
+}
+
+// bankColumns selects from question_banks b.
+const bankColumns = `b.id, b.name, b.description, b.course_id, b.owner_id,
+	(SELECT COUNT(*) FROM questions q WHERE q.bank_id = b.id), b.created_at, b.updated_at`
+
+func scanBank(row pgx.Row) (*models.QuestionBank, error) {
+	bank := &models.QuestionBank{}
+	err := row.Scan(
+		&bank.ID, &bank.Name, &bank.Description, &bank.CourseID, &bank.OwnerID, &bank.QuestionCount,
+		&bank.CreatedAt, &bank.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return bank, nil
+}
+
+// bankWriteError maps errors of writing a bank, whose course must exist.
+func bankWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+		return appErrors.New(http.StatusBadRequest, "Course does not exist")
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErrors.ErrNotFound
+	}
+	return appErrors.ErrInternalServerError
+}
+
+// questionColumns selects from questions q.
+const questionColumns = `q.id, q.bank_id, q.type, q.prompt, q.points, q.options, q.correct_options, q.correct_bool,
+	q.numeric_answer, q.tolerance, q.created_at, q.updated_at`
+
+func scanQuestion(row pgx.Row) (*models.Question, error) {
+	question := &models.Question{}
+	err := row.Scan(
+		&question.ID, &question.BankID, &question.Type, &question.Prompt, &question.Points, &question.Options,
+		&question.CorrectOptions, &question.CorrectBool, &question.NumericAnswer, &question.Tolerance,
+		&question.CreatedAt, &question.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	if question.Options == nil {
+		question.Options = make([]models.QuestionOption, 0)
+	}
+	if question.CorrectOptions == nil {
+		question.CorrectOptions = make([]int64, 0)
+	}
+	return question, nil
+}
+
+// quizColumns selects from quizzes z joined with course_sections s and courses c.
+const quizColumns = `z.id, z.section_id, s.code, s.course_id, c.code, s.term_id, z.title, z.instructions, z.opens_at,
+	z.closes_at, z.time_limit_minutes, z.max_attempts, z.shuffle_questions, z.shuffle_options,
+	(SELECT COUNT(*) FROM quiz_questions qq WHERE qq.quiz_id = z.id),
+	(SELECT COALESCE(SUM(q.points), 0) FROM quiz_questions qq JOIN questions q ON q.id = qq.question_id WHERE qq.quiz_id = z.id),
+	z.created_by, z.created_at, z.updated_at`
+
+const quizJoins = ` z JOIN course_sections s ON s.id = z.section_id JOIN courses c ON c.id = s.course_id`
+
+func scanQuiz(row pgx.Row) (*models.Quiz, error) {
+	quiz := &models.Quiz{}
+	err := row.Scan(
+		&quiz.ID, &quiz.SectionID, &quiz.SectionCode, &quiz.CourseID, &quiz.CourseCode, &quiz.TermID, &quiz.Title,
+		&quiz.Instructions, &quiz.OpensAt, &quiz.ClosesAt, &quiz.TimeLimitMinutes, &quiz.MaxAttempts,
+		&quiz.ShuffleQuestions, &quiz.ShuffleOptions, &quiz.QuestionCount, &quiz.MaxScore, &quiz.CreatedBy,
+		&quiz.CreatedAt, &quiz.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return quiz, nil
+}
+
+// attemptColumns selects from quiz_attempts qa joined with the student u.
+const attemptColumns = `qa.id, qa.quiz_id, qa.student_id, u.name, qa.attempt_number, qa.status, qa.started_at,
+	qa.deadline_at, qa.submitted_at, qa.score, qa.max_score, qa.layout`
+
+const attemptJoins = ` qa JOIN users u ON u.id = qa.student_id`
+
+func scanAttempt(row pgx.Row) (*models.QuizAttempt, error) {
+	attempt := &models.QuizAttempt{}
+	err := row.Scan(
+		&attempt.ID, &attempt.QuizID, &attempt.StudentID, &attempt.StudentName, &attempt.AttemptNumber,
+		&attempt.Status, &attempt.StartedAt, &attempt.DeadlineAt, &attempt.SubmittedAt, &attempt.Score,
+		&attempt.MaxScore, &attempt.Layout,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return attempt, nil
+}
+
+// responseColumns selects from quiz_responses r.
+const responseColumns = `r.attempt_id, r.question_id, r.selected_options, r.bool_answer, r.numeric_answer, r.text_answer,
+	r.answered_at, r.points, r.auto_graded, r.feedback, r.graded_by, r.graded_at`
+
+func (r *quizRepository) CreateBank(ctx context.Context, bank *models.QuestionBank) error {
+	var id int64
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO question_banks (name, description, course_id, owner_id) VALUES ($1, $2, $3, $4) RETURNING id
+	`, bank.Name, bank.Description, bank.CourseID, bank.OwnerID).Scan(&id)
+	if err != nil {
+		return bankWriteError(err)
+	}
+	created, err := r.GetBank(ctx, id)
+	if err != nil {
+		return err
+	}
+	*bank = *created
+	return nil
+}
+
+func (r *quizRepository) GetBank(ctx context.Context, id int64) (*models.QuestionBank, error) {
+	return scanBank(r.db.QueryRow(ctx, "SELECT "+bankColumns+" FROM question_banks b WHERE b.id = $1", id))
+}
+
+func (r *quizRepository) UpdateBank(ctx context.Context, bank *models.QuestionBank) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE question_banks SET name = $2, description = $3, course_id = $4, updated_at = NOW() WHERE id = $1
+	`, bank.ID, bank.Name, bank.Description, bank.CourseID)
+	if err != nil {
+		return bankWriteError(err)
+	}
+	updated, err := r.GetBank(ctx, bank.ID)
+	if err != nil {
+		return err
+	}
+	*bank = *updated
+	return nil
+}
+
+func (r *quizRepository) DeleteBank(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM question_banks WHERE id = $1", id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+			return appErrors.ErrQuestionBankInUse
+		}
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *quizRepository) ListBanks(ctx context.Context, ownerID *int64) ([]models.QuestionBank, error) {
+	rows, err := r.db.Query(ctx, "SELECT "+bankColumns+" FROM question_banks b WHERE $1::int IS NULL OR b.owner_id = $1 ORDER BY b.name, b.id", ownerID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	banks := make([]models.QuestionBank, 0)
+	for rows.Next() {
+		bank, err := scanBank(rows)
+		if err != nil {
+			return nil, err
+		}
+		banks = append(banks, *bank)
+	}
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return banks, nil
+}
+
+func (r *quizRepository) CreateQuestion(ctx context.Context, question *models.Question) error {
+	query := `
+		WITH created AS (
+			INSERT INTO questions (bank_id, type, prompt, points, options, correct_options, correct_bool, numeric_answer, tolerance)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING *
+		)
+		SELECT ` + questionColumns + ` FROM created q`
+	created, err := scanQuestion(r.db.QueryRow(ctx, query,
+		question.BankID, question.Type, question.Prompt, question.Points, question.Options, question.CorrectOptions,
+		question.CorrectBool, question.NumericAnswer, question.Tolerance,
+	))
+	if err != nil {
+		return err
+	}
+	*question = *created
+	return nil
+}
+
+func (r *quizRepository) GetQuestion(ctx context.Context, id int64) (*models.Question, error) {
+	return scanQuestion(r.db.QueryRow(ctx, "SELECT "+questionColumns+" FROM questions q WHERE q.id = $1", id))
+}
+
+func (r *quizRepository) UpdateQuestion(ctx context.Context, question *models.Question) error {
+	query := `
+		WITH updated AS (
+			UPDATE questions
+			SET type = $2, prompt = $3, points = $4, options = $5, correct_options = $6, correct_bool = $7,
+			    numeric_answer = $8, tolerance = $9, updated_at = NOW()
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + questionColumns + ` FROM updated q`
+	updated, err := scanQuestion(r.db.QueryRow(ctx, query,
+		question.ID, question.Type, question.Prompt, question.Points, question.Options, question.CorrectOptions,
+		question.CorrectBool, question.NumericAnswer, question.Tolerance,
+	))
+	if err != nil {
+		return err
+	}
+	*question = *updated
+	return nil
+}
+
+func (r *quizRepository) DeleteQuestion(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM questions WHERE id = $1", id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+			return appErrors.ErrQuestionInUse
+		}
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *quizRepository) ImportQuestions(ctx context.Context, bank *models.QuestionBank, questions []models.Question) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	if bank.ID == 0 {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO question_banks (name, description, course_id, owner_id) VALUES ($1, $2, $3, $4) RETURNING id
+		`, bank.Name, bank.Description, bank.CourseID, bank.OwnerID).Scan(&bank.ID)
+		if err != nil {
+			return bankWriteError(err)
+		}
+	} else if _, err := tx.Exec(ctx, "UPDATE question_banks SET updated_at = NOW() WHERE id = $1", bank.ID); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	for i := range questions {
+		question := &questions[i]
+		question.BankID = bank.ID
+		err := tx.QueryRow(ctx, `
+			INSERT INTO questions (bank_id, type, prompt, points, options, correct_options, correct_bool, numeric_answer, tolerance)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id
+		`, question.BankID, question.Type, question.Prompt, question.Points, question.Options, question.CorrectOptions,
+			question.CorrectBool, question.NumericAnswer, question.Tolerance,
+		).Scan(&question.ID)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	imported, err := r.GetBank(ctx, bank.ID)
+	if err != nil {
+		return err
+	}
+	*bank = *imported
+	return nil
+}
+
+func (r *quizRepository) queryQuestions(ctx context.Context, query string, args ...interface{}) ([]models.Question, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	questions := make([]models.Question, 0)
+	for rows.Next() {
+		question, err := scanQuestion(rows)
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, *question)
+	}
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return questions, nil
+}
+
+func (r *quizRepository) ListBankQuestions(ctx context.Context, bankID int64) ([]models.Question, error) {
+	return r.queryQuestions(ctx, "SELECT "+questionColumns+" FROM questions q WHERE q.bank_id = $1 ORDER BY q.id", bankID)
+}
+
+func (r *quizRepository) GetQuestions(ctx context.Context, ids []int64) ([]models.Question, error) {
+	return r.queryQuestions(ctx, "SELECT "+questionColumns+" FROM questions q WHERE q.id = ANY($1) ORDER BY q.id", ids)
+}
+
+func (r *quizRepository) IsQuestionAnswered(ctx context.Context, id int64) (bool, error) {
+	var answered bool
+	err := r.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM quiz_questions qq JOIN quiz_attempts qa ON qa.quiz_id = qq.quiz_id WHERE qq.question_id = $1
+		)
+	`, id).Scan(&answered)
+	if err != nil {
+		return false, appErrors.ErrInternalServerError
+	}
+	return answered, nil
+}
+
+func (r *quizRepository) CreateQuiz(ctx context.Context, quiz *models.Quiz) error {
+	query := `
+		WITH created AS (
+			INSERT INTO quizzes (section_id, title, instructions, opens_at, closes_at, time_limit_minutes, max_attempts,
+			                     shuffle_questions, shuffle_options, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING *
+		)
+		SELECT ` + quizColumns + ` FROM created` + quizJoins
+	created, err := scanQuiz(r.db.QueryRow(ctx, query,
+		quiz.SectionID, quiz.Title, quiz.Instructions, quiz.OpensAt, quiz.ClosesAt, quiz.TimeLimitMinutes,
+		quiz.MaxAttempts, quiz.ShuffleQuestions, quiz.ShuffleOptions, quiz.CreatedBy,
+	))
+	if err != nil {
+		return err
+	}
+	*quiz = *created
+	return nil
+}
+
+func (r *quizRepository) GetQuiz(ctx context.Context, id int64) (*models.Quiz, error) {
+	return scanQuiz(r.db.QueryRow(ctx, "SELECT "+quizColumns+" FROM quizzes"+quizJoins+" WHERE z.id = $1", id))
+}
+
+func (r *quizRepository) UpdateQuiz(ctx context.Context, quiz *models.Quiz) error {
+	query := `
+		WITH updated AS (
+			UPDATE quizzes
+			SET title = $2, instructions = $3, opens_at = $4, closes_at = $5, time_limit_minutes = $6,
+			    max_attempts = $7, shuffle_questions = $8, shuffle_options = $9, updated_at = NOW()
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + quizColumns + ` FROM updated` + quizJoins
+	updated, err := scanQuiz(r.db.QueryRow(ctx, query,
+		quiz.ID, quiz.Title, quiz.Instructions, quiz.OpensAt, quiz.ClosesAt, quiz.TimeLimitMinutes,
+		quiz.MaxAttempts, quiz.ShuffleQuestions, quiz.ShuffleOptions,
+	))
+	if err != nil {
+		return err
+	}
+	*quiz = *updated
+	return nil
+}
+
+func (r *quizRepository) DeleteQuiz(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM quizzes WHERE id = $1", id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+			return appErrors.ErrQuizInUse
+		}
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *quizRepository) queryQuizzes(ctx context.Context, query string, args ...interface{}) ([]models.Quiz, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	quizzes := make([]models.Quiz, 0)
+	for rows.Next() {
+		quiz, err := scanQuiz(rows)
+		if err != nil {
+			return nil, err
+		}
+		quizzes = append(quizzes, *quiz)
+	}
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return quizzes, nil
+}
+
+func (r *quizRepository) ListForSection(ctx context.Context, sectionID int64) ([]models.Quiz, error) {
+	query := "SELECT " + quizColumns + " FROM quizzes" + quizJoins + " WHERE z.section_id = $1 ORDER BY z.opens_at, z.id"
+	return r.queryQuizzes(ctx, query, sectionID)
+}
+
+func (r *quizRepository) ListForStudent(ctx context.Context, studentID, termID int64) ([]models.Quiz, error) {
+	query := "SELECT " + quizColumns + " FROM quizzes" + quizJoins + `
+		JOIN enrollments e ON e.section_id = s.id
+		WHERE e.student_id = $1 AND e.status = 'enrolled' AND s.term_id = $2
+		ORDER BY z.opens_at, z.id`
+	return r.queryQuizzes(ctx, query, studentID, termID)
+}
+
+func (r *quizRepository) ListQuizQuestions(ctx context.Context, quizID int64) ([]models.Question, error) {
+	query := "SELECT " + questionColumns + ` FROM quiz_questions qq JOIN questions q ON q.id = qq.question_id
+		WHERE qq.quiz_id = $1 ORDER BY qq.position`
+	return r.queryQuestions(ctx, query, quizID)
+}
+
+func (r *quizRepository) SetQuizQuestions(ctx context.Context, quizID int64, questionIDs []int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	// Lock the quiz so that no attempt starts while its questions change
+	var attempted bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM quiz_attempts WHERE quiz_id = z.id) FROM quizzes z WHERE z.id = $1 FOR UPDATE
+	`, quizID).Scan(&attempted)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErrors.ErrNotFound
+	}
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if attempted {
+		return appErrors.ErrQuizInUse
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM quiz_questions WHERE quiz_id = $1", quizID); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	for i, questionID := range questionIDs {
+		_, err := tx.Exec(ctx, "INSERT INTO quiz_questions (quiz_id, question_id, position) VALUES ($1, $2, $3)", quizID, questionID, i+1)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+	if _, err := tx.Exec(ctx, "UPDATE quizzes SET updated_at = NOW() WHERE id = $1", quizID); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *quizRepository) StartAttempt(ctx context.Context, attempt *models.QuizAttempt) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	// Lock the quiz so that simultaneous starts get consecutive attempt numbers
+	var maxAttempts int
+	err = tx.QueryRow(ctx, "SELECT max_attempts FROM quizzes WHERE id = $1 FOR UPDATE", attempt.QuizID).Scan(&maxAttempts)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return false, appErrors.ErrInternalServerError
+	}
+
+	current, err := scanAttempt(tx.QueryRow(ctx, "SELECT "+attemptColumns+" FROM quiz_attempts"+attemptJoins+`
+		WHERE qa.quiz_id = $1 AND qa.student_id = $2 AND qa.status = 'in_progress'`, attempt.QuizID, attempt.StudentID))
+	if err == nil {
+		*attempt = *current
+		return false, nil
+	}
+	if err != appErrors.ErrNotFound {
+		return false, err
+	}
+
+	var taken int
+	err = tx.QueryRow(ctx, "SELECT COUNT(*) FROM quiz_attempts WHERE quiz_id = $1 AND student_id = $2", attempt.QuizID, attempt.StudentID).Scan(&taken)
+	if err != nil {
+		return false, appErrors.ErrInternalServerError
+	}
+	if taken >= maxAttempts {
+		return false, appErrors.ErrNoAttemptsLeft
+	}
+
+	// LEAST ignores the NULL of quizzes without a time limit
+	var id int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO quiz_attempts (quiz_id, student_id, attempt_number, layout, deadline_at, max_score)
+		SELECT z.id, $2, $3, $4, LEAST(z.closes_at, CURRENT_TIMESTAMP + make_interval(mins => z.time_limit_minutes)), $5
+		FROM quizzes z
+		WHERE z.id = $1
+		RETURNING id
+	`, attempt.QuizID, attempt.StudentID, taken+1, attempt.Layout, attempt.MaxScore).Scan(&id)
+	if err != nil {
+		return false, appErrors.ErrInternalServerError
+	}
+	created, err := scanAttempt(tx.QueryRow(ctx, "SELECT "+attemptColumns+" FROM quiz_attempts"+attemptJoins+" WHERE qa.id = $1", id))
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, appErrors.ErrInternalServerError
+	}
+	*attempt = *created
+	return true, nil
+}
+
+func (r *quizRepository) GetAttempt(ctx context.Context, id int64) (*models.QuizAttempt, error) {
+	return scanAttempt(r.db.QueryRow(ctx, "SELECT "+attemptColumns+" FROM quiz_attempts"+attemptJoins+" WHERE qa.id = $1", id))
+}
+
+func (r *quizRepository) queryAttempts(ctx context.Context, query string, args ...interface{}) ([]models.QuizAttempt, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	attempts := make([]models.QuizAttempt, 0)
+	for rows.Next() {
+		attempt, err := scanAttempt(rows)
+		if err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, *attempt)
+	}
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return attempts, nil
+}
+
+func (r *quizRepository) ListAttempts(ctx context.Context, quizID int64) ([]models.QuizAttempt, error) {
+	query := "SELECT " + attemptColumns + " FROM quiz_attempts" + attemptJoins + `
+		WHERE qa.quiz_id = $1
+		ORDER BY u.name, qa.student_id, qa.attempt_number`
+	return r.queryAttempts(ctx, query, quizID)
+}
+
+func (r *quizRepository) ListStudentAttempts(ctx context.Context, studentID, termID int64) ([]models.QuizAttempt, error) {
+	query := "SELECT " + attemptColumns + " FROM quiz_attempts" + attemptJoins + `
+		JOIN quizzes z ON z.id = qa.quiz_id
+		JOIN course_sections s ON s.id = z.section_id
+		WHERE qa.student_id = $1 AND s.term_id = $2
+		ORDER BY qa.quiz_id, qa.attempt_number`
+	return r.queryAttempts(ctx, query, studentID, termID)
+}
+
+func (r *quizRepository) ListUnfinished(ctx context.Context, quizID, studentID int64, grace time.Duration) ([]int64, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id FROM quiz_attempts
+		WHERE ($1 = 0 OR quiz_id = $1) AND ($2 = 0 OR student_id = $2)
+		  AND ((status = 'in_progress' AND deadline_at + $3 < CURRENT_TIMESTAMP) OR (status <> 'in_progress' AND score IS NULL))
+		ORDER BY id
+	`, quizID, studentID, grace)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		ids = append(ids, id)
+	}
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return ids, nil
+}
+
+func (r *quizRepository) ListResponses(ctx context.Context, attemptID int64) ([]models.QuizResponse, error) {
+	rows, err := r.db.Query(ctx, "SELECT "+responseColumns+" FROM quiz_responses r WHERE r.attempt_id = $1 ORDER BY r.question_id", attemptID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	responses := make([]models.QuizResponse, 0)
+	for rows.Next() {
+		var response models.QuizResponse
+		err := rows.Scan(
+			&response.AttemptID, &response.QuestionID, &response.Selected, &response.Value, &response.Number,
+			&response.Text, &response.AnsweredAt, &response.Points, &response.AutoGraded, &response.Feedback,
+			&response.GradedBy, &response.GradedAt,
+		)
+		if err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		responses = append(responses, response)
+	}
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return responses, nil
+}
+
+// lockAttempt locks an attempt for the rest of the transaction and returns its status.
+func lockAttempt(ctx context.Context, tx pgx.Tx, attemptID int64) (string, error) {
+	var status string
+	err := tx.QueryRow(ctx, "SELECT status FROM quiz_attempts WHERE id = $1 FOR UPDATE", attemptID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", appErrors.ErrNotFound
+	}
+	if err != nil {
+		return "", appErrors.ErrInternalServerError
+	}
+	return status, nil
+}
+
+func (r *quizRepository) SaveAnswers(ctx context.Context, attemptID int64, answers []models.QuizAnswer, grace time.Duration) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	// The database clock decides whether time is up, as it set the deadline
+	var status string
+	var open bool
+	err = tx.QueryRow(ctx, `
+		SELECT status, CURRENT_TIMESTAMP <= deadline_at + $2 FROM quiz_attempts WHERE id = $1 FOR UPDATE
+	`, attemptID, grace).Scan(&status, &open)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErrors.ErrNotFound
+	}
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if status != "in_progress" {
+		return appErrors.ErrAttemptSubmitted
+	}
+	if !open {
+		return appErrors.ErrQuizTimeUp
+	}
+
+	for _, answer := range answers {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO quiz_responses (attempt_id, question_id, selected_options, bool_answer, numeric_answer, text_answer, answered_at)
+			VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)
+			ON CONFLICT (attempt_id, question_id) DO UPDATE
+			SET selected_options = EXCLUDED.selected_options, bool_answer = EXCLUDED.bool_answer,
+			    numeric_answer = EXCLUDED.numeric_answer, text_answer = EXCLUDED.text_answer, answered_at = EXCLUDED.answered_at
+		`, attemptID, answer.QuestionID, answer.Selected, answer.Value, answer.Number, answer.Text)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *quizRepository) CloseAttempt(ctx context.Context, attemptID int64) error {
+	// Attempts closed after their deadline count as submitted when time ran out
+	cmdTag, err := r.db.Exec(ctx, `
+		UPDATE quiz_attempts SET status = 'submitted', submitted_at = LEAST(CURRENT_TIMESTAMP, deadline_at)
+		WHERE id = $1 AND status = 'in_progress'
+	`, attemptID)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		if _, err := r.GetAttempt(ctx, attemptID); err != nil {
+			return err
+		}
+		return appErrors.ErrAttemptSubmitted
+	}
+	return nil
+}
+
+// updateScore sums up the graded responses of a submitted attempt; it is graded once no
+// response is waiting for points.
+func updateScore(ctx context.Context, tx pgx.Tx, attemptID int64) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE quiz_attempts qa
+		SET score = totals.score, status = CASE WHEN totals.pending > 0 THEN 'submitted' ELSE 'graded' END
+		FROM (
+			SELECT COALESCE(SUM(points), 0) AS score, COUNT(*) FILTER (WHERE points IS NULL) AS pending
+			FROM quiz_responses
+			WHERE attempt_id = $1
+		) totals
+		WHERE qa.id = $1
+	`, attemptID)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *quizRepository) RecordAutoGrades(ctx context.Context, attemptID int64, responses []models.QuizResponse) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	status, err := lockAttempt(ctx, tx, attemptID)
+	if err != nil {
+		return err
+	}
+	if status == "in_progress" {
+		return appErrors.New(http.StatusConflict, "Attempt has not been submitted yet")
+	}
+
+	// Unanswered questions get a response row too, so that every question has its points
+	for _, response := range responses {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO quiz_responses (attempt_id, question_id, points, auto_graded, graded_at)
+			VALUES ($1, $2, $3, TRUE, CURRENT_TIMESTAMP)
+			ON CONFLICT (attempt_id, question_id) DO UPDATE
+			SET points = EXCLUDED.points, auto_graded = TRUE, graded_at = EXCLUDED.graded_at
+		`, attemptID, response.QuestionID, response.Points)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+	if err := updateScore(ctx, tx, attemptID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *quizRepository) GradeResponse(ctx context.Context, response *models.QuizResponse) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	status, err := lockAttempt(ctx, tx, response.AttemptID)
+	if err != nil {
+		return err
+	}
+	if status == "in_progress" {
+		return appErrors.New(http.StatusConflict, "Attempt has not been submitted yet")
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE quiz_responses SET points = $3, feedback = $4, auto_graded = FALSE, graded_by = $5, graded_at = CURRENT_TIMESTAMP
+		WHERE attempt_id = $1 AND question_id = $2
+		RETURNING graded_at
+	`, response.AttemptID, response.QuestionID, response.Points, response.Feedback, response.GradedBy).Scan(&response.GradedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErrors.ErrNotFound
+	}
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if err := updateScore(ctx, tx, response.AttemptID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *quizRepository) GradingQueue(ctx context.Context, quizID int64) ([]models.QueuedResponse, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT qa.id, qa.student_id, u.name, qa.attempt_number, q.id, q.prompt, q.points, r.text_answer, qa.submitted_at
+		FROM quiz_responses r
+		JOIN quiz_attempts qa ON qa.id = r.attempt_id
+		JOIN users u ON u.id = qa.student_id
+		JOIN questions q ON q.id = r.question_id
+		WHERE qa.quiz_id = $1 AND qa.status = 'submitted' AND r.points IS NULL
+		ORDER BY qa.submitted_at, qa.id, q.id
+	`, quizID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	queue := make([]models.QueuedResponse, 0)
+	for rows.Next() {
+		var queued models.QueuedResponse
+		err := rows.Scan(
+			&queued.AttemptID, &queued.StudentID, &queued.StudentName, &queued.AttemptNumber, &queued.QuestionID,
+			&queued.Prompt, &queued.Points, &queued.Text, &queued.SubmittedAt,
+		)
+		if err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		queue = append(queue, queued)
+	}
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return queue, nil
+}
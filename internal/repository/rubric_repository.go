@@ -0,0 +1,394 @@
+// internal/repository/rubric_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RubricRepository defines the methods for rubrics and the scores given with them.
+type RubricRepository interface {
+	// CreateRubric adds the rubric with its criteria and levels.
+	CreateRubric(ctx context.Context, rubric *models.Rubric) error
+	GetRubric(ctx context.Context, id int64) (*models.Rubric, error)
+	// UpdateRubric replaces the rubric's details, criteria and levels. Rubrics that have been
+	// scored with cannot be changed.
+	UpdateRubric(ctx context.Context, rubric *models.Rubric) error
+	DeleteRubric(ctx context.Context, id int64) error
+	// ListRubrics returns the rubrics of the owner, or every rubric for nil.
+	ListRubrics(ctx context.Context, ownerID *int64) ([]models.Rubric, error)
+
+	// SetAssignmentRubric attaches the rubric to the assignment, or detaches it for nil, as
+	// long as nobody has been scored or peer reviewed on the assignment yet.
+	SetAssignmentRubric(ctx context.Context, assignmentID int64, rubricID *int64) error
+	// ListScores returns the student's rubric scores on the assignment.
+	ListScores(ctx context.Context, assignmentID, studentID int64) ([]models.RubricScore, error)
+	// SaveScores replaces the student's rubric scores on the assignment, provided the
+	// assignment is still graded against the given rubric.
+	SaveScores(ctx context.Context, assignmentID, studentID, rubricID int64, scores []models.RubricScore) error
+}
+
+type rubricRepository struct {
+	db *txPool
+}
+
+// NewRubricRepository creates a new RubricRepository instance.
+func NewRubricRepository(db *pgxpool.Pool) RubricRepository {
+	return &rubricRepository{db: newTxPool(db)}
+}
+
+// rubricColumns selects from rubrics r.
+const rubricColumns = `r.id, r.title, r.description, r.owner_id, r.created_at, r.updated_at`
+
+func scanRubric(row pgx.Row) (*models.Rubric, error) {
+	rubric := &models.Rubric{}
+	err := row.Scan(&rubric.ID, &rubric.Title, &rubric.Description, &rubric.OwnerID, &rubric.CreatedAt, &rubric.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	rubric.Criteria = make([]models.RubricCriterion, 0)
+	return rubric, nil
+}
+
+// loadCriteria fills in the criteria and levels of the given rubrics, and their maximum points.
+func (r *rubricRepository) loadCriteria(ctx context.Context, rubrics ...*models.Rubric) error {
+	if len(rubrics) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*models.Rubric, len(rubrics))
+	ids := make([]int64, 0, len(rubrics))
+	for _, rubric := range rubrics {
+		byID[rubric.ID] = rubric
+		ids = append(ids, rubric.ID)
+	}
+
+	query := `
+		SELECT rc.rubric_id, rc.id, rc.title, rc.description, rl.id, rl.title, rl.description, rl.points
+		FROM rubric_criteria rc
+		LEFT JOIN rubric_levels rl ON rl.criterion_id = rc.id
+		WHERE rc.rubric_id = ANY($1)
+		ORDER BY rc.rubric_id, rc.position, rl.position`
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rubricID int64
+		var criterion models.RubricCriterion
+		var levelID *int64
+		var levelTitle *string
+		var levelDescription *string
+		var levelPoints *float64
+		err := rows.Scan(
+			&rubricID, &criterion.ID, &criterion.Title, &criterion.Description, &levelID, &levelTitle,
+			&levelDescription, &levelPoints,
+		)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+		rubric := byID[rubricID]
+		if n := len(rubric.Criteria); n == 0 || rubric.Criteria[n-1].ID != criterion.ID {
+			criterion.Levels = make([]models.RubricLevel, 0)
+			rubric.Criteria = append(rubric.Criteria, criterion)
+		}
+		if levelID != nil {
+			last := &rubric.Criteria[len(rubric.Criteria)-1]
+			last.Levels = append(last.Levels, models.RubricLevel{
+				ID: *levelID, Title: *levelTitle, Description: levelDescription, Points: *levelPoints,
+			})
+			last.MaxPoints = math.Max(last.MaxPoints, *levelPoints)
+		}
+	}
+	if rows.Err() != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	for _, rubric := range rubrics {
+		rubric.MaxPoints = 0
+		for _, criterion := range rubric.Criteria {
+			rubric.MaxPoints += criterion.MaxPoints
+		}
+	}
+	return nil
+}
+
+// insertCriteria adds the criteria of the rubric with their levels, in their given order.
+func insertCriteria(ctx context.Context, tx pgx.Tx, rubric *models.Rubric) error {
+	for i := range rubric.Criteria {
+		criterion := &rubric.Criteria[i]
+		err := tx.QueryRow(ctx, `
+			INSERT INTO rubric_criteria (rubric_id, title, description, position) VALUES ($1, $2, $3, $4) RETURNING id
+		`, rubric.ID, criterion.Title, criterion.Description, i+1).Scan(&criterion.ID)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+		for j := range criterion.Levels {
+			level := &criterion.Levels[j]
+			err := tx.QueryRow(ctx, `
+				INSERT INTO rubric_levels (criterion_id, title, description, points, position) VALUES ($1, $2, $3, $4, $5) RETURNING id
+			`, criterion.ID, level.Title, level.Description, level.Points, j+1).Scan(&level.ID)
+			if err != nil {
+				return appErrors.ErrInternalServerError
+			}
+		}
+	}
+	return nil
+}
+
+func (r *rubricRepository) CreateRubric(ctx context.Context, rubric *models.Rubric) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO rubrics (title, description, owner_id) VALUES ($1, $2, $3) RETURNING id
+	`, rubric.Title, rubric.Description, rubric.OwnerID).Scan(&rubric.ID)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if err := insertCriteria(ctx, tx, rubric); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	created, err := r.GetRubric(ctx, rubric.ID)
+	if err != nil {
+		return err
+	}
+	*rubric = *created
+	return nil
+}
+
+func (r *rubricRepository) GetRubric(ctx context.Context, id int64) (*models.Rubric, error) {
+	rubric, err := scanRubric(r.db.QueryRow(ctx, "SELECT "+rubricColumns+" FROM rubrics r WHERE r.id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadCriteria(ctx, rubric); err != nil {
+		return nil, err
+	}
+	return rubric, nil
+}
+
+func (r *rubricRepository) UpdateRubric(ctx context.Context, rubric *models.Rubric) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	cmdTag, err := tx.Exec(ctx, `
+		UPDATE rubrics SET title = $2, description = $3, updated_at = NOW() WHERE id = $1
+	`, rubric.ID, rubric.Title, rubric.Description)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	// Criteria that have been scored with are protected by the scores referencing them
+	if _, err := tx.Exec(ctx, "DELETE FROM rubric_criteria WHERE rubric_id = $1", rubric.ID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+			return appErrors.ErrRubricInUse
+		}
+		return appErrors.ErrInternalServerError
+	}
+	if err := insertCriteria(ctx, tx, rubric); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	updated, err := r.GetRubric(ctx, rubric.ID)
+	if err != nil {
+		return err
+	}
+	*rubric = *updated
+	return nil
+}
+
+func (r *rubricRepository) DeleteRubric(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM rubrics WHERE id = $1", id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+			return appErrors.ErrRubricInUse
+		}
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *rubricRepository) ListRubrics(ctx context.Context, ownerID *int64) ([]models.Rubric, error) {
+	rows, err := r.db.Query(ctx, "SELECT "+rubricColumns+" FROM rubrics r WHERE $1::int IS NULL OR r.owner_id = $1 ORDER BY r.title, r.id", ownerID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	rubrics := make([]models.Rubric, 0)
+	for rows.Next() {
+		rubric, err := scanRubric(rows)
+		if err != nil {
+			return nil, err
+		}
+		rubrics = append(rubrics, *rubric)
+	}
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	pointers := make([]*models.Rubric, len(rubrics))
+	for i := range rubrics {
+		pointers[i] = &rubrics[i]
+	}
+	if err := r.loadCriteria(ctx, pointers...); err != nil {
+		return nil, err
+	}
+	return rubrics, nil
+}
+
+// lockAssignmentRubric locks the assignment and returns the rubric it is graded against.
+func lockAssignmentRubric(ctx context.Context, tx pgx.Tx, assignmentID int64) (*int64, error) {
+	var rubricID *int64
+	err := tx.QueryRow(ctx, "SELECT rubric_id FROM assignments WHERE id = $1 FOR UPDATE", assignmentID).Scan(&rubricID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return rubricID, nil
+}
+
+func (r *rubricRepository) SetAssignmentRubric(ctx context.Context, assignmentID int64, rubricID *int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	if _, err := lockAssignmentRubric(ctx, tx, assignmentID); err != nil {
+		return err
+	}
+	// Peer reviews are handed in against the rubric too, or against the assignment's points without one
+	var scored bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM rubric_scores WHERE assignment_id = $1)
+		    OR EXISTS (SELECT 1 FROM peer_reviews WHERE assignment_id = $1 AND submitted_at IS NOT NULL)
+	`, assignmentID).Scan(&scored)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if scored {
+		return appErrors.ErrAssignmentScored
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE assignments SET rubric_id = $2, updated_at = NOW() WHERE id = $1", assignmentID, rubricID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+			return appErrors.New(http.StatusBadRequest, "Rubric does not exist")
+		}
+		return appErrors.ErrInternalServerError
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *rubricRepository) ListScores(ctx context.Context, assignmentID, studentID int64) ([]models.RubricScore, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT rs.criterion_id, rs.level_id, rs.points, rs.comment, rs.scored_by, rs.scored_at
+		FROM rubric_scores rs
+		JOIN rubric_criteria rc ON rc.id = rs.criterion_id
+		WHERE rs.assignment_id = $1 AND rs.student_id = $2
+		ORDER BY rc.position
+	`, assignmentID, studentID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	scores := make([]models.RubricScore, 0)
+	for rows.Next() {
+		var score models.RubricScore
+		err := rows.Scan(&score.CriterionID, &score.LevelID, &score.Points, &score.Comment, &score.ScoredBy, &score.ScoredAt)
+		if err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		scores = append(scores, score)
+	}
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return scores, nil
+}
+
+func (r *rubricRepository) SaveScores(ctx context.Context, assignmentID, studentID, rubricID int64, scores []models.RubricScore) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	// The lock keeps the rubric from being swapped while the scores are written
+	current, err := lockAssignmentRubric(ctx, tx, assignmentID)
+	if err != nil {
+		return err
+	}
+	if current == nil || *current != rubricID {
+		return appErrors.New(http.StatusConflict, "The assignment's rubric has changed")
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM rubric_scores WHERE assignment_id = $1 AND student_id = $2", assignmentID, studentID)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	for i := range scores {
+		score := &scores[i]
+		err := tx.QueryRow(ctx, `
+			INSERT INTO rubric_scores (assignment_id, student_id, criterion_id, level_id, points, comment, scored_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING scored_at
+		`, assignmentID, studentID, score.CriterionID, score.LevelID, score.Points, score.Comment, score.ScoredBy,
+		).Scan(&score.ScoredAt)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+// internal/repository/section_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SectionRepository defines the methods for course sections, their meetings and timetables.
+type SectionRepository interface {
+	CreateSection(ctx context.Context, section *models.Section) error
+	GetSection(ctx context.Context, id int64) (*models.Section, error)
+	UpdateSection(ctx context.Context, section *models.Section) error
+	DeleteSection(ctx context.Context, id int64) error
+	ListForOffering(ctx context.Context, courseID, termID int64) ([]models.Section, error)
+	// StudentTimetable returns the meetings of the sections the student is enrolled or waitlisted in.
+	StudentTimetable(ctx context.Context, studentID, termID int64) ([]models.TimetableEntry, error)
+	// InstructorTimetable returns the meetings of the sections the instructor teaches.
+	InstructorTimetable(ctx context.Context, instructorID, termID int64) ([]models.TimetableEntry, error)
+	ListTermMeetings(ctx context.Context, termID int64) ([]models.TimetableEntry, error)
+}
+
+type sectionRepository struct {
+	db *txPool
+}
+
+// NewSectionRepository creates a new SectionRepository instance.
+func NewSectionRepository(db *pgxpool.Pool) SectionRepository {
+	return &sectionRepository{db: newTxPool(db)}
+}
+
+// sectionColumns selects from course_sections s joined with courses c, terms t and the instructor iu.
+const sectionColumns = `s.id, s.course_id, c.code, s.term_id, t.code, s.code, s.instructor_id, iu.name, s.created_at, s.updated_at`
+
+const sectionJoins = ` s JOIN courses c ON c.id = s.course_id JOIN terms t ON t.id = s.term_id
+	LEFT JOIN users iu ON iu.id = s.instructor_id`
+
+func scanSection(row pgx.Row) (*models.Section, error) {
+	section := &models.Section{}
+	err := row.Scan(
+		&section.ID, &section.CourseID, &section.CourseCode, &section.TermID, &section.TermCode, &section.Code,
+		&section.InstructorID, &section.InstructorName, &section.CreatedAt, &section.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	section.Meetings = make([]models.SectionMeeting, 0)
+	return section, nil
+}
+
+const meetingColumns = `id, section_id, day_of_week, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'), room`
+
+// timetableColumns selects from section_meetings m joined with course_sections s, courses c
+// and the instructor iu. The status column is appended by the caller.
+const timetableColumns = `s.course_id, c.code, c.title, s.id, s.code, m.id, m.day_of_week,
+	to_char(m.start_time, 'HH24:MI'), to_char(m.end_time, 'HH24:MI'), m.room, s.instructor_id, iu.name`
+
+const timetableJoins = ` m JOIN course_sections s ON s.id = m.section_id JOIN courses c ON c.id = s.course_id
+	LEFT JOIN users iu ON iu.id = s.instructor_id`
+
+// sectionWriteError maps constraint violations on section writes to application errors.
+func sectionWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique violation
+			return appErrors.ErrSectionCodeExists
+		case "23503": // foreign key violation
+			return appErrors.ErrOfferingNotFound
+		}
+	}
+	return appErrors.ErrInternalServerError
+}
+
+// loadMeetings fills in the meetings of the given sections.
+func (r *sectionRepository) loadMeetings(ctx context.Context, sections ...*models.Section) error {
+	if len(sections) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*models.Section, len(sections))
+	ids := make([]int64, 0, len(sections))
+	for _, section := range sections {
+		byID[section.ID] = section
+		ids = append(ids, section.ID)
+	}
+
+	query := "SELECT " + meetingColumns + " FROM section_meetings WHERE section_id = ANY($1) ORDER BY day_of_week, start_time, id"
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var meeting models.SectionMeeting
+		if err := rows.Scan(&meeting.ID, &meeting.SectionID, &meeting.DayOfWeek, &meeting.StartTime, &meeting.EndTime, &meeting.Room); err != nil {
+			return appErrors.ErrInternalServerError
+		}
+		section := byID[meeting.SectionID]
+		section.Meetings = append(section.Meetings, meeting)
+	}
+
+	if rows.Err() != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+// replaceMeetings swaps the section's meetings for the given ones within the transaction.
+func replaceMeetings(ctx context.Context, tx pgx.Tx, section *models.Section) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM section_meetings WHERE section_id = $1", section.ID); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	for i := range section.Meetings {
+		meeting := &section.Meetings[i]
+		meeting.SectionID = section.ID
+		err := tx.QueryRow(ctx, `
+			INSERT INTO section_meetings (section_id, day_of_week, start_time, end_time, room)
+			VALUES ($1, $2, $3::time, $4::time, $5)
+			RETURNING id
+		`, section.ID, meeting.DayOfWeek, meeting.StartTime, meeting.EndTime, meeting.Room).Scan(&meeting.ID)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+	return nil
+}
+
+func (r *sectionRepository) CreateSection(ctx context.Context, section *models.Section) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO course_sections (course_id, term_id, code, instructor_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`, section.CourseID, section.TermID, section.Code, section.InstructorID).Scan(&section.ID)
+	if err != nil {
+		return sectionWriteError(err)
+	}
+	if err := replaceMeetings(ctx, tx, section); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	created, err := r.GetSection(ctx, section.ID)
+	if err != nil {
+		return err
+	}
+	*section = *created
+	return nil
+}
+
+func (r *sectionRepository) GetSection(ctx context.Context, id int64) (*models.Section, error) {
+	section, err := scanSection(r.db.QueryRow(ctx, "SELECT "+sectionColumns+" FROM course_sections"+sectionJoins+" WHERE s.id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadMeetings(ctx, section); err != nil {
+		return nil, err
+	}
+	return section, nil
+}
+
+// UpdateSection saves the section's code and instructor and replaces its meetings.
+func (r *sectionRepository) UpdateSection(ctx context.Context, section *models.Section) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	cmdTag, err := tx.Exec(ctx, `
+		UPDATE course_sections SET code = $2, instructor_id = $3, updated_at = NOW() WHERE id = $1
+	`, section.ID, section.Code, section.InstructorID)
+	if err != nil {
+		return sectionWriteError(err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	if err := replaceMeetings(ctx, tx, section); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+
+	updated, err := r.GetSection(ctx, section.ID)
+	if err != nil {
+		return err
+	}
+	*section = *updated
+	return nil
+}
+
+func (r *sectionRepository) DeleteSection(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM course_sections WHERE id = $1", id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+			return appErrors.ErrSectionInUse
+		}
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *sectionRepository) ListForOffering(ctx context.Context, courseID, termID int64) ([]models.Section, error) {
+	query := "SELECT " + sectionColumns + " FROM course_sections" + sectionJoins + " WHERE s.course_id = $1 AND s.term_id = $2 ORDER BY s.code"
+	rows, err := r.db.Query(ctx, query, courseID, termID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	sections := make([]models.Section, 0)
+	for rows.Next() {
+		section, err := scanSection(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		sections = append(sections, *section)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	ptrs := make([]*models.Section, len(sections))
+	for i := range sections {
+		ptrs[i] = &sections[i]
+	}
+	if err := r.loadMeetings(ctx, ptrs...); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
+// queryTimetable runs a timetable query whose last selected column is the entry status.
+func (r *sectionRepository) queryTimetable(ctx context.Context, query string, args ...interface{}) ([]models.TimetableEntry, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	entries := make([]models.TimetableEntry, 0)
+	for rows.Next() {
+		var e models.TimetableEntry
+		err := rows.Scan(
+			&e.CourseID, &e.CourseCode, &e.CourseTitle, &e.SectionID, &e.SectionCode, &e.MeetingID, &e.DayOfWeek,
+			&e.StartTime, &e.EndTime, &e.Room, &e.InstructorID, &e.InstructorName, &e.Status,
+		)
+		if err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		entries = append(entries, e)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return entries, nil
+}
+
+func (r *sectionRepository) StudentTimetable(ctx context.Context, studentID, termID int64) ([]models.TimetableEntry, error) {
+	query := "SELECT " + timetableColumns + ", e.status FROM section_meetings" + timetableJoins + `
+		JOIN enrollments e ON e.section_id = s.id
+		WHERE e.student_id = $1 AND e.term_id = $2 AND e.status <> 'dropped'
+		ORDER BY m.day_of_week, m.start_time, c.code`
+	return r.queryTimetable(ctx, query, studentID, termID)
+}
+
+func (r *sectionRepository) InstructorTimetable(ctx context.Context, instructorID, termID int64) ([]models.TimetableEntry, error) {
+	query := "SELECT " + timetableColumns + ", '' FROM section_meetings" + timetableJoins + `
+		WHERE s.instructor_id = $1 AND s.term_id = $2
+		ORDER BY m.day_of_week, m.start_time, c.code`
+	return r.queryTimetable(ctx, query, instructorID, termID)
+}
+
+// ListTermMeetings returns every meeting of the term's sections.
+func (r *sectionRepository) ListTermMeetings(ctx context.Context, termID int64) ([]models.TimetableEntry, error) {
+	query := "SELECT " + timetableColumns + ", '' FROM section_meetings" + timetableJoins + `
+		WHERE s.term_id = $1
+		ORDER BY m.day_of_week, m.start_time, c.code, s.code`
+	return r.queryTimetable(ctx, query, termID)
+}
@@ -0,0 +1,281 @@
+// internal/repository/term_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TermRepository defines the methods for academic terms and their holidays.
+type TermRepository interface {
+	CreateTerm(ctx context.Context, term *models.Term) error
+	GetTermByID(ctx context.Context, id int64) (*models.Term, error)
+	GetTermAt(ctx context.Context, day time.Time) (*models.Term, error)
+	UpdateTerm(ctx context.Context, term *models.Term) error
+	DeleteTerm(ctx context.Context, id int64) error
+	ListTerms(ctx context.Context, limit, offset int) ([]models.Term, int64, error)
+	// ListTermsEndingAfter returns the terms that end on or after the given day, earliest first.
+	ListTermsEndingAfter(ctx context.Context, day time.Time) ([]models.Term, error)
+	CreateHoliday(ctx context.Context, holiday *models.TermHoliday) error
+	DeleteHoliday(ctx context.Context, termID, id int64) (*models.TermHoliday, error)
+}
+
+type termRepository struct {
+	db *txPool
+}
+
+// NewTermRepository creates a new TermRepository instance.
+func NewTermRepository(db *pgxpool.Pool) TermRepository {
+	return &termRepository{db: newTxPool(db)}
+}
+
+const termColumns = `id, code, name, start_date, end_date, add_deadline, drop_deadline, exam_start, exam_end,
+	created_by, created_at, updated_at`
+
+func scanTerm(row pgx.Row) (*models.Term, error) {
+	term := &models.Term{}
+	err := row.Scan(
+		&term.ID, &term.Code, &term.Name, &term.StartDate, &term.EndDate, &term.AddDeadline, &term.DropDeadline,
+		&term.ExamStart, &term.ExamEnd, &term.CreatedBy, &term.CreatedAt, &term.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	term.Holidays = make([]models.TermHoliday, 0)
+	return term, nil
+}
+
+const holidayColumns = `id, term_id, name, start_date, end_date`
+
+func scanHoliday(row pgx.Row) (*models.TermHoliday, error) {
+	holiday := &models.TermHoliday{}
+	err := row.Scan(&holiday.ID, &holiday.TermID, &holiday.Name, &holiday.StartDate, &holiday.EndDate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return holiday, nil
+}
+
+// termWriteError maps constraint violations on term writes to application errors.
+func termWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is unique violation
+		return appErrors.ErrTermCodeExists
+	}
+	if errors.As(err, &pgErr) && pgErr.Code == "23P01" { // 23P01 is exclusion violation
+		return appErrors.ErrTermOverlaps
+	}
+	return appErrors.ErrInternalServerError
+}
+
+// loadHolidays fills in the holidays of the given terms.
+func (r *termRepository) loadHolidays(ctx context.Context, terms ...*models.Term) error {
+	if len(terms) == 0 {
+		return nil
+	}
+	byID := make(map[int64]*models.Term, len(terms))
+	ids := make([]int64, 0, len(terms))
+	for _, term := range terms {
+		byID[term.ID] = term
+		ids = append(ids, term.ID)
+	}
+
+	rows, err := r.db.Query(ctx, "SELECT "+holidayColumns+" FROM term_holidays WHERE term_id = ANY($1) ORDER BY start_date, id", ids)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		holiday, err := scanHoliday(rows)
+		if err != nil {
+			return err
+		}
+		term := byID[holiday.TermID]
+		term.Holidays = append(term.Holidays, *holiday)
+	}
+
+	if rows.Err() != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *termRepository) CreateTerm(ctx context.Context, term *models.Term) error {
+	query := `
+		INSERT INTO terms (code, name, start_date, end_date, add_deadline, drop_deadline, exam_start, exam_end, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		term.Code, term.Name, term.StartDate, term.EndDate, term.AddDeadline, term.DropDeadline,
+		term.ExamStart, term.ExamEnd, term.CreatedBy,
+	).Scan(&term.ID, &term.CreatedAt, &term.UpdatedAt)
+	if err != nil {
+		return termWriteError(err)
+	}
+	term.Holidays = make([]models.TermHoliday, 0)
+	return nil
+}
+
+func (r *termRepository) GetTermByID(ctx context.Context, id int64) (*models.Term, error) {
+	term, err := scanTerm(r.db.QueryRow(ctx, "SELECT "+termColumns+" FROM terms WHERE id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadHolidays(ctx, term); err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+// GetTermAt returns the term running on the given day. When terms overlap, the one that
+// started last wins.
+func (r *termRepository) GetTermAt(ctx context.Context, day time.Time) (*models.Term, error) {
+	query := "SELECT " + termColumns + " FROM terms WHERE start_date <= $1 AND end_date >= $1 ORDER BY start_date DESC, id DESC LIMIT 1"
+	term, err := scanTerm(r.db.QueryRow(ctx, query, day))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadHolidays(ctx, term); err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+func (r *termRepository) UpdateTerm(ctx context.Context, term *models.Term) error {
+	query := `
+		UPDATE terms
+		SET code = $2, name = $3, start_date = $4, end_date = $5, add_deadline = $6, drop_deadline = $7,
+		    exam_start = $8, exam_end = $9, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		term.ID, term.Code, term.Name, term.StartDate, term.EndDate, term.AddDeadline, term.DropDeadline,
+		term.ExamStart, term.ExamEnd,
+	).Scan(&term.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErrors.ErrNotFound
+	}
+	if err != nil {
+		return termWriteError(err)
+	}
+	return nil
+}
+
+func (r *termRepository) DeleteTerm(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM terms WHERE id = $1", id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+			return appErrors.ErrTermInUse
+		}
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+// ListTerms returns a page of terms, latest first.
+func (r *termRepository) ListTerms(ctx context.Context, limit, offset int) ([]models.Term, int64, error) {
+	var totalCount int64
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM terms").Scan(&totalCount); err != nil {
+		return nil, 0, appErrors.ErrInternalServerError
+	}
+
+	rows, err := r.db.Query(ctx, "SELECT "+termColumns+" FROM terms ORDER BY start_date DESC, id DESC LIMIT $1 OFFSET $2", limit, offset)
+	if err != nil {
+		return nil, 0, appErrors.ErrInternalServerError
+	}
+	terms := make([]models.Term, 0)
+	for rows.Next() {
+		term, err := scanTerm(rows)
+		if err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		terms = append(terms, *term)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, 0, appErrors.ErrInternalServerError
+	}
+
+	ptrs := make([]*models.Term, len(terms))
+	for i := range terms {
+		ptrs[i] = &terms[i]
+	}
+	if err := r.loadHolidays(ctx, ptrs...); err != nil {
+		return nil, 0, err
+	}
+	return terms, totalCount, nil
+}
+
+func (r *termRepository) ListTermsEndingAfter(ctx context.Context, day time.Time) ([]models.Term, error) {
+	rows, err := r.db.Query(ctx, "SELECT "+termColumns+" FROM terms WHERE end_date >= $1 ORDER BY start_date, id", day)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	terms := make([]models.Term, 0)
+	for rows.Next() {
+		term, err := scanTerm(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		terms = append(terms, *term)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	ptrs := make([]*models.Term, len(terms))
+	for i := range terms {
+		ptrs[i] = &terms[i]
+	}
+	if err := r.loadHolidays(ctx, ptrs...); err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+func (r *termRepository) CreateHoliday(ctx context.Context, holiday *models.TermHoliday) error {
+	query := `
+		INSERT INTO term_holidays (term_id, name, start_date, end_date)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	err := r.db.QueryRow(ctx, query, holiday.TermID, holiday.Name, holiday.StartDate, holiday.EndDate).Scan(&holiday.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is foreign key violation
+			return appErrors.ErrNotFound
+		}
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+// DeleteHoliday deletes a holiday of the term and returns it.
+func (r *termRepository) DeleteHoliday(ctx context.Context, termID, id int64) (*models.TermHoliday, error) {
+	query := "DELETE FROM term_holidays WHERE id = $1 AND term_id = $2 RETURNING " + holidayColumns
+	return scanHoliday(r.db.QueryRow(ctx, query, id, termID))
+}
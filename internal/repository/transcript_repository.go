@@ -0,0 +1,323 @@
+// internal/repository/transcript_repository.go
+package repository
+
+import (
+	"context"
+	"errors"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TranscriptRepository defines the methods for grade-point scales and official transcripts.
+type TranscriptRepository interface {
+	ListScales(ctx context.Context) ([]models.GradePointScale, error)
+	GetScale(ctx context.Context, id int64) (*models.GradePointScale, error)
+	GetDefaultScale(ctx context.Context) (*models.GradePointScale, error)
+	// CreateScale and UpdateScale store the scale with its points; a scale made the default
+	// takes over from the previous default.
+	CreateScale(ctx context.Context, scale *models.GradePointScale) error
+	UpdateScale(ctx context.Context, scale *models.GradePointScale) error
+	DeleteScale(ctx context.Context, id int64) error
+
+	// ListCompletions returns the student's completed courses in term order, completions
+	// without a term first.
+	ListCompletions(ctx context.Context, studentID int64) ([]models.TranscriptCompletion, error)
+
+	CreateTranscript(ctx context.Context, transcript *models.IssuedTranscript) error
+	GetTranscript(ctx context.Context, id int64) (*models.IssuedTranscript, error)
+	ListTranscripts(ctx context.Context, studentID int64) ([]models.IssuedTranscript, error)
+	// Verify looks up a transcript by its verification code.
+	Verify(ctx context.Context, code string) (*models.TranscriptVerification, error)
+}
+
+type transcriptRepository struct {
+	db *txPool
+}
+
+// NewTranscriptRepository creates a new TranscriptRepository instance.
+func NewTranscriptRepository(db *pgxpool.Pool) TranscriptRepository {
+	return &transcriptRepository{db: newTxPool(db)}
+}
+
+const gradePointScaleColumns = `id, name, is_default, created_at, updated_at`
+
+func scanGradePointScale(row pgx.Row) (*models.GradePointScale, error) {
+	scale := &models.GradePointScale{}
+	err := row.Scan(&scale.ID, &scale.Name, &scale.IsDefault, &scale.CreatedAt, &scale.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return scale, nil
+}
+
+// loadPoints fills in the points of the given scales.
+func (r *transcriptRepository) loadPoints(ctx context.Context, scales ...*models.GradePointScale) error {
+	if len(scales) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(scales))
+	byID := make(map[int64]*models.GradePointScale, len(scales))
+	for _, scale := range scales {
+		scale.Points = make(map[string]float64)
+		ids = append(ids, scale.ID)
+		byID[scale.ID] = scale
+	}
+
+	rows, err := r.db.Query(ctx, "SELECT scale_id, letter, points FROM grade_point_values WHERE scale_id = ANY($1)", ids)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			scaleID int64
+			letter  string
+			points  float64
+		)
+		if err := rows.Scan(&scaleID, &letter, &points); err != nil {
+			return appErrors.ErrInternalServerError
+		}
+		byID[scaleID].Points[letter] = points
+	}
+
+	if rows.Err() != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *transcriptRepository) ListScales(ctx context.Context) ([]models.GradePointScale, error) {
+	rows, err := r.db.Query(ctx, "SELECT "+gradePointScaleColumns+" FROM grade_point_scales ORDER BY name")
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	scales := make([]models.GradePointScale, 0)
+	for rows.Next() {
+		scale, err := scanGradePointScale(rows)
+		if err != nil {
+			return nil, err
+		}
+		scales = append(scales, *scale)
+	}
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	rows.Close()
+
+	ptrs := make([]*models.GradePointScale, 0, len(scales))
+	for i := range scales {
+		ptrs = append(ptrs, &scales[i])
+	}
+	if err := r.loadPoints(ctx, ptrs...); err != nil {
+		return nil, err
+	}
+	return scales, nil
+}
+
+func (r *transcriptRepository) getScale(ctx context.Context, where string, args ...interface{}) (*models.GradePointScale, error) {
+	scale, err := scanGradePointScale(r.db.QueryRow(ctx, "SELECT "+gradePointScaleColumns+" FROM grade_point_scales WHERE "+where, args...))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadPoints(ctx, scale); err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+func (r *transcriptRepository) GetScale(ctx context.Context, id int64) (*models.GradePointScale, error) {
+	return r.getScale(ctx, "id = $1", id)
+}
+
+func (r *transcriptRepository) GetDefaultScale(ctx context.Context) (*models.GradePointScale, error) {
+	return r.getScale(ctx, "is_default")
+}
+
+// scaleWriteError maps constraint violations on scale writes to application errors.
+func scaleWriteError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return appErrors.ErrNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is unique violation
+		return appErrors.ErrScaleNameExists
+	}
+	return appErrors.ErrInternalServerError
+}
+
+// writeScale stores the scale row through the given statement, then replaces its points, in
+// one transaction.
+func (r *transcriptRepository) writeScale(ctx context.Context, scale *models.GradePointScale, statement string, args ...interface{}) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	if scale.IsDefault {
+		if _, err := tx.Exec(ctx, "UPDATE grade_point_scales SET is_default = FALSE, updated_at = NOW() WHERE is_default AND id <> $1", scale.ID); err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+	err = tx.QueryRow(ctx, statement, args...).Scan(&scale.ID, &scale.Name, &scale.IsDefault, &scale.CreatedAt, &scale.UpdatedAt)
+	if err != nil {
+		return scaleWriteError(err)
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM grade_point_values WHERE scale_id = $1", scale.ID); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	for letter, points := range scale.Points {
+		_, err := tx.Exec(ctx, "INSERT INTO grade_point_values (scale_id, letter, points) VALUES ($1, $2, $3)", scale.ID, letter, points)
+		if err != nil {
+			return appErrors.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+func (r *transcriptRepository) CreateScale(ctx context.Context, scale *models.GradePointScale) error {
+	return r.writeScale(ctx, scale, `
+		INSERT INTO grade_point_scales (name, is_default) VALUES ($1, $2)
+		RETURNING `+gradePointScaleColumns, scale.Name, scale.IsDefault)
+}
+
+func (r *transcriptRepository) UpdateScale(ctx context.Context, scale *models.GradePointScale) error {
+	return r.writeScale(ctx, scale, `
+		UPDATE grade_point_scales SET name = $2, is_default = $3, updated_at = NOW() WHERE id = $1
+		RETURNING `+gradePointScaleColumns, scale.ID, scale.Name, scale.IsDefault)
+}
+
+func (r *transcriptRepository) DeleteScale(ctx context.Context, id int64) error {
+	cmdTag, err := r.db.Exec(ctx, "DELETE FROM grade_point_scales WHERE id = $1", id)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return appErrors.ErrNotFound
+	}
+	return nil
+}
+
+func (r *transcriptRepository) ListCompletions(ctx context.Context, studentID int64) ([]models.TranscriptCompletion, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT cc.id, cc.course_id, c.code, c.title, c.credits, cc.grade, cc.completed_at, cc.term_id, t.code, t.name, t.start_date
+		FROM course_completions cc
+		JOIN courses c ON c.id = cc.course_id
+		LEFT JOIN terms t ON t.id = cc.term_id
+		WHERE cc.student_id = $1
+		ORDER BY t.start_date NULLS FIRST, t.id NULLS FIRST, cc.completed_at, cc.id
+	`, studentID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	completions := make([]models.TranscriptCompletion, 0)
+	for rows.Next() {
+		var c models.TranscriptCompletion
+		err := rows.Scan(
+			&c.CompletionID, &c.CourseID, &c.CourseCode, &c.CourseTitle, &c.Credits, &c.Grade, &c.CompletedAt,
+			&c.TermID, &c.TermCode, &c.TermName, &c.TermStart,
+		)
+		if err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		completions = append(completions, c)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return completions, nil
+}
+
+const issuedTranscriptColumns = `id, student_id, verification_code, scale_name, cumulative_gpa, earned_credits, file_id, issued_by, issued_at`
+
+func scanIssuedTranscript(row pgx.Row) (*models.IssuedTranscript, error) {
+	t := &models.IssuedTranscript{}
+	err := row.Scan(&t.ID, &t.StudentID, &t.VerificationCode, &t.ScaleName, &t.CumulativeGPA, &t.EarnedCredits, &t.FileID, &t.IssuedBy, &t.IssuedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return t, nil
+}
+
+func (r *transcriptRepository) CreateTranscript(ctx context.Context, transcript *models.IssuedTranscript) error {
+	query := `
+		INSERT INTO transcripts (student_id, verification_code, scale_name, cumulative_gpa, earned_credits, file_id, issued_by, issued_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + issuedTranscriptColumns
+	created, err := scanIssuedTranscript(r.db.QueryRow(ctx, query,
+		transcript.StudentID, transcript.VerificationCode, transcript.ScaleName, transcript.CumulativeGPA,
+		transcript.EarnedCredits, transcript.FileID, transcript.IssuedBy, transcript.IssuedAt,
+	))
+	if err != nil {
+		return err
+	}
+	*transcript = *created
+	return nil
+}
+
+func (r *transcriptRepository) GetTranscript(ctx context.Context, id int64) (*models.IssuedTranscript, error) {
+	return scanIssuedTranscript(r.db.QueryRow(ctx, "SELECT "+issuedTranscriptColumns+" FROM transcripts WHERE id = $1", id))
+}
+
+func (r *transcriptRepository) ListTranscripts(ctx context.Context, studentID int64) ([]models.IssuedTranscript, error) {
+	rows, err := r.db.Query(ctx, "SELECT "+issuedTranscriptColumns+" FROM transcripts WHERE student_id = $1 ORDER BY issued_at DESC, id DESC", studentID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	transcripts := make([]models.IssuedTranscript, 0)
+	for rows.Next() {
+		t, err := scanIssuedTranscript(rows)
+		if err != nil {
+			return nil, err
+		}
+		transcripts = append(transcripts, *t)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return transcripts, nil
+}
+
+func (r *transcriptRepository) Verify(ctx context.Context, code string) (*models.TranscriptVerification, error) {
+	verification := &models.TranscriptVerification{Valid: true}
+	err := r.db.QueryRow(ctx, `
+		SELECT u.name, tr.issued_at, f.checksum
+		FROM transcripts tr
+		JOIN users u ON u.id = tr.student_id
+		JOIN files f ON f.id = tr.file_id
+		WHERE tr.verification_code = $1
+	`, code).Scan(&verification.StudentName, &verification.IssuedAt, &verification.Checksum)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, appErrors.ErrInvalidTranscript
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return verification, nil
+}
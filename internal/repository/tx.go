@@ -0,0 +1,92 @@
+// internal/repository/tx.go
+package repository
+
+import (
+	"context"
+
+	appErrors "student-portal/internal/commons/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Transactor runs a unit of work spanning several repository calls in one database
+// transaction.
+type Transactor interface {
+	// WithinTx runs fn in a transaction that is committed if fn returns nil and rolled back
+	// otherwise. Repository calls made with the context passed to fn join the transaction;
+	// transactions those calls begin themselves become savepoints in it. Calls nested in an
+	// outer WithinTx join the outer transaction.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txKey struct{}
+
+type transactor struct {
+	db *pgxpool.Pool
+}
+
+// NewTransactor creates a new Transactor instance.
+func NewTransactor(db *pgxpool.Pool) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.Begin(ctx)
+	if err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	return nil
+}
+
+// txPool is the connection pool the repositories query through. Queries run in the
+// transaction carried by the context, if any, and on the pool otherwise.
+type txPool struct {
+	pool *pgxpool.Pool
+}
+
+func newTxPool(db *pgxpool.Pool) *txPool {
+	return &txPool{pool: db}
+}
+
+func (p *txPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx.Begin(ctx)
+	}
+	return p.pool.Begin(ctx)
+}
+
+func (p *txPool) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx.Exec(ctx, sql, args...)
+	}
+	return p.pool.Exec(ctx, sql, args...)
+}
+
+func (p *txPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx.Query(ctx, sql, args...)
+	}
+	return p.pool.Query(ctx, sql, args...)
+}
+
+func (p *txPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx.QueryRow(ctx, sql, args...)
+	}
+	return p.pool.QueryRow(ctx, sql, args...)
+}
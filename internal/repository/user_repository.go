@@ -4,6 +4,9 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 
 	appErrors "student-portal/internal/commons/errors"
 	"student-portal/internal/models"
@@ -19,27 +22,36 @@ type UserRepository interface {
 	GetUserByID(ctx context.Context, id int64) (*models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	UpdateUser(ctx context.Context, user *models.User) error
+	// BumpVersion marks the user as changed without checking the version read. Only use it
+	// next to writes that touch nothing but their own columns.
+	BumpVersion(ctx context.Context, id int64) (int64, error)
+	PatchUser(ctx context.Context, id int64, version int64, fields map[string]interface{}) (*models.User, error)
 	DeleteUser(ctx context.Context, id int64) error
 	ListUsers(ctx context.Context, limit, offset int) ([]models.User, int64, error)
+	GetStatus(ctx context.Context, id int64) (string, error)
+	// ChangeStatus applies a status change unless the status has changed since it was read.
+	// Erased accounts keep their status.
+	ChangeStatus(ctx context.Context, change *models.UserStatusChange) (*models.User, error)
+	ListStatusChanges(ctx context.Context, userID int64) ([]models.UserStatusChange, error)
 }
 
 type userRepository struct {
-	db *pgxpool.Pool
+	db *txPool
 }
 
 // NewUserRepository creates a new UserRepository instance.
 func NewUserRepository(db *pgxpool.Pool) UserRepository {
-	return &userRepository{db: db}
+	return &userRepository{db: newTxPool(db)}
 }
 
 func (r *userRepository) CreateUser(ctx context.Context, user *models.User) error {
 	query := `
 		INSERT INTO users (name, email, password, role) 
 		VALUES ($1, $2, $3, $4) 
-		RETURNING id, created_at, updated_at
+		RETURNING id, status, version, created_at, updated_at
 	`
 	err := r.db.QueryRow(ctx, query, user.Name, user.Email, user.Password, user.Role).Scan(
-		&user.ID, &user.CreatedAt, &user.UpdatedAt,
+		&user.ID, &user.Status, &user.Version, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
@@ -55,12 +67,12 @@ func (r *userRepository) CreateUser(ctx context.Context, user *models.User) erro
 func (r *userRepository) GetUserByID(ctx context.Context, id int64) (*models.User, error) {
 	user := &models.User{}
 	query := `
-		SELECT id, name, email, password, role, created_at, updated_at 
+		SELECT id, name, email, password, role, status, version, created_at, updated_at 
 		FROM users 
 		WHERE id = $1
 	`
 	err := r.db.QueryRow(ctx, query, id).Scan(
-		&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt,
+		&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.Status, &user.Version, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -75,12 +87,14 @@ func (r *userRepository) GetUserByID(ctx context.Context, id int64) (*models.Use
 func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	user := &models.User{}
 	query := `
-		SELECT id, name, email, password, role, created_at, updated_at 
+		SELECT id, name, email, password, role, status, version, created_at, updated_at 
 		FROM users 
-		WHERE email = $1
+		WHERE lower(email) = lower($1)
+		ORDER BY email = $1 DESC, id -- Exact match first while legacy case collisions remain
+		LIMIT 1
 	`
 	err := r.db.QueryRow(ctx, query, email).Scan(
-		&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.CreatedAt, &user.UpdatedAt,
+		&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.Status, &user.Version, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -92,17 +106,19 @@ func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 	return user, nil
 }
 
+// UpdateUser writes the user only if its stored version still equals user.Version,
+// then increments the version. It returns ErrPreconditionFailed on a version mismatch.
 func (r *userRepository) UpdateUser(ctx context.Context, user *models.User) error {
 	query := `
 		UPDATE users 
-		SET name = $2, email = $3, role = $4, updated_at = NOW() 
-		WHERE id = $1 
-		RETURNING updated_at
+		SET name = $2, email = $3, role = $4, updated_at = NOW(), version = version + 1 
+		WHERE id = $1 AND version = $5 
+		RETURNING updated_at, version
 	`
-	err := r.db.QueryRow(ctx, query, user.ID, user.Name, user.Email, user.Role).Scan(&user.UpdatedAt)
+	err := r.db.QueryRow(ctx, query, user.ID, user.Name, user.Email, user.Role, user.Version).Scan(&user.UpdatedAt, &user.Version)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return appErrors.ErrNotFound
+		return r.missingOrConflict(ctx, user.ID)
 	}
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -114,6 +130,81 @@ func (r *userRepository) UpdateUser(ctx context.Context, user *models.User) erro
 	return nil
 }
 
+// patchableUserColumns whitelists the users columns PatchUser may write.
+var patchableUserColumns = map[string]bool{"name": true, "email": true, "role": true}
+
+// PatchUser writes only the given columns, conditional on the stored version, and
+// increments the version (even when fields is empty, e.g. for profile-only changes).
+func (r *userRepository) PatchUser(ctx context.Context, id int64, version int64, fields map[string]interface{}) (*models.User, error) {
+	columns := make([]string, 0, len(fields))
+	for column := range fields {
+		if !patchableUserColumns[column] {
+			return nil, appErrors.ErrBadRequest
+		}
+		columns = append(columns, column)
+	}
+	sort.Strings(columns) // Deterministic SQL for the statement cache
+
+	args := []interface{}{id, version}
+	sets := []string{"updated_at = NOW()", "version = version + 1"}
+	for _, column := range columns {
+		args = append(args, fields[column])
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	query := `
+		UPDATE users 
+		SET ` + strings.Join(sets, ", ") + ` 
+		WHERE id = $1 AND version = $2 
+		RETURNING id, name, email, password, role, status, version, created_at, updated_at
+	`
+	user := &models.User{}
+	err := r.db.QueryRow(ctx, query, args...).Scan(
+		&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.Status, &user.Version, &user.CreatedAt, &user.UpdatedAt,
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, r.missingOrConflict(ctx, id)
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is unique violation
+			return nil, appErrors.ErrEmailExists
+		}
+		return nil, appErrors.ErrInternalServerError
+	}
+	return user, nil
+}
+
+// missingOrConflict distinguishes a deleted user from a concurrent modification
+// after a conditional update matched no rows.
+func (r *userRepository) missingOrConflict(ctx context.Context, id int64) error {
+	var exists bool
+	if err := r.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)", id).Scan(&exists); err != nil {
+		return appErrors.ErrInternalServerError
+	}
+	if !exists {
+		return appErrors.ErrNotFound
+	}
+	return appErrors.ErrPreconditionFailed
+}
+
+// BumpVersion increments the user's version for changes stored outside the users table
+// (e.g. a new avatar) and returns the new version. The bump is unconditional, so it must not
+// accompany a write of columns another request may have changed since they were read: the
+// new version would hide that lost update from the If-Match check.
+func (r *userRepository) BumpVersion(ctx context.Context, id int64) (int64, error) {
+	var version int64
+	err := r.db.QueryRow(ctx, "UPDATE users SET version = version + 1, updated_at = NOW() WHERE id = $1 RETURNING version", id).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return 0, appErrors.ErrInternalServerError
+	}
+	return version, nil
+}
+
 func (r *userRepository) DeleteUser(ctx context.Context, id int64) error {
 	cmdTag, err := r.db.Exec(ctx, "DELETE FROM users WHERE id = $1", id)
 	if err != nil {
@@ -136,7 +227,7 @@ func (r *userRepository) ListUsers(ctx context.Context, limit, offset int) ([]mo
 
 	// Query to get paginated users
 	usersQuery := `
-		SELECT id, name, email, role, created_at, updated_at 
+		SELECT id, name, email, role, status, version, created_at, updated_at 
 		FROM users 
 		ORDER BY id 
 		LIMIT $1 OFFSET $2
@@ -152,7 +243,7 @@ func (r *userRepository) ListUsers(ctx context.Context, limit, offset int) ([]mo
 		user := models.User{}
 		// Note: We don't select 'password' here as it's not needed for listing
 		err := rows.Scan(
-			&user.ID, &user.Name, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt,
+			&user.ID, &user.Name, &user.Email, &user.Role, &user.Status, &user.Version, &user.CreatedAt, &user.UpdatedAt,
 		)
 		if err != nil {
 			return nil, 0, appErrors.ErrInternalServerError
@@ -166,3 +257,94 @@ func (r *userRepository) ListUsers(ctx context.Context, limit, offset int) ([]mo
 
 	return users, totalCount, nil
 }
+
+// GetStatus returns the stored status of the user.
+func (r *userRepository) GetStatus(ctx context.Context, id int64) (string, error) {
+	var status string
+	err := r.db.QueryRow(ctx, "SELECT status FROM users WHERE id = $1", id).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", appErrors.ErrNotFound
+	}
+	if err != nil {
+		return "", appErrors.ErrInternalServerError
+	}
+	return status, nil
+}
+
+// ChangeStatus moves the user from change.FromStatus to change.ToStatus and records the
+// transition in one transaction. It returns ErrPreconditionFailed if the stored status is
+// no longer change.FromStatus, i.e. someone else changed it in the meantime.
+func (r *userRepository) ChangeStatus(ctx context.Context, change *models.UserStatusChange) (*models.User, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	// nolint:errcheck
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE users 
+		SET status = $3, updated_at = NOW(), version = version + 1 
+		WHERE id = $1 AND status = $2 AND erased_at IS NULL
+		RETURNING id, name, email, password, role, status, version, created_at, updated_at
+	`
+	user := &models.User{}
+	err = tx.QueryRow(ctx, query, change.UserID, change.FromStatus, change.ToStatus).Scan(
+		&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.Status, &user.Version, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var erased bool
+		if err := tx.QueryRow(ctx, "SELECT erased_at IS NOT NULL FROM users WHERE id = $1", change.UserID).Scan(&erased); err == nil && erased {
+			return nil, appErrors.ErrUserErased
+		}
+		return nil, r.missingOrConflict(ctx, change.UserID)
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO user_status_changes (user_id, from_status, to_status, reason, changed_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, changed_at
+	`, change.UserID, change.FromStatus, change.ToStatus, change.Reason, change.ChangedBy).Scan(&change.ID, &change.ChangedAt)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return user, nil
+}
+
+func (r *userRepository) ListStatusChanges(ctx context.Context, userID int64) ([]models.UserStatusChange, error) {
+	query := `
+		SELECT id, user_id, from_status, to_status, reason, changed_by, changed_at 
+		FROM user_status_changes 
+		WHERE user_id = $1 
+		ORDER BY id DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	changes := make([]models.UserStatusChange, 0)
+	for rows.Next() {
+		change := models.UserStatusChange{}
+		err := rows.Scan(
+			&change.ID, &change.UserID, &change.FromStatus, &change.ToStatus, &change.Reason, &change.ChangedBy, &change.ChangedAt,
+		)
+		if err != nil {
+			return nil, appErrors.ErrInternalServerError
+		}
+		changes = append(changes, change)
+	}
+
+	if rows.Err() != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return changes, nil
+}
@@ -13,18 +13,19 @@ import (
 )
 
 // SetupRouter configures the Chi router with middlewares and routes.
-func SetupRouter(cfg *config.Config, authHandler *handler.AuthHandler, userHandler *handler.UserHandler) *chi.Mux {
+func SetupRouter(cfg *config.Config, authHandler *handler.AuthHandler, userHandler *handler.UserHandler, fileHandler *handler.FileHandler, privacyHandler *handler.PrivacyHandler, historyHandler *handler.HistoryHandler, mergeHandler *handler.MergeHandler, guardianHandler *handler.GuardianHandler, courseHandler *handler.CourseHandler, enrollmentHandler *handler.EnrollmentHandler, termHandler *handler.TermHandler, offeringHandler *handler.OfferingHandler, sectionHandler *handler.SectionHandler, calendarHandler *handler.CalendarHandler, attendanceHandler *handler.AttendanceHandler, checkInHandler *handler.CheckInHandler, assignmentHandler *handler.AssignmentHandler, gradebookHandler *handler.GradebookHandler, transcriptHandler *handler.TranscriptHandler, quizHandler *handler.QuizHandler, rubricHandler *handler.RubricHandler, peerReviewHandler *handler.PeerReviewHandler, guardianAccess appMiddleware.GuardianAccessChecker, accountStatus appMiddleware.AccountStatusChecker) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Global Middleware
 	r.Use(
+		middleware.RequestID,        // Request IDs for logs and change history
 		appMiddleware.RequestLogger, // Custom structured request logging
 		middleware.Recoverer,        // Recover from panics
 		cors.New(cors.Options{ // CORS setup
 			AllowedOrigins:   []string{"*"},
-			AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-			AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-			ExposedHeaders:   []string{"Link"},
+			AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-Match"},
+			ExposedHeaders:   []string{"Link", "ETag"},
 			AllowCredentials: true,
 			MaxAge:           300,
 		}).Handler,
@@ -36,20 +37,324 @@ func SetupRouter(cfg *config.Config, authHandler *handler.AuthHandler, userHandl
 		r.Post("/login", authHandler.Login)
 	})
 
+	// Guardian invitations (the invitation token is the credential)
+	r.Post("/api/guardians/invitations/accept", guardianHandler.AcceptInvitation)
+
+	// Signed download links (the signature in the query string is the credential)
+	r.Get("/api/files/download", fileHandler.Download)
+
+	// Calendar subscriptions, e.g. /api/calendar/{token}.ics (the secret token is the credential)
+	r.Get("/api/calendar/{token}", calendarHandler.Subscribe)
+
 	// Protected Routes (Authentication required)
 	r.Route("/api", func(r chi.Router) {
 		r.Route("/profile", func(r chi.Router) {
-			r.Use(appMiddleware.AuthMiddleware(cfg))
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus))
 			r.Get("/", userHandler.GetOwnProfile)
 			r.Put("/", userHandler.UpdateOwnProfile)
+			r.Patch("/", userHandler.PatchOwnProfile)
+			r.Post("/avatar", userHandler.UploadAvatar)
+			r.Get("/avatar", userHandler.GetAvatar)
+			r.Delete("/avatar", userHandler.DeleteAvatar)
+			r.Post("/exports", privacyHandler.RequestExport)
+			r.Get("/exports", privacyHandler.ListExports)
+			r.Get("/exports/{id}", privacyHandler.GetExport)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Get("/enrollments", enrollmentHandler.ListOwnEnrollments)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Get("/completions", enrollmentHandler.ListOwnCompletions)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent), string(enums.RoleTeacher))).Get("/timetable", sectionHandler.GetOwnTimetable)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Get("/attendance", attendanceHandler.GetOwnAttendance)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Post("/attendance/check-in", checkInHandler.CheckIn)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Get("/assignments", assignmentHandler.GetOwnAssignments)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Get("/assignments/{id}/submissions", assignmentHandler.ListOwnVersions)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Get("/assignments/{id}/rubric-feedback", rubricHandler.GetOwnFeedback)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Get("/assignments/{id}/peer-feedback", peerReviewHandler.GetOwnPeerFeedback)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Get("/peer-reviews", peerReviewHandler.ListOwnReviews)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Get("/grades", gradebookHandler.GetOwnGrades)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Get("/transcript", transcriptHandler.GetOwnTranscript)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Get("/transcripts", transcriptHandler.ListOwnIssued)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Get("/quizzes", quizHandler.GetOwnQuizzes)
+			r.Get("/calendar-feed", calendarHandler.GetOwnFeed)
+			r.Post("/calendar-feed", calendarHandler.CreateOwnFeed)
+			r.Delete("/calendar-feed", calendarHandler.RevokeOwnFeed)
+		})
+
+		r.Route("/files", func(r chi.Router) {
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus))
+			r.Get("/{id}", fileHandler.GetFileURL)
+		})
+
+		r.Route("/profile-fields", func(r chi.Router) {
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus))
+			r.Get("/", userHandler.ListProfileFields)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleAdmin))).Post("/", userHandler.CreateProfileField)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleAdmin))).Delete("/{key}", userHandler.DeleteProfileField)
+		})
+
+		// The catalog is public; admins additionally see inactive courses and manage the catalog
+		r.Route("/courses", func(r chi.Router) {
+			r.Group(func(r chi.Router) {
+				r.Use(appMiddleware.OptionalAuthMiddleware(cfg, accountStatus))
+				r.Get("/", courseHandler.ListCourses)
+				r.Get("/{id}", courseHandler.GetCourse)
+			})
+			r.With(appMiddleware.AuthMiddleware(cfg, accountStatus)).Get("/{id}/grade-scale", gradebookHandler.GetGradeScale)
+			r.Group(func(r chi.Router) {
+				r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus), appMiddleware.RoleMiddleware(string(enums.RoleAdmin)))
+				r.Post("/", courseHandler.CreateCourse)
+				r.Put("/{id}", courseHandler.UpdateCourse)
+				r.Delete("/{id}", courseHandler.DeleteCourse)
+				r.Put("/{id}/requirements", courseHandler.SetRequirements)
+				r.Put("/{id}/grade-scale", gradebookHandler.SetGradeScale)
+				r.Get("/{id}/grade-scale/history", historyHandler.ListHistory(enums.EntityGradeScale))
+				r.Get("/{id}/history", historyHandler.ListHistory(enums.EntityCourse))
+				r.Get("/{id}/enrollments", enrollmentHandler.ListCourseEnrollments)
+				r.Post("/{id}/enrollments", enrollmentHandler.AdminEnroll)
+				r.Delete("/{id}/enrollments/{studentId}", enrollmentHandler.AdminDrop)
+			})
+			r.Group(func(r chi.Router) {
+				r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus), appMiddleware.RoleMiddleware(string(enums.RoleStudent)))
+				r.Get("/{id}/eligibility", enrollmentHandler.CheckEligibility)
+				r.Post("/{id}/enroll", enrollmentHandler.Enroll)
+				r.Put("/{id}/enroll/section", enrollmentHandler.ChangeSection)
+				r.Delete("/{id}/enroll", enrollmentHandler.Drop)
+			})
+		})
+
+		// The academic calendar and each term's offerings are public; admins manage them
+		r.Route("/terms", func(r chi.Router) {
+			r.Group(func(r chi.Router) {
+				r.Use(appMiddleware.OptionalAuthMiddleware(cfg, accountStatus))
+				r.Get("/", termHandler.ListTerms)
+				r.Get("/current", termHandler.GetCurrentTerm)
+				r.Get("/{id}", termHandler.GetTerm)
+				r.Get("/{id}/offerings", offeringHandler.ListOfferings)
+				r.Get("/{id}/offerings/{courseId}/sections", sectionHandler.ListSections)
+			})
+			r.Group(func(r chi.Router) {
+				r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus), appMiddleware.RoleMiddleware(string(enums.RoleAdmin)))
+				r.Post("/", termHandler.CreateTerm)
+				r.Put("/{id}", termHandler.UpdateTerm)
+				r.Delete("/{id}", termHandler.DeleteTerm)
+				r.Get("/{id}/history", historyHandler.ListHistory(enums.EntityTerm))
+				r.Post("/{id}/holidays", termHandler.AddHoliday)
+				r.Delete("/{id}/holidays/{holidayId}", termHandler.DeleteHoliday)
+				r.Post("/{id}/offerings", offeringHandler.CreateOffering)
+				r.Put("/{id}/offerings/{courseId}", offeringHandler.UpdateOffering)
+				r.Delete("/{id}/offerings/{courseId}", offeringHandler.DeleteOffering)
+				r.Post("/{id}/offerings/{courseId}/sections", sectionHandler.CreateSection)
+				r.Get("/{id}/clashes", sectionHandler.ListClashes)
+			})
+		})
+
+		r.Route("/sections", func(r chi.Router) {
+			r.Get("/{id}", sectionHandler.GetSection)
+			r.Group(func(r chi.Router) {
+				r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus), appMiddleware.RoleMiddleware(string(enums.RoleAdmin)))
+				r.Put("/{id}", sectionHandler.UpdateSection)
+				r.Delete("/{id}", sectionHandler.DeleteSection)
+				r.Get("/{id}/history", historyHandler.ListHistory(enums.EntitySection))
+				r.Get("/{id}/grade-categories/history", historyHandler.ListHistory(enums.EntityGradeCategories))
+				r.Get("/{id}/gradebook/final/history", historyHandler.ListHistory(enums.EntityFinalGrades))
+			})
+			r.Group(func(r chi.Router) {
+				// Teachers are further limited to the sections they teach
+				r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus), appMiddleware.RoleMiddleware(string(enums.RoleTeacher), string(enums.RoleAdmin)))
+				r.Get("/{id}/sessions", attendanceHandler.ListSessions)
+				r.Post("/{id}/sessions", attendanceHandler.GenerateSessions)
+				r.Get("/{id}/attendance", attendanceHandler.ListSectionAttendance)
+				r.Get("/{id}/assignments", assignmentHandler.ListAssignments)
+				r.Post("/{id}/assignments", assignmentHandler.CreateAssignment)
+				r.Get("/{id}/grade-categories", gradebookHandler.ListCategories)
+				r.Put("/{id}/grade-categories", gradebookHandler.SetCategories)
+				r.Get("/{id}/gradebook", gradebookHandler.GetGradebook)
+				r.Get("/{id}/gradebook/students/{studentId}", gradebookHandler.GetStudentBreakdown)
+				r.Post("/{id}/gradebook/final", gradebookHandler.PostFinalGrades)
+				r.Get("/{id}/quizzes", quizHandler.ListQuizzes)
+				r.Post("/{id}/quizzes", quizHandler.CreateQuiz)
+			})
+		})
+
+		// Assignments are visible to the students of their section; teachers are further
+		// limited to the sections they teach
+		r.Route("/assignments", func(r chi.Router) {
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus))
+			r.Get("/{id}", assignmentHandler.GetAssignment)
+			r.Get("/{id}/rubric", rubricHandler.GetAssignmentRubric)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Post("/{id}/submissions", assignmentHandler.Submit)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleAdmin))).Get("/{id}/history", historyHandler.ListHistory(enums.EntityAssignment))
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleAdmin))).Get("/{id}/peer-review/history", historyHandler.ListHistory(enums.EntityPeerReview))
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleAdmin))).Get("/{id}/grades/history", historyHandler.ListHistory(enums.EntityAssignmentGrades))
+			r.Group(func(r chi.Router) {
+				r.Use(appMiddleware.RoleMiddleware(string(enums.RoleTeacher), string(enums.RoleAdmin)))
+				r.Put("/{id}", assignmentHandler.UpdateAssignment)
+				r.Delete("/{id}", assignmentHandler.DeleteAssignment)
+				r.Get("/{id}/submissions", assignmentHandler.ListSubmissions)
+				r.Get("/{id}/submissions/{studentId}", assignmentHandler.ListStudentVersions)
+				r.Get("/{id}/grades", gradebookHandler.ListGrades)
+				r.Put("/{id}/grades", gradebookHandler.SetGrades)
+				r.Post("/{id}/grades/release", gradebookHandler.ReleaseGrades)
+				r.Put("/{id}/rubric", rubricHandler.SetAssignmentRubric)
+				r.Get("/{id}/rubric-scores/{studentId}", rubricHandler.GetAssessment)
+				r.Put("/{id}/rubric-scores/{studentId}", rubricHandler.ScoreRubric)
+				r.Get("/{id}/peer-review", peerReviewHandler.GetSettings)
+				r.Put("/{id}/peer-review", peerReviewHandler.SetSettings)
+				r.Delete("/{id}/peer-review", peerReviewHandler.DisablePeerReview)
+				r.Post("/{id}/peer-review/allocate", peerReviewHandler.AllocateReviews)
+				r.Get("/{id}/peer-reviews", peerReviewHandler.GetOverview)
+				r.Post("/{id}/peer-reviews/accept", peerReviewHandler.AcceptScores)
+			})
+		})
+
+		// Peer reviews are seen only by their reviewer, who never learns whose work it is
+		r.Route("/peer-reviews", func(r chi.Router) {
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus), appMiddleware.RoleMiddleware(string(enums.RoleStudent)))
+			r.Get("/{id}", peerReviewHandler.GetOwnReview)
+			r.Put("/{id}", peerReviewHandler.SubmitOwnReview)
+			r.Get("/{id}/files/{position}", peerReviewHandler.DownloadReviewFile)
+		})
+
+		// Rubrics are private to the teacher who owns them
+		r.Route("/rubrics", func(r chi.Router) {
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus), appMiddleware.RoleMiddleware(string(enums.RoleTeacher), string(enums.RoleAdmin)))
+			r.Get("/", rubricHandler.ListRubrics)
+			r.Post("/", rubricHandler.CreateRubric)
+			r.Get("/{id}", rubricHandler.GetRubric)
+			r.Put("/{id}", rubricHandler.UpdateRubric)
+			r.Delete("/{id}", rubricHandler.DeleteRubric)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleAdmin))).Get("/{id}/history", historyHandler.ListHistory(enums.EntityRubric))
+		})
+
+		// Question banks are private to the teacher who owns them
+		r.Route("/question-banks", func(r chi.Router) {
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus), appMiddleware.RoleMiddleware(string(enums.RoleTeacher), string(enums.RoleAdmin)))
+			r.Get("/", quizHandler.ListBanks)
+			r.Post("/", quizHandler.CreateBank)
+			r.Post("/import", quizHandler.ImportQTI)
+			r.Get("/{id}", quizHandler.GetBank)
+			r.Put("/{id}", quizHandler.UpdateBank)
+			r.Delete("/{id}", quizHandler.DeleteBank)
+			r.Post("/{id}/questions", quizHandler.AddQuestion)
+			r.Put("/{id}/questions/{questionId}", quizHandler.UpdateQuestion)
+			r.Delete("/{id}/questions/{questionId}", quizHandler.DeleteQuestion)
+			r.Get("/{id}/export", quizHandler.ExportBank)
+			r.Post("/{id}/import", quizHandler.ImportQTI)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleAdmin))).Get("/{id}/history", historyHandler.ListHistory(enums.EntityQuestionBank))
+		})
+
+		// Quizzes are visible to the students of their section; teachers are further limited
+		// to the sections they teach
+		r.Route("/quizzes", func(r chi.Router) {
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus))
+			r.Get("/{id}", quizHandler.GetQuiz)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Post("/{id}/attempts", quizHandler.StartAttempt)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleAdmin))).Get("/{id}/history", historyHandler.ListHistory(enums.EntityQuiz))
+			r.Group(func(r chi.Router) {
+				r.Use(appMiddleware.RoleMiddleware(string(enums.RoleTeacher), string(enums.RoleAdmin)))
+				r.Put("/{id}", quizHandler.UpdateQuiz)
+				r.Delete("/{id}", quizHandler.DeleteQuiz)
+				r.Get("/{id}/questions", quizHandler.GetQuizQuestions)
+				r.Put("/{id}/questions", quizHandler.SetQuizQuestions)
+				r.Get("/{id}/attempts", quizHandler.ListAttempts)
+				r.Get("/{id}/grading-queue", quizHandler.GradingQueue)
+			})
+		})
+
+		// Attempts are seen by their student and the section's teacher; only the student answers
+		r.Route("/quiz-attempts", func(r chi.Router) {
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus))
+			r.Get("/{id}", quizHandler.GetAttempt)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Put("/{id}/answers", quizHandler.SaveAnswers)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleStudent))).Post("/{id}/submit", quizHandler.SubmitAttempt)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleTeacher), string(enums.RoleAdmin))).Put("/{id}/responses/{questionId}", quizHandler.GradeResponse)
+		})
+
+		r.Route("/submissions", func(r chi.Router) {
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus))
+			r.Get("/{id}", assignmentHandler.GetSubmission)
+			r.Get("/{id}/files/{fileId}", assignmentHandler.GetSubmissionFileURL)
+		})
+
+		// Anyone holding a transcript can verify it; the code reveals nothing beyond the document
+		r.Route("/transcripts", func(r chi.Router) {
+			r.Get("/verify/{code}", transcriptHandler.Verify)
+			r.With(appMiddleware.AuthMiddleware(cfg, accountStatus)).Get("/{id}/file", transcriptHandler.GetIssuedFileURL)
+		})
+
+		r.Route("/grade-point-scales", func(r chi.Router) {
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus))
+			r.Get("/", transcriptHandler.ListScales)
+			r.Group(func(r chi.Router) {
+				r.Use(appMiddleware.RoleMiddleware(string(enums.RoleAdmin)))
+				r.Post("/", transcriptHandler.CreateScale)
+				r.Put("/{id}", transcriptHandler.UpdateScale)
+				r.Delete("/{id}", transcriptHandler.DeleteScale)
+				r.Get("/{id}/history", historyHandler.ListHistory(enums.EntityGradePointScale))
+			})
+		})
+
+		r.Route("/sessions", func(r chi.Router) {
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus), appMiddleware.RoleMiddleware(string(enums.RoleTeacher), string(enums.RoleAdmin)))
+			r.Get("/{id}/attendance", attendanceHandler.GetSessionAttendance)
+			r.Put("/{id}/attendance", attendanceHandler.MarkAttendance)
+			r.With(appMiddleware.RoleMiddleware(string(enums.RoleAdmin))).Get("/{id}/attendance/history", historyHandler.ListHistory(enums.EntityClassSession))
+			r.Post("/{id}/check-in", checkInHandler.OpenWindow)
+			r.Get("/{id}/check-in", checkInHandler.GetCode)
+			r.Delete("/{id}/check-in", checkInHandler.CloseWindow)
+		})
+
+		r.Route("/guardians", func(r chi.Router) {
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus), appMiddleware.RoleMiddleware(string(enums.RoleAdmin)))
+			r.Post("/links", guardianHandler.CreateLink)
+			r.Put("/links/{id}", guardianHandler.UpdateLink)
+			r.Delete("/links/{id}", guardianHandler.DeleteLink)
+			r.Post("/invitations", guardianHandler.CreateInvitation)
+		})
+
+		// Read-only access for guardians to their linked students
+		r.Route("/guardian/students", func(r chi.Router) {
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus), appMiddleware.RoleMiddleware(string(enums.RoleGuardian)))
+			r.Get("/", guardianHandler.ListLinkedStudents)
+			r.With(appMiddleware.StudentAccessMiddleware(guardianAccess, "id", false)).Get("/{id}", guardianHandler.GetLinkedStudent)
+			r.With(appMiddleware.StudentAccessMiddleware(guardianAccess, "id", true)).Get("/{id}/enrollments", enrollmentHandler.ListStudentEnrollments)
+			r.With(appMiddleware.StudentAccessMiddleware(guardianAccess, "id", true)).Get("/{id}/completions", enrollmentHandler.ListCompletions)
+			r.With(appMiddleware.StudentAccessMiddleware(guardianAccess, "id", true)).Get("/{id}/timetable", sectionHandler.GetTimetable)
+			r.With(appMiddleware.StudentAccessMiddleware(guardianAccess, "id", true)).Get("/{id}/attendance", attendanceHandler.GetStudentAttendance)
+			r.With(appMiddleware.StudentAccessMiddleware(guardianAccess, "id", true)).Get("/{id}/assignments", assignmentHandler.GetStudentAssignments)
+			r.With(appMiddleware.StudentAccessMiddleware(guardianAccess, "id", true)).Get("/{id}/grades", gradebookHandler.GetStudentGrades)
+			r.With(appMiddleware.StudentAccessMiddleware(guardianAccess, "id", true)).Get("/{id}/transcript", transcriptHandler.GetTranscript)
+			r.With(appMiddleware.StudentAccessMiddleware(guardianAccess, "id", true)).Get("/{id}/quizzes", quizHandler.GetStudentQuizzes)
 		})
 
 		r.Route("/users", func(r chi.Router) {
-			r.Use(appMiddleware.AuthMiddleware(cfg), appMiddleware.RoleMiddleware(string(enums.RoleAdmin)))
+			r.Use(appMiddleware.AuthMiddleware(cfg, accountStatus), appMiddleware.RoleMiddleware(string(enums.RoleAdmin)))
 			r.Get("/", userHandler.ListUsers)
+			r.Get("/duplicates", mergeHandler.ListDuplicates)
+			r.Post("/merge/preview", mergeHandler.PreviewMerge)
+			r.Post("/merge", mergeHandler.MergeUsers)
 			r.Get("/{id}", userHandler.GetUserByID)
 			r.Put("/{id}", userHandler.UpdateUser)
+			r.Patch("/{id}", userHandler.PatchUser)
 			r.Delete("/{id}", userHandler.DeleteUser)
+			r.Post("/{id}/erase", privacyHandler.EraseUser)
+			r.Get("/{id}/history", historyHandler.ListHistory(enums.EntityUser))
+			r.Put("/{id}/status", userHandler.ChangeStatus)
+			r.Get("/{id}/status-changes", userHandler.ListStatusChanges)
+			r.Get("/{id}/guardians", guardianHandler.ListGuardians)
+			r.Get("/{id}/enrollments", enrollmentHandler.ListStudentEnrollments)
+			r.Get("/{id}/completions", enrollmentHandler.ListCompletions)
+			r.Post("/{id}/completions", enrollmentHandler.RecordCompletion)
+			r.Delete("/{id}/completions/{completionId}", enrollmentHandler.DeleteCompletion)
+			r.Get("/{id}/timetable", sectionHandler.GetTimetable)
+			r.Get("/{id}/attendance", attendanceHandler.GetStudentAttendance)
+			r.Get("/{id}/assignments", assignmentHandler.GetStudentAssignments)
+			r.Get("/{id}/grades", gradebookHandler.GetStudentGrades)
+			r.Get("/{id}/transcript", transcriptHandler.GetTranscript)
+			r.Get("/{id}/transcripts", transcriptHandler.ListIssued)
+			r.Post("/{id}/transcripts", transcriptHandler.IssueTranscript)
+			r.Get("/{id}/quizzes", quizHandler.GetStudentQuizzes)
+			r.Get("/{id}/calendar-feed", calendarHandler.GetFeed)
+			r.Post("/{id}/calendar-feed", calendarHandler.RegenerateFeed)
+			r.Delete("/{id}/calendar-feed", calendarHandler.RevokeFeed)
 		})
 	})
 
@@ -0,0 +1,453 @@
+// internal/service/assignment_service.go
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/commons/logger"
+	"student-portal/internal/config"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// AssignmentService defines the methods for assignments and submissions. Methods taking a
+// userID and isAdmin act on behalf of that user: the section's instructor and admins manage
+// its assignments, and the students enrolled in it hand in work.
+type AssignmentService interface {
+	CreateAssignment(ctx context.Context, sectionID int64, req *models.CreateAssignmentRequest, userID int64, isAdmin bool) (*models.Assignment, error)
+	ListAssignments(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.Assignment, error)
+	// GetAssignment returns the assignment to its instructor, admins and the students of its section.
+	GetAssignment(ctx context.Context, id, userID int64, isAdmin bool) (*models.Assignment, error)
+	UpdateAssignment(ctx context.Context, id int64, req *models.UpdateAssignmentRequest, userID int64, isAdmin bool) (*models.Assignment, error)
+	// DeleteAssignment removes an assignment nobody has submitted to or been graded on yet.
+	DeleteAssignment(ctx context.Context, id, userID int64, isAdmin bool) error
+
+	// Submit hands in a new version of the student's work. Earlier versions are kept.
+	Submit(ctx context.Context, assignmentID, studentID int64, comment string, files []UploadedFile) (*models.Submission, error)
+	// ListSubmissions returns the latest submission of every student of the assignment's section.
+	ListSubmissions(ctx context.Context, assignmentID, userID int64, isAdmin bool) (*models.AssignmentSubmissions, error)
+	// ListVersions returns every version a student handed in, to the student and their teacher.
+	ListVersions(ctx context.Context, assignmentID, studentID, userID int64, isAdmin bool) ([]models.Submission, error)
+	GetSubmission(ctx context.Context, id, userID int64, isAdmin bool) (*models.Submission, error)
+	// GetSubmissionFileURL returns a signed download URL for a file attached to a submission.
+	GetSubmissionFileURL(ctx context.Context, submissionID, fileID, userID int64, isAdmin bool) (*models.FileURLResponse, error)
+	// GetStudentAssignments returns a student's assignments in the term (zero means the current
+	// term) with their latest submissions.
+	GetStudentAssignments(ctx context.Context, studentID, termID int64) (*models.StudentAssignments, error)
+}
+
+// validLatePolicies are the late policies an assignment can have.
+var validLatePolicies = map[string]bool{
+	string(enums.LatePolicyReject):  true,
+	string(enums.LatePolicyAccept):  true,
+	string(enums.LatePolicyPenalty): true,
+}
+
+type assignmentService struct {
+	repo          repository.AssignmentRepository
+	sectionRepo   repository.SectionRepository
+	termRepo      repository.TermRepository
+	userRepo      repository.UserRepository
+	gradebookRepo repository.GradebookRepository
+	transactor    repository.Transactor
+	fileSvc       FileService
+	gradebookSvc  GradebookService
+	historySvc    HistoryService
+	cfg           *config.Config
+}
+
+// NewAssignmentService creates a new AssignmentService instance.
+func NewAssignmentService(repo repository.AssignmentRepository, sectionRepo repository.SectionRepository, termRepo repository.TermRepository, userRepo repository.UserRepository, gradebookRepo repository.GradebookRepository, transactor repository.Transactor, fileSvc FileService, gradebookSvc GradebookService, historySvc HistoryService, cfg *config.Config) AssignmentService {
+	return &assignmentService{repo: repo, sectionRepo: sectionRepo, termRepo: termRepo, userRepo: userRepo, gradebookRepo: gradebookRepo, transactor: transactor, fileSvc: fileSvc, gradebookSvc: gradebookSvc, historySvc: historySvc, cfg: cfg}
+}
+
+// validateAssignment normalizes the assignment's fields and checks them.
+func validateAssignment(assignment *models.Assignment) error {
+	assignment.Title = strings.TrimSpace(assignment.Title)
+	if assignment.Title == "" || len(assignment.Title) > 200 {
+		return appErrors.New(http.StatusBadRequest, "Title must be 1-200 characters")
+	}
+	if assignment.DueAt.IsZero() {
+		return appErrors.New(http.StatusBadRequest, "Due date is required")
+	}
+	if assignment.MaxPoints <= 0 || assignment.MaxPoints >= 100000 {
+		return appErrors.New(http.StatusBadRequest, "Max points must be greater than 0 and less than 100000")
+	}
+	if !validLatePolicies[assignment.LatePolicy] {
+		return appErrors.New(http.StatusBadRequest, "Invalid late policy '%s'", assignment.LatePolicy)
+	}
+	if assignment.LatePolicy == string(enums.LatePolicyPenalty) {
+		if assignment.LatePenaltyPercent <= 0 || assignment.LatePenaltyPercent > 100 {
+			return appErrors.New(http.StatusBadRequest, "Late penalty must be greater than 0 and at most 100 percent per day")
+		}
+	} else {
+		assignment.LatePenaltyPercent = 0
+	}
+	if assignment.LatePolicy == string(enums.LatePolicyReject) {
+		assignment.LateUntil = nil
+	}
+	if assignment.LateUntil != nil && assignment.LateUntil.Before(assignment.DueAt) {
+		return appErrors.New(http.StatusBadRequest, "Late submissions cannot close before the due date")
+	}
+	return nil
+}
+
+// checkCategory checks that the assignment's grade category belongs to its section.
+func (s *assignmentService) checkCategory(ctx context.Context, assignment *models.Assignment) error {
+	if assignment.CategoryID == nil {
+		return nil
+	}
+	categories, err := s.gradebookRepo.ListCategories(ctx, assignment.SectionID)
+	if err != nil {
+		return err
+	}
+	for _, c := range categories {
+		if c.ID == *assignment.CategoryID {
+			return nil
+		}
+	}
+	return appErrors.New(http.StatusBadRequest, "Grade category %d does not belong to this section", *assignment.CategoryID)
+}
+
+// refreshGrades recomputes the course grades of the section after its assignments changed.
+// The change itself has been saved, so failures are only logged.
+func (s *assignmentService) refreshGrades(ctx context.Context, sectionID int64) {
+	if err := s.gradebookSvc.RecomputeSection(ctx, sectionID); err != nil {
+		logger.Logger.Error("Failed to recompute section grades", zap.Error(err), zap.Int64("section_id", sectionID))
+	}
+}
+
+// requireAssignmentInstructor loads the assignment and checks that the user teaches its section.
+func (s *assignmentService) requireAssignmentInstructor(ctx context.Context, id, userID int64, isAdmin bool) (*models.Assignment, error) {
+	assignment, err := s.repo.GetAssignment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, assignment.SectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// canSeeStudentWork reports whether the user may see what the student handed in for the
+// assignment: the student themselves, the section's instructor and admins may.
+func (s *assignmentService) canSeeStudentWork(ctx context.Context, assignment *models.Assignment, studentID, userID int64, isAdmin bool) error {
+	if isAdmin || studentID == userID {
+		return nil
+	}
+	_, err := requireSectionInstructor(ctx, s.sectionRepo, assignment.SectionID, userID, false)
+	return err
+}
+
+func (s *assignmentService) CreateAssignment(ctx context.Context, sectionID int64, req *models.CreateAssignmentRequest, userID int64, isAdmin bool) (*models.Assignment, error) {
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, sectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	assignment := &models.Assignment{
+		SectionID:          sectionID,
+		Title:              req.Title,
+		Instructions:       optionalString(req.Instructions),
+		DueAt:              req.DueAt,
+		LatePolicy:         req.LatePolicy,
+		LatePenaltyPercent: req.LatePenaltyPercent,
+		LateUntil:          req.LateUntil,
+		MaxPoints:          req.MaxPoints,
+		CategoryID:         req.CategoryID,
+		ExtraCredit:        req.ExtraCredit,
+		CreatedBy:          &userID,
+	}
+	if assignment.LatePolicy == "" {
+		assignment.LatePolicy = string(enums.LatePolicyAccept)
+	}
+	if err := validateAssignment(assignment); err != nil {
+		return nil, err
+	}
+	if err := s.checkCategory(ctx, assignment); err != nil {
+		return nil, err
+	}
+
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateAssignment(ctx, assignment); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityAssignment, assignment.ID, enums.ActionCreate, nil, assignment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.refreshGrades(ctx, sectionID)
+	return assignment, nil
+}
+
+func (s *assignmentService) ListAssignments(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.Assignment, error) {
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, sectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return s.repo.ListForSection(ctx, sectionID)
+}
+
+func (s *assignmentService) GetAssignment(ctx context.Context, id, userID int64, isAdmin bool) (*models.Assignment, error) {
+	assignment, err := s.repo.GetAssignment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if isAdmin {
+		return assignment, nil
+	}
+	enrolled, err := s.repo.IsEnrolled(ctx, assignment.SectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		if _, err := requireSectionInstructor(ctx, s.sectionRepo, assignment.SectionID, userID, false); err != nil {
+			return nil, err
+		}
+	}
+	return assignment, nil
+}
+
+func (s *assignmentService) UpdateAssignment(ctx context.Context, id int64, req *models.UpdateAssignmentRequest, userID int64, isAdmin bool) (*models.Assignment, error) {
+	assignment, err := s.requireAssignmentInstructor(ctx, id, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	before := *assignment
+
+	if req.Title != nil {
+		assignment.Title = *req.Title
+	}
+	if req.Instructions != nil {
+		assignment.Instructions = optionalString(*req.Instructions)
+	}
+	if req.DueAt != nil {
+		assignment.DueAt = *req.DueAt
+	}
+	if req.LatePolicy != nil {
+		assignment.LatePolicy = *req.LatePolicy
+	}
+	if req.LatePenaltyPercent != nil {
+		assignment.LatePenaltyPercent = *req.LatePenaltyPercent
+	}
+	if req.LateUntil != nil {
+		assignment.LateUntil = req.LateUntil
+	} else if req.ClearLateUntil {
+		assignment.LateUntil = nil
+	}
+	if req.MaxPoints != nil {
+		assignment.MaxPoints = *req.MaxPoints
+	}
+	if req.CategoryID != nil {
+		assignment.CategoryID = req.CategoryID
+	} else if req.ClearCategory {
+		assignment.CategoryID = nil
+	}
+	if req.ExtraCredit != nil {
+		assignment.ExtraCredit = *req.ExtraCredit
+	}
+	if err := validateAssignment(assignment); err != nil {
+		return nil, err
+	}
+	if err := s.checkCategory(ctx, assignment); err != nil {
+		return nil, err
+	}
+
+	// Submissions keep the late flag they were handed in with; moving the due date does not
+	// rewrite history.
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateAssignment(ctx, assignment); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityAssignment, assignment.ID, enums.ActionUpdate, &before, assignment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.refreshGrades(ctx, assignment.SectionID)
+	return assignment, nil
+}
+
+func (s *assignmentService) DeleteAssignment(ctx context.Context, id, userID int64, isAdmin bool) error {
+	assignment, err := s.requireAssignmentInstructor(ctx, id, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteAssignment(ctx, id); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityAssignment, id, enums.ActionDelete, assignment, nil)
+	})
+	if err != nil {
+		return err
+	}
+	s.refreshGrades(ctx, assignment.SectionID)
+	return nil
+}
+
+// acceptsSubmissions checks the assignment's late policy against the given time.
+func acceptsSubmissions(assignment *models.Assignment, now time.Time) error {
+	if !now.After(assignment.DueAt) {
+		return nil
+	}
+	if assignment.LatePolicy == string(enums.LatePolicyReject) {
+		return appErrors.ErrSubmissionClosed
+	}
+	if assignment.LateUntil != nil && now.After(*assignment.LateUntil) {
+		return appErrors.ErrSubmissionClosed
+	}
+	return nil
+}
+
+func (s *assignmentService) Submit(ctx context.Context, assignmentID, studentID int64, comment string, files []UploadedFile) (*models.Submission, error) {
+	// 1. Check the student can hand in work for the assignment now
+	assignment, err := s.repo.GetAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	enrolled, err := s.repo.IsEnrolled(ctx, assignment.SectionID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, appErrors.New(http.StatusForbidden, "Only students enrolled in the section can submit")
+	}
+	if err := acceptsSubmissions(assignment, time.Now()); err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, appErrors.New(http.StatusBadRequest, "Attach at least one file")
+	}
+	if len(files) > s.cfg.MaxSubmissionFiles {
+		return nil, appErrors.New(http.StatusBadRequest, "At most %d files can be attached", s.cfg.MaxSubmissionFiles)
+	}
+
+	// 2. Store the files; they are removed again if the submission cannot be recorded
+	fileIDs := make([]int64, 0, len(files))
+	discard := func() {
+		for _, id := range fileIDs {
+			if err := s.fileSvc.DeleteFile(ctx, id); err != nil {
+				logger.Logger.Error("Failed to remove file of a failed submission", zap.Error(err), zap.Int64("file_id", id))
+			}
+		}
+	}
+	for _, upload := range files {
+		file, err := s.fileSvc.Upload(ctx, studentID, enums.FilePurposeSubmission, upload.Filename, upload.Content)
+		if err != nil {
+			discard()
+			return nil, err
+		}
+		fileIDs = append(fileIDs, file.ID)
+	}
+
+	// 3. Record the new version; the database stamps the submission time and checks the late
+	// policy again against it, as the upload may have taken the submission past a deadline
+	submission := &models.Submission{AssignmentID: assignment.ID, StudentID: studentID, Comment: optionalString(comment)}
+	if err := s.repo.CreateSubmission(ctx, submission, fileIDs); err != nil {
+		discard()
+		return nil, err
+	}
+	return s.repo.GetSubmission(ctx, submission.ID)
+}
+
+func (s *assignmentService) ListSubmissions(ctx context.Context, assignmentID, userID int64, isAdmin bool) (*models.AssignmentSubmissions, error) {
+	assignment, err := s.requireAssignmentInstructor(ctx, assignmentID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := s.repo.SubmissionStatuses(ctx, assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.AssignmentSubmissions{Assignment: *assignment, Students: statuses}
+	for _, status := range statuses {
+		if status.Latest == nil {
+			continue
+		}
+		result.Submitted++
+		if status.Latest.Late {
+			result.Late++
+		}
+	}
+	return result, nil
+}
+
+func (s *assignmentService) ListVersions(ctx context.Context, assignmentID, studentID, userID int64, isAdmin bool) ([]models.Submission, error) {
+	assignment, err := s.repo.GetAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.canSeeStudentWork(ctx, assignment, studentID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return s.repo.ListSubmissions(ctx, assignment.ID, studentID)
+}
+
+func (s *assignmentService) GetSubmission(ctx context.Context, id, userID int64, isAdmin bool) (*models.Submission, error) {
+	submission, err := s.repo.GetSubmission(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := s.repo.GetAssignment(ctx, submission.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.canSeeStudentWork(ctx, assignment, submission.StudentID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return submission, nil
+}
+
+func (s *assignmentService) GetSubmissionFileURL(ctx context.Context, submissionID, fileID, userID int64, isAdmin bool) (*models.FileURLResponse, error) {
+	submission, err := s.GetSubmission(ctx, submissionID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	for i := range submission.Files {
+		if submission.Files[i].ID == fileID {
+			return s.fileSvc.SignedURL(ctx, &submission.Files[i])
+		}
+	}
+	return nil, appErrors.ErrNotFound
+}
+
+func (s *assignmentService) GetStudentAssignments(ctx context.Context, studentID, termID int64) (*models.StudentAssignments, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, studentID); err != nil {
+		return nil, err
+	}
+	term, err := resolveTerm(ctx, s.termRepo, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, err := s.repo.ListForStudent(ctx, studentID, term.ID)
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := s.repo.LatestSubmissions(ctx, studentID, term.ID)
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[int64]*models.Submission, len(submissions))
+	for i := range submissions {
+		latest[submissions[i].AssignmentID] = &submissions[i]
+	}
+
+	result := &models.StudentAssignments{Term: term, Assignments: make([]models.StudentAssignment, 0, len(assignments))}
+	for _, assignment := range assignments {
+		entry := models.StudentAssignment{Assignment: assignment, Latest: latest[assignment.ID]}
+		if entry.Latest != nil {
+			entry.Versions = entry.Latest.Version
+		}
+		result.Assignments = append(result.Assignments, entry)
+	}
+	return result, nil
+}
@@ -0,0 +1,348 @@
+// internal/service/attendance_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/commons/logger"
+	"student-portal/internal/config"
+	kafka "student-portal/internal/kafka"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// AttendanceService defines the methods for class sessions and attendance. Methods taking a
+// userID and isAdmin act on behalf of that user: only the section's instructor and admins
+// may take attendance.
+type AttendanceService interface {
+	// GenerateSessions creates the class sessions of the section from its weekly meetings
+	// over the term, skipping holidays. Sessions with attendance records are kept, so it can
+	// be run again after the meetings change.
+	GenerateSessions(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.ClassSession, error)
+	ListSessions(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.ClassSession, error)
+	GetSessionAttendance(ctx context.Context, sessionID, userID int64, isAdmin bool) (*models.SessionAttendance, error)
+	MarkAttendance(ctx context.Context, sessionID int64, req *models.MarkAttendanceRequest, userID int64, isAdmin bool) (*models.SessionAttendance, error)
+	ListSectionSummaries(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.AttendanceSummary, error)
+	// GetStudentAttendance returns a student's attendance in the term (zero means the current term).
+	GetStudentAttendance(ctx context.Context, studentID, termID int64) (*models.StudentAttendance, error)
+}
+
+// validAttendanceStatuses are the statuses a student can be marked with.
+var validAttendanceStatuses = map[string]bool{
+	string(enums.AttendanceStatusPresent): true,
+	string(enums.AttendanceStatusAbsent):  true,
+	string(enums.AttendanceStatusLate):    true,
+	string(enums.AttendanceStatusExcused): true,
+}
+
+type attendanceService struct {
+	repo        repository.AttendanceRepository
+	sectionRepo repository.SectionRepository
+	termRepo    repository.TermRepository
+	userRepo    repository.UserRepository
+	transactor  repository.Transactor
+	historySvc  HistoryService
+	cfg         *config.Config
+	kafka       *kafka.KafkaProducer
+}
+
+// NewAttendanceService creates a new AttendanceService instance.
+func NewAttendanceService(repo repository.AttendanceRepository, sectionRepo repository.SectionRepository, termRepo repository.TermRepository, userRepo repository.UserRepository, transactor repository.Transactor, historySvc HistoryService, cfg *config.Config, kafka *kafka.KafkaProducer) AttendanceService {
+	return &attendanceService{repo: repo, sectionRepo: sectionRepo, termRepo: termRepo, userRepo: userRepo, transactor: transactor, historySvc: historySvc, cfg: cfg, kafka: kafka}
+}
+
+// requireSectionInstructor loads the section and checks that the user teaches it; admins
+// are let through. It guards everything an instructor manages for their section.
+func requireSectionInstructor(ctx context.Context, sectionRepo repository.SectionRepository, sectionID, userID int64, isAdmin bool) (*models.Section, error) {
+	section, err := sectionRepo.GetSection(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && (section.InstructorID == nil || *section.InstructorID != userID) {
+		return nil, appErrors.New(http.StatusForbidden, "Only the instructor of the section has access")
+	}
+	return section, nil
+}
+
+// scoreSummary fills in the attendance rate of a summary and whether it is below the threshold.
+func (s *attendanceService) scoreSummary(summary *models.AttendanceSummary) {
+	attended := summary.Counts.Present + summary.Counts.Late
+	countable := attended + summary.Counts.Absent
+	if countable == 0 {
+		return
+	}
+	rate := math.Round(float64(attended)/float64(countable)*1000) / 10
+	summary.Rate = &rate
+	summary.BelowThreshold = countable >= s.cfg.AttendanceAlertMinSessions && rate < s.cfg.AttendanceAlertThreshold
+}
+
+// sectionSummaries returns the scored attendance summaries of a section.
+func (s *attendanceService) sectionSummaries(ctx context.Context, sectionID int64) ([]models.AttendanceSummary, error) {
+	summaries, err := s.repo.SectionSummaries(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range summaries {
+		s.scoreSummary(&summaries[i])
+	}
+	return summaries, nil
+}
+
+// attendanceSnapshot is the change history representation of a session's attendance: the
+// status and note of every marked student, keyed by student ID.
+func attendanceSnapshot(roster []models.AttendanceRosterEntry) map[string]interface{} {
+	snapshot := make(map[string]interface{}, len(roster))
+	for _, entry := range roster {
+		if entry.Status != nil {
+			snapshot[strconv.FormatInt(entry.StudentID, 10)] = map[string]interface{}{"status": *entry.Status, "note": entry.Note}
+		}
+	}
+	return snapshot
+}
+
+// sessionsSnapshot is the change history representation of a section's class sessions.
+func sessionsSnapshot(sessions []models.ClassSession) map[string]interface{} {
+	dates := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		dates = append(dates, fmt.Sprintf("%s %s-%s", session.Date.Format(models.DateLayout), session.StartTime, session.EndTime))
+	}
+	return map[string]interface{}{"sessions": dates}
+}
+
+// writeAttendance runs write, which stores attendance records of the session, in one
+// transaction with the change history entry for the roster statuses it changed.
+func writeAttendance(ctx context.Context, transactor repository.Transactor, historySvc HistoryService, repo repository.AttendanceRepository, session *models.ClassSession, write func(ctx context.Context) error) error {
+	return transactor.WithinTx(ctx, func(ctx context.Context) error {
+		roster, err := repo.ListRoster(ctx, session)
+		if err != nil {
+			return err
+		}
+		before := attendanceSnapshot(roster)
+		if err := write(ctx); err != nil {
+			return err
+		}
+		if roster, err = repo.ListRoster(ctx, session); err != nil {
+			return err
+		}
+		return historySvc.Record(ctx, enums.EntityClassSession, session.ID, enums.ActionUpdate, before, attendanceSnapshot(roster))
+	})
+}
+
+// sessionsFromMeetings lists every occurrence of the section's meetings in the term.
+func sessionsFromMeetings(section *models.Section, term *models.Term) []models.ClassSession {
+	first := calendarDay(term.StartDate, time.UTC)
+	last := calendarDay(term.EndDate, time.UTC)
+	firstWeekday := (int(first.Weekday())+6)%7 + 1 // ISO 8601: Monday is 1, Sunday is 7
+
+	sessions := make([]models.ClassSession, 0)
+	for _, meeting := range section.Meetings {
+		meetingID := meeting.ID
+		for day := first.AddDate(0, 0, (meeting.DayOfWeek-firstWeekday+7)%7); !day.After(last); day = day.AddDate(0, 0, 7) {
+			if onHoliday(term, day) {
+				continue
+			}
+			sessions = append(sessions, models.ClassSession{
+				SectionID: section.ID,
+				MeetingID: &meetingID,
+				Date:      day,
+				StartTime: meeting.StartTime,
+				EndTime:   meeting.EndTime,
+				Room:      meeting.Room,
+			})
+		}
+	}
+	return sessions
+}
+
+func (s *attendanceService) GenerateSessions(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.ClassSession, error) {
+	section, err := requireSectionInstructor(ctx, s.sectionRepo, sectionID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	term, err := s.termRepo.GetTermByID(ctx, section.TermID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []models.ClassSession
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.ListSessions(ctx, section.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.ReplaceSessions(ctx, section.ID, sessionsFromMeetings(section, term)); err != nil {
+			return err
+		}
+		if sessions, err = s.repo.ListSessions(ctx, section.ID); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntitySection, section.ID, enums.ActionUpdate, sessionsSnapshot(before), sessionsSnapshot(sessions))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (s *attendanceService) ListSessions(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.ClassSession, error) {
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, sectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return s.repo.ListSessions(ctx, sectionID)
+}
+
+func (s *attendanceService) GetSessionAttendance(ctx context.Context, sessionID, userID int64, isAdmin bool) (*models.SessionAttendance, error) {
+	session, err := s.repo.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, session.SectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	roster, err := s.repo.ListRoster(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SessionAttendance{Session: *session, Roster: roster}, nil
+}
+
+func (s *attendanceService) MarkAttendance(ctx context.Context, sessionID int64, req *models.MarkAttendanceRequest, userID int64, isAdmin bool) (*models.SessionAttendance, error) {
+	attendance, err := s.GetSessionAttendance(ctx, sessionID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	session := &attendance.Session
+
+	// Only absences can be excused ahead of time
+	started := !session.Date.After(localToday(s.cfg.CalendarLocation))
+
+	onRoster := make(map[int64]*models.AttendanceRosterEntry, len(attendance.Roster))
+	for i := range attendance.Roster {
+		onRoster[attendance.Roster[i].StudentID] = &attendance.Roster[i]
+	}
+	checkStatus := func(status string) error {
+		if !validAttendanceStatuses[status] {
+			return appErrors.New(http.StatusBadRequest, "Invalid attendance status '%s'", status)
+		}
+		if !started && status != string(enums.AttendanceStatusExcused) {
+			return appErrors.New(http.StatusBadRequest, "Only excused absences can be recorded before the day of the session")
+		}
+		return nil
+	}
+
+	records := make([]models.AttendanceRecord, 0, len(req.Records))
+	listed := make(map[int64]bool, len(req.Records))
+	for _, mark := range req.Records {
+		if err := checkStatus(mark.Status); err != nil {
+			return nil, err
+		}
+		if onRoster[mark.StudentID] == nil {
+			return nil, appErrors.New(http.StatusBadRequest, "Student %d is not enrolled in this section", mark.StudentID)
+		}
+		if listed[mark.StudentID] {
+			return nil, appErrors.New(http.StatusBadRequest, "Student %d is listed more than once", mark.StudentID)
+		}
+		note := optionalString(mark.Note)
+		if note != nil && len(*note) > 255 {
+			return nil, appErrors.New(http.StatusBadRequest, "Note for student %d must be at most 255 characters", mark.StudentID)
+		}
+		listed[mark.StudentID] = true
+		records = append(records, models.AttendanceRecord{SessionID: session.ID, StudentID: mark.StudentID, Status: mark.Status, Note: note, MarkedBy: &userID})
+	}
+	if req.MarkRemaining != nil {
+		if err := checkStatus(*req.MarkRemaining); err != nil {
+			return nil, err
+		}
+		for _, entry := range attendance.Roster {
+			if entry.Status == nil && !listed[entry.StudentID] {
+				records = append(records, models.AttendanceRecord{SessionID: session.ID, StudentID: entry.StudentID, Status: *req.MarkRemaining, MarkedBy: &userID})
+			}
+		}
+	}
+	if len(records) == 0 {
+		return attendance, nil
+	}
+
+	before, err := s.sectionSummaries(ctx, session.SectionID)
+	if err != nil {
+		return nil, err
+	}
+	err = writeAttendance(ctx, s.transactor, s.historySvc, s.repo, session, func(ctx context.Context) error {
+		return s.repo.MarkAttendance(ctx, records)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publishAlerts(ctx, session.SectionID, before)
+
+	return s.GetSessionAttendance(ctx, sessionID, userID, isAdmin)
+}
+
+// publishAlerts publishes an alert for every student of the section whose attendance rate
+// dropped below the threshold since the given summaries were taken. Students already below
+// it are not alerted again.
+func (s *attendanceService) publishAlerts(ctx context.Context, sectionID int64, before []models.AttendanceSummary) {
+	after, err := s.sectionSummaries(ctx, sectionID)
+	if err != nil {
+		logger.Logger.Error("Failed to check attendance thresholds", zap.Error(err), zap.Int64("section_id", sectionID))
+		return
+	}
+	wasBelow := make(map[int64]bool, len(before))
+	for _, summary := range before {
+		wasBelow[summary.StudentID] = summary.BelowThreshold
+	}
+
+	threshold := s.cfg.AttendanceAlertThreshold
+	for _, summary := range after {
+		if !summary.BelowThreshold || wasBelow[summary.StudentID] {
+			continue
+		}
+		publishAsync(
+			func(ctx context.Context) error {
+				return s.kafka.PublishAttendanceBelowThresholdEvent(ctx, summary.StudentID, summary.CourseID, summary.TermID, summary.SectionID, *summary.Rate, threshold)
+			},
+			"attendance_below_threshold",
+			zap.Int64("user_id", summary.StudentID),
+			zap.Int64("section_id", summary.SectionID),
+		)
+	}
+}
+
+func (s *attendanceService) ListSectionSummaries(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.AttendanceSummary, error) {
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, sectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return s.sectionSummaries(ctx, sectionID)
+}
+
+func (s *attendanceService) GetStudentAttendance(ctx context.Context, studentID, termID int64) (*models.StudentAttendance, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, studentID); err != nil {
+		return nil, err
+	}
+	term, err := resolveTerm(ctx, s.termRepo, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := s.repo.StudentSummaries(ctx, studentID, term.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range summaries {
+		s.scoreSummary(&summaries[i])
+	}
+	records, err := s.repo.StudentRecords(ctx, studentID, term.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.StudentAttendance{Term: term, Summaries: summaries, Records: records}, nil
+}
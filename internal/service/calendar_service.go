@@ -0,0 +1,246 @@
+// internal/service/calendar_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+)
+
+// CalendarService defines the methods for iCalendar subscription feeds of timetables and deadlines.
+type CalendarService interface {
+	GetFeed(ctx context.Context, userID int64) (*models.CalendarFeed, error)
+	// CreateFeed generates a new secret feed URL for the user; any previous URL stops working.
+	CreateFeed(ctx context.Context, userID, createdBy int64) (*models.CalendarFeedResponse, error)
+	RevokeFeed(ctx context.Context, userID int64) error
+	// RenderFeed returns the iCalendar document of the feed the token belongs to.
+	RenderFeed(ctx context.Context, token string) ([]byte, error)
+}
+
+// calendarFeedHistory is how long a term stays in the feed after it ended.
+const calendarFeedHistory = 90 * 24 * time.Hour
+
+type calendarService struct {
+	repo           repository.CalendarRepository
+	userRepo       repository.UserRepository
+	termRepo       repository.TermRepository
+	sectionRepo    repository.SectionRepository
+	assignmentRepo repository.AssignmentRepository
+	cfg            *config.Config
+}
+
+// NewCalendarService creates a new CalendarService instance.
+func NewCalendarService(repo repository.CalendarRepository, userRepo repository.UserRepository, termRepo repository.TermRepository, sectionRepo repository.SectionRepository, assignmentRepo repository.AssignmentRepository, cfg *config.Config) CalendarService {
+	return &calendarService{repo: repo, userRepo: userRepo, termRepo: termRepo, sectionRepo: sectionRepo, assignmentRepo: assignmentRepo, cfg: cfg}
+}
+
+// feedError reports a missing feed as such rather than as a missing user.
+func feedError(err error) error {
+	if err == appErrors.ErrNotFound {
+		return appErrors.ErrNoCalendarFeed
+	}
+	return err
+}
+
+func (s *calendarService) GetFeed(ctx context.Context, userID int64) (*models.CalendarFeed, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	feed, err := s.repo.GetFeed(ctx, userID)
+	if err != nil {
+		return nil, feedError(err)
+	}
+	return feed, nil
+}
+
+func (s *calendarService) CreateFeed(ctx context.Context, userID, createdBy int64) (*models.CalendarFeedResponse, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	switch enums.Role(user.Role) {
+	case enums.RoleStudent, enums.RoleTeacher, enums.RoleAdmin:
+	default:
+		return nil, appErrors.New(http.StatusBadRequest, "Only students and teachers have a timetable")
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	feed := &models.CalendarFeed{UserID: userID, TokenHash: hashSecretToken(token), CreatedBy: &createdBy}
+	if err := s.repo.SaveFeed(ctx, feed); err != nil {
+		return nil, err
+	}
+
+	feedURL := s.cfg.PublicBaseURL + "/api/calendar/" + token + ".ics"
+	return &models.CalendarFeedResponse{CalendarFeed: *feed, Token: token, URL: feedURL}, nil
+}
+
+func (s *calendarService) RevokeFeed(ctx context.Context, userID int64) error {
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		return err
+	}
+	return feedError(s.repo.DeleteFeed(ctx, userID))
+}
+
+func (s *calendarService) RenderFeed(ctx context.Context, token string) ([]byte, error) {
+	feed, err := s.repo.UseFeed(ctx, hashSecretToken(token))
+	if err != nil {
+		return nil, feedError(err)
+	}
+	user, err := s.userRepo.GetUserByID(ctx, feed.UserID)
+	if err != nil {
+		return nil, feedError(err)
+	}
+	// Feeds of accounts that cannot sign in go quiet rather than leaking a closed account's schedule
+	if user.Status != string(enums.AccountStatusActive) {
+		return nil, appErrors.ErrNoCalendarFeed
+	}
+
+	now := time.Now()
+	loc := s.cfg.CalendarLocation
+	terms, err := s.termRepo.ListTermsEndingAfter(ctx, now.Add(-calendarFeedHistory))
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &icsCalendar{Name: "Timetable of " + user.Name, Location: loc, From: now, To: now, Events: make([]calendarEvent, 0)}
+	for i := range terms {
+		term := &terms[i]
+		var entries []models.TimetableEntry
+		var assignments []models.Assignment
+		if enums.Role(user.Role) == enums.RoleStudent {
+			entries, err = s.sectionRepo.StudentTimetable(ctx, user.ID, term.ID)
+			if err == nil {
+				assignments, err = s.assignmentRepo.ListForStudent(ctx, user.ID, term.ID)
+			}
+		} else {
+			entries, err = s.sectionRepo.InstructorTimetable(ctx, user.ID, term.ID)
+			if err == nil {
+				assignments, err = s.assignmentRepo.ListForInstructor(ctx, user.ID, term.ID)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		calendar.Events = append(calendar.Events, meetingEvents(term, entries, loc)...)
+		calendar.Events = append(calendar.Events, termEvents(term, loc)...)
+		for _, assignment := range assignments {
+			event := dueDateEvent(&assignment, loc)
+			if event.Start.Before(calendar.From) {
+				calendar.From = event.Start
+			}
+			if event.End.After(calendar.To) {
+				calendar.To = event.End
+			}
+			calendar.Events = append(calendar.Events, event)
+		}
+		if start := calendarDay(term.StartDate, loc); start.Before(calendar.From) {
+			calendar.From = start
+		}
+		if end := calendarDay(term.EndDate, loc).AddDate(0, 0, 1); end.After(calendar.To) {
+			calendar.To = end
+		}
+	}
+	return calendar.Render(now), nil
+}
+
+// atClock returns the given minutes since midnight on the day.
+func atClock(day time.Time, minutes int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), minutes/60, minutes%60, 0, 0, day.Location())
+}
+
+// meetingEvents turns the weekly meetings of a term into events repeating from the first
+// matching day of the term until its end, skipping the term's holidays.
+func meetingEvents(term *models.Term, entries []models.TimetableEntry, loc *time.Location) []calendarEvent {
+	first := calendarDay(term.StartDate, loc)
+	last := calendarDay(term.EndDate, loc)
+	firstWeekday := (int(first.Weekday())+6)%7 + 1 // ISO 8601: Monday is 1, Sunday is 7
+
+	events := make([]calendarEvent, 0, len(entries))
+	for _, e := range entries {
+		day := first.AddDate(0, 0, (e.DayOfWeek-firstWeekday+7)%7)
+		if day.After(last) {
+			continue
+		}
+		start, _ := clockMinutes(e.StartTime)
+		end, _ := clockMinutes(e.EndTime)
+
+		event := calendarEvent{
+			UID:         fmt.Sprintf("meeting-%d@student-portal", e.MeetingID),
+			Summary:     fmt.Sprintf("%s %s (%s)", e.CourseCode, e.CourseTitle, e.SectionCode),
+			Description: term.Name,
+			Start:       atClock(day, start),
+			End:         atClock(day, end),
+			Until:       last.AddDate(0, 0, 1).Add(-time.Second),
+			ExDates:     make([]time.Time, 0),
+			Tentative:   e.Status == string(enums.EnrollmentStatusWaitlisted),
+		}
+		if e.InstructorName != nil {
+			event.Description += "\nInstructor: " + *e.InstructorName
+		}
+		if e.Room != nil {
+			event.Location = *e.Room
+		}
+		if event.Tentative {
+			event.Summary += " (waitlisted)"
+		}
+		for d := day; !d.After(last); d = d.AddDate(0, 0, 7) {
+			if onHoliday(term, d) {
+				event.ExDates = append(event.ExDates, atClock(d, start))
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// allDayEvent returns an event covering the days from first to last, inclusive.
+func allDayEvent(uid, summary, description string, first, last time.Time) calendarEvent {
+	return calendarEvent{UID: uid, Summary: summary, Description: description, AllDay: true, Start: first, End: last.AddDate(0, 0, 1)}
+}
+
+// termEvents returns the all-day events of a term: its deadlines, exam period and holidays.
+func termEvents(term *models.Term, loc *time.Location) []calendarEvent {
+	events := make([]calendarEvent, 0)
+	if term.AddDeadline != nil {
+		day := term.AddDeadline.In(loc)
+		events = append(events, allDayEvent(fmt.Sprintf("term-%d-add-deadline@student-portal", term.ID),
+			"Add deadline "+term.Code, "Last day to enroll in courses for "+term.Name, day, day))
+	}
+	if term.DropDeadline != nil {
+		day := term.DropDeadline.In(loc)
+		events = append(events, allDayEvent(fmt.Sprintf("term-%d-drop-deadline@student-portal", term.ID),
+			"Drop deadline "+term.Code, "Last day to drop courses for "+term.Name, day, day))
+	}
+	if term.ExamStart != nil && term.ExamEnd != nil {
+		events = append(events, allDayEvent(fmt.Sprintf("term-%d-exams@student-portal", term.ID),
+			"Exams "+term.Code, term.Name+" exam period", calendarDay(*term.ExamStart, loc), calendarDay(*term.ExamEnd, loc)))
+	}
+	for _, holiday := range term.Holidays {
+		events = append(events, allDayEvent(fmt.Sprintf("holiday-%d@student-portal", holiday.ID),
+			holiday.Name, term.Name+": no classes", calendarDay(holiday.StartDate, loc), calendarDay(holiday.EndDate, loc)))
+	}
+	return events
+}
+
+// dueDateEvent returns an all-day event on the day an assignment is due; the time it is due is
+// given in the description. Calendar clients render zero-length timed events inconsistently.
+func dueDateEvent(assignment *models.Assignment, loc *time.Location) calendarEvent {
+	due := assignment.DueAt.In(loc)
+	return allDayEvent(fmt.Sprintf("assignment-%d@student-portal", assignment.ID),
+		fmt.Sprintf("Due: %s (%s)", assignment.Title, assignment.CourseCode),
+		fmt.Sprintf("%s section %s, %s points, due at %s", assignment.CourseCode, assignment.SectionCode,
+			strconv.FormatFloat(assignment.MaxPoints, 'f', -1, 64), due.Format("15:04")),
+		calendarDay(due, loc), calendarDay(due, loc))
+}
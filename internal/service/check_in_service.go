@@ -0,0 +1,345 @@
+// internal/service/check_in_service.go
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+)
+
+// CheckInService defines the methods for attendance self check-in. Instructors open a
+// window on a class session and display its rotating code; students submit it to be marked
+// present.
+type CheckInService interface {
+	OpenWindow(ctx context.Context, sessionID int64, req *models.OpenCheckInRequest, userID int64, isAdmin bool) (*models.CheckInCode, error)
+	// GetCode returns the current code of the session's open window.
+	GetCode(ctx context.Context, sessionID, userID int64, isAdmin bool) (*models.CheckInCode, error)
+	CloseWindow(ctx context.Context, sessionID, userID int64, isAdmin bool) error
+	// CheckIn marks the student present in the session whose code they submitted. ip is the
+	// address the request came from.
+	CheckIn(ctx context.Context, studentID int64, req *models.CheckInRequest, ip string) (*models.AttendanceRecord, error)
+}
+
+const (
+	defaultCheckInMinutes = 15
+	maxCheckInMinutes     = 180
+	minGeofenceMeters     = 10
+	maxGeofenceMeters     = 5000
+	maxCheckInNetworks    = 20
+	checkInCodeDigits     = 6
+	earthRadiusMeters     = 6371000
+)
+
+type checkInService struct {
+	repo           repository.CheckInRepository
+	attendanceRepo repository.AttendanceRepository
+	sectionRepo    repository.SectionRepository
+	transactor     repository.Transactor
+	historySvc     HistoryService
+	cfg            *config.Config
+}
+
+// NewCheckInService creates a new CheckInService instance.
+func NewCheckInService(repo repository.CheckInRepository, attendanceRepo repository.AttendanceRepository, sectionRepo repository.SectionRepository, transactor repository.Transactor, historySvc HistoryService, cfg *config.Config) CheckInService {
+	return &checkInService{repo: repo, attendanceRepo: attendanceRepo, sectionRepo: sectionRepo, transactor: transactor, historySvc: historySvc, cfg: cfg}
+}
+
+// codeStep returns the number of the code rotation period t falls in.
+func (s *checkInService) codeStep(t time.Time) int64 {
+	return t.Unix() / int64(s.cfg.CheckInCodeTTL/time.Second)
+}
+
+// codeMAC signs a window's code for one rotation period with the server key.
+func (s *checkInService) codeMAC(windowID, step int64) []byte {
+	mac := hmac.New(sha256.New, []byte(s.cfg.CheckInSigningKey))
+	fmt.Fprintf(mac, "check-in:%d:%d", windowID, step)
+	return mac.Sum(nil)
+}
+
+// shortCode derives the typed code from a signature by dynamic truncation (RFC 4226).
+func shortCode(sum []byte) string {
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", checkInCodeDigits, value%uint32(math.Pow10(checkInCodeDigits)))
+}
+
+// qrPayload returns the self-contained QR payload "<window>.<step>.<signature>".
+func qrPayload(windowID, step int64, sum []byte) string {
+	return fmt.Sprintf("%d.%d.%s", windowID, step, base64.RawURLEncoding.EncodeToString(sum[:16]))
+}
+
+// currentCode returns the code of the window for the current rotation period.
+func (s *checkInService) currentCode(window *models.CheckInWindow) *models.CheckInCode {
+	step := s.codeStep(time.Now())
+	sum := s.codeMAC(window.ID, step)
+	validUntil := time.Unix((step+1)*int64(s.cfg.CheckInCodeTTL/time.Second), 0)
+	if validUntil.After(window.ClosesAt) {
+		validUntil = window.ClosesAt
+	}
+	return &models.CheckInCode{Window: *window, Code: shortCode(sum), Payload: qrPayload(window.ID, step, sum), ValidUntil: validUntil}
+}
+
+// matchesWindow reports whether the submitted code is the window's code of the current or
+// the previous rotation period, which covers a code that rotated while it was being typed.
+func (s *checkInService) matchesWindow(window *models.CheckInWindow, code string) bool {
+	current := s.codeStep(time.Now())
+	if parts := strings.Split(code, "."); len(parts) == 3 {
+		windowID, err1 := strconv.ParseInt(parts[0], 10, 64)
+		step, err2 := strconv.ParseInt(parts[1], 10, 64)
+		if err1 != nil || err2 != nil || windowID != window.ID || (step != current && step != current-1) {
+			return false
+		}
+		return hmac.Equal([]byte(qrPayload(window.ID, step, s.codeMAC(window.ID, step))), []byte(code))
+	}
+	for _, step := range []int64{current, current - 1} {
+		if hmac.Equal([]byte(shortCode(s.codeMAC(window.ID, step))), []byte(code)) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseNetworks validates the allowed networks and normalizes them to CIDR notation; single
+// addresses are accepted as well.
+func parseNetworks(networks []string) ([]string, error) {
+	if len(networks) > maxCheckInNetworks {
+		return nil, appErrors.New(http.StatusBadRequest, "At most %d networks can be allowed", maxCheckInNetworks)
+	}
+	normalized := make([]string, 0, len(networks))
+	for _, network := range networks {
+		network = strings.TrimSpace(network)
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(network)
+			if addrErr != nil {
+				return nil, appErrors.New(http.StatusBadRequest, "Invalid network '%s'", network)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		normalized = append(normalized, prefix.Masked().String())
+	}
+	return normalized, nil
+}
+
+// distanceMeters returns the great-circle distance between two coordinates.
+func distanceMeters(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLng := (lng2 - lng1) * toRad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
+
+// validCoordinates reports whether a latitude and longitude are on the globe.
+func validCoordinates(lat, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
+
+// checkRestrictions returns why the check-in breaks the window's restrictions, if it does.
+func checkRestrictions(window *models.CheckInWindow, req *models.CheckInRequest, ip string) string {
+	if len(window.AllowedNetworks) > 0 {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil {
+			return "network"
+		}
+		allowed := false
+		for _, network := range window.AllowedNetworks {
+			if prefix, err := netip.ParsePrefix(network); err == nil && prefix.Contains(addr.Unmap()) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return "network"
+		}
+	}
+	if window.RadiusMeters != nil {
+		if req.Latitude == nil || req.Longitude == nil || !validCoordinates(*req.Latitude, *req.Longitude) {
+			return "location_missing"
+		}
+		if distanceMeters(*window.Latitude, *window.Longitude, *req.Latitude, *req.Longitude) > float64(*window.RadiusMeters) {
+			return "location"
+		}
+	}
+	return ""
+}
+
+// instructorSession loads the class session and checks that the user teaches it.
+func (s *checkInService) instructorSession(ctx context.Context, sessionID, userID int64, isAdmin bool) (*models.ClassSession, error) {
+	session, err := s.attendanceRepo.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, session.SectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *checkInService) OpenWindow(ctx context.Context, sessionID int64, req *models.OpenCheckInRequest, userID int64, isAdmin bool) (*models.CheckInCode, error) {
+	session, err := s.instructorSession(ctx, sessionID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if !session.Date.Equal(localToday(s.cfg.CalendarLocation)) {
+		return nil, appErrors.New(http.StatusBadRequest, "Check-in can only be opened on the day of the session")
+	}
+	if _, err := s.repo.GetOpenWindow(ctx, sessionID); err == nil {
+		return nil, appErrors.ErrCheckInWindowOpen
+	} else if err != appErrors.ErrNotFound {
+		return nil, err
+	}
+
+	minutes := req.DurationMinutes
+	if minutes == 0 {
+		minutes = defaultCheckInMinutes
+	}
+	if minutes < 1 || minutes > maxCheckInMinutes {
+		return nil, appErrors.New(http.StatusBadRequest, "Check-in can be open for 1 to %d minutes", maxCheckInMinutes)
+	}
+	window := &models.CheckInWindow{
+		SessionID: sessionID,
+		OpenedBy:  &userID,
+		ClosesAt:  time.Now().Add(time.Duration(minutes) * time.Minute),
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+	}
+	if window.AllowedNetworks, err = parseNetworks(req.AllowedNetworks); err != nil {
+		return nil, err
+	}
+	if req.Latitude != nil || req.Longitude != nil || req.RadiusMeters != nil {
+		if req.Latitude == nil || req.Longitude == nil || req.RadiusMeters == nil || !validCoordinates(*req.Latitude, *req.Longitude) {
+			return nil, appErrors.New(http.StatusBadRequest, "A geofence needs a valid latitude, longitude and radius_meters")
+		}
+		if *req.RadiusMeters < minGeofenceMeters || *req.RadiusMeters > maxGeofenceMeters {
+			return nil, appErrors.New(http.StatusBadRequest, "radius_meters must be between %d and %d", minGeofenceMeters, maxGeofenceMeters)
+		}
+		window.RadiusMeters = req.RadiusMeters
+	}
+
+	if err := s.repo.CreateWindow(ctx, window); err != nil {
+		return nil, err
+	}
+	return s.currentCode(window), nil
+}
+
+func (s *checkInService) GetCode(ctx context.Context, sessionID, userID int64, isAdmin bool) (*models.CheckInCode, error) {
+	if _, err := s.instructorSession(ctx, sessionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	window, err := s.repo.GetOpenWindow(ctx, sessionID)
+	if err == appErrors.ErrNotFound {
+		return nil, appErrors.ErrNoCheckInWindow
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.currentCode(window), nil
+}
+
+func (s *checkInService) CloseWindow(ctx context.Context, sessionID, userID int64, isAdmin bool) error {
+	if _, err := s.instructorSession(ctx, sessionID, userID, isAdmin); err != nil {
+		return err
+	}
+	if err := s.repo.CloseWindow(ctx, sessionID); err != nil {
+		if err == appErrors.ErrNotFound {
+			return appErrors.ErrNoCheckInWindow
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *checkInService) CheckIn(ctx context.Context, studentID int64, req *models.CheckInRequest, ip string) (*models.AttendanceRecord, error) {
+	code := strings.TrimSpace(req.Code)
+	if code == "" {
+		return nil, appErrors.ErrInvalidCheckInCode
+	}
+	windows, err := s.repo.ListOpenWindowsForStudent(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Windows the student has guessed at too often no longer accept their codes
+	candidates := make([]models.CheckInWindow, 0, len(windows))
+	for _, window := range windows {
+		failed, err := s.repo.CountFailedAttempts(ctx, window.ID, studentID)
+		if err != nil {
+			return nil, err
+		}
+		if failed < s.cfg.CheckInMaxAttempts {
+			candidates = append(candidates, window)
+		}
+	}
+	if len(candidates) == 0 && len(windows) > 0 {
+		return nil, appErrors.ErrTooManyCheckIns
+	}
+
+	var address *string
+	if ip != "" {
+		address = &ip
+	}
+	fail := func(windowID int64, reason string) error {
+		return s.repo.RecordAttempt(ctx, &models.CheckInAttempt{WindowID: windowID, StudentID: studentID, Reason: &reason, IPAddress: address})
+	}
+
+	for i := range candidates {
+		window := &candidates[i]
+		if !s.matchesWindow(window, code) {
+			continue
+		}
+		if reason := checkRestrictions(window, req, ip); reason != "" {
+			if err := fail(window.ID, reason); err != nil {
+				return nil, err
+			}
+			if reason == "location_missing" {
+				return nil, appErrors.New(http.StatusBadRequest, "This check-in requires your location")
+			}
+			return nil, appErrors.New(http.StatusForbidden, "Check-in is not allowed from your %s", reason)
+		}
+
+		note := "Self check-in"
+		record := &models.AttendanceRecord{
+			SessionID: window.SessionID,
+			StudentID: studentID,
+			Status:    string(enums.AttendanceStatusPresent),
+			Note:      &note,
+			MarkedBy:  &studentID,
+		}
+		session, err := s.attendanceRepo.GetSession(ctx, window.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		attempt := &models.CheckInAttempt{WindowID: window.ID, StudentID: studentID, Succeeded: true, IPAddress: address}
+		err = writeAttendance(ctx, s.transactor, s.historySvc, s.attendanceRepo, session, func(ctx context.Context) error {
+			return s.repo.CheckIn(ctx, attempt, record)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+
+	// The code matched none of the student's windows; count it against each of them
+	for _, window := range candidates {
+		if err := fail(window.ID, "invalid_code"); err != nil {
+			return nil, err
+		}
+	}
+	return nil, appErrors.ErrInvalidCheckInCode
+}
@@ -0,0 +1,312 @@
+// internal/service/course_service.go
+package service
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	kafka "student-portal/internal/kafka"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// CourseService defines the methods for the course catalog.
+type CourseService interface {
+	CreateCourse(ctx context.Context, req *models.CreateCourseRequest, createdBy int64) (*models.Course, error)
+	GetCourse(ctx context.Context, id int64, includeInactive bool) (*models.Course, error)
+	UpdateCourse(ctx context.Context, id int64, req *models.UpdateCourseRequest, updatedBy int64) (*models.Course, error)
+	DeleteCourse(ctx context.Context, id int64, deletedBy int64) error
+	ListCourses(ctx context.Context, filter models.CourseFilter, limit, offset int) ([]models.Course, int64, error)
+	SetRequirements(ctx context.Context, id int64, req *models.CourseRequirements) (*models.Course, error)
+}
+
+// courseCodePattern matches codes like "CS101", "MATH-201A" or "BIO 110" after upper-casing.
+var courseCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9]*([ -]?[A-Z0-9]+)*$`)
+
+// maxCourseCredits bounds the credits of a single course.
+const maxCourseCredits = 60
+
+type courseService struct {
+	repo       repository.CourseRepository
+	transactor repository.Transactor
+	historySvc HistoryService
+	cfg        *config.Config
+	kafka      *kafka.KafkaProducer
+}
+
+// NewCourseService creates a new CourseService instance.
+func NewCourseService(repo repository.CourseRepository, transactor repository.Transactor, historySvc HistoryService, cfg *config.Config, kafka *kafka.KafkaProducer) CourseService {
+	return &courseService{repo: repo, transactor: transactor, historySvc: historySvc, cfg: cfg, kafka: kafka}
+}
+
+// normalizeCourseCode trims and upper-cases a course code and validates it.
+func normalizeCourseCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) < 2 || len(code) > 20 || !courseCodePattern.MatchString(code) {
+		return "", appErrors.New(http.StatusBadRequest, "Invalid course code '%s'", code)
+	}
+	return code, nil
+}
+
+// validateCourse checks the fields of a course before it is saved.
+func validateCourse(course *models.Course) error {
+	if course.Title == "" {
+		return appErrors.New(http.StatusBadRequest, "Title cannot be empty")
+	}
+	if course.Credits < 0 || course.Credits > maxCourseCredits || math.IsNaN(course.Credits) {
+		return appErrors.New(http.StatusBadRequest, "Credits must be between 0 and %d", maxCourseCredits)
+	}
+	// The column keeps one decimal place.
+	if math.Round(course.Credits*10) != course.Credits*10 {
+		return appErrors.New(http.StatusBadRequest, "Credits can have at most one decimal place")
+	}
+	if course.Capacity != nil && *course.Capacity <= 0 {
+		return appErrors.New(http.StatusBadRequest, "Capacity must be positive")
+	}
+	return nil
+}
+
+func (s *courseService) CreateCourse(ctx context.Context, req *models.CreateCourseRequest, createdBy int64) (*models.Course, error) {
+	code, err := normalizeCourseCode(req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	course := &models.Course{
+		Code:        code,
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		Credits:     req.Credits,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+		Capacity:    req.Capacity,
+		CreatedBy:   &createdBy,
+	}
+	if req.Department != nil {
+		course.Department = optionalString(*req.Department)
+	}
+	if err := validateCourse(course); err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateCourse(ctx, course); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityCourse, course.ID, enums.ActionCreate, nil, course)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishAsync(
+		func(ctx context.Context) error {
+			return s.kafka.PublishCourseCreatedEvent(ctx, course.ID, course.Code, course.Title, course.Credits, course.IsActive, createdBy)
+		},
+		"course_created",
+		zap.Int64("course_id", course.ID),
+	)
+
+	return course, nil
+}
+
+func (s *courseService) GetCourse(ctx context.Context, id int64, includeInactive bool) (*models.Course, error) {
+	course, err := s.repo.GetCourseByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Inactive courses do not exist as far as the public catalog is concerned.
+	if !course.IsActive && !includeInactive {
+		return nil, appErrors.ErrNotFound
+	}
+	return course, nil
+}
+
+func (s *courseService) UpdateCourse(ctx context.Context, id int64, req *models.UpdateCourseRequest, updatedBy int64) (*models.Course, error) {
+	course, err := s.repo.GetCourseByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *course
+
+	if req.Code != nil {
+		if course.Code, err = normalizeCourseCode(*req.Code); err != nil {
+			return nil, err
+		}
+	}
+	if req.Title != nil {
+		course.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		course.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Credits != nil {
+		course.Credits = *req.Credits
+	}
+	if req.Department != nil {
+		course.Department = optionalString(*req.Department)
+	}
+	if req.IsActive != nil {
+		course.IsActive = *req.IsActive
+	}
+	if req.Capacity != nil {
+		course.Capacity = req.Capacity
+		if *req.Capacity == 0 {
+			course.Capacity = nil
+		}
+	}
+	if err := validateCourse(course); err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateCourse(ctx, course); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityCourse, course.ID, enums.ActionUpdate, &before, course)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishAsync(
+		func(ctx context.Context) error {
+			return s.kafka.PublishCourseUpdatedEvent(ctx, course.ID, course.Code, course.Title, course.Credits, course.IsActive, updatedBy)
+		},
+		"course_updated",
+		zap.Int64("course_id", course.ID),
+	)
+
+	return course, nil
+}
+
+func (s *courseService) DeleteCourse(ctx context.Context, id int64, deletedBy int64) error {
+	course, err := s.repo.GetCourseByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteCourse(ctx, id); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityCourse, course.ID, enums.ActionDelete, course, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	publishAsync(
+		func(ctx context.Context) error {
+			return s.kafka.PublishCourseDeletedEvent(ctx, course.ID, course.Code, deletedBy)
+		},
+		"course_deleted",
+		zap.Int64("course_id", course.ID),
+	)
+
+	return nil
+}
+
+func (s *courseService) ListCourses(ctx context.Context, filter models.CourseFilter, limit, offset int) ([]models.Course, int64, error) {
+	filter.Search = strings.TrimSpace(filter.Search)
+	filter.Department = strings.TrimSpace(filter.Department)
+	return s.repo.ListCourses(ctx, filter, limit, offset)
+}
+
+func (s *courseService) SetRequirements(ctx context.Context, id int64, req *models.CourseRequirements) (*models.Course, error) {
+	course, err := s.repo.GetCourseByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *course
+
+	// 1. Check the shape of the expression and collect the referenced courses
+	referenced := make(map[int64]bool)
+	if req.Prerequisites != nil {
+		count := 0
+		if err := validateRequirementNode(req.Prerequisites, 1, &count, referenced); err != nil {
+			return nil, err
+		}
+	}
+	corequisites := make(map[int64]bool, len(req.CorequisiteIDs))
+	for _, coreqID := range req.CorequisiteIDs {
+		if corequisites[coreqID] {
+			return nil, appErrors.New(http.StatusBadRequest, "Co-requisite %d is listed twice", coreqID)
+		}
+		corequisites[coreqID] = true
+		referenced[coreqID] = true
+	}
+	if referenced[id] {
+		return nil, appErrors.New(http.StatusBadRequest, "A course cannot require itself")
+	}
+
+	// 2. Make sure the referenced courses exist
+	ids := make([]int64, 0, len(referenced))
+	for refID := range referenced {
+		ids = append(ids, refID)
+	}
+	codes, err := s.repo.GetCourseCodes(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, refID := range ids {
+		if _, ok := codes[refID]; !ok {
+			return nil, appErrors.New(http.StatusBadRequest, "Course %d does not exist", refID)
+		}
+	}
+
+	// 3. A prerequisite cycle would make every course in it impossible to take
+	if req.Prerequisites != nil {
+		if err := s.checkPrerequisiteCycle(ctx, id, requirementCourseIDs(&models.Course{Prerequisites: req.Prerequisites})); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.SetRequirements(ctx, id, req); err != nil {
+			return err
+		}
+		course.Prerequisites, course.CorequisiteIDs = req.Prerequisites, req.CorequisiteIDs
+		if course.CorequisiteIDs == nil {
+			course.CorequisiteIDs = []int64{}
+		}
+		return s.historySvc.Record(ctx, enums.EntityCourse, course.ID, enums.ActionUpdate, &before, course)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+// checkPrerequisiteCycle fails if any of the given prerequisite courses, directly or through
+// their own prerequisites, requires the course itself.
+func (s *courseService) checkPrerequisiteCycle(ctx context.Context, courseID int64, prerequisiteIDs []int64) error {
+	visited := make(map[int64]bool)
+	queue := append([]int64{}, prerequisiteIDs...)
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if next == courseID {
+			return appErrors.New(http.StatusBadRequest, "Prerequisites would form a cycle back to this course")
+		}
+		if visited[next] {
+			continue
+		}
+		visited[next] = true
+
+		prerequisite, err := s.repo.GetCourseByID(ctx, next)
+		if err != nil {
+			return err
+		}
+		if prerequisite.Prerequisites != nil {
+			queue = append(queue, requirementCourseIDs(&models.Course{Prerequisites: prerequisite.Prerequisites})...)
+		}
+	}
+	return nil
+}
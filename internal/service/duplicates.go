@@ -0,0 +1,248 @@
+// internal/service/duplicates.go
+package service
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"student-portal/internal/models"
+	"student-portal/internal/utils"
+)
+
+// Weights of the individual duplicate signals. They are combined as independent
+// probabilities, so several weak signals add up without exceeding 1.
+const (
+	weightSameEmail         = 0.95
+	weightSameStudentNumber = 0.9
+	weightSameEmailLocal    = 0.5
+	weightSamePhone         = 0.5
+	weightSimilarName       = 0.45
+
+	// minNameSimilarity is the normalized edit similarity above which two names count as similar.
+	minNameSimilarity = 0.85
+
+	// DefaultDuplicateMinScore is the score above which a pair is reported as a likely duplicate.
+	// A similar name alone stays below it; it needs a second signal such as the email's local part.
+	DefaultDuplicateMinScore = 0.5
+
+	// maxDuplicatePairs caps the candidate pairs fetched for scoring, so that a very common
+	// name cannot make the comparison unbounded.
+	maxDuplicatePairs = 10000
+)
+
+// gmailDomains ignore dots in the local part and are aliases of each other.
+var gmailDomains = map[string]bool{"gmail.com": true, "googlemail.com": true}
+
+// normalizeEmail lowercases an address and strips "+tag" suffixes, plus the dots
+// Gmail ignores, so that aliases of the same mailbox compare equal.
+func normalizeEmail(email string) string {
+	email = utils.NormalizeEmail(email, true)
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+
+	local, domain := email[:at], email[at+1:]
+	if plus := strings.Index(local, "+"); plus >= 0 {
+		local = local[:plus]
+	}
+	if gmailDomains[domain] {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+	return local + "@" + domain
+}
+
+// emailLocalPart returns the normalized part of an address before the "@", without
+// separators, so that jane.doe@gmail.com and jane_doe@school.edu match.
+func emailLocalPart(email string) string {
+	normalized := normalizeEmail(email)
+	if at := strings.LastIndex(normalized, "@"); at >= 0 {
+		normalized = normalized[:at]
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, normalized)
+}
+
+// normalizePhone keeps the last nine digits of a phone number, so that numbers written with
+// and without the country code or separators compare equal. Numbers of fewer than seven
+// digits are ignored. It matches the phone key computed by the repository.
+func normalizePhone(phone *string) string {
+	if phone == nil {
+		return ""
+	}
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, *phone)
+	if len(digits) < 7 {
+		return ""
+	}
+	return digits[max(0, len(digits)-9):]
+}
+
+// normalizeName lowercases a name, drops punctuation and sorts its words,
+// so that "Doe, Jane" and "jane doe" compare equal.
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// nameSimilarity returns 1 minus the edit distance of the normalized names relative to the longer one.
+func nameSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein computes the edit distance between two rune slices.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// duplicateKeys holds the normalized values of one user.
+type duplicateKeys struct {
+	email, local, name, studentNumber, phone string
+}
+
+func newDuplicateKeys(user *models.DuplicateUser) duplicateKeys {
+	keys := duplicateKeys{
+		email: normalizeEmail(user.Email),
+		local: emailLocalPart(user.Email),
+		name:  normalizeName(user.Name),
+		phone: normalizePhone(user.Phone),
+	}
+	if user.StudentNumber != nil {
+		keys.studentNumber = strings.ToLower(strings.TrimSpace(*user.StudentNumber))
+	}
+	return keys
+}
+
+// scoreDuplicates scores the candidate pairs found by the repository and returns those
+// scoring at least minScore, most likely duplicates first.
+func scoreDuplicates(pairs [][2]models.DuplicateUser, minScore float64) []models.DuplicateCandidate {
+	candidates := make([]models.DuplicateCandidate, 0)
+	for _, pair := range pairs {
+		if pair[0].Role != pair[1].Role {
+			continue
+		}
+
+		a, b := newDuplicateKeys(&pair[0]), newDuplicateKeys(&pair[1])
+		unlikely := 1.0 // Probability that the pair is not a duplicate
+		reasons := make([]string, 0, 4)
+
+		if a.email == b.email {
+			unlikely *= 1 - weightSameEmail
+			reasons = append(reasons, "same_normalized_email")
+		} else if a.local != "" && a.local == b.local {
+			unlikely *= 1 - weightSameEmailLocal
+			reasons = append(reasons, "same_email_local_part")
+		}
+		if a.studentNumber != "" && a.studentNumber == b.studentNumber {
+			unlikely *= 1 - weightSameStudentNumber
+			reasons = append(reasons, "same_student_number")
+		}
+		if a.phone != "" && a.phone == b.phone {
+			unlikely *= 1 - weightSamePhone
+			reasons = append(reasons, "same_phone")
+		}
+		if similarity := nameSimilarity(a.name, b.name); similarity >= minNameSimilarity {
+			unlikely *= 1 - weightSimilarName*similarity
+			reasons = append(reasons, "similar_name")
+		}
+
+		if score := 1 - unlikely; len(reasons) > 0 && score >= minScore {
+			candidates = append(candidates, models.DuplicateCandidate{
+				Users:   pair,
+				Score:   math.Round(score*100) / 100,
+				Reasons: reasons,
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates
+}
+
+// mergedProfile returns the profile the target would have after merging the source into it,
+// plus the fields set on both with different values (the target's value is kept).
+// It mirrors the merge performed by the repository.
+func mergedProfile(target, source *models.Profile) (*models.Profile, []string) {
+	result := *target
+	result.CustomFields = make(map[string]interface{}, len(target.CustomFields)+len(source.CustomFields))
+	conflicts := make([]string, 0)
+
+	mergeString := func(field string, dst **string, src *string) {
+		if *dst == nil {
+			*dst = src
+		} else if src != nil && **dst != *src {
+			conflicts = append(conflicts, field)
+		}
+	}
+	mergeString(fieldPhone, &result.Phone, source.Phone)
+	mergeString(fieldAddress, &result.Address, source.Address)
+	mergeString(fieldStudentNumber, &result.StudentNumber, source.StudentNumber)
+	mergeString(fieldProgram, &result.Program, source.Program)
+
+	if result.DateOfBirth == nil {
+		result.DateOfBirth = source.DateOfBirth
+	} else if source.DateOfBirth != nil && !result.DateOfBirth.Equal(*source.DateOfBirth) {
+		conflicts = append(conflicts, fieldDateOfBirth)
+	}
+	if result.EnrollmentYear == nil {
+		result.EnrollmentYear = source.EnrollmentYear
+	} else if source.EnrollmentYear != nil && *result.EnrollmentYear != *source.EnrollmentYear {
+		conflicts = append(conflicts, fieldEnrollmentYear)
+	}
+	if result.AvatarFileID == nil {
+		result.AvatarFileID = source.AvatarFileID
+	}
+
+	for key, value := range source.CustomFields {
+		result.CustomFields[key] = value
+	}
+	for key, value := range target.CustomFields {
+		if sourceValue, ok := source.CustomFields[key]; ok && !reflect.DeepEqual(sourceValue, value) {
+			conflicts = append(conflicts, fieldCustomFields+"."+key)
+		}
+		result.CustomFields[key] = value
+	}
+	sort.Strings(conflicts)
+	return &result, conflicts
+}
@@ -0,0 +1,492 @@
+// internal/service/enrollment_service.go
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	kafka "student-portal/internal/kafka"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// EnrollmentService defines the methods for course enrollments and waitlists.
+//
+// Enrollments are made in a course's offering for a term; a termID of zero means the
+// term currently running.
+type EnrollmentService interface {
+	// Enroll checks the course's requirements unless an admin override is given. sectionID is
+	// required when the offering has sections. Students enrolling themselves (selfService) are
+	// held to the term's add deadline and the timetable clash policy; for admins clashes are
+	// only reported on the returned enrollment.
+	Enroll(ctx context.Context, courseID, termID, sectionID, studentID, performedBy int64, override *models.RequirementOverride, selfService bool) (*models.Enrollment, error)
+	// ChangeSection moves the student to another section of the offering, keeping their seat.
+	ChangeSection(ctx context.Context, courseID, termID, sectionID, studentID int64, selfService bool) (*models.Enrollment, error)
+	CheckEligibility(ctx context.Context, courseID, termID, studentID int64) (*models.EnrollmentEligibility, error)
+	// Drop removes the student from the offering or its waitlist. Students dropping themselves
+	// are held to the drop deadline of the offering or term; admins pass enforceDeadline=false.
+	Drop(ctx context.Context, courseID, termID, studentID, droppedBy int64, enforceDeadline bool) (*models.Enrollment, error)
+	// FillSeats promotes waitlisted students into free seats after the capacity of an offering changed.
+	FillSeats(ctx context.Context, courseID, termID int64) error
+	ListStudentEnrollments(ctx context.Context, studentID int64) ([]models.Enrollment, error)
+	ListCourseEnrollments(ctx context.Context, courseID, termID int64, status string) ([]models.Enrollment, error)
+	RecordCompletion(ctx context.Context, studentID int64, req *models.CreateCompletionRequest, recordedBy int64) (*models.CourseCompletion, error)
+	ListCompletions(ctx context.Context, studentID int64) ([]models.CourseCompletion, error)
+	DeleteCompletion(ctx context.Context, studentID, id int64) error
+}
+
+type enrollmentService struct {
+	repo           repository.EnrollmentRepository
+	courseRepo     repository.CourseRepository
+	termRepo       repository.TermRepository
+	offeringRepo   repository.OfferingRepository
+	sectionRepo    repository.SectionRepository
+	completionRepo repository.CompletionRepository
+	userRepo       repository.UserRepository
+	transactor     repository.Transactor
+	historySvc     HistoryService
+	cfg            *config.Config
+	kafka          *kafka.KafkaProducer
+}
+
+// NewEnrollmentService creates a new EnrollmentService instance.
+func NewEnrollmentService(repo repository.EnrollmentRepository, courseRepo repository.CourseRepository, termRepo repository.TermRepository, offeringRepo repository.OfferingRepository, sectionRepo repository.SectionRepository, completionRepo repository.CompletionRepository, userRepo repository.UserRepository, transactor repository.Transactor, historySvc HistoryService, cfg *config.Config, kafka *kafka.KafkaProducer) EnrollmentService {
+	return &enrollmentService{repo: repo, courseRepo: courseRepo, termRepo: termRepo, offeringRepo: offeringRepo, sectionRepo: sectionRepo, completionRepo: completionRepo, userRepo: userRepo, transactor: transactor, historySvc: historySvc, cfg: cfg, kafka: kafka}
+}
+
+// requireStudent loads the user and checks that they are a student.
+func (s *enrollmentService) requireStudent(ctx context.Context, id int64) (*models.User, error) {
+	student, err := s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if student.Role != string(enums.RoleStudent) {
+		return nil, appErrors.New(http.StatusBadRequest, "User %d is not a student", id)
+	}
+	return student, nil
+}
+
+// getOffering resolves the term and loads the course's offering in it.
+func (s *enrollmentService) getOffering(ctx context.Context, courseID, termID int64) (*models.Term, *models.CourseOffering, error) {
+	term, err := resolveTerm(ctx, s.termRepo, termID)
+	if err != nil {
+		return nil, nil, err
+	}
+	offering, err := s.offeringRepo.GetOffering(ctx, courseID, term.ID)
+	if err == appErrors.ErrNotFound {
+		if _, err := s.courseRepo.GetCourseByID(ctx, courseID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, appErrors.ErrOfferingNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return term, offering, nil
+}
+
+// chooseSection checks the requested section against the offering's sections and returns
+// it, or nil when the offering is not split into sections.
+func (s *enrollmentService) chooseSection(ctx context.Context, courseID, termID, sectionID int64) (*models.Section, error) {
+	sections, err := s.sectionRepo.ListForOffering(ctx, courseID, termID)
+	if err != nil {
+		return nil, err
+	}
+	if sectionID == 0 {
+		if len(sections) > 0 {
+			return nil, appErrors.ErrSectionRequired
+		}
+		return nil, nil
+	}
+	for i := range sections {
+		if sections[i].ID == sectionID {
+			return &sections[i], nil
+		}
+	}
+	return nil, appErrors.New(http.StatusBadRequest, "Section %d is not a section of this course in this term", sectionID)
+}
+
+// checkClashes compares the section's meetings with the student's timetable in the term,
+// ignoring the course itself. Clashes are an error when selfService is set and the clash
+// policy rejects them; otherwise they are returned as warnings.
+func (s *enrollmentService) checkClashes(ctx context.Context, section *models.Section, courseTitle string, studentID int64, selfService bool) ([]models.ScheduleClash, error) {
+	if section == nil || len(section.Meetings) == 0 {
+		return nil, nil
+	}
+	timetable, err := s.sectionRepo.StudentTimetable(ctx, studentID, section.TermID)
+	if err != nil {
+		return nil, err
+	}
+	existing := make([]models.TimetableEntry, 0, len(timetable))
+	for _, entry := range timetable {
+		if entry.CourseID != section.CourseID {
+			existing = append(existing, entry)
+		}
+	}
+
+	clashes := findClashes(sectionEntries(section, courseTitle), existing)
+	if len(clashes) == 0 {
+		return nil, nil
+	}
+	if selfService && s.cfg.TimetableClashPolicy != "warn" {
+		return nil, appErrors.ErrTimetableClash.WithDetails(clashes)
+	}
+	return clashes, nil
+}
+
+func (s *enrollmentService) Enroll(ctx context.Context, courseID, termID, sectionID, studentID, performedBy int64, override *models.RequirementOverride, selfService bool) (*models.Enrollment, error) {
+	student, err := s.requireStudent(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if student.Status != string(enums.AccountStatusActive) {
+		return nil, appErrors.New(http.StatusConflict, "Only active students can enroll")
+	}
+
+	term, offering, err := s.getOffering(ctx, courseID, termID)
+	if err != nil {
+		return nil, err
+	}
+	course, err := s.courseRepo.GetCourseByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !course.IsActive || !offering.IsActive {
+		return nil, appErrors.ErrCourseNotOpen
+	}
+	if selfService {
+		// Without an add deadline students can enroll until the term is over.
+		deadline := term.EndDate.AddDate(0, 0, 1)
+		if term.AddDeadline != nil {
+			deadline = *term.AddDeadline
+		}
+		if !time.Now().Before(deadline) {
+			return nil, appErrors.ErrAddDeadlinePassed
+		}
+	}
+
+	if override != nil {
+		override.Justification = strings.TrimSpace(override.Justification)
+		if override.Justification == "" {
+			return nil, appErrors.New(http.StatusBadRequest, "A justification is required to override course requirements")
+		}
+	} else {
+		eligibility, err := s.evaluate(ctx, course, term.ID, studentID)
+		if err != nil {
+			return nil, err
+		}
+		if !eligibility.Eligible {
+			return nil, appErrors.ErrRequirementsNotMet.WithDetails(eligibility)
+		}
+	}
+
+	section, err := s.chooseSection(ctx, courseID, term.ID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	clashes, err := s.checkClashes(ctx, section, course.Title, studentID, selfService)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := s.repo.GetEnrollment(ctx, courseID, term.ID, studentID)
+	if err != nil && err != appErrors.ErrNotFound {
+		return nil, err
+	}
+
+	var chosen *int64
+	if section != nil {
+		chosen = &section.ID
+	}
+	var enrollment *models.Enrollment
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if enrollment, err = s.repo.Enroll(ctx, courseID, term.ID, studentID, chosen, override); err != nil {
+			return err
+		}
+		if before == nil {
+			return s.historySvc.Record(ctx, enums.EntityEnrollment, enrollment.ID, enums.ActionCreate, nil, enrollment)
+		}
+		return s.historySvc.Record(ctx, enums.EntityEnrollment, enrollment.ID, enums.ActionUpdate, before, enrollment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishAsync(
+		func(ctx context.Context) error {
+			return s.kafka.PublishCourseEnrolledEvent(ctx, courseID, enrollment.TermID, studentID, enrollment.Status, performedBy)
+		},
+		"course_enrolled",
+		zap.Int64("course_id", courseID),
+		zap.Int64("term_id", enrollment.TermID),
+		zap.Int64("user_id", studentID),
+	)
+
+	enrollment.Clashes = clashes
+	return enrollment, nil
+}
+
+func (s *enrollmentService) ChangeSection(ctx context.Context, courseID, termID, sectionID, studentID int64, selfService bool) (*models.Enrollment, error) {
+	if sectionID == 0 {
+		return nil, appErrors.ErrSectionRequired
+	}
+	term, _, err := s.getOffering(ctx, courseID, termID)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.repo.GetEnrollment(ctx, courseID, term.ID, studentID)
+	if err == appErrors.ErrNotFound || (err == nil && before.Status == string(enums.EnrollmentStatusDropped)) {
+		return nil, appErrors.ErrNotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	section, err := s.chooseSection(ctx, courseID, term.ID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	clashes, err := s.checkClashes(ctx, section, before.CourseTitle, studentID, selfService)
+	if err != nil {
+		return nil, err
+	}
+
+	var enrollment *models.Enrollment
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if enrollment, err = s.repo.SetSection(ctx, before.ID, section.ID); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityEnrollment, enrollment.ID, enums.ActionUpdate, before, enrollment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment.Clashes = clashes
+	return enrollment, nil
+}
+
+func (s *enrollmentService) Drop(ctx context.Context, courseID, termID, studentID, droppedBy int64, enforceDeadline bool) (*models.Enrollment, error) {
+	term, offering, err := s.getOffering(ctx, courseID, termID)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.repo.GetEnrollment(ctx, courseID, term.ID, studentID)
+	if err == appErrors.ErrNotFound {
+		return nil, appErrors.ErrNotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if enforceDeadline && before.Status == string(enums.EnrollmentStatusEnrolled) {
+		// Leaving the waitlist is always allowed; giving up a seat is not after the deadline.
+		// An offering's own deadline takes precedence over the term's.
+		deadline := term.DropDeadline
+		if offering.DropDeadline != nil {
+			deadline = offering.DropDeadline
+		}
+		if deadline != nil && time.Now().After(*deadline) {
+			return nil, appErrors.ErrDropDeadlinePassed
+		}
+	}
+
+	var dropped *models.Enrollment
+	var promoted []models.Enrollment
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if dropped, promoted, err = s.repo.Drop(ctx, courseID, term.ID, studentID, droppedBy); err != nil {
+			return err
+		}
+		if err := s.historySvc.Record(ctx, enums.EntityEnrollment, dropped.ID, enums.ActionUpdate, before, dropped); err != nil {
+			return err
+		}
+		return s.recordPromoted(ctx, promoted)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishAsync(
+		func(ctx context.Context) error {
+			return s.kafka.PublishCourseDroppedEvent(ctx, courseID, term.ID, studentID, droppedBy)
+		},
+		"course_dropped",
+		zap.Int64("course_id", courseID),
+		zap.Int64("term_id", term.ID),
+		zap.Int64("user_id", studentID),
+	)
+	s.announcePromoted(promoted)
+
+	return dropped, nil
+}
+
+func (s *enrollmentService) FillSeats(ctx context.Context, courseID, termID int64) error {
+	var promoted []models.Enrollment
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if promoted, err = s.repo.FillSeats(ctx, courseID, termID); err != nil {
+			return err
+		}
+		return s.recordPromoted(ctx, promoted)
+	})
+	if err != nil {
+		return err
+	}
+	s.announcePromoted(promoted)
+	return nil
+}
+
+// recordPromoted records the history of students that moved from the waitlist into a seat.
+func (s *enrollmentService) recordPromoted(ctx context.Context, promoted []models.Enrollment) error {
+	for i := range promoted {
+		enrollment := promoted[i]
+		before := enrollment
+		before.Status, before.EnrolledAt = string(enums.EnrollmentStatusWaitlisted), nil
+		if err := s.historySvc.Record(ctx, enums.EntityEnrollment, enrollment.ID, enums.ActionUpdate, &before, &enrollment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// announcePromoted announces students that moved from the waitlist into a seat.
+func (s *enrollmentService) announcePromoted(promoted []models.Enrollment) {
+	for i := range promoted {
+		enrollment := promoted[i]
+		publishAsync(
+			func(ctx context.Context) error {
+				return s.kafka.PublishWaitlistPromotedEvent(ctx, enrollment.CourseID, enrollment.TermID, enrollment.StudentID)
+			},
+			"waitlist_promoted",
+			zap.Int64("course_id", enrollment.CourseID),
+			zap.Int64("term_id", enrollment.TermID),
+			zap.Int64("user_id", enrollment.StudentID),
+		)
+	}
+}
+
+func (s *enrollmentService) ListStudentEnrollments(ctx context.Context, studentID int64) ([]models.Enrollment, error) {
+	return s.repo.ListForStudent(ctx, studentID)
+}
+
+func (s *enrollmentService) ListCourseEnrollments(ctx context.Context, courseID, termID int64, status string) ([]models.Enrollment, error) {
+	switch enums.EnrollmentStatus(status) {
+	case "", enums.EnrollmentStatusEnrolled, enums.EnrollmentStatusWaitlisted, enums.EnrollmentStatusDropped:
+	default:
+		return nil, appErrors.New(http.StatusBadRequest, "Invalid enrollment status '%s'", status)
+	}
+	term, _, err := s.getOffering(ctx, courseID, termID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListForCourse(ctx, courseID, term.ID, status)
+}
+
+func (s *enrollmentService) CheckEligibility(ctx context.Context, courseID, termID, studentID int64) (*models.EnrollmentEligibility, error) {
+	term, err := resolveTerm(ctx, s.termRepo, termID)
+	if err != nil {
+		return nil, err
+	}
+	course, err := s.courseRepo.GetCourseByID(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return s.evaluate(ctx, course, term.ID, studentID)
+}
+
+// evaluate checks the course's requirements against the student's completed courses and
+// their enrollments in the same term.
+func (s *enrollmentService) evaluate(ctx context.Context, course *models.Course, termID, studentID int64) (*models.EnrollmentEligibility, error) {
+	if course.Prerequisites == nil && len(course.CorequisiteIDs) == 0 {
+		return &models.EnrollmentEligibility{CourseID: course.ID, Eligible: true, Unmet: []models.RequirementCheck{}}, nil
+	}
+
+	completions, err := s.completionRepo.ListForStudent(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	enrollments, err := s.repo.ListForStudent(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := s.courseRepo.GetCourseCodes(ctx, requirementCourseIDs(course))
+	if err != nil {
+		return nil, err
+	}
+
+	rc := requirementContext{grades: bestGrades(completions), enrolled: make(map[int64]bool), codes: codes}
+	for _, e := range enrollments {
+		if e.TermID == termID && e.Status != string(enums.EnrollmentStatusDropped) {
+			rc.enrolled[e.CourseID] = true
+		}
+	}
+	return checkRequirements(course, rc), nil
+}
+
+func (s *enrollmentService) RecordCompletion(ctx context.Context, studentID int64, req *models.CreateCompletionRequest, recordedBy int64) (*models.CourseCompletion, error) {
+	if _, err := s.requireStudent(ctx, studentID); err != nil {
+		return nil, err
+	}
+	if _, err := s.courseRepo.GetCourseByID(ctx, req.CourseID); err != nil {
+		return nil, err
+	}
+	if req.TermID != nil {
+		if _, err := s.termRepo.GetTermByID(ctx, *req.TermID); err != nil {
+			return nil, err
+		}
+	}
+	grade, err := normalizeGrade(req.Grade)
+	if err != nil {
+		return nil, err
+	}
+
+	completion := &models.CourseCompletion{
+		StudentID:   studentID,
+		CourseID:    req.CourseID,
+		TermID:      req.TermID,
+		Grade:       grade,
+		CompletedAt: time.Now(),
+		RecordedBy:  &recordedBy,
+	}
+	if req.CompletedAt != nil {
+		completion.CompletedAt = *req.CompletedAt
+	}
+	if completion.CompletedAt.After(time.Now()) {
+		return nil, appErrors.New(http.StatusBadRequest, "Completion date cannot be in the future")
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.completionRepo.CreateCompletion(ctx, completion); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityCourseCompletion, completion.ID, enums.ActionCreate, nil, completion)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return completion, nil
+}
+
+func (s *enrollmentService) ListCompletions(ctx context.Context, studentID int64) ([]models.CourseCompletion, error) {
+	return s.completionRepo.ListForStudent(ctx, studentID)
+}
+
+func (s *enrollmentService) DeleteCompletion(ctx context.Context, studentID, id int64) error {
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		completion, err := s.completionRepo.DeleteCompletion(ctx, studentID, id)
+		if err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityCourseCompletion, completion.ID, enums.ActionDelete, completion, nil)
+	})
+}
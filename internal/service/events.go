@@ -0,0 +1,25 @@
+// internal/service/events.go
+package service
+
+import (
+	"context"
+
+	"student-portal/internal/commons/logger"
+
+	"go.uber.org/zap"
+)
+
+// publishAsync handles the non-blocking publication and logs any failure.
+// The fields identify the affected entity in the log entries (e.g. zap.Int64("user_id", id)).
+func publishAsync(eventFunc func(context.Context) error, eventName string, fields ...zap.Field) {
+	go func() {
+		logFields := append([]zap.Field{zap.String("event", eventName)}, fields...)
+
+		// Use a background context as the original request context may expire.
+		if err := eventFunc(context.Background()); err != nil {
+			logger.Logger.Error("Failed to publish Kafka event", append(logFields, zap.Error(err))...)
+		} else {
+			logger.Logger.Info("Successfully published Kafka event", logFields...)
+		}
+	}()
+}
@@ -0,0 +1,287 @@
+// internal/service/file_service.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/commons/logger"
+	"student-portal/internal/config"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+	"student-portal/internal/storage"
+	"student-portal/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// FileService defines the methods for uploading and serving files.
+// It is shared by every feature that stores user-provided content.
+type FileService interface {
+	Upload(ctx context.Context, ownerID int64, purpose enums.FilePurpose, filename string, r io.Reader) (*models.File, error)
+	GetFile(ctx context.Context, id int64) (*models.File, error)
+	SignedURL(ctx context.Context, file *models.File) (*models.FileURLResponse, error)
+	OpenSigned(ctx context.Context, key, expires, signature string) (*models.File, io.ReadCloser, error)
+	// Open returns the content of a stored file, for features that serve it through the API.
+	Open(ctx context.Context, file *models.File) (io.ReadCloser, error)
+	DeleteFile(ctx context.Context, id int64) error
+	StoreGenerated(ctx context.Context, ownerID int64, purpose enums.FilePurpose, filename, contentType string, content []byte) (*models.File, error)
+	DeleteBlobs(ctx context.Context, files []models.File)
+}
+
+type fileService struct {
+	repo     repository.FileRepository
+	blobs    storage.Storage
+	scanners []storage.Scanner
+	cfg      *config.Config
+}
+
+// NewFileService creates a new FileService instance.
+// Scanners are invoked in order on every upload before anything is stored.
+func NewFileService(repo repository.FileRepository, store storage.Storage, cfg *config.Config, scanners ...storage.Scanner) FileService {
+	return &fileService{repo: repo, blobs: store, scanners: scanners, cfg: cfg}
+}
+
+// allowedContentTypes lists the sniffed content types accepted for each purpose.
+var allowedContentTypes = map[enums.FilePurpose]map[string]bool{
+	enums.FilePurposeAvatar: {
+		"image/jpeg": true, "image/png": true, "image/gif": true,
+	},
+	enums.FilePurposeDocument: {
+		"application/pdf": true, "text/plain; charset=utf-8": true, "application/zip": true,
+		"image/jpeg": true, "image/png": true,
+	},
+	// Office documents are zip containers and are sniffed as such
+	enums.FilePurposeSubmission: {
+		"application/pdf": true, "text/plain; charset=utf-8": true, "application/zip": true,
+		"image/jpeg": true, "image/png": true,
+	},
+}
+
+// UploadedFile is a file received from a client that has not been stored yet.
+type UploadedFile struct {
+	Filename string
+	Content  io.Reader
+}
+
+func (s *fileService) maxSize(purpose enums.FilePurpose) int64 {
+	if purpose == enums.FilePurposeAvatar {
+		return s.cfg.AvatarMaxSize
+	}
+	return s.cfg.MaxUploadSize
+}
+
+func (s *fileService) Upload(ctx context.Context, ownerID int64, purpose enums.FilePurpose, filename string, r io.Reader) (*models.File, error) {
+	// 1. Read the content, enforcing the size limit for this purpose
+	limit := s.maxSize(purpose)
+	content, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, appErrors.ErrBadRequest
+	}
+	if int64(len(content)) > limit {
+		return nil, appErrors.ErrFileTooLarge
+	}
+	if len(content) == 0 {
+		return nil, appErrors.New(http.StatusBadRequest, "File is empty")
+	}
+
+	// 2. Sniff the content type instead of trusting the client-supplied header
+	contentType := http.DetectContentType(content)
+	allowed, ok := allowedContentTypes[purpose]
+	if !ok || !allowed[contentType] {
+		return nil, appErrors.ErrUnsupportedMedia
+	}
+
+	// 3. Run virus-scan hooks
+	for _, scanner := range s.scanners {
+		if err := scanner.Scan(ctx, filename, content); err != nil {
+			if errors.Is(err, storage.ErrInfected) {
+				logger.Logger.Warn("Upload rejected by virus scan", zap.Int64("owner_id", ownerID), zap.String("filename", filename))
+				return nil, appErrors.ErrFileRejected
+			}
+			logger.Logger.Error("Virus scan failed", zap.Error(err))
+			return nil, appErrors.ErrInternalServerError
+		}
+	}
+
+	// 4. Post-process avatars (resize and strip metadata)
+	if purpose == enums.FilePurposeAvatar {
+		content, contentType, err = utils.ResizeImage(content, s.cfg.AvatarMaxDimension, s.cfg.AvatarMaxPixels)
+		if errors.Is(err, utils.ErrImageTooLarge) {
+			return nil, appErrors.New(http.StatusRequestEntityTooLarge, "Image must not exceed %d pixels", s.cfg.AvatarMaxPixels)
+		}
+		if err != nil {
+			return nil, appErrors.ErrUnsupportedMedia
+		}
+	}
+
+	// 5. Store the blob, then its metadata
+	return s.store(ctx, ownerID, purpose, filename, contentType, content)
+}
+
+// StoreGenerated stores content produced by the server itself (e.g. data exports).
+// It skips the size limit, sniffing and scanning applied to user uploads.
+func (s *fileService) StoreGenerated(ctx context.Context, ownerID int64, purpose enums.FilePurpose, filename, contentType string, content []byte) (*models.File, error) {
+	return s.store(ctx, ownerID, purpose, filename, contentType, content)
+}
+
+// store puts the blob into the storage backend and records its metadata.
+func (s *fileService) store(ctx context.Context, ownerID int64, purpose enums.FilePurpose, filename, contentType string, content []byte) (*models.File, error) {
+	key, err := newStorageKey(purpose, ownerID, contentType, filename)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	if err := s.blobs.Put(ctx, key, content, contentType); err != nil {
+		logger.Logger.Error("Failed to store file", zap.Error(err), zap.String("key", key))
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	checksum := sha256.Sum256(content)
+	file := &models.File{
+		OwnerID:     &ownerID,
+		StorageKey:  key,
+		Filename:    sanitizeFilename(filename),
+		ContentType: contentType,
+		SizeBytes:   int64(len(content)),
+		Checksum:    hex.EncodeToString(checksum[:]),
+		Purpose:     string(purpose),
+	}
+	if err := s.repo.CreateFile(ctx, file); err != nil {
+		// Do not leave orphaned blobs behind.
+		if delErr := s.blobs.Delete(context.Background(), key); delErr != nil {
+			logger.Logger.Error("Failed to clean up stored file", zap.Error(delErr), zap.String("key", key))
+		}
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (s *fileService) GetFile(ctx context.Context, id int64) (*models.File, error) {
+	return s.repo.GetFileByID(ctx, id)
+}
+
+func (s *fileService) SignedURL(ctx context.Context, file *models.File) (*models.FileURLResponse, error) {
+	url, err := s.blobs.SignedURL(ctx, file.StorageKey, s.cfg.SignedURLTTL)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return &models.FileURLResponse{
+		File:      *file,
+		URL:       url,
+		ExpiresAt: time.Now().Add(s.cfg.SignedURLTTL),
+	}, nil
+}
+
+// OpenSigned verifies a signed download link (for backends whose links are served
+// by this API) and opens the referenced file.
+func (s *fileService) OpenSigned(ctx context.Context, key, expires, signature string) (*models.File, io.ReadCloser, error) {
+	verifier, ok := s.blobs.(storage.URLVerifier)
+	if !ok {
+		return nil, nil, appErrors.ErrNotFound
+	}
+	if err := verifier.VerifySignedURL(key, expires, signature); err != nil {
+		return nil, nil, appErrors.ErrInvalidSignature
+	}
+
+	file, err := s.repo.GetFileByKey(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := s.blobs.Get(ctx, key)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, appErrors.ErrInternalServerError
+	}
+	return file, body, nil
+}
+
+func (s *fileService) Open(ctx context.Context, file *models.File) (io.ReadCloser, error) {
+	body, err := s.blobs.Get(ctx, file.StorageKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, appErrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	return body, nil
+}
+
+func (s *fileService) DeleteFile(ctx context.Context, id int64) error {
+	file, err := s.repo.GetFileByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteFile(ctx, id); err != nil {
+		return err
+	}
+	s.DeleteBlobs(ctx, []models.File{*file})
+	return nil
+}
+
+// DeleteBlobs removes blobs whose metadata has already been deleted.
+// Failures are logged only: without metadata the blobs are unreachable anyway.
+func (s *fileService) DeleteBlobs(ctx context.Context, files []models.File) {
+	for _, file := range files {
+		if err := s.blobs.Delete(ctx, file.StorageKey); err != nil {
+			logger.Logger.Error("Failed to delete stored file", zap.Error(err), zap.String("key", file.StorageKey))
+		}
+	}
+}
+
+// extensionsByContentType maps sniffed content types to the extension used in storage keys.
+var extensionsByContentType = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"application/pdf": ".pdf",
+}
+
+// newStorageKey builds a unique, non-guessable object key such as "avatar/42/3f9a...e1.png".
+func newStorageKey(purpose enums.FilePurpose, ownerID int64, contentType, filename string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	ext, ok := extensionsByContentType[contentType]
+	if !ok {
+		ext = strings.ToLower(filepath.Ext(sanitizeFilename(filename)))
+		if len(ext) > 10 {
+			ext = ""
+		}
+	}
+	return fmt.Sprintf("%s/%d/%s%s", purpose, ownerID, hex.EncodeToString(random), ext), nil
+}
+
+// sanitizeFilename strips any directory components and control characters from a client filename.
+func sanitizeFilename(filename string) string {
+	name := filepath.Base(strings.ReplaceAll(filename, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
@@ -0,0 +1,277 @@
+// internal/service/gradebook.go
+package service
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+)
+
+// defaultGradeScale is used by courses without a grade scale of their own.
+var defaultGradeScale = []models.GradeScaleEntry{
+	{Letter: "A+", MinPercent: 97}, {Letter: "A", MinPercent: 93}, {Letter: "A-", MinPercent: 90},
+	{Letter: "B+", MinPercent: 87}, {Letter: "B", MinPercent: 83}, {Letter: "B-", MinPercent: 80},
+	{Letter: "C+", MinPercent: 77}, {Letter: "C", MinPercent: 73}, {Letter: "C-", MinPercent: 70},
+	{Letter: "D+", MinPercent: 67}, {Letter: "D", MinPercent: 63}, {Letter: "D-", MinPercent: 60},
+	{Letter: "F", MinPercent: 0},
+}
+
+// Names of the implicit categories of a breakdown.
+const (
+	allWorkCategory       = "All work"
+	uncategorizedCategory = "Uncategorized"
+)
+
+// roundPoints rounds to the two decimals points and percentages are stored with.
+func roundPoints(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// validateGradeScale normalizes the letters of a grade scale, sorts it best letter first and
+// checks that better letters need higher percentages and that every percentage earns a letter.
+func validateGradeScale(entries []models.GradeScaleEntry) error {
+	if len(entries) == 0 {
+		return appErrors.New(http.StatusBadRequest, "Grade scale needs at least one grade")
+	}
+	seen := make(map[string]bool, len(entries))
+	for i := range entries {
+		letter, err := normalizeGrade(entries[i].Letter)
+		if err != nil {
+			return err
+		}
+		if seen[letter] {
+			return appErrors.New(http.StatusBadRequest, "Grade '%s' appears more than once", letter)
+		}
+		seen[letter] = true
+		entries[i].Letter = letter
+		if entries[i].MinPercent < 0 || entries[i].MinPercent > 100 {
+			return appErrors.New(http.StatusBadRequest, "Minimum percentage of '%s' must be between 0 and 100", letter)
+		}
+		entries[i].MinPercent = roundPoints(entries[i].MinPercent)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		rankI, _ := gradeRank(entries[i].Letter)
+		rankJ, _ := gradeRank(entries[j].Letter)
+		return rankI < rankJ
+	})
+	for i := 1; i < len(entries); i++ {
+		if entries[i].MinPercent >= entries[i-1].MinPercent {
+			return appErrors.New(http.StatusBadRequest, "Grade '%s' must need a higher percentage than '%s'", entries[i-1].Letter, entries[i].Letter)
+		}
+	}
+	if entries[len(entries)-1].MinPercent != 0 {
+		return appErrors.New(http.StatusBadRequest, "The lowest grade must start at 0 percent")
+	}
+	return nil
+}
+
+// letterFor returns the best letter of the scale the percentage reaches.
+func letterFor(scale []models.GradeScaleEntry, percent float64) string {
+	for _, entry := range scale {
+		if percent >= entry.MinPercent {
+			return entry.Letter
+		}
+	}
+	return scale[len(scale)-1].Letter
+}
+
+// latePenalty returns the points deducted from a late submission under the assignment's
+// penalty policy: the penalty percentage of the max points for every started day late, never
+// more than the points awarded.
+func latePenalty(assignment *models.Assignment, submission *models.Submission, points float64) float64 {
+	if assignment.LatePolicy != string(enums.LatePolicyPenalty) || submission == nil || !submission.Late {
+		return 0
+	}
+	days := math.Ceil(submission.SubmittedAt.Sub(assignment.DueAt).Hours() / 24)
+	if days < 1 {
+		days = 1
+	}
+	penalty := roundPoints(assignment.MaxPoints * assignment.LatePenaltyPercent / 100 * days)
+	return math.Min(penalty, points)
+}
+
+// gradeInput is everything a student's grade in a section is computed from.
+type gradeInput struct {
+	scale       []models.GradeScaleEntry
+	categories  []models.GradeCategory
+	assignments []models.Assignment
+	grades      map[int64]*models.AssignmentGrade // By assignment ID
+}
+
+// gradeItem builds the breakdown entry of an assignment from the student's grade, if any.
+func gradeItem(assignment *models.Assignment, grade *models.AssignmentGrade) models.GradeItem {
+	item := models.GradeItem{
+		AssignmentID: assignment.ID,
+		Title:        assignment.Title,
+		DueAt:        assignment.DueAt,
+		MaxPoints:    assignment.MaxPoints,
+		ExtraCredit:  assignment.ExtraCredit,
+	}
+	if grade == nil {
+		return item
+	}
+	item.Points = grade.Points
+	item.LatePenalty = grade.LatePenalty
+	item.Excused = grade.Excused
+	item.Feedback = grade.Feedback
+	item.ReleasedAt = grade.ReleasedAt
+	if grade.Points != nil {
+		score := math.Max(*grade.Points-grade.LatePenalty, 0)
+		item.Score = &score
+	}
+	return item
+}
+
+// scoreCategory totals the items of a category. Items without a score count as zero when
+// countMissing is set and are skipped otherwise; excused items never count. The lowest
+// DropLowest counted items, by percentage, are dropped, but at least one is always kept.
+func scoreCategory(category *models.CategoryGrade, dueAt map[int64]time.Time, countMissing bool) {
+	counted := make([]int, 0, len(category.Items))
+	for i, item := range category.Items {
+		if item.ExtraCredit || item.Excused || (item.Score == nil && !countMissing) {
+			continue
+		}
+		counted = append(counted, i)
+	}
+
+	ratio := func(item models.GradeItem) float64 {
+		if item.Score == nil {
+			return 0
+		}
+		return *item.Score / item.MaxPoints
+	}
+	sort.SliceStable(counted, func(a, b int) bool {
+		itemA, itemB := category.Items[counted[a]], category.Items[counted[b]]
+		if ratio(itemA) != ratio(itemB) {
+			return ratio(itemA) < ratio(itemB)
+		}
+		if !dueAt[itemA.AssignmentID].Equal(dueAt[itemB.AssignmentID]) {
+			return dueAt[itemA.AssignmentID].Before(dueAt[itemB.AssignmentID])
+		}
+		return itemA.AssignmentID < itemB.AssignmentID
+	})
+	drop := category.DropLowest
+	if drop > len(counted)-1 {
+		drop = len(counted) - 1
+	}
+	for k, i := range counted {
+		if k < drop {
+			category.Items[i].Dropped = true
+			continue
+		}
+		category.Possible += category.Items[i].MaxPoints
+		if category.Items[i].Score != nil {
+			category.Earned += *category.Items[i].Score
+		}
+	}
+
+	// Extra credit adds to what was earned without adding to what could be
+	for _, item := range category.Items {
+		if item.ExtraCredit && !item.Excused && item.Score != nil {
+			category.Earned += *item.Score
+		}
+	}
+
+	category.Earned = roundPoints(category.Earned)
+	category.Possible = roundPoints(category.Possible)
+	if category.Possible > 0 {
+		percent := roundPoints(category.Earned / category.Possible * 100)
+		category.Percent = &percent
+	}
+}
+
+// weightedPercent averages the percentages of the counted categories by weight. Categories
+// without a percentage are left out and the remaining weights scaled up.
+func weightedPercent(categories []models.CategoryGrade) *float64 {
+	var total, weights float64
+	for _, category := range categories {
+		if !category.Counted || category.Percent == nil {
+			continue
+		}
+		total += *category.Percent * category.Weight
+		weights += category.Weight
+	}
+	if weights == 0 {
+		return nil
+	}
+	percent := roundPoints(total / weights)
+	return &percent
+}
+
+// groupCategories sorts the assignments into the breakdown's categories. Sections without
+// categories count all work together; once a section has categories, uncategorized work is
+// shown but does not count.
+func groupCategories(input *gradeInput) []models.CategoryGrade {
+	if len(input.categories) == 0 {
+		all := models.CategoryGrade{Name: allWorkCategory, Weight: 100, Counted: true, Items: make([]models.GradeItem, 0)}
+		for i := range input.assignments {
+			all.Items = append(all.Items, gradeItem(&input.assignments[i], input.grades[input.assignments[i].ID]))
+		}
+		return []models.CategoryGrade{all}
+	}
+
+	categories := make([]models.CategoryGrade, 0, len(input.categories)+1)
+	index := make(map[int64]int, len(input.categories))
+	for _, c := range input.categories {
+		id := c.ID
+		index[id] = len(categories)
+		categories = append(categories, models.CategoryGrade{
+			CategoryID: &id, Name: c.Name, Weight: c.Weight, DropLowest: c.DropLowest, Counted: true, Items: make([]models.GradeItem, 0),
+		})
+	}
+	uncategorized := models.CategoryGrade{Name: uncategorizedCategory, Items: make([]models.GradeItem, 0)}
+	for i := range input.assignments {
+		assignment := &input.assignments[i]
+		item := gradeItem(assignment, input.grades[assignment.ID])
+		if assignment.CategoryID != nil {
+			if k, ok := index[*assignment.CategoryID]; ok {
+				categories[k].Items = append(categories[k].Items, item)
+				continue
+			}
+		}
+		uncategorized.Items = append(uncategorized.Items, item)
+	}
+	if len(uncategorized.Items) > 0 {
+		categories = append(categories, uncategorized)
+	}
+	return categories
+}
+
+// computeGrade computes a student's running and final grade in a section. The returned
+// categories show the running grade.
+func computeGrade(input *gradeInput) *models.GradeBreakdown {
+	dueAt := make(map[int64]time.Time, len(input.assignments))
+	for _, assignment := range input.assignments {
+		dueAt[assignment.ID] = assignment.DueAt
+	}
+
+	breakdown := &models.GradeBreakdown{}
+	running := groupCategories(input)
+	for i := range running {
+		scoreCategory(&running[i], dueAt, false)
+	}
+	breakdown.Categories = running
+	breakdown.RunningPercent = weightedPercent(running)
+
+	final := groupCategories(input)
+	for i := range final {
+		scoreCategory(&final[i], dueAt, true)
+	}
+	breakdown.FinalPercent = weightedPercent(final)
+
+	if breakdown.RunningPercent != nil {
+		letter := letterFor(input.scale, *breakdown.RunningPercent)
+		breakdown.RunningLetter = &letter
+	}
+	if breakdown.FinalPercent != nil {
+		letter := letterFor(input.scale, *breakdown.FinalPercent)
+		breakdown.FinalLetter = &letter
+	}
+	return breakdown
+}
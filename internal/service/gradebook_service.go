@@ -0,0 +1,580 @@
+// internal/service/gradebook_service.go
+package service
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/commons/logger"
+	kafka "student-portal/internal/kafka"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// GradebookService defines the methods for grading. Methods taking a userID and isAdmin act
+// on behalf of that user: only the section's instructor and admins grade its work.
+type GradebookService interface {
+	// GetGradeScale returns the course's grade scale, or the default scale if it has none.
+	GetGradeScale(ctx context.Context, courseID int64) (*models.GradeScale, error)
+	// SetGradeScale replaces the course's grade scale and recomputes the grades of its
+	// sections in terms that have not ended.
+	SetGradeScale(ctx context.Context, courseID int64, req *models.SetGradeScaleRequest) (*models.GradeScale, error)
+
+	ListCategories(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.GradeCategory, error)
+	SetCategories(ctx context.Context, sectionID int64, req *models.SetGradeCategoriesRequest, userID int64, isAdmin bool) ([]models.GradeCategory, error)
+
+	ListGrades(ctx context.Context, assignmentID, userID int64, isAdmin bool) ([]models.AssignmentGrade, error)
+	// SetGrades grades students of the assignment's section. The late penalty is worked out
+	// from the student's latest submission.
+	SetGrades(ctx context.Context, assignmentID int64, req *models.SetGradesRequest, userID int64, isAdmin bool) ([]models.AssignmentGrade, error)
+	// ReleaseGrades makes the assignment's grades visible to the students.
+	ReleaseGrades(ctx context.Context, assignmentID, userID int64, isAdmin bool) ([]models.AssignmentGrade, error)
+
+	GetGradebook(ctx context.Context, sectionID, userID int64, isAdmin bool) (*models.Gradebook, error)
+	// GetStudentBreakdown explains a student's grade in the section to its teacher, counting
+	// grades that have not been released yet.
+	GetStudentBreakdown(ctx context.Context, sectionID, studentID, userID int64, isAdmin bool) (*models.GradeBreakdown, error)
+	// PostFinalGrades records the students' final grades as course completions. It can be run
+	// again after regrading; only changed grades are posted again.
+	PostFinalGrades(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.SectionGrade, error)
+	// GetStudentGrades returns a student's grades in the term (zero means the current term),
+	// from released grades only.
+	GetStudentGrades(ctx context.Context, studentID, termID int64) (*models.StudentGrades, error)
+
+	// RecomputeSection refreshes the stored course grades of the section's students.
+	RecomputeSection(ctx context.Context, sectionID int64) error
+}
+
+type gradebookService struct {
+	repo           repository.GradebookRepository
+	assignmentRepo repository.AssignmentRepository
+	sectionRepo    repository.SectionRepository
+	courseRepo     repository.CourseRepository
+	termRepo       repository.TermRepository
+	userRepo       repository.UserRepository
+	transactor     repository.Transactor
+	historySvc     HistoryService
+	kafka          *kafka.KafkaProducer
+}
+
+// NewGradebookService creates a new GradebookService instance.
+func NewGradebookService(repo repository.GradebookRepository, assignmentRepo repository.AssignmentRepository, sectionRepo repository.SectionRepository, courseRepo repository.CourseRepository, termRepo repository.TermRepository, userRepo repository.UserRepository, transactor repository.Transactor, historySvc HistoryService, kafka *kafka.KafkaProducer) GradebookService {
+	return &gradebookService{repo: repo, assignmentRepo: assignmentRepo, sectionRepo: sectionRepo, courseRepo: courseRepo, termRepo: termRepo, userRepo: userRepo, transactor: transactor, historySvc: historySvc, kafka: kafka}
+}
+
+// gradesSnapshot is the change history representation of an assignment's grades, keyed by
+// student ID.
+func gradesSnapshot(grades []models.AssignmentGrade) map[string]interface{} {
+	snapshot := make(map[string]interface{}, len(grades))
+	for _, grade := range grades {
+		snapshot[strconv.FormatInt(grade.StudentID, 10)] = map[string]interface{}{
+			"points":       grade.Points,
+			"late_penalty": grade.LatePenalty,
+			"excused":      grade.Excused,
+			"feedback":     grade.Feedback,
+			"released":     grade.ReleasedAt != nil,
+		}
+	}
+	return snapshot
+}
+
+// finalGradesSnapshot is the change history representation of a section's posted final
+// grades: the posted letter of every student, keyed by student ID.
+func finalGradesSnapshot(students []models.SectionGrade) map[string]interface{} {
+	snapshot := make(map[string]interface{}, len(students))
+	for _, student := range students {
+		if student.PostedLetter != nil {
+			snapshot[strconv.FormatInt(student.StudentID, 10)] = *student.PostedLetter
+		}
+	}
+	return snapshot
+}
+
+// scaleFor returns the grade scale of the course, best letter first.
+func (s *gradebookService) scaleFor(ctx context.Context, courseID int64) (*models.GradeScale, error) {
+	entries, err := s.repo.GetGradeScale(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return &models.GradeScale{CourseID: courseID, Default: true, Entries: defaultGradeScale}, nil
+	}
+	return &models.GradeScale{CourseID: courseID, Entries: entries}, nil
+}
+
+func (s *gradebookService) GetGradeScale(ctx context.Context, courseID int64) (*models.GradeScale, error) {
+	if _, err := s.courseRepo.GetCourseByID(ctx, courseID); err != nil {
+		return nil, err
+	}
+	return s.scaleFor(ctx, courseID)
+}
+
+func (s *gradebookService) SetGradeScale(ctx context.Context, courseID int64, req *models.SetGradeScaleRequest) (*models.GradeScale, error) {
+	if _, err := s.courseRepo.GetCourseByID(ctx, courseID); err != nil {
+		return nil, err
+	}
+	if len(req.Entries) > 0 {
+		if err := validateGradeScale(req.Entries); err != nil {
+			return nil, err
+		}
+	}
+	var scale *models.GradeScale
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.scaleFor(ctx, courseID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.SetGradeScale(ctx, courseID, req.Entries); err != nil {
+			return err
+		}
+
+		// Grades already posted keep their letter; open sections pick up the new scale
+		sectionIDs, err := s.repo.ListOpenSectionIDs(ctx, courseID)
+		if err != nil {
+			return err
+		}
+		for _, sectionID := range sectionIDs {
+			if err := s.RecomputeSection(ctx, sectionID); err != nil {
+				return err
+			}
+		}
+		if scale, err = s.scaleFor(ctx, courseID); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityGradeScale, courseID, enums.ActionUpdate, before, scale)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+func (s *gradebookService) ListCategories(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.GradeCategory, error) {
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, sectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return s.repo.ListCategories(ctx, sectionID)
+}
+
+func (s *gradebookService) SetCategories(ctx context.Context, sectionID int64, req *models.SetGradeCategoriesRequest, userID int64, isAdmin bool) ([]models.GradeCategory, error) {
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, sectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	categories := make([]models.GradeCategory, 0, len(req.Categories))
+	names := make(map[string]bool, len(req.Categories))
+	var total float64
+	for i, c := range req.Categories {
+		name := strings.TrimSpace(c.Name)
+		if name == "" || len(name) > 100 {
+			return nil, appErrors.New(http.StatusBadRequest, "Category names must be 1-100 characters")
+		}
+		if names[strings.ToLower(name)] {
+			return nil, appErrors.New(http.StatusBadRequest, "Category '%s' appears more than once", name)
+		}
+		names[strings.ToLower(name)] = true
+		if c.Weight <= 0 || c.Weight > 100 {
+			return nil, appErrors.New(http.StatusBadRequest, "Weight of '%s' must be greater than 0 and at most 100", name)
+		}
+		if c.DropLowest < 0 || c.DropLowest > 100 {
+			return nil, appErrors.New(http.StatusBadRequest, "Drop lowest of '%s' must be between 0 and 100", name)
+		}
+
+		category := models.GradeCategory{Name: name, Weight: roundPoints(c.Weight), DropLowest: c.DropLowest, Position: i}
+		if c.ID != nil {
+			category.ID = *c.ID
+		}
+		total += category.Weight
+		categories = append(categories, category)
+	}
+	if len(categories) > 0 && math.Abs(total-100) > 0.005 {
+		return nil, appErrors.New(http.StatusBadRequest, "Category weights must add up to 100, not %s", strconv.FormatFloat(roundPoints(total), 'f', -1, 64))
+	}
+
+	var updated []models.GradeCategory
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.ListCategories(ctx, sectionID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.ReplaceCategories(ctx, sectionID, categories); err != nil {
+			return err
+		}
+		if err := s.RecomputeSection(ctx, sectionID); err != nil {
+			return err
+		}
+		if updated, err = s.repo.ListCategories(ctx, sectionID); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityGradeCategories, sectionID, enums.ActionUpdate,
+			map[string]interface{}{"categories": before}, map[string]interface{}{"categories": updated})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// requireGradingAssignment loads the assignment and checks that the user teaches its section.
+func (s *gradebookService) requireGradingAssignment(ctx context.Context, assignmentID, userID int64, isAdmin bool) (*models.Assignment, error) {
+	assignment, err := s.assignmentRepo.GetAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, assignment.SectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+func (s *gradebookService) ListGrades(ctx context.Context, assignmentID, userID int64, isAdmin bool) ([]models.AssignmentGrade, error) {
+	if _, err := s.requireGradingAssignment(ctx, assignmentID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAssignmentGrades(ctx, assignmentID)
+}
+
+func (s *gradebookService) SetGrades(ctx context.Context, assignmentID int64, req *models.SetGradesRequest, userID int64, isAdmin bool) ([]models.AssignmentGrade, error) {
+	assignment, err := s.requireGradingAssignment(ctx, assignmentID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Grades) == 0 {
+		return nil, appErrors.New(http.StatusBadRequest, "At least one grade is required")
+	}
+
+	// Students of the section and anyone who handed in work can be graded
+	statuses, err := s.assignmentRepo.SubmissionStatuses(ctx, assignment)
+	if err != nil {
+		return nil, err
+	}
+	roster := make(map[int64]*models.SubmissionStatus, len(statuses))
+	for i := range statuses {
+		roster[statuses[i].StudentID] = &statuses[i]
+	}
+
+	grades := make([]models.AssignmentGrade, 0, len(req.Grades))
+	seen := make(map[int64]bool, len(req.Grades))
+	for _, entry := range req.Grades {
+		status, ok := roster[entry.StudentID]
+		if !ok {
+			return nil, appErrors.New(http.StatusBadRequest, "Student %d is not in this section", entry.StudentID)
+		}
+		if seen[entry.StudentID] {
+			return nil, appErrors.New(http.StatusBadRequest, "Student %d appears more than once", entry.StudentID)
+		}
+		seen[entry.StudentID] = true
+
+		grade := models.AssignmentGrade{
+			AssignmentID: assignment.ID,
+			StudentID:    entry.StudentID,
+			StudentName:  status.StudentName,
+			Excused:      entry.Excused,
+			Feedback:     optionalString(entry.Feedback),
+			GradedBy:     &userID,
+		}
+		if status.Latest != nil {
+			grade.SubmissionID = &status.Latest.ID
+		}
+		if !entry.Excused {
+			if entry.Points == nil {
+				return nil, appErrors.New(http.StatusBadRequest, "Points are required for student %d unless excused", entry.StudentID)
+			}
+			points := roundPoints(*entry.Points)
+			if points < 0 || points > assignment.MaxPoints {
+				return nil, appErrors.New(http.StatusBadRequest, "Points for student %d must be between 0 and %s", entry.StudentID, strconv.FormatFloat(assignment.MaxPoints, 'f', -1, 64))
+			}
+			grade.Points = &points
+			grade.LatePenalty = latePenalty(assignment, status.Latest, points)
+		}
+		grades = append(grades, grade)
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.ListAssignmentGrades(ctx, assignment.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.SaveGrades(ctx, grades, req.Release); err != nil {
+			return err
+		}
+		if err := s.RecomputeSection(ctx, assignment.SectionID); err != nil {
+			return err
+		}
+		after, err := s.repo.ListAssignmentGrades(ctx, assignment.ID)
+		if err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityAssignmentGrades, assignment.ID, enums.ActionUpdate, gradesSnapshot(before), gradesSnapshot(after))
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Students hear about released grades, including changes to grades released earlier
+	for i := range grades {
+		if grades[i].ReleasedAt != nil {
+			s.publishGradeReleased(assignment, &grades[i])
+		}
+	}
+	return grades, nil
+}
+
+func (s *gradebookService) ReleaseGrades(ctx context.Context, assignmentID, userID int64, isAdmin bool) ([]models.AssignmentGrade, error) {
+	assignment, err := s.requireGradingAssignment(ctx, assignmentID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	var released []models.AssignmentGrade
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.ListAssignmentGrades(ctx, assignment.ID)
+		if err != nil {
+			return err
+		}
+		if released, err = s.repo.ReleaseGrades(ctx, assignment.ID); err != nil {
+			return err
+		}
+		after, err := s.repo.ListAssignmentGrades(ctx, assignment.ID)
+		if err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityAssignmentGrades, assignment.ID, enums.ActionUpdate, gradesSnapshot(before), gradesSnapshot(after))
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range released {
+		s.publishGradeReleased(assignment, &released[i])
+	}
+	return released, nil
+}
+
+// publishGradeReleased tells the student about a released assignment grade.
+func (s *gradebookService) publishGradeReleased(assignment *models.Assignment, grade *models.AssignmentGrade) {
+	value := "excused"
+	if grade.Points != nil {
+		score := math.Max(*grade.Points-grade.LatePenalty, 0)
+		value = strconv.FormatFloat(score, 'f', -1, 64) + "/" + strconv.FormatFloat(assignment.MaxPoints, 'f', -1, 64)
+	}
+	assignmentID := assignment.ID
+	publishAsync(func(ctx context.Context) error {
+		return s.kafka.PublishGradePostedEvent(ctx, grade.StudentID, assignment.CourseID, assignment.TermID, assignment.SectionID, &assignmentID, value)
+	}, "grade_posted", zap.Int64("student_id", grade.StudentID), zap.Int64("assignment_id", assignmentID))
+}
+
+// loadGradeInput loads everything the grades of the section's students are computed from,
+// except the grades themselves.
+func (s *gradebookService) loadGradeInput(ctx context.Context, section *models.Section) (*gradeInput, error) {
+	scale, err := s.scaleFor(ctx, section.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.repo.ListCategories(ctx, section.ID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.assignmentRepo.ListForSection(ctx, section.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &gradeInput{scale: scale.Entries, categories: categories, assignments: assignments}, nil
+}
+
+// gradesByAssignment indexes a student's grades by assignment, keeping only released grades
+// when releasedOnly is set.
+func gradesByAssignment(grades []models.AssignmentGrade, releasedOnly bool) map[int64]*models.AssignmentGrade {
+	result := make(map[int64]*models.AssignmentGrade, len(grades))
+	for i := range grades {
+		if releasedOnly && grades[i].ReleasedAt == nil {
+			continue
+		}
+		result[grades[i].AssignmentID] = &grades[i]
+	}
+	return result
+}
+
+// breakdownFor computes a student's grade in the section from the given grades.
+func breakdownFor(section *models.Section, input *gradeInput, studentID int64, grades map[int64]*models.AssignmentGrade) *models.GradeBreakdown {
+	input.grades = grades
+	breakdown := computeGrade(input)
+	breakdown.StudentID = studentID
+	breakdown.SectionID = section.ID
+	breakdown.SectionCode = section.Code
+	breakdown.CourseID = section.CourseID
+	breakdown.CourseCode = section.CourseCode
+	breakdown.TermID = section.TermID
+	return breakdown
+}
+
+// recompute computes and stores the course grades of the section's students.
+func (s *gradebookService) recompute(ctx context.Context, section *models.Section) ([]models.SectionGrade, error) {
+	input, err := s.loadGradeInput(ctx, section)
+	if err != nil {
+		return nil, err
+	}
+	students, err := s.repo.ListCourseGrades(ctx, section.ID)
+	if err != nil {
+		return nil, err
+	}
+	allGrades, err := s.repo.ListSectionGrades(ctx, section.ID)
+	if err != nil {
+		return nil, err
+	}
+	byStudent := make(map[int64][]models.AssignmentGrade)
+	for _, grade := range allGrades {
+		byStudent[grade.StudentID] = append(byStudent[grade.StudentID], grade)
+	}
+
+	for i := range students {
+		breakdown := breakdownFor(section, input, students[i].StudentID, gradesByAssignment(byStudent[students[i].StudentID], false))
+		students[i].RunningPercent = breakdown.RunningPercent
+		students[i].RunningLetter = breakdown.RunningLetter
+		students[i].FinalPercent = breakdown.FinalPercent
+		students[i].FinalLetter = breakdown.FinalLetter
+	}
+	if err := s.repo.SaveCourseGrades(ctx, section.ID, students); err != nil {
+		return nil, err
+	}
+	return students, nil
+}
+
+func (s *gradebookService) RecomputeSection(ctx context.Context, sectionID int64) error {
+	section, err := s.sectionRepo.GetSection(ctx, sectionID)
+	if err != nil {
+		return err
+	}
+	_, err = s.recompute(ctx, section)
+	return err
+}
+
+func (s *gradebookService) GetGradebook(ctx context.Context, sectionID, userID int64, isAdmin bool) (*models.Gradebook, error) {
+	section, err := requireSectionInstructor(ctx, s.sectionRepo, sectionID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	scale, err := s.scaleFor(ctx, section.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.repo.ListCategories(ctx, section.ID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.assignmentRepo.ListForSection(ctx, section.ID)
+	if err != nil {
+		return nil, err
+	}
+	students, err := s.repo.ListCourseGrades(ctx, section.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Gradebook{SectionID: section.ID, Scale: *scale, Categories: categories, Assignments: assignments, Students: students}, nil
+}
+
+func (s *gradebookService) GetStudentBreakdown(ctx context.Context, sectionID, studentID, userID int64, isAdmin bool) (*models.GradeBreakdown, error) {
+	section, err := requireSectionInstructor(ctx, s.sectionRepo, sectionID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	enrolled, err := s.assignmentRepo.IsEnrolled(ctx, section.ID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	grades, err := s.repo.ListStudentGrades(ctx, section.ID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled && len(grades) == 0 {
+		return nil, appErrors.ErrNotFound
+	}
+
+	input, err := s.loadGradeInput(ctx, section)
+	if err != nil {
+		return nil, err
+	}
+	return breakdownFor(section, input, studentID, gradesByAssignment(grades, false)), nil
+}
+
+func (s *gradebookService) PostFinalGrades(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.SectionGrade, error) {
+	section, err := requireSectionInstructor(ctx, s.sectionRepo, sectionID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed, posted []models.SectionGrade
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		students, err := s.recompute(ctx, section)
+		if err != nil {
+			return err
+		}
+
+		changed = make([]models.SectionGrade, 0, len(students))
+		for _, student := range students {
+			if student.FinalLetter == nil {
+				return appErrors.New(http.StatusConflict, "Section has no gradable work yet")
+			}
+			if student.PostedLetter == nil || *student.PostedLetter != *student.FinalLetter {
+				changed = append(changed, student)
+			}
+		}
+		if len(changed) > 0 {
+			if err := s.repo.PostCourseGrades(ctx, section, changed, userID); err != nil {
+				return err
+			}
+		}
+		if posted, err = s.repo.ListCourseGrades(ctx, section.ID); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityFinalGrades, section.ID, enums.ActionUpdate, finalGradesSnapshot(students), finalGradesSnapshot(posted))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, student := range changed {
+		studentID, letter := student.StudentID, *student.FinalLetter
+		publishAsync(func(ctx context.Context) error {
+			return s.kafka.PublishGradePostedEvent(ctx, studentID, section.CourseID, section.TermID, section.ID, nil, letter)
+		}, "grade_posted", zap.Int64("student_id", studentID), zap.Int64("section_id", section.ID))
+	}
+	logger.Logger.Info("Posted final grades", zap.Int64("section_id", section.ID), zap.Int("changed", len(changed)))
+	return posted, nil
+}
+
+func (s *gradebookService) GetStudentGrades(ctx context.Context, studentID, termID int64) (*models.StudentGrades, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, studentID); err != nil {
+		return nil, err
+	}
+	term, err := resolveTerm(ctx, s.termRepo, termID)
+	if err != nil {
+		return nil, err
+	}
+	sectionIDs, err := s.repo.ListStudentSectionIDs(ctx, studentID, term.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.StudentGrades{Term: term, Grades: make([]models.GradeBreakdown, 0, len(sectionIDs))}
+	for _, sectionID := range sectionIDs {
+		section, err := s.sectionRepo.GetSection(ctx, sectionID)
+		if err != nil {
+			return nil, err
+		}
+		input, err := s.loadGradeInput(ctx, section)
+		if err != nil {
+			return nil, err
+		}
+		grades, err := s.repo.ListStudentGrades(ctx, section.ID, studentID)
+		if err != nil {
+			return nil, err
+		}
+		result.Grades = append(result.Grades, *breakdownFor(section, input, studentID, gradesByAssignment(grades, true)))
+	}
+	return result, nil
+}
@@ -0,0 +1,188 @@
+// internal/service/gradebook_test.go
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"student-portal/internal/models"
+)
+
+func TestScoreCategory(t *testing.T) {
+	graded := func(id int64, score, max float64) models.GradeItem {
+		return models.GradeItem{AssignmentID: id, MaxPoints: max, Score: &score}
+	}
+	ungraded := func(id int64, max float64) models.GradeItem {
+		return models.GradeItem{AssignmentID: id, MaxPoints: max}
+	}
+	excused := func(item models.GradeItem) models.GradeItem {
+		item.Excused = true
+		return item
+	}
+	extra := func(item models.GradeItem) models.GradeItem {
+		item.ExtraCredit = true
+		return item
+	}
+	base := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		items        []models.GradeItem
+		dropLowest   int
+		countMissing bool
+		dueAt        map[int64]time.Time
+		wantEarned   float64
+		wantPossible float64
+		wantPercent  float64 // Negative when no percentage is expected
+		wantDropped  []int64
+	}{
+		{
+			name:         "nothing dropped",
+			items:        []models.GradeItem{graded(1, 8, 10), graded(2, 5, 10)},
+			wantEarned:   13,
+			wantPossible: 20,
+			wantPercent:  65,
+		},
+		{
+			name:         "lowest percentage dropped",
+			items:        []models.GradeItem{graded(1, 8, 10), graded(2, 5, 10), graded(3, 18, 20)},
+			dropLowest:   1,
+			wantEarned:   26,
+			wantPossible: 30,
+			wantPercent:  86.67,
+			wantDropped:  []int64{2},
+		},
+		{
+			name:         "dropping by percentage, not points",
+			items:        []models.GradeItem{graded(1, 4, 5), graded(2, 12, 20)},
+			dropLowest:   1,
+			wantEarned:   4,
+			wantPossible: 5,
+			wantPercent:  80,
+			wantDropped:  []int64{2},
+		},
+		{
+			name:         "at least one item kept",
+			items:        []models.GradeItem{graded(1, 8, 10), graded(2, 5, 10)},
+			dropLowest:   5,
+			wantEarned:   8,
+			wantPossible: 10,
+			wantPercent:  80,
+			wantDropped:  []int64{2},
+		},
+		{
+			name:         "excused items neither count nor get dropped",
+			items:        []models.GradeItem{graded(1, 8, 10), excused(graded(2, 0, 10))},
+			dropLowest:   1,
+			wantEarned:   8,
+			wantPossible: 10,
+			wantPercent:  80,
+		},
+		{
+			name:         "missing work skipped",
+			items:        []models.GradeItem{graded(1, 8, 10), ungraded(2, 10)},
+			wantEarned:   8,
+			wantPossible: 10,
+			wantPercent:  80,
+		},
+		{
+			name:         "missing work counts as zero",
+			items:        []models.GradeItem{graded(1, 8, 10), ungraded(2, 10)},
+			countMissing: true,
+			wantEarned:   8,
+			wantPossible: 20,
+			wantPercent:  40,
+		},
+		{
+			name:         "missing work dropped first",
+			items:        []models.GradeItem{graded(1, 8, 10), ungraded(2, 10)},
+			countMissing: true,
+			dropLowest:   1,
+			wantEarned:   8,
+			wantPossible: 10,
+			wantPercent:  80,
+			wantDropped:  []int64{2},
+		},
+		{
+			name:         "extra credit adds to earned only",
+			items:        []models.GradeItem{graded(1, 8, 10), extra(graded(2, 2, 5))},
+			wantEarned:   10,
+			wantPossible: 10,
+			wantPercent:  100,
+		},
+		{
+			name:         "ties dropped by earlier due date",
+			items:        []models.GradeItem{graded(1, 5, 10), graded(2, 5, 10)},
+			dropLowest:   1,
+			dueAt:        map[int64]time.Time{1: base.AddDate(0, 0, 7), 2: base},
+			wantEarned:   5,
+			wantPossible: 10,
+			wantPercent:  50,
+			wantDropped:  []int64{2},
+		},
+		{
+			name:        "nothing graded",
+			items:       []models.GradeItem{ungraded(1, 10)},
+			wantPercent: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category := &models.CategoryGrade{DropLowest: tt.dropLowest, Items: tt.items}
+			scoreCategory(category, tt.dueAt, tt.countMissing)
+
+			if category.Earned != tt.wantEarned || category.Possible != tt.wantPossible {
+				t.Errorf("earned %v of %v, want %v of %v", category.Earned, category.Possible, tt.wantEarned, tt.wantPossible)
+			}
+			switch {
+			case tt.wantPercent < 0 && category.Percent != nil:
+				t.Errorf("Percent = %v, want nil", *category.Percent)
+			case tt.wantPercent >= 0 && (category.Percent == nil || *category.Percent != tt.wantPercent):
+				t.Errorf("Percent = %v, want %v", category.Percent, tt.wantPercent)
+			}
+			var dropped []int64
+			for _, item := range category.Items {
+				if item.Dropped {
+					dropped = append(dropped, item.AssignmentID)
+				}
+			}
+			if !slices.Equal(dropped, tt.wantDropped) {
+				t.Errorf("dropped %v, want %v", dropped, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestWeightedPercent(t *testing.T) {
+	category := func(weight, percent float64, counted bool) models.CategoryGrade {
+		return models.CategoryGrade{Weight: weight, Percent: &percent, Counted: counted}
+	}
+
+	tests := []struct {
+		name       string
+		categories []models.CategoryGrade
+		want       float64 // Negative when no percentage is expected
+	}{
+		{"single category", []models.CategoryGrade{category(100, 72.5, true)}, 72.5},
+		{"weighted", []models.CategoryGrade{category(60, 80, true), category(40, 90, true)}, 84},
+		{"weights scaled up", []models.CategoryGrade{category(30, 80, true), category(20, 90, true)}, 84},
+		{"category without percentage left out", []models.CategoryGrade{category(60, 80, true), {Weight: 40, Counted: true}}, 80},
+		{"uncounted category left out", []models.CategoryGrade{category(50, 60, true), category(50, 100, false)}, 60},
+		{"rounded", []models.CategoryGrade{category(1, 100, true), category(2, 50, true)}, 66.67},
+		{"nothing to average", []models.CategoryGrade{{Weight: 100, Counted: true}}, -1},
+		{"zero weights", []models.CategoryGrade{category(0, 80, true)}, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := weightedPercent(tt.categories)
+			switch {
+			case tt.want < 0 && got != nil:
+				t.Errorf("weightedPercent = %v, want nil", *got)
+			case tt.want >= 0 && (got == nil || *got != tt.want):
+				t.Errorf("weightedPercent = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,407 @@
+// internal/service/guardian_service.go
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/commons/logger"
+	"student-portal/internal/config"
+	kafka "student-portal/internal/kafka"
+	"student-portal/internal/mail"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+	"student-portal/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// GuardianService defines the methods for guardian links, invitations and guardian access.
+type GuardianService interface {
+	CreateLink(ctx context.Context, req *models.CreateGuardianLinkRequest, createdBy int64) (*models.GuardianLink, error)
+	UpdateLink(ctx context.Context, id int64, req *models.UpdateGuardianLinkRequest) (*models.GuardianLink, error)
+	DeleteLink(ctx context.Context, id int64) error
+	ListGuardians(ctx context.Context, studentID int64) ([]models.GuardianLink, error)
+	// CreateInvitation stores the invitation and emails its accept link to the invited address.
+	// The link carries the only copy of the token.
+	CreateInvitation(ctx context.Context, req *models.CreateGuardianInvitationRequest, invitedBy int64) (*models.GuardianInvitation, error)
+	AcceptInvitation(ctx context.Context, req *models.AcceptGuardianInvitationRequest) (*models.GuardianLink, error)
+	ListLinkedStudents(ctx context.Context, guardianID int64) ([]models.LinkedStudent, error)
+	GetLinkedStudent(ctx context.Context, guardianID, studentID int64) (*models.UserResponse, error)
+	// CheckAccess returns the guardian's link to the student, failing with ErrForbidden if
+	// there is none or the student did not consent to the given kind of access.
+	CheckAccess(ctx context.Context, guardianID, studentID int64, academic bool) (*models.GuardianLink, error)
+}
+
+// validRelationships are the accepted guardian relationship types.
+var validRelationships = map[string]bool{
+	string(enums.RelationshipParent): true, string(enums.RelationshipLegalGuardian): true,
+	string(enums.RelationshipGrandparent): true, string(enums.RelationshipSibling): true,
+	string(enums.RelationshipOther): true,
+}
+
+type guardianService struct {
+	repo        repository.GuardianRepository
+	userRepo    repository.UserRepository
+	profileRepo repository.ProfileRepository
+	transactor  repository.Transactor
+	historySvc  HistoryService
+	cfg         *config.Config
+	kafka       *kafka.KafkaProducer
+	mailer      mail.Sender
+}
+
+// NewGuardianService creates a new GuardianService instance.
+func NewGuardianService(repo repository.GuardianRepository, userRepo repository.UserRepository, profileRepo repository.ProfileRepository, transactor repository.Transactor, historySvc HistoryService, cfg *config.Config, kafka *kafka.KafkaProducer, mailer mail.Sender) GuardianService {
+	return &guardianService{repo: repo, userRepo: userRepo, profileRepo: profileRepo, transactor: transactor, historySvc: historySvc, cfg: cfg, kafka: kafka, mailer: mailer}
+}
+
+// requireRole loads the user and checks that they have the given role.
+func (s *guardianService) requireRole(ctx context.Context, id int64, role enums.Role) (*models.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != string(role) {
+		return nil, appErrors.New(http.StatusBadRequest, "User %d is not a %s", id, role)
+	}
+	return user, nil
+}
+
+func (s *guardianService) CreateLink(ctx context.Context, req *models.CreateGuardianLinkRequest, createdBy int64) (*models.GuardianLink, error) {
+	if !validRelationships[req.Relationship] {
+		return nil, appErrors.New(http.StatusBadRequest, "Invalid relationship '%s'", req.Relationship)
+	}
+	if _, err := s.requireRole(ctx, req.StudentID, enums.RoleStudent); err != nil {
+		return nil, err
+	}
+	if _, err := s.requireRole(ctx, req.GuardianID, enums.RoleGuardian); err != nil {
+		return nil, err
+	}
+
+	link := &models.GuardianLink{
+		StudentID:       req.StudentID,
+		GuardianID:      req.GuardianID,
+		Relationship:    req.Relationship,
+		ConsentProfile:  req.ConsentProfile == nil || *req.ConsentProfile,
+		ConsentAcademic: req.ConsentAcademic,
+		CreatedBy:       &createdBy,
+	}
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateLink(ctx, link); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityGuardianLink, link.ID, enums.ActionCreate, nil, link)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.announceLinked(link)
+	return link, nil
+}
+
+// announceLinked publishes the event for a new link once it is committed.
+func (s *guardianService) announceLinked(link *models.GuardianLink) {
+	publishAsync(
+		func(ctx context.Context) error {
+			return s.kafka.PublishGuardianLinkedEvent(ctx, link.StudentID, link.GuardianID, link.ID, link.Relationship)
+		},
+		"guardian_linked",
+		zap.Int64("user_id", link.StudentID),
+		zap.Int64("guardian_id", link.GuardianID),
+	)
+}
+
+func (s *guardianService) UpdateLink(ctx context.Context, id int64, req *models.UpdateGuardianLinkRequest) (*models.GuardianLink, error) {
+	link, err := s.repo.GetLink(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *link
+
+	if req.Relationship != nil {
+		if !validRelationships[*req.Relationship] {
+			return nil, appErrors.New(http.StatusBadRequest, "Invalid relationship '%s'", *req.Relationship)
+		}
+		link.Relationship = *req.Relationship
+	}
+	if req.ConsentProfile != nil {
+		link.ConsentProfile = *req.ConsentProfile
+	}
+	if req.ConsentAcademic != nil {
+		link.ConsentAcademic = *req.ConsentAcademic
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateLink(ctx, link); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityGuardianLink, link.ID, enums.ActionUpdate, &before, link)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (s *guardianService) DeleteLink(ctx context.Context, id int64) error {
+	link, err := s.repo.GetLink(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteLink(ctx, id); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityGuardianLink, link.ID, enums.ActionDelete, link, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	publishAsync(
+		func(ctx context.Context) error {
+			return s.kafka.PublishGuardianUnlinkedEvent(ctx, link.StudentID, link.GuardianID, link.ID)
+		},
+		"guardian_unlinked",
+		zap.Int64("user_id", link.StudentID),
+		zap.Int64("guardian_id", link.GuardianID),
+	)
+	return nil
+}
+
+func (s *guardianService) ListGuardians(ctx context.Context, studentID int64) ([]models.GuardianLink, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, studentID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListLinksForStudent(ctx, studentID)
+}
+
+// newSecretToken returns a random URL-safe token for links that act as credentials.
+func newSecretToken() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+// hashSecretToken returns the hex-encoded SHA-256 under which a token is stored.
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *guardianService) CreateInvitation(ctx context.Context, req *models.CreateGuardianInvitationRequest, invitedBy int64) (*models.GuardianInvitation, error) {
+	if !validRelationships[req.Relationship] {
+		return nil, appErrors.New(http.StatusBadRequest, "Invalid relationship '%s'", req.Relationship)
+	}
+	email := utils.NormalizeEmail(req.Email, s.cfg.EmailLowercaseLocalPart)
+	if !strings.Contains(email, "@") {
+		return nil, appErrors.New(http.StatusBadRequest, "Invalid email address")
+	}
+	student, err := s.requireRole(ctx, req.StudentID, enums.RoleStudent)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	invitation := &models.GuardianInvitation{
+		StudentID:       req.StudentID,
+		Email:           email,
+		Relationship:    req.Relationship,
+		ConsentProfile:  req.ConsentProfile == nil || *req.ConsentProfile,
+		ConsentAcademic: req.ConsentAcademic,
+		TokenHash:       hashSecretToken(token),
+		InvitedBy:       &invitedBy,
+		ExpiresAt:       time.Now().Add(s.cfg.GuardianInviteTTL),
+	}
+	// The token is only ever sent to the invited address; it is not stored, returned or
+	// published. The email goes out once the invitation is stored, and an invitation whose
+	// email could not be sent is withdrawn.
+	if err := s.repo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+	if err := s.sendInvitation(ctx, invitation, student, token); err != nil {
+		if delErr := s.repo.DeleteInvitation(context.WithoutCancel(ctx), invitation.ID); delErr != nil {
+			logger.Logger.Error("Failed to withdraw unsent guardian invitation", zap.Int64("invitation_id", invitation.ID), zap.Error(delErr))
+		}
+		return nil, err
+	}
+
+	publishAsync(
+		func(ctx context.Context) error {
+			return s.kafka.PublishGuardianInvitedEvent(ctx, invitation.StudentID, invitation.ID, invitation.Email)
+		},
+		"guardian_invited",
+		zap.Int64("user_id", invitation.StudentID),
+		zap.Int64("invitation_id", invitation.ID),
+	)
+
+	return invitation, nil
+}
+
+// sendInvitation emails the accept link of the invitation to the invited address.
+func (s *guardianService) sendInvitation(ctx context.Context, invitation *models.GuardianInvitation, student *models.User, token string) error {
+	acceptURL := s.cfg.GuardianInviteURL + "?token=" + url.QueryEscape(token)
+	msg := mail.Message{
+		To:      invitation.Email,
+		Subject: "Invitation to " + s.cfg.InstitutionName,
+		Body: fmt.Sprintf(
+			"You have been invited to %s as %s's %s.\n\n"+
+				"To accept, open the link below before %s:\n%s\n\n"+
+				"If you did not expect this invitation, you can ignore this email.\n",
+			s.cfg.InstitutionName, student.Name, strings.ReplaceAll(invitation.Relationship, "_", " "),
+			invitation.ExpiresAt.UTC().Format("2 January 2006 15:04 MST"), acceptURL,
+		),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		logger.Logger.Error("Failed to send guardian invitation", zap.Int64("invitation_id", invitation.ID), zap.Error(err))
+		return appErrors.New(http.StatusBadGateway, "The invitation email could not be sent")
+	}
+	return nil
+}
+
+func (s *guardianService) AcceptInvitation(ctx context.Context, req *models.AcceptGuardianInvitationRequest) (*models.GuardianLink, error) {
+	invitation, err := s.repo.GetInvitationByTokenHash(ctx, hashSecretToken(strings.TrimSpace(req.Token)))
+	if err == appErrors.ErrNotFound {
+		return nil, appErrors.ErrInvitationInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if invitation.AcceptedAt != nil || time.Now().After(invitation.ExpiresAt) {
+		return nil, appErrors.ErrInvitationInvalid
+	}
+
+	guardian, err := s.guardianForInvitation(ctx, invitation, req)
+	if err != nil {
+		return nil, err
+	}
+	created := guardian.ID == 0
+
+	// A new account is only kept if the invitation is accepted with it.
+	var link *models.GuardianLink
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if created {
+			if err := s.userRepo.CreateUser(ctx, guardian); err != nil {
+				return err
+			}
+			if err := s.historySvc.Record(ctx, enums.EntityUser, guardian.ID, enums.ActionCreate, nil, userSnapshot(guardian, models.NewProfile(guardian.ID))); err != nil {
+				return err
+			}
+		}
+		// The repository re-checks that the invitation is unused and unexpired.
+		var err error
+		if link, err = s.repo.AcceptInvitation(ctx, invitation.ID, guardian.ID); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityGuardianLink, link.ID, enums.ActionCreate, nil, link)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if created {
+		publishAsync(
+			func(ctx context.Context) error {
+				return s.kafka.PublishRegisterEvent(ctx, guardian.ID, guardian.Email, guardian.Name, guardian.Role)
+			},
+			"user_registered",
+			zap.Int64("user_id", guardian.ID),
+		)
+	}
+	s.announceLinked(link)
+	return link, nil
+}
+
+// guardianForInvitation returns the guardian account for the invited email address. An existing
+// account must be a guardian and the password must match; otherwise a new, not yet saved
+// account is returned for the caller to create.
+func (s *guardianService) guardianForInvitation(ctx context.Context, invitation *models.GuardianInvitation, req *models.AcceptGuardianInvitationRequest) (*models.User, error) {
+	existing, err := s.userRepo.GetUserByEmail(ctx, invitation.Email)
+	if err == nil {
+		if !utils.CheckPasswordHash(req.Password, existing.Password) {
+			return nil, appErrors.ErrInvalidCredentials
+		}
+		if existing.Role != string(enums.RoleGuardian) {
+			return nil, appErrors.New(http.StatusConflict, "The invited email address belongs to an account that is not a guardian account")
+		}
+		if err := checkCanLogin(existing); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	if err != appErrors.ErrNotFound {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, appErrors.New(http.StatusBadRequest, "Name is required to create the guardian account")
+	}
+	if len(req.Password) < 6 {
+		return nil, appErrors.New(http.StatusBadRequest, "Password must be at least 6 characters")
+	}
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+
+	return &models.User{
+		Name:     name,
+		Email:    invitation.Email,
+		Password: hashedPassword,
+		Role:     string(enums.RoleGuardian),
+	}, nil
+}
+
+func (s *guardianService) ListLinkedStudents(ctx context.Context, guardianID int64) ([]models.LinkedStudent, error) {
+	return s.repo.ListLinkedStudents(ctx, guardianID)
+}
+
+func (s *guardianService) CheckAccess(ctx context.Context, guardianID, studentID int64, academic bool) (*models.GuardianLink, error) {
+	link, err := s.repo.FindLink(ctx, studentID, guardianID)
+	if err == appErrors.ErrNotFound {
+		return nil, appErrors.ErrForbidden
+	}
+	if err != nil {
+		return nil, err
+	}
+	if (academic && !link.ConsentAcademic) || (!academic && !link.ConsentProfile) {
+		return nil, appErrors.New(http.StatusForbidden, "The student has not consented to sharing this information")
+	}
+	return link, nil
+}
+
+func (s *guardianService) GetLinkedStudent(ctx context.Context, guardianID, studentID int64) (*models.UserResponse, error) {
+	if _, err := s.CheckAccess(ctx, guardianID, studentID, false); err != nil {
+		return nil, err
+	}
+
+	student, err := s.userRepo.GetUserByID(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.profileRepo.GetProfile(ctx, studentID)
+	if err == appErrors.ErrNotFound {
+		profile = models.NewProfile(studentID)
+	} else if err != nil {
+		return nil, err
+	}
+	return toResponseWithProfile(student, profile), nil
+}
@@ -0,0 +1,130 @@
+// internal/service/history_service.go
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"student-portal/internal/commons/constants"
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/commons/logger"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+	"student-portal/internal/utils"
+
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+// HistoryService records and lists per-entity change history.
+// Any service can record changes for its entities by passing before/after snapshots.
+type HistoryService interface {
+	// Record diffs the JSON representations of before and after (either may be nil for
+	// creations and deletions) and stores the changed fields together with the actor and
+	// request ID taken from ctx. Call it inside the repository.Transactor transaction that
+	// writes the change, so that the change is only committed with its history.
+	Record(ctx context.Context, entityType enums.EntityType, entityID int64, action enums.ChangeAction, before, after interface{}) error
+	ListHistory(ctx context.Context, entityType enums.EntityType, entityID int64, limit, offset int) ([]models.ChangeHistoryEntry, int64, error)
+}
+
+// historyIgnoredFields are bookkeeping fields that are not reported as changes.
+var historyIgnoredFields = map[string]bool{
+	"version": true, "created_at": true, "updated_at": true,
+}
+
+type historyService struct {
+	repo repository.HistoryRepository
+}
+
+// NewHistoryService creates a new HistoryService instance.
+func NewHistoryService(repo repository.HistoryRepository) HistoryService {
+	return &historyService{repo: repo}
+}
+
+func (s *historyService) Record(ctx context.Context, entityType enums.EntityType, entityID int64, action enums.ChangeAction, before, after interface{}) error {
+	changes, err := diffSnapshots(before, after)
+	if err != nil {
+		logger.Logger.Error("Failed to diff change history snapshots", zap.Error(err), zap.String("entity_type", string(entityType)))
+		return appErrors.ErrInternalServerError
+	}
+	if len(changes) == 0 && action == enums.ActionUpdate {
+		return nil
+	}
+
+	entry := &models.ChangeHistoryEntry{
+		EntityType: string(entityType),
+		EntityID:   entityID,
+		Action:     string(action),
+		Changes:    changes,
+	}
+	if claims, ok := ctx.Value(constants.UserClaimsKey).(*utils.UserClaims); ok {
+		entry.ActorID = &claims.UserID
+		entry.ActorRole = &claims.Role
+	}
+	if requestID := chiMiddleware.GetReqID(ctx); requestID != "" {
+		entry.RequestID = &requestID
+	}
+
+	if err := s.repo.RecordChange(ctx, entry); err != nil {
+		logger.Logger.Error("Failed to record change history",
+			zap.Error(err),
+			zap.String("entity_type", string(entityType)),
+			zap.Int64("entity_id", entityID),
+			zap.String("action", string(action)),
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *historyService) ListHistory(ctx context.Context, entityType enums.EntityType, entityID int64, limit, offset int) ([]models.ChangeHistoryEntry, int64, error) {
+	return s.repo.ListChanges(ctx, string(entityType), entityID, limit, offset)
+}
+
+// diffSnapshots compares the top-level JSON fields of two snapshots.
+func diffSnapshots(before, after interface{}) (map[string]models.FieldChange, error) {
+	oldFields, err := snapshotFields(before)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := snapshotFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]models.FieldChange{}
+	for key, oldValue := range oldFields {
+		if historyIgnoredFields[key] {
+			continue
+		}
+		if newValue := newFields[key]; !reflect.DeepEqual(oldValue, newValue) {
+			changes[key] = models.FieldChange{Old: oldValue, New: newValue}
+		}
+	}
+	for key, newValue := range newFields {
+		if _, seen := oldFields[key]; seen || historyIgnoredFields[key] || newValue == nil {
+			continue
+		}
+		changes[key] = models.FieldChange{Old: nil, New: newValue}
+	}
+	return changes, nil
+}
+
+// snapshotFields converts a snapshot into its JSON object representation.
+func snapshotFields(snapshot interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if snapshot == nil || (reflect.ValueOf(snapshot).Kind() == reflect.Ptr && reflect.ValueOf(snapshot).IsNil()) {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,190 @@
+// internal/service/icalendar.go
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// icsMaxLineOctets is the longest content line RFC 5545 allows, excluding the CRLF.
+const icsMaxLineOctets = 75
+
+const (
+	icsDateLayout  = "20060102"
+	icsLocalLayout = "20060102T150405"
+	icsUTCLayout   = "20060102T150405Z"
+)
+
+// icsTextEscaper escapes TEXT property values (RFC 5545, section 3.3.11).
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// calendarEvent is one VEVENT of a calendar feed. Timed events carry wall-clock times in the
+// calendar's location; all-day events only use the date, and their End is exclusive.
+type calendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	AllDay      bool
+	Start       time.Time
+	End         time.Time
+	Until       time.Time   // When set, the event repeats weekly up to this instant
+	ExDates     []time.Time // Start times of cancelled occurrences
+	Tentative   bool
+}
+
+// icsCalendar is a VCALENDAR of events in one time zone. From and To bound the period the
+// events fall in, which the VTIMEZONE has to describe.
+type icsCalendar struct {
+	Name     string
+	Location *time.Location
+	From     time.Time
+	To       time.Time
+	Events   []calendarEvent
+}
+
+// icsWriter writes folded content lines.
+type icsWriter struct {
+	b strings.Builder
+}
+
+// property writes a content line, folding it every 75 octets without splitting UTF-8 sequences.
+func (w *icsWriter) property(name, value string) {
+	line := name + ":" + value
+	limit := icsMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.b.WriteString(line[:cut])
+		w.b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icsMaxLineOctets - 1 // The folding space counts towards the next line
+	}
+	w.b.WriteString(line)
+	w.b.WriteString("\r\n")
+}
+
+// formatUTCOffset formats an offset in seconds east of UTC as +HHMM, or +HHMMSS if needed.
+func formatUTCOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	formatted := fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset/60%60)
+	if offset%60 != 0 {
+		formatted += fmt.Sprintf("%02d", offset%60)
+	}
+	return formatted
+}
+
+// Render returns the calendar as an RFC 5545 document stamped with the given time.
+func (c *icsCalendar) Render(now time.Time) []byte {
+	w := &icsWriter{}
+	tzid := "TZID=" + c.Location.String()
+	stamp := now.UTC().Format(icsUTCLayout)
+
+	w.property("BEGIN", "VCALENDAR")
+	w.property("VERSION", "2.0")
+	w.property("PRODID", "-//Student Portal//Timetable//EN")
+	w.property("CALSCALE", "GREGORIAN")
+	w.property("METHOD", "PUBLISH")
+	w.property("X-WR-CALNAME", icsTextEscaper.Replace(c.Name))
+	w.property("X-WR-TIMEZONE", c.Location.String())
+	c.writeTimezone(w)
+
+	for _, e := range c.Events {
+		w.property("BEGIN", "VEVENT")
+		w.property("UID", e.UID)
+		w.property("DTSTAMP", stamp)
+		if e.AllDay {
+			w.property("DTSTART;VALUE=DATE", e.Start.Format(icsDateLayout))
+			w.property("DTEND;VALUE=DATE", e.End.Format(icsDateLayout))
+			w.property("TRANSP", "TRANSPARENT")
+		} else {
+			w.property("DTSTART;"+tzid, e.Start.Format(icsLocalLayout))
+			w.property("DTEND;"+tzid, e.End.Format(icsLocalLayout))
+		}
+		if !e.Until.IsZero() {
+			// UNTIL must be in UTC when DTSTART has a time zone
+			w.property("RRULE", "FREQ=WEEKLY;UNTIL="+e.Until.UTC().Format(icsUTCLayout))
+		}
+		if len(e.ExDates) > 0 {
+			dates := make([]string, len(e.ExDates))
+			for i, d := range e.ExDates {
+				dates[i] = d.Format(icsLocalLayout)
+			}
+			w.property("EXDATE;"+tzid, strings.Join(dates, ","))
+		}
+		w.property("SUMMARY", icsTextEscaper.Replace(e.Summary))
+		if e.Description != "" {
+			w.property("DESCRIPTION", icsTextEscaper.Replace(e.Description))
+		}
+		if e.Location != "" {
+			w.property("LOCATION", icsTextEscaper.Replace(e.Location))
+		}
+		if e.Tentative {
+			w.property("STATUS", "TENTATIVE")
+		} else {
+			w.property("STATUS", "CONFIRMED")
+		}
+		w.property("END", "VEVENT")
+	}
+
+	w.property("END", "VCALENDAR")
+	return []byte(w.b.String())
+}
+
+// writeTimezone writes the VTIMEZONE of the calendar's location: the offset in effect at From
+// and every change of it up to To. Clients need it to place the weekly meetings correctly
+// across daylight saving time changes.
+func (c *icsCalendar) writeTimezone(w *icsWriter) {
+	w.property("BEGIN", "VTIMEZONE")
+	w.property("TZID", c.Location.String())
+
+	t := c.From.Truncate(time.Hour)
+	name, offset := t.In(c.Location).Zone()
+	writeObservance(w, t.In(c.Location).IsDST(), time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), offset, offset, name)
+
+	for t.Before(c.To) {
+		next := t.Add(time.Hour)
+		if _, nextOffset := next.In(c.Location).Zone(); nextOffset != offset {
+			// Narrow the change down to the second; not every zone changes on the hour
+			lo, hi := t, next
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if _, midOffset := mid.In(c.Location).Zone(); midOffset == offset {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			name, newOffset := hi.In(c.Location).Zone()
+			// The onset is the wall-clock time just before the change
+			onset := hi.UTC().Add(time.Duration(offset) * time.Second)
+			writeObservance(w, hi.In(c.Location).IsDST(), onset, offset, newOffset, name)
+			offset = newOffset
+		}
+		t = next
+	}
+
+	w.property("END", "VTIMEZONE")
+}
+
+// writeObservance writes a STANDARD or DAYLIGHT component starting at the wall-clock onset.
+func writeObservance(w *icsWriter, daylight bool, onset time.Time, from, to int, name string) {
+	kind := "STANDARD"
+	if daylight {
+		kind = "DAYLIGHT"
+	}
+	w.property("BEGIN", kind)
+	w.property("DTSTART", onset.Format(icsLocalLayout))
+	w.property("TZOFFSETFROM", formatUTCOffset(from))
+	w.property("TZOFFSETTO", formatUTCOffset(to))
+	w.property("TZNAME", icsTextEscaper.Replace(name))
+	w.property("END", kind)
+}
@@ -0,0 +1,91 @@
+// internal/service/icalendar_test.go
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestICSTextEscaper(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Lecture", "Lecture"},
+		{"Room 1, Building A", `Room 1\, Building A`},
+		{"Bring; a pen", `Bring\; a pen`},
+		{`C:\path`, `C:\\path`},
+		{"line one\nline two", `line one\nline two`},
+		{"line one\r\nline two", `line one\nline two`},
+		{`a\,b`, `a\\\,b`},
+	}
+	for _, tt := range tests {
+		if got := icsTextEscaper.Replace(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestICSWriterFolding(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		lines int
+	}{
+		{"short", "Lecture", 1},
+		{"exactly one line", strings.Repeat("x", icsMaxLineOctets-len("SUMMARY:")), 1},
+		{"one octet over", strings.Repeat("x", icsMaxLineOctets-len("SUMMARY:")+1), 2},
+		{"long ascii", strings.Repeat("abcdefghij", 20), 3},
+		{"multibyte", strings.Repeat("äöü€", 30), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &icsWriter{}
+			w.property("SUMMARY", tt.value)
+			out := w.b.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("output %q does not end with CRLF", out)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			if len(lines) != tt.lines {
+				t.Errorf("got %d lines, want %d", len(lines), tt.lines)
+			}
+			for i, line := range lines {
+				if len(line) > icsMaxLineOctets {
+					t.Errorf("line %d is %d octets long", i+1, len(line))
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 sequence: %q", i+1, line)
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d does not start with a space", i+1)
+				}
+			}
+
+			unfolded := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", "")
+			if unfolded != "SUMMARY:"+tt.value {
+				t.Errorf("unfolded line = %q, want %q", unfolded, "SUMMARY:"+tt.value)
+			}
+		})
+	}
+}
+
+func TestFormatUTCOffset(t *testing.T) {
+	tests := []struct {
+		offset int
+		want   string
+	}{
+		{0, "+0000"},
+		{3600, "+0100"},
+		{-5 * 3600, "-0500"},
+		{5*3600 + 30*60, "+0530"},
+		{-(3*3600 + 30*60), "-0330"},
+		{1172, "+001932"},
+	}
+	for _, tt := range tests {
+		if got := formatUTCOffset(tt.offset); got != tt.want {
+			t.Errorf("formatUTCOffset(%d) = %q, want %q", tt.offset, got, tt.want)
+		}
+	}
+}
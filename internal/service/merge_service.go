@@ -0,0 +1,166 @@
+// internal/service/merge_service.go
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/commons/logger"
+	"student-portal/internal/config"
+	kafka "student-portal/internal/kafka"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// MergeService defines the methods for finding and merging duplicate accounts.
+type MergeService interface {
+	FindDuplicates(ctx context.Context, minScore float64, limit, offset int) ([]models.DuplicateCandidate, int64, error)
+	PreviewMerge(ctx context.Context, req *models.MergeUsersRequest) (*models.MergePreview, error)
+	MergeUsers(ctx context.Context, req *models.MergeUsersRequest, performedBy int64) (*models.UserMerge, error)
+}
+
+type mergeService struct {
+	repo        repository.MergeRepository
+	userRepo    repository.UserRepository
+	profileRepo repository.ProfileRepository
+	transactor  repository.Transactor
+	historySvc  HistoryService
+	cfg         *config.Config
+	kafka       *kafka.KafkaProducer
+}
+
+// NewMergeService creates a new MergeService instance.
+func NewMergeService(repo repository.MergeRepository, userRepo repository.UserRepository, profileRepo repository.ProfileRepository, transactor repository.Transactor, historySvc HistoryService, cfg *config.Config, kafka *kafka.KafkaProducer) MergeService {
+	return &mergeService{repo: repo, userRepo: userRepo, profileRepo: profileRepo, transactor: transactor, historySvc: historySvc, cfg: cfg, kafka: kafka}
+}
+
+func (s *mergeService) FindDuplicates(ctx context.Context, minScore float64, limit, offset int) ([]models.DuplicateCandidate, int64, error) {
+	pairs, err := s.repo.ListDuplicateCandidates(ctx, maxDuplicatePairs)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(pairs) == maxDuplicatePairs {
+		logger.Logger.Warn("Duplicate detection reached its candidate limit; some pairs were not scored", zap.Int("limit", maxDuplicatePairs))
+	}
+
+	candidates := scoreDuplicates(pairs, minScore)
+	total := int64(len(candidates))
+	if offset >= len(candidates) {
+		return []models.DuplicateCandidate{}, total, nil
+	}
+	return candidates[offset:min(offset+limit, len(candidates))], total, nil
+}
+
+// mergeParties loads both accounts of a merge request and checks that they can be merged.
+func (s *mergeService) mergeParties(ctx context.Context, req *models.MergeUsersRequest) (source, target *models.User, sourceProfile, targetProfile *models.Profile, err error) {
+	if req.SourceUserID == req.TargetUserID {
+		return nil, nil, nil, nil, appErrors.New(http.StatusBadRequest, "Cannot merge an account into itself")
+	}
+
+	if source, err = s.userRepo.GetUserByID(ctx, req.SourceUserID); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if target, err = s.userRepo.GetUserByID(ctx, req.TargetUserID); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if source.Role != target.Role {
+		return nil, nil, nil, nil, appErrors.New(http.StatusBadRequest, "Cannot merge accounts with different roles")
+	}
+
+	if sourceProfile, err = s.loadProfile(ctx, source.ID); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if targetProfile, err = s.loadProfile(ctx, target.ID); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return source, target, sourceProfile, targetProfile, nil
+}
+
+// loadProfile returns the user's profile, or an empty one if it has not been created yet.
+func (s *mergeService) loadProfile(ctx context.Context, userID int64) (*models.Profile, error) {
+	profile, err := s.profileRepo.GetProfile(ctx, userID)
+	if err == appErrors.ErrNotFound {
+		return models.NewProfile(userID), nil
+	}
+	return profile, err
+}
+
+func (s *mergeService) PreviewMerge(ctx context.Context, req *models.MergeUsersRequest) (*models.MergePreview, error) {
+	source, target, sourceProfile, targetProfile, err := s.mergeParties(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.CountMergeRecords(ctx, source.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, conflicts := mergedProfile(targetProfile, sourceProfile)
+	return &models.MergePreview{
+		Source:    *toResponseWithProfile(source, sourceProfile),
+		Target:    *toResponseWithProfile(target, targetProfile),
+		Result:    *toResponseWithProfile(target, merged),
+		Records:   records,
+		Conflicts: conflicts,
+	}, nil
+}
+
+func (s *mergeService) MergeUsers(ctx context.Context, req *models.MergeUsersRequest, performedBy int64) (*models.UserMerge, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, appErrors.New(http.StatusBadRequest, "A reason is required to merge accounts")
+	}
+	if req.SourceUserID == performedBy {
+		return nil, appErrors.New(http.StatusBadRequest, "You cannot merge away your own account")
+	}
+
+	source, target, _, targetProfile, err := s.mergeParties(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	merge := &models.UserMerge{
+		SourceUserID: source.ID,
+		TargetUserID: target.ID,
+		PerformedBy:  &performedBy,
+		Reason:       reason,
+	}
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.MergeUsers(ctx, merge); err != nil {
+			return err
+		}
+
+		// The source's history now belongs to the target; the merge entry completes it.
+		merged, err := s.userRepo.GetUserByID(ctx, target.ID)
+		if err != nil {
+			return err
+		}
+		profile, err := s.loadProfile(ctx, target.ID)
+		if err != nil {
+			return err
+		}
+		after := userSnapshot(merged, profile)
+		after["merged_user_id"] = source.ID
+		return s.historySvc.Record(ctx, enums.EntityUser, target.ID, enums.ActionMerge, userSnapshot(target, targetProfile), after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishAsync(
+		func(ctx context.Context) error {
+			return s.kafka.PublishUserMergedEvent(ctx, source.ID, target.ID, performedBy, merge.ID)
+		},
+		"user_merged",
+		zap.Int64("user_id", target.ID),
+		zap.Int64("source_user_id", source.ID),
+	)
+
+	return merge, nil
+}
@@ -0,0 +1,149 @@
+// internal/service/offering_service.go
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+)
+
+// OfferingService defines the methods for the courses offered in each term.
+type OfferingService interface {
+	CreateOffering(ctx context.Context, termID int64, req *models.CreateOfferingRequest, createdBy int64) (*models.CourseOffering, error)
+	UpdateOffering(ctx context.Context, termID, courseID int64, req *models.UpdateOfferingRequest) (*models.CourseOffering, error)
+	DeleteOffering(ctx context.Context, termID, courseID int64) error
+	ListOfferings(ctx context.Context, termID int64, includeInactive bool) ([]models.CourseOffering, error)
+}
+
+type offeringService struct {
+	repo          repository.OfferingRepository
+	courseRepo    repository.CourseRepository
+	termRepo      repository.TermRepository
+	transactor    repository.Transactor
+	enrollmentSvc EnrollmentService
+	historySvc    HistoryService
+	cfg           *config.Config
+}
+
+// NewOfferingService creates a new OfferingService instance.
+func NewOfferingService(repo repository.OfferingRepository, courseRepo repository.CourseRepository, termRepo repository.TermRepository, transactor repository.Transactor, enrollmentSvc EnrollmentService, historySvc HistoryService, cfg *config.Config) OfferingService {
+	return &offeringService{repo: repo, courseRepo: courseRepo, termRepo: termRepo, transactor: transactor, enrollmentSvc: enrollmentSvc, historySvc: historySvc, cfg: cfg}
+}
+
+// seatsAdded reports whether a capacity change makes room for more students.
+func seatsAdded(before, after *int) bool {
+	if after == nil {
+		return before != nil
+	}
+	return before != nil && *after > *before
+}
+
+func (s *offeringService) CreateOffering(ctx context.Context, termID int64, req *models.CreateOfferingRequest, createdBy int64) (*models.CourseOffering, error) {
+	if _, err := s.termRepo.GetTermByID(ctx, termID); err != nil {
+		return nil, err
+	}
+	course, err := s.courseRepo.GetCourseByID(ctx, req.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	offering := &models.CourseOffering{
+		CourseID:     course.ID,
+		TermID:       termID,
+		Capacity:     course.Capacity,
+		DropDeadline: req.DropDeadline,
+		IsActive:     true,
+		CreatedBy:    &createdBy,
+	}
+	if req.Capacity != nil {
+		if *req.Capacity <= 0 {
+			return nil, appErrors.New(http.StatusBadRequest, "Capacity must be positive")
+		}
+		offering.Capacity = req.Capacity
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateOffering(ctx, offering); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityCourseOffering, offering.ID, enums.ActionCreate, nil, offering)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return offering, nil
+}
+
+func (s *offeringService) UpdateOffering(ctx context.Context, termID, courseID int64, req *models.UpdateOfferingRequest) (*models.CourseOffering, error) {
+	offering, err := s.repo.GetOffering(ctx, courseID, termID)
+	if err != nil {
+		return nil, err
+	}
+	before := *offering
+
+	if req.Capacity != nil {
+		switch {
+		case *req.Capacity < 0:
+			return nil, appErrors.New(http.StatusBadRequest, "Capacity must be positive")
+		case *req.Capacity == 0:
+			offering.Capacity = nil
+		default:
+			offering.Capacity = req.Capacity
+		}
+	}
+	if req.DropDeadline != nil {
+		offering.DropDeadline = req.DropDeadline
+	} else if req.ClearDropDeadline {
+		offering.DropDeadline = nil
+	}
+	if req.IsActive != nil {
+		offering.IsActive = *req.IsActive
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateOffering(ctx, offering); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityCourseOffering, offering.ID, enums.ActionUpdate, &before, offering)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// More seats may let students in from the waitlist
+	if seatsAdded(before.Capacity, offering.Capacity) {
+		if err := s.enrollmentSvc.FillSeats(ctx, courseID, termID); err != nil {
+			return nil, err
+		}
+		if offering, err = s.repo.GetOffering(ctx, courseID, termID); err != nil {
+			return nil, err
+		}
+	}
+
+	return offering, nil
+}
+
+func (s *offeringService) DeleteOffering(ctx context.Context, termID, courseID int64) error {
+	offering, err := s.repo.GetOffering(ctx, courseID, termID)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteOffering(ctx, courseID, termID); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityCourseOffering, offering.ID, enums.ActionDelete, offering, nil)
+	})
+}
+
+func (s *offeringService) ListOfferings(ctx context.Context, termID int64, includeInactive bool) ([]models.CourseOffering, error) {
+	if _, err := s.termRepo.GetTermByID(ctx, termID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListForTerm(ctx, termID, includeInactive)
+}
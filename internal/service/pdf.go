@@ -0,0 +1,157 @@
+// internal/service/pdf.go
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A4 in PDF points, with the margins content is laid out in.
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+	pdfMargin     = 50.0
+	pdfFooterY    = 30.0
+)
+
+// PDF fonts: the standard Helvetica faces, which every reader provides.
+const (
+	pdfFontRegular = "F1"
+	pdfFontBold    = "F2"
+)
+
+// pdfTextEscaper escapes the delimiters of PDF literal strings.
+var pdfTextEscaper = strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\r", " ", "\n", " ")
+
+// pdfDocument lays out text lines top to bottom on A4 pages, starting a new page when one is
+// full. It only supports what transcripts need: text in two weights and horizontal rules.
+type pdfDocument struct {
+	title  string
+	pages  []*bytes.Buffer
+	y      float64 // Baseline of the next line on the current page
+	footer func(page, pages int) string
+}
+
+// newPDFDocument starts a document with one empty page. The footer, if set, is printed at the
+// bottom of every page once the page count is known.
+func newPDFDocument(title string, footer func(page, pages int) string) *pdfDocument {
+	d := &pdfDocument{title: title, footer: footer}
+	d.newPage()
+	return d
+}
+
+func (d *pdfDocument) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// ensureSpace starts a new page unless height points are left above the footer.
+func (d *pdfDocument) ensureSpace(height float64) {
+	if d.y-height < pdfMargin {
+		d.newPage()
+	}
+}
+
+// pdfText encodes text for the standard fonts (WinAnsiEncoding); characters outside Latin-1
+// are replaced.
+func pdfText(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if r < 32 || r > 255 || (r >= 127 && r < 160) {
+			b.WriteByte('?')
+			continue
+		}
+		b.WriteByte(byte(r))
+	}
+	return pdfTextEscaper.Replace(b.String())
+}
+
+// writeText draws text on the page with its baseline at (x, y).
+func writeText(page *bytes.Buffer, font string, size, x, y float64, text string) {
+	fmt.Fprintf(page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfText(text))
+}
+
+// pdfCell is a piece of text in a row, starting X points from the left margin.
+type pdfCell struct {
+	X    float64
+	Text string
+}
+
+// row draws one line of cells and moves down by the line height.
+func (d *pdfDocument) row(font string, size float64, cells ...pdfCell) {
+	height := size * 1.4
+	d.ensureSpace(height)
+	d.y -= height
+	page := d.pages[len(d.pages)-1]
+	for _, cell := range cells {
+		writeText(page, font, size, pdfMargin+cell.X, d.y, cell.Text)
+	}
+}
+
+// text draws a line of text at the left margin.
+func (d *pdfDocument) text(font string, size float64, text string) {
+	d.row(font, size, pdfCell{Text: text})
+}
+
+// rule draws a horizontal line across the content area.
+func (d *pdfDocument) rule() {
+	d.ensureSpace(8)
+	d.y -= 4
+	fmt.Fprintf(d.pages[len(d.pages)-1], "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, d.y, pdfPageWidth-pdfMargin, d.y)
+	d.y -= 4
+}
+
+// space moves down without drawing anything.
+func (d *pdfDocument) space(height float64) {
+	d.y -= height
+}
+
+// bytes serializes the document as PDF 1.4.
+func (d *pdfDocument) bytes(created time.Time) []byte {
+	var out bytes.Buffer
+	offsets := make([]int, 0)
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-4 are the catalog, page tree, fonts; each page then takes a page and a content object
+	pageCount := len(d.pages)
+	kids := make([]string, 0, pageCount)
+	for i := 0; i < pageCount; i++ {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pageCount))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		content := page.Bytes()
+		if d.footer != nil {
+			var footer bytes.Buffer
+			writeText(&footer, pdfFontRegular, 8, pdfMargin, pdfFooterY, d.footer(i+1, pageCount))
+			content = append(append([]byte{}, content...), footer.Bytes()...)
+		}
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, pdfFontRegular, pdfFontBold, 6+2*i,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+	object(fmt.Sprintf("<< /Title (%s) /Producer (student-portal) /CreationDate (D:%s) >>",
+		pdfText(d.title), created.UTC().Format("20060102150405")+"Z"))
+	info := len(offsets)
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, info, xref)
+	return out.Bytes()
+}
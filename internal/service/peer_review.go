@@ -0,0 +1,163 @@
+// internal/service/peer_review.go
+package service
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"path/filepath"
+	"strings"
+
+	"student-portal/internal/models"
+)
+
+// Limits on peer review.
+const (
+	maxReviewersPerSubmission = 10
+	maxReviewComment          = 10000
+)
+
+// allocateReviewers assigns every submission to perSubmission of the other authors. The
+// authors are shuffled into a ring and each one reviews the next perSubmission authors along
+// it, so nobody reviews their own work, no submission gets the same reviewer twice and every
+// reviewer has the same load. perSubmission is capped at one less than the number of authors.
+func allocateReviewers(submissions []models.Submission, perSubmission int) []models.PeerReview {
+	ring := make([]models.Submission, len(submissions))
+	copy(ring, submissions)
+	rand.Shuffle(len(ring), func(i, j int) { ring[i], ring[j] = ring[j], ring[i] })
+
+	perSubmission = min(perSubmission, len(ring)-1)
+	reviews := make([]models.PeerReview, 0, len(ring)*max(perSubmission, 0))
+	for i, submission := range ring {
+		for k := 1; k <= perSubmission; k++ {
+			reviews = append(reviews, models.PeerReview{
+				AssignmentID: submission.AssignmentID,
+				SubmissionID: submission.ID,
+				AuthorID:     submission.StudentID,
+				ReviewerID:   ring[(i+len(ring)-k)%len(ring)].StudentID,
+			})
+		}
+	}
+	return reviews
+}
+
+// averagePoints returns the mean points of the handed-in reviews that gave points, or nil.
+func averagePoints(reviews []models.PeerReview) *float64 {
+	total, count := 0.0, 0
+	for _, review := range reviews {
+		if review.SubmittedAt != nil && review.Points != nil {
+			total += *review.Points
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	average := roundPoints(total / float64(count))
+	return &average
+}
+
+// criterionAverages returns the mean score of each rubric criterion over the handed-in reviews.
+func criterionAverages(rubric *models.Rubric, reviews []models.PeerReview) []models.CriterionAverage {
+	totals := make(map[int64]float64)
+	counts := make(map[int64]int)
+	for _, review := range reviews {
+		if review.SubmittedAt == nil {
+			continue
+		}
+		for _, score := range review.Scores {
+			totals[score.CriterionID] += score.Points
+			counts[score.CriterionID]++
+		}
+	}
+	averages := make([]models.CriterionAverage, 0, len(rubric.Criteria))
+	for _, criterion := range rubric.Criteria {
+		if counts[criterion.ID] > 0 {
+			averages = append(averages, models.CriterionAverage{
+				CriterionID: criterion.ID,
+				Average:     roundPoints(totals[criterion.ID] / float64(counts[criterion.ID])),
+			})
+		}
+	}
+	return averages
+}
+
+// summarizePeerReviews groups the reviews of the assignment by submission and aggregates their
+// scores. Peer points are rubric totals with a rubric and the assignment's points without one.
+func summarizePeerReviews(assignment *models.Assignment, rubric *models.Rubric, reviews []models.PeerReview) []models.PeerReviewSummary {
+	summaries := make([]models.PeerReviewSummary, 0)
+	byAuthor := make(map[int64]int)
+	for _, review := range reviews {
+		i, ok := byAuthor[review.AuthorID]
+		if !ok {
+			i = len(summaries)
+			byAuthor[review.AuthorID] = i
+			summaries = append(summaries, models.PeerReviewSummary{
+				AuthorID:     review.AuthorID,
+				AuthorName:   review.AuthorName,
+				SubmissionID: review.SubmissionID,
+				Reviews:      make([]models.PeerReview, 0),
+			})
+		}
+		summary := &summaries[i]
+		summary.Assigned++
+		if review.SubmittedAt != nil {
+			summary.Completed++
+		}
+		summary.Reviews = append(summary.Reviews, review)
+	}
+
+	for i := range summaries {
+		summary := &summaries[i]
+		summary.AveragePoints = averagePoints(summary.Reviews)
+		if rubric != nil {
+			summary.CriterionAverages = criterionAverages(rubric, summary.Reviews)
+		}
+		if summary.AveragePoints != nil {
+			grade := roundPoints(math.Min(*summary.AveragePoints, assignment.MaxPoints))
+			if rubric != nil {
+				grade = scaleRubricPoints(*summary.AveragePoints, rubric.MaxPoints, assignment.MaxPoints)
+			}
+			summary.SuggestedGrade = &grade
+		}
+	}
+	return summaries
+}
+
+// peerReviewScores converts validated rubric scores into the scores of a peer review.
+func peerReviewScores(scores []models.RubricScore) []models.PeerReviewScore {
+	converted := make([]models.PeerReviewScore, 0, len(scores))
+	for _, score := range scores {
+		converted = append(converted, models.PeerReviewScore{
+			CriterionID: score.CriterionID,
+			LevelID:     score.LevelID,
+			Points:      score.Points,
+			Comment:     score.Comment,
+		})
+	}
+	return converted
+}
+
+// reviewFiles lists the files of a submission under anonymous names, keeping their extension.
+func reviewFiles(files []models.File) []models.ReviewFile {
+	reviewFiles := make([]models.ReviewFile, 0, len(files))
+	for i, file := range files {
+		reviewFiles = append(reviewFiles, models.ReviewFile{
+			Position:    i + 1,
+			Filename:    reviewFilename(i+1, file.Filename),
+			ContentType: file.ContentType,
+			SizeBytes:   file.SizeBytes,
+		})
+	}
+	return reviewFiles
+}
+
+// reviewFilename names the file at the position of a submission under review. Filenames often
+// carry the author's name, so only the extension is kept.
+func reviewFilename(position int, filename string) string {
+	ext := strings.ToLower(filepath.Ext(sanitizeFilename(filename)))
+	if len(ext) > 10 {
+		ext = ""
+	}
+	return fmt.Sprintf("submission-file-%d%s", position, ext)
+}
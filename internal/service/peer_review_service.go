@@ -0,0 +1,464 @@
+// internal/service/peer_review_service.go
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+)
+
+// PeerReviewService defines the methods for anonymous peer review of assignments. Methods
+// taking a userID and isAdmin act on behalf of that user: the section's instructor and admins
+// run the peer review, while students review the work they were assigned and never learn who
+// wrote it or who reviewed theirs.
+type PeerReviewService interface {
+	GetSettings(ctx context.Context, assignmentID, userID int64, isAdmin bool) (*models.PeerReviewSettings, error)
+	// SetSettings turns peer review of the assignment on or changes its settings. The review
+	// deadline can be moved at any time.
+	SetSettings(ctx context.Context, assignmentID int64, req *models.PeerReviewSettingsRequest, userID int64, isAdmin bool) (*models.PeerReviewSettings, error)
+	// DisablePeerReview turns peer review of the assignment off until a review is handed in.
+	DisablePeerReview(ctx context.Context, assignmentID, userID int64, isAdmin bool) error
+	// AllocateReviews assigns the latest submission of every student to reviewers once the
+	// assignment is due. It can be run again, e.g. to include late work, until the first
+	// review is handed in.
+	AllocateReviews(ctx context.Context, assignmentID, userID int64, isAdmin bool) (*models.PeerReviewOverview, error)
+	// GetOverview returns the assignment's reviews with the aggregated scores per submission.
+	GetOverview(ctx context.Context, assignmentID, userID int64, isAdmin bool) (*models.PeerReviewOverview, error)
+	// AcceptScores grades students with the aggregated peer scores of their submission.
+	AcceptScores(ctx context.Context, assignmentID int64, req *models.AcceptPeerScoresRequest, userID int64, isAdmin bool) ([]models.AssignmentGrade, error)
+
+	// ListOwnReviews returns the reviews assigned to the student.
+	ListOwnReviews(ctx context.Context, reviewerID int64) ([]models.ReviewTask, error)
+	// GetOwnReview returns a review assigned to the student with the work to review.
+	GetOwnReview(ctx context.Context, id, reviewerID int64) (*models.ReviewTask, error)
+	// SubmitOwnReview hands in a review before the review deadline; it can be revised until then.
+	SubmitOwnReview(ctx context.Context, id, reviewerID int64, req *models.SubmitPeerReviewRequest) (*models.ReviewTask, error)
+	// OpenReviewFile returns the file at the position (from 1) of the submission under review.
+	OpenReviewFile(ctx context.Context, id int64, position int, reviewerID int64) (*models.ReviewFile, io.ReadCloser, error)
+	// GetOwnPeerFeedback returns the reviews of the student's submission once the review
+	// deadline has passed.
+	GetOwnPeerFeedback(ctx context.Context, assignmentID, authorID int64) (*models.PeerFeedback, error)
+}
+
+type peerReviewService struct {
+	repo           repository.PeerReviewRepository
+	rubricRepo     repository.RubricRepository
+	assignmentRepo repository.AssignmentRepository
+	sectionRepo    repository.SectionRepository
+	transactor     repository.Transactor
+	fileSvc        FileService
+	gradebookSvc   GradebookService
+	historySvc     HistoryService
+}
+
+// NewPeerReviewService creates a new PeerReviewService instance.
+func NewPeerReviewService(repo repository.PeerReviewRepository, rubricRepo repository.RubricRepository, assignmentRepo repository.AssignmentRepository, sectionRepo repository.SectionRepository, transactor repository.Transactor, fileSvc FileService, gradebookSvc GradebookService, historySvc HistoryService) PeerReviewService {
+	return &peerReviewService{repo: repo, rubricRepo: rubricRepo, assignmentRepo: assignmentRepo, sectionRepo: sectionRepo, transactor: transactor, fileSvc: fileSvc, gradebookSvc: gradebookSvc, historySvc: historySvc}
+}
+
+// requirePeerReviewAssignment loads the assignment and checks that the user teaches its section.
+func (s *peerReviewService) requirePeerReviewAssignment(ctx context.Context, assignmentID, userID int64, isAdmin bool) (*models.Assignment, error) {
+	assignment, err := s.assignmentRepo.GetAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, assignment.SectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// settingsFor returns the peer review settings of the assignment.
+func (s *peerReviewService) settingsFor(ctx context.Context, assignmentID int64) (*models.PeerReviewSettings, error) {
+	settings, err := s.repo.GetSettings(ctx, assignmentID)
+	if err == appErrors.ErrNotFound {
+		return nil, appErrors.New(http.StatusNotFound, "Assignment has no peer review")
+	}
+	return settings, err
+}
+
+// rubricFor returns the rubric of the assignment, or nil if it has none.
+func (s *peerReviewService) rubricFor(ctx context.Context, assignment *models.Assignment) (*models.Rubric, error) {
+	if assignment.RubricID == nil {
+		return nil, nil
+	}
+	return s.rubricRepo.GetRubric(ctx, *assignment.RubricID)
+}
+
+// ownReview loads a review and checks that it is assigned to the student. Other reviews are
+// reported as missing so as not to give anything away.
+func (s *peerReviewService) ownReview(ctx context.Context, id, reviewerID int64) (*models.PeerReview, error) {
+	review, err := s.repo.GetReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.ReviewerID != reviewerID {
+		return nil, appErrors.ErrNotFound
+	}
+	return review, nil
+}
+
+// reviewTask presents a review to its reviewer.
+func reviewTask(review *models.PeerReview, assignment *models.Assignment, settings *models.PeerReviewSettings) models.ReviewTask {
+	return models.ReviewTask{
+		ID:              review.ID,
+		AssignmentID:    assignment.ID,
+		AssignmentTitle: assignment.Title,
+		CourseCode:      assignment.CourseCode,
+		ReviewDueAt:     settings.ReviewDueAt,
+		Points:          review.Points,
+		Comment:         review.Comment,
+		Scores:          review.Scores,
+		SubmittedAt:     review.SubmittedAt,
+	}
+}
+
+func (s *peerReviewService) GetSettings(ctx context.Context, assignmentID, userID int64, isAdmin bool) (*models.PeerReviewSettings, error) {
+	if _, err := s.requirePeerReviewAssignment(ctx, assignmentID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return s.settingsFor(ctx, assignmentID)
+}
+
+func (s *peerReviewService) SetSettings(ctx context.Context, assignmentID int64, req *models.PeerReviewSettingsRequest, userID int64, isAdmin bool) (*models.PeerReviewSettings, error) {
+	assignment, err := s.requirePeerReviewAssignment(ctx, assignmentID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if req.ReviewersPerSubmission < 1 || req.ReviewersPerSubmission > maxReviewersPerSubmission {
+		return nil, appErrors.New(http.StatusBadRequest, "Reviewers per submission must be between 1 and %d", maxReviewersPerSubmission)
+	}
+	if !req.ReviewDueAt.After(assignment.DueAt) {
+		return nil, appErrors.New(http.StatusBadRequest, "Review deadline must be after the assignment's due date")
+	}
+
+	before, err := s.repo.GetSettings(ctx, assignment.ID)
+	if err != nil && err != appErrors.ErrNotFound {
+		return nil, err
+	}
+	settings := &models.PeerReviewSettings{
+		AssignmentID:           assignment.ID,
+		ReviewersPerSubmission: req.ReviewersPerSubmission,
+		ReviewDueAt:            req.ReviewDueAt,
+		CreatedBy:              &userID,
+	}
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.SaveSettings(ctx, settings); err != nil {
+			return err
+		}
+		if before == nil {
+			return s.historySvc.Record(ctx, enums.EntityPeerReview, assignment.ID, enums.ActionCreate, nil, settings)
+		}
+		return s.historySvc.Record(ctx, enums.EntityPeerReview, assignment.ID, enums.ActionUpdate, before, settings)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (s *peerReviewService) DisablePeerReview(ctx context.Context, assignmentID, userID int64, isAdmin bool) error {
+	if _, err := s.requirePeerReviewAssignment(ctx, assignmentID, userID, isAdmin); err != nil {
+		return err
+	}
+	settings, err := s.settingsFor(ctx, assignmentID)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteSettings(ctx, assignmentID); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityPeerReview, assignmentID, enums.ActionDelete, settings, nil)
+	})
+}
+
+func (s *peerReviewService) AllocateReviews(ctx context.Context, assignmentID, userID int64, isAdmin bool) (*models.PeerReviewOverview, error) {
+	assignment, err := s.requirePeerReviewAssignment(ctx, assignmentID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsFor(ctx, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if now.Before(assignment.DueAt) {
+		return nil, appErrors.New(http.StatusConflict, "Reviews can be assigned once the assignment is due")
+	}
+	if !now.Before(settings.ReviewDueAt) {
+		return nil, appErrors.ErrReviewClosed
+	}
+
+	statuses, err := s.assignmentRepo.SubmissionStatuses(ctx, assignment)
+	if err != nil {
+		return nil, err
+	}
+	submissions := make([]models.Submission, 0, len(statuses))
+	for _, status := range statuses {
+		if status.Latest != nil {
+			submissions = append(submissions, *status.Latest)
+		}
+	}
+	if len(submissions) < 2 {
+		return nil, appErrors.New(http.StatusConflict, "At least two students must have handed in work for peer review")
+	}
+
+	reviews := allocateReviewers(submissions, settings.ReviewersPerSubmission)
+	if err := s.repo.Allocate(ctx, assignment.ID, reviews); err != nil {
+		return nil, err
+	}
+	return s.GetOverview(ctx, assignment.ID, userID, isAdmin)
+}
+
+func (s *peerReviewService) GetOverview(ctx context.Context, assignmentID, userID int64, isAdmin bool) (*models.PeerReviewOverview, error) {
+	assignment, err := s.requirePeerReviewAssignment(ctx, assignmentID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsFor(ctx, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	rubric, err := s.rubricFor(ctx, assignment)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.repo.ListForAssignment(ctx, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PeerReviewOverview{
+		Settings:  settings,
+		Rubric:    rubric,
+		Summaries: summarizePeerReviews(assignment, rubric, reviews),
+	}, nil
+}
+
+func (s *peerReviewService) AcceptScores(ctx context.Context, assignmentID int64, req *models.AcceptPeerScoresRequest, userID int64, isAdmin bool) ([]models.AssignmentGrade, error) {
+	overview, err := s.GetOverview(ctx, assignmentID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	// Accepting peer scores keeps the teacher's feedback and leaves excused students alone
+	existing, err := s.gradebookSvc.ListGrades(ctx, assignmentID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[int64]*models.AssignmentGrade, len(existing))
+	for i := range existing {
+		current[existing[i].StudentID] = &existing[i]
+	}
+	suggested := make(map[int64]*float64, len(overview.Summaries))
+	for _, summary := range overview.Summaries {
+		if summary.SuggestedGrade != nil {
+			suggested[summary.AuthorID] = summary.SuggestedGrade
+		}
+	}
+
+	studentIDs := req.StudentIDs
+	if len(studentIDs) == 0 {
+		for _, summary := range overview.Summaries {
+			if grade := current[summary.AuthorID]; summary.SuggestedGrade != nil && (grade == nil || !grade.Excused) {
+				studentIDs = append(studentIDs, summary.AuthorID)
+			}
+		}
+		if len(studentIDs) == 0 {
+			return nil, appErrors.New(http.StatusConflict, "No peer reviews with points have been handed in yet")
+		}
+	}
+
+	entries := make([]models.GradeEntry, 0, len(studentIDs))
+	for _, studentID := range studentIDs {
+		points := suggested[studentID]
+		if points == nil {
+			return nil, appErrors.New(http.StatusBadRequest, "Student %d has no peer reviews with points", studentID)
+		}
+		entry := models.GradeEntry{StudentID: studentID, Points: points}
+		if grade := current[studentID]; grade != nil && grade.Feedback != nil {
+			entry.Feedback = *grade.Feedback
+		}
+		entries = append(entries, entry)
+	}
+	return s.gradebookSvc.SetGrades(ctx, assignmentID, &models.SetGradesRequest{Grades: entries, Release: req.Release}, userID, isAdmin)
+}
+
+func (s *peerReviewService) ListOwnReviews(ctx context.Context, reviewerID int64) ([]models.ReviewTask, error) {
+	reviews, err := s.repo.ListForReviewer(ctx, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	assignments := make(map[int64]*models.Assignment)
+	settings := make(map[int64]*models.PeerReviewSettings)
+	tasks := make([]models.ReviewTask, 0, len(reviews))
+	for i := range reviews {
+		review := &reviews[i]
+		if assignments[review.AssignmentID] == nil {
+			assignment, err := s.assignmentRepo.GetAssignment(ctx, review.AssignmentID)
+			if err != nil {
+				return nil, err
+			}
+			assignmentSettings, err := s.settingsFor(ctx, review.AssignmentID)
+			if err != nil {
+				return nil, err
+			}
+			assignments[review.AssignmentID] = assignment
+			settings[review.AssignmentID] = assignmentSettings
+		}
+		tasks = append(tasks, reviewTask(review, assignments[review.AssignmentID], settings[review.AssignmentID]))
+	}
+	return tasks, nil
+}
+
+func (s *peerReviewService) GetOwnReview(ctx context.Context, id, reviewerID int64) (*models.ReviewTask, error) {
+	review, err := s.ownReview(ctx, id, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := s.assignmentRepo.GetAssignment(ctx, review.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsFor(ctx, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	rubric, err := s.rubricFor(ctx, assignment)
+	if err != nil {
+		return nil, err
+	}
+	submission, err := s.assignmentRepo.GetSubmission(ctx, review.SubmissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	task := reviewTask(review, assignment, settings)
+	task.Rubric = rubric
+	task.SubmissionNote = submission.Comment
+	task.Files = reviewFiles(submission.Files)
+	return &task, nil
+}
+
+func (s *peerReviewService) SubmitOwnReview(ctx context.Context, id, reviewerID int64, req *models.SubmitPeerReviewRequest) (*models.ReviewTask, error) {
+	review, err := s.ownReview(ctx, id, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	assignment, err := s.assignmentRepo.GetAssignment(ctx, review.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsFor(ctx, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !time.Now().Before(settings.ReviewDueAt) {
+		return nil, appErrors.ErrReviewClosed
+	}
+	rubric, err := s.rubricFor(ctx, assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Comment) > maxReviewComment {
+		return nil, appErrors.New(http.StatusBadRequest, "Comment must be at most %d characters", maxReviewComment)
+	}
+	review.Comment = optionalString(req.Comment)
+	if rubric != nil {
+		if req.Points != nil {
+			return nil, appErrors.New(http.StatusBadRequest, "Points come from the rubric; score its criteria instead")
+		}
+		scores, total, err := scoreRubric(rubric, req.Scores)
+		if err != nil {
+			return nil, err
+		}
+		review.Scores = peerReviewScores(scores)
+		review.Points = &total
+	} else {
+		if len(req.Scores) > 0 {
+			return nil, appErrors.New(http.StatusBadRequest, "Assignment has no rubric to score")
+		}
+		if review.Comment == nil {
+			return nil, appErrors.New(http.StatusBadRequest, "A comment is required")
+		}
+		review.Scores = nil
+		review.Points = nil
+		if req.Points != nil {
+			points := roundPoints(*req.Points)
+			if points < 0 || points > assignment.MaxPoints {
+				return nil, appErrors.New(http.StatusBadRequest, "Points must be between 0 and %s", strconv.FormatFloat(assignment.MaxPoints, 'f', -1, 64))
+			}
+			review.Points = &points
+		}
+	}
+
+	if err := s.repo.SubmitReview(ctx, review, assignment.RubricID); err != nil {
+		return nil, err
+	}
+	return s.GetOwnReview(ctx, review.ID, reviewerID)
+}
+
+func (s *peerReviewService) OpenReviewFile(ctx context.Context, id int64, position int, reviewerID int64) (*models.ReviewFile, io.ReadCloser, error) {
+	review, err := s.ownReview(ctx, id, reviewerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	submission, err := s.assignmentRepo.GetSubmission(ctx, review.SubmissionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if position < 1 || position > len(submission.Files) {
+		return nil, nil, appErrors.ErrNotFound
+	}
+	body, err := s.fileSvc.Open(ctx, &submission.Files[position-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	file := reviewFiles(submission.Files)[position-1]
+	return &file, body, nil
+}
+
+func (s *peerReviewService) GetOwnPeerFeedback(ctx context.Context, assignmentID, authorID int64) (*models.PeerFeedback, error) {
+	assignment, err := s.assignmentRepo.GetAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsFor(ctx, assignment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().Before(settings.ReviewDueAt) {
+		return nil, appErrors.New(http.StatusNotFound, "Peer feedback is shared once the review deadline has passed")
+	}
+	rubric, err := s.rubricFor(ctx, assignment)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.repo.ListReceived(ctx, assignment.ID, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	feedback := &models.PeerFeedback{
+		AssignmentID:  assignment.ID,
+		Rubric:        rubric,
+		Reviews:       make([]models.ReceivedReview, 0, len(reviews)),
+		AveragePoints: averagePoints(reviews),
+	}
+	for _, review := range reviews {
+		feedback.Reviews = append(feedback.Reviews, models.ReceivedReview{
+			Points:      review.Points,
+			Comment:     review.Comment,
+			Scores:      review.Scores,
+			SubmittedAt: *review.SubmittedAt,
+		})
+	}
+	return feedback, nil
+}
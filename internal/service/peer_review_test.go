@@ -0,0 +1,70 @@
+// internal/service/peer_review_test.go
+package service
+
+import (
+	"testing"
+
+	"student-portal/internal/models"
+)
+
+func TestAllocateReviewers(t *testing.T) {
+	tests := []struct {
+		name          string
+		authors       int
+		perSubmission int
+		wantPer       int // Reviews per submission and per reviewer
+	}{
+		{"no submissions", 0, 3, 0},
+		{"single submission", 1, 3, 0},
+		{"two authors", 2, 1, 1},
+		{"capped at the other authors", 3, 5, 2},
+		{"one each", 5, 1, 1},
+		{"three each", 12, 3, 3},
+		{"everyone reviews everyone", 6, 5, 5},
+		{"peer review turned off", 4, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submissions := make([]models.Submission, 0, tt.authors)
+			for i := range tt.authors {
+				submissions = append(submissions, models.Submission{ID: int64(100 + i), AssignmentID: 1, StudentID: int64(i + 1)})
+			}
+
+			// The ring is shuffled, so check the properties over many allocations
+			for range 50 {
+				reviews := allocateReviewers(submissions, tt.perSubmission)
+				if len(reviews) != tt.authors*tt.wantPer {
+					t.Fatalf("allocated %d reviews, want %d", len(reviews), tt.authors*tt.wantPer)
+				}
+
+				perSubmission := make(map[int64]int)
+				perReviewer := make(map[int64]int)
+				pairs := make(map[[2]int64]bool)
+				for _, review := range reviews {
+					if review.ReviewerID == review.AuthorID {
+						t.Fatalf("student %d reviews their own submission", review.ReviewerID)
+					}
+					if review.SubmissionID != review.AuthorID+99 {
+						t.Fatalf("submission %d attributed to student %d", review.SubmissionID, review.AuthorID)
+					}
+					pair := [2]int64{review.SubmissionID, review.ReviewerID}
+					if pairs[pair] {
+						t.Fatalf("student %d reviews submission %d twice", review.ReviewerID, review.SubmissionID)
+					}
+					pairs[pair] = true
+					perSubmission[review.SubmissionID]++
+					perReviewer[review.ReviewerID]++
+				}
+				for _, submission := range submissions {
+					if perSubmission[submission.ID] != tt.wantPer {
+						t.Fatalf("submission %d has %d reviewers, want %d", submission.ID, perSubmission[submission.ID], tt.wantPer)
+					}
+					if perReviewer[submission.StudentID] != tt.wantPer {
+						t.Fatalf("student %d has %d reviews, want %d", submission.StudentID, perReviewer[submission.StudentID], tt.wantPer)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+// internal/service/privacy_service.go
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/commons/logger"
+	"student-portal/internal/config"
+	kafka "student-portal/internal/kafka"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// PrivacyService defines the methods for personal data export and erasure (GDPR/FERPA).
+type PrivacyService interface {
+	RequestExport(ctx context.Context, userID, requestedBy int64) (*models.DataExportJobResponse, error)
+	GetExport(ctx context.Context, userID, jobID int64) (*models.DataExportJobResponse, error)
+	ListExports(ctx context.Context, userID int64) ([]models.DataExportJob, error)
+	// ExpireStaleExports fails export jobs left pending or running by a process that stopped
+	// mid-export, so that they no longer block new exports.
+	ExpireStaleExports(ctx context.Context) error
+	EraseUser(ctx context.Context, userID, performedBy int64, req *models.EraseUserRequest) (*models.UserErasure, error)
+}
+
+// erasableFilePurposes are the uploads removed on erasure. Files attached to academic
+// records that must be retained by law use other purposes and are kept.
+var erasableFilePurposes = []string{
+	string(enums.FilePurposeAvatar),
+	string(enums.FilePurposeDocument),
+	string(enums.FilePurposeExport),
+}
+
+// exportTimeout bounds how long a single export job may run.
+const exportTimeout = 5 * time.Minute
+
+// staleExportAge is how long after it was requested a job still pending or running is taken to
+// have been abandoned; runExport gives up well before that.
+const staleExportAge = 2 * exportTimeout
+
+type privacyService struct {
+	repo       repository.PrivacyRepository
+	transactor repository.Transactor
+	fileSvc    FileService
+	historySvc HistoryService
+	cfg        *config.Config
+	kafka      *kafka.KafkaProducer
+}
+
+// NewPrivacyService creates a new PrivacyService instance.
+func NewPrivacyService(repo repository.PrivacyRepository, transactor repository.Transactor, fileSvc FileService, historySvc HistoryService, cfg *config.Config, kafka *kafka.KafkaProducer) PrivacyService {
+	return &privacyService{repo: repo, transactor: transactor, fileSvc: fileSvc, historySvc: historySvc, cfg: cfg, kafka: kafka}
+}
+
+func (s *privacyService) RequestExport(ctx context.Context, userID, requestedBy int64) (*models.DataExportJobResponse, error) {
+	if err := s.ExpireStaleExports(ctx); err != nil {
+		return nil, err
+	}
+
+	// The repository fails with ErrExportInProgress while the user has an active job.
+	job := &models.DataExportJob{
+		UserID:      userID,
+		RequestedBy: &requestedBy,
+		Status:      string(enums.ExportStatusPending),
+	}
+	if err := s.repo.CreateExportJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// Build the archive in the background; the client polls GetExport for the result.
+	go s.runExport(*job)
+
+	return &models.DataExportJobResponse{DataExportJob: *job}, nil
+}
+
+func (s *privacyService) ExpireStaleExports(ctx context.Context) error {
+	expired, err := s.repo.ExpireExportJobs(ctx, time.Now().Add(-staleExportAge), "export abandoned")
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		logger.Logger.Warn("Expired abandoned data exports", zap.Int64("count", expired))
+	}
+	return nil
+}
+
+// runExport gathers the user's data into a ZIP of JSON files and stores it.
+func (s *privacyService) runExport(job models.DataExportJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	fail := func(err error) {
+		logger.Logger.Error("Data export failed", zap.Error(err), zap.Int64("job_id", job.ID), zap.Int64("user_id", job.UserID))
+		message := "export failed"
+		now := time.Now()
+		job.Status, job.Error, job.CompletedAt = string(enums.ExportStatusFailed), &message, &now
+		if err := s.repo.UpdateExportJob(ctx, &job); err != nil {
+			logger.Logger.Error("Failed to mark data export as failed", zap.Error(err), zap.Int64("job_id", job.ID))
+		}
+	}
+
+	job.Status = string(enums.ExportStatusRunning)
+	if err := s.repo.UpdateExportJob(ctx, &job); err != nil {
+		fail(err)
+		return
+	}
+
+	data, err := s.repo.ExportUserData(ctx, job.UserID)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	archive, err := buildExportArchive(job.UserID, data)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	filename := fmt.Sprintf("personal-data-%d-%s.zip", job.UserID, time.Now().UTC().Format("20060102"))
+	file, err := s.fileSvc.StoreGenerated(ctx, job.UserID, enums.FilePurposeExport, filename, "application/zip", archive)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	now := time.Now()
+	job.Status, job.FileID, job.CompletedAt = string(enums.ExportStatusCompleted), &file.ID, &now
+	if err := s.repo.UpdateExportJob(ctx, &job); err != nil {
+		fail(err)
+		return
+	}
+
+	requestedBy := job.UserID
+	if job.RequestedBy != nil {
+		requestedBy = *job.RequestedBy
+	}
+	publishAsync(
+		func(ctx context.Context) error {
+			return s.kafka.PublishDataExportedEvent(ctx, job.UserID, requestedBy, job.ID)
+		},
+		"user_data_exported",
+		zap.Int64("user_id", job.UserID),
+		zap.Int64("job_id", job.ID),
+	)
+}
+
+// buildExportArchive writes one pretty-printed JSON file per export source plus a manifest.
+func buildExportArchive(userID int64, data map[string][]json.RawMessage) ([]byte, error) {
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	manifest := map[string]interface{}{
+		"user_id":      userID,
+		"generated_at": time.Now().UTC(),
+		"files":        names,
+	}
+	if err := writeZipJSON(zw, "manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if err := writeZipJSON(zw, name, data[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeZipJSON(zw *zip.Writer, name string, value interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func (s *privacyService) GetExport(ctx context.Context, userID, jobID int64) (*models.DataExportJobResponse, error) {
+	job, err := s.repo.GetExportJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	// Do not reveal other users' jobs.
+	if job.UserID != userID {
+		return nil, appErrors.ErrNotFound
+	}
+
+	resp := &models.DataExportJobResponse{DataExportJob: *job}
+	if job.Status == string(enums.ExportStatusCompleted) && job.FileID != nil {
+		file, err := s.fileSvc.GetFile(ctx, *job.FileID)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Download, err = s.fileSvc.SignedURL(ctx, file); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (s *privacyService) ListExports(ctx context.Context, userID int64) ([]models.DataExportJob, error) {
+	return s.repo.ListExportJobs(ctx, userID)
+}
+
+func (s *privacyService) EraseUser(ctx context.Context, userID, performedBy int64, req *models.EraseUserRequest) (*models.UserErasure, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, appErrors.New(http.StatusBadRequest, "A reason is required to erase a user")
+	}
+	if userID == performedBy {
+		return nil, appErrors.New(http.StatusBadRequest, "You cannot erase your own account")
+	}
+
+	erasure := &models.UserErasure{
+		UserID:      userID,
+		PerformedBy: &performedBy,
+		Reason:      reason,
+	}
+	var files []models.File
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if files, err = s.repo.EraseUser(ctx, erasure, erasableFilePurposes); err != nil {
+			return err
+		}
+		// The entry only notes that an erasure happened; it must not carry the erased data.
+		return s.historySvc.Record(ctx, enums.EntityUser, userID, enums.ActionErase, nil, map[string]interface{}{"erasure_id": erasure.ID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Blobs are removed only after the transaction committed.
+	s.fileSvc.DeleteBlobs(ctx, files)
+
+	publishAsync(
+		func(ctx context.Context) error {
+			return s.kafka.PublishUserErasedEvent(ctx, userID, performedBy, reason)
+		},
+		"user_erased",
+		zap.Int64("user_id", userID),
+	)
+
+	return erasure, nil
+}
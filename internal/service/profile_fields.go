@@ -0,0 +1,329 @@
+// internal/service/profile_fields.go
+package service
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+)
+
+// Field names used for field-level permissions on user and profile updates.
+const (
+	fieldName           = "name"
+	fieldEmail          = "email"
+	fieldRole           = "role"
+	fieldPhone          = "phone"
+	fieldDateOfBirth    = "date_of_birth"
+	fieldAddress        = "address"
+	fieldStudentNumber  = "student_number"
+	fieldProgram        = "program"
+	fieldEnrollmentYear = "enrollment_year"
+	fieldCustomFields   = "custom_fields"
+)
+
+// selfEditableFields lists the fields each role may change on its own profile.
+// Roles not listed here fall back to the student permissions.
+var selfEditableFields = map[string]map[string]bool{
+	string(enums.RoleStudent): {
+		fieldName: true, fieldEmail: true, fieldPhone: true, fieldDateOfBirth: true,
+		fieldAddress: true, fieldCustomFields: true,
+	},
+	string(enums.RoleAdmin): {
+		fieldName: true, fieldEmail: true, fieldPhone: true, fieldDateOfBirth: true, fieldAddress: true,
+		fieldStudentNumber: true, fieldProgram: true, fieldEnrollmentYear: true, fieldCustomFields: true,
+	},
+	string(enums.RoleGuardian): {
+		fieldName: true, fieldEmail: true, fieldPhone: true, fieldAddress: true,
+	},
+	string(enums.RoleTeacher): {
+		fieldName: true, fieldEmail: true, fieldPhone: true, fieldDateOfBirth: true, fieldAddress: true,
+	},
+}
+
+// validRoles are the roles a user can be given.
+var validRoles = map[string]bool{
+	string(enums.RoleStudent): true, string(enums.RoleAdmin): true, string(enums.RoleGuardian): true,
+	string(enums.RoleTeacher): true,
+}
+
+var (
+	phonePattern    = regexp.MustCompile(`^\+?[0-9 ()\-]{5,20}$`)
+	fieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+)
+
+// userChanges is the role-independent form of UpdateProfileRequest and UpdateUserRequest.
+type userChanges struct {
+	Name           *string
+	Email          *string
+	Role           *string
+	Phone          *string
+	DateOfBirth    *string
+	Address        *string
+	StudentNumber  *string
+	Program        *string
+	EnrollmentYear *int
+	CustomFields   map[string]interface{}
+}
+
+func changesFromProfileRequest(req *models.UpdateProfileRequest) *userChanges {
+	return &userChanges{
+		Name: req.Name, Email: req.Email, Phone: req.Phone, DateOfBirth: req.DateOfBirth, Address: req.Address,
+		StudentNumber: req.StudentNumber, Program: req.Program, EnrollmentYear: req.EnrollmentYear,
+		CustomFields: req.CustomFields,
+	}
+}
+
+func changesFromUserRequest(req *models.UpdateUserRequest) *userChanges {
+	return &userChanges{
+		Name: req.Name, Email: req.Email, Role: req.Role, Phone: req.Phone, DateOfBirth: req.DateOfBirth,
+		Address: req.Address, StudentNumber: req.StudentNumber, Program: req.Program,
+		EnrollmentYear: req.EnrollmentYear, CustomFields: req.CustomFields,
+	}
+}
+
+// fields returns the names of the fields present in the change set.
+func (c *userChanges) fields() []string {
+	present := map[string]bool{
+		fieldName: c.Name != nil, fieldEmail: c.Email != nil, fieldRole: c.Role != nil,
+		fieldPhone: c.Phone != nil, fieldDateOfBirth: c.DateOfBirth != nil, fieldAddress: c.Address != nil,
+		fieldStudentNumber: c.StudentNumber != nil, fieldProgram: c.Program != nil,
+		fieldEnrollmentYear: c.EnrollmentYear != nil, fieldCustomFields: c.CustomFields != nil,
+	}
+
+	fields := make([]string, 0)
+	for _, f := range []string{
+		fieldName, fieldEmail, fieldRole, fieldPhone, fieldDateOfBirth, fieldAddress,
+		fieldStudentNumber, fieldProgram, fieldEnrollmentYear, fieldCustomFields,
+	} {
+		if present[f] {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// touchesProfile reports whether any user_profiles column is part of the change set.
+func (c *userChanges) touchesProfile() bool {
+	return c.Phone != nil || c.DateOfBirth != nil || c.Address != nil || c.StudentNumber != nil ||
+		c.Program != nil || c.EnrollmentYear != nil || c.CustomFields != nil
+}
+
+// checkSelfEditable rejects changes to fields the given role may not edit on its own profile.
+func checkSelfEditable(role string, changes *userChanges) error {
+	allowed, ok := selfEditableFields[role]
+	if !ok {
+		allowed = selfEditableFields[string(enums.RoleStudent)]
+	}
+
+	for _, f := range changes.fields() {
+		if !allowed[f] {
+			return appErrors.New(http.StatusForbidden, "You are not allowed to change '%s'", f)
+		}
+	}
+	return nil
+}
+
+// applyChanges validates the change set and applies it to the user and profile models.
+// When selfService is true, custom fields that are not student-editable are rejected for students.
+func applyChanges(user *models.User, profile *models.Profile, changes *userChanges, defs []models.ProfileFieldDefinition, selfService bool) error {
+	if changes.Name != nil {
+		name := strings.TrimSpace(*changes.Name)
+		if name == "" {
+			return appErrors.New(http.StatusBadRequest, "Name cannot be empty")
+		}
+		user.Name = name
+	}
+	if changes.Email != nil {
+		email := strings.TrimSpace(*changes.Email)
+		if !strings.Contains(email, "@") {
+			return appErrors.New(http.StatusBadRequest, "Invalid email address")
+		}
+		user.Email = email
+	}
+	if changes.Role != nil {
+		if !validRoles[*changes.Role] {
+			return appErrors.New(http.StatusBadRequest, "Invalid role '%s'", *changes.Role)
+		}
+		user.Role = *changes.Role
+	}
+
+	if changes.Phone != nil {
+		phone := optionalString(*changes.Phone)
+		if phone != nil && !phonePattern.MatchString(*phone) {
+			return appErrors.New(http.StatusBadRequest, "Invalid phone number")
+		}
+		profile.Phone = phone
+	}
+	if changes.DateOfBirth != nil {
+		dob, err := parseDateOfBirth(*changes.DateOfBirth)
+		if err != nil {
+			return err
+		}
+		profile.DateOfBirth = dob
+	}
+	if changes.Address != nil {
+		profile.Address = optionalString(*changes.Address)
+	}
+	if changes.StudentNumber != nil {
+		profile.StudentNumber = optionalString(*changes.StudentNumber)
+	}
+	if changes.Program != nil {
+		profile.Program = optionalString(*changes.Program)
+	}
+	if changes.EnrollmentYear != nil {
+		year := *changes.EnrollmentYear
+		if year < 1900 || year > 2200 {
+			return appErrors.New(http.StatusBadRequest, "Invalid enrollment_year")
+		}
+		profile.EnrollmentYear = &year
+	}
+	restrict := selfService && user.Role == string(enums.RoleStudent)
+	if changes.CustomFields != nil {
+		return applyCustomFields(profile, changes.CustomFields, defs, restrict)
+	}
+	if changes.touchesProfile() {
+		return checkRequiredFields(profile.CustomFields, defs, restrict)
+	}
+	return nil
+}
+
+// applyCustomFields merges the given values into the profile's custom fields,
+// validating each value against its admin-defined field definition and checking that
+// the required fields still have a value afterwards.
+func applyCustomFields(profile *models.Profile, values map[string]interface{}, defs []models.ProfileFieldDefinition, studentEditableOnly bool) error {
+	byKey := make(map[string]models.ProfileFieldDefinition, len(defs))
+	for _, def := range defs {
+		byKey[def.Key] = def
+	}
+
+	if profile.CustomFields == nil {
+		profile.CustomFields = map[string]interface{}{}
+	}
+
+	for key, value := range values {
+		def, ok := byKey[key]
+		if !ok {
+			return appErrors.New(http.StatusBadRequest, "Unknown custom field '%s'", key)
+		}
+		if studentEditableOnly && !def.StudentEditable {
+			return appErrors.New(http.StatusForbidden, "You are not allowed to change custom field '%s'", key)
+		}
+
+		if value == nil {
+			delete(profile.CustomFields, key)
+			continue
+		}
+
+		if !validCustomFieldValue(def, value) {
+			return appErrors.New(http.StatusBadRequest, "Invalid value for custom field '%s' (expected %s)", key, def.FieldType)
+		}
+		profile.CustomFields[key] = value
+	}
+	return checkRequiredFields(profile.CustomFields, defs, studentEditableOnly)
+}
+
+// checkRequiredFields rejects custom fields lacking a value for a required field. Students
+// editing their own profile are only held to the fields they may set themselves.
+func checkRequiredFields(values map[string]interface{}, defs []models.ProfileFieldDefinition, studentEditableOnly bool) error {
+	for _, def := range defs {
+		if !def.Required || (studentEditableOnly && !def.StudentEditable) {
+			continue
+		}
+		if values[def.Key] == nil {
+			return appErrors.New(http.StatusBadRequest, "Custom field '%s' is required", def.Key)
+		}
+	}
+	return nil
+}
+
+func validCustomFieldValue(def models.ProfileFieldDefinition, value interface{}) bool {
+	switch def.FieldType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "date":
+		str, ok := value.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(models.DateLayout, str)
+		return err == nil
+	case "select":
+		str, ok := value.(string)
+		if !ok {
+			return false
+		}
+		for _, option := range def.Options {
+			if option == str {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// validateFieldDefinition checks an admin-submitted custom field definition.
+func validateFieldDefinition(req *models.CreateProfileFieldRequest) error {
+	if !fieldKeyPattern.MatchString(req.Key) {
+		return appErrors.New(http.StatusBadRequest, "Field key must be lower_snake_case and at most 64 characters")
+	}
+	if strings.TrimSpace(req.Label) == "" {
+		return appErrors.New(http.StatusBadRequest, "Field label is required")
+	}
+	switch req.FieldType {
+	case "string", "number", "boolean", "date":
+	case "select":
+		if len(req.Options) == 0 {
+			return appErrors.New(http.StatusBadRequest, "Select fields require at least one option")
+		}
+	default:
+		return appErrors.New(http.StatusBadRequest, "Invalid field_type '%s'", req.FieldType)
+	}
+	return nil
+}
+
+// optionalString trims the value and maps the empty string to nil (cleared).
+func optionalString(value string) *string {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// parseDateOfBirth validates a date of birth given as YYYY-MM-DD, mapping the empty string to
+// nil (cleared). PUT and PATCH requests both validate it here.
+func parseDateOfBirth(value string) (*time.Time, error) {
+	dob, err := parseOptionalDate(value)
+	if err != nil {
+		return nil, appErrors.New(http.StatusBadRequest, "Invalid date_of_birth, expected YYYY-MM-DD")
+	}
+	if dob != nil && dob.After(time.Now()) {
+		return nil, appErrors.New(http.StatusBadRequest, "date_of_birth cannot be in the future")
+	}
+	return dob, nil
+}
+
+// parseOptionalDate parses a YYYY-MM-DD date, mapping the empty string to nil (cleared).
+func parseOptionalDate(value string) (*time.Time, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(models.DateLayout, strings.TrimSpace(value))
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,1025 @@
+// internal/service/qti.go
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+)
+
+// QTI versions question banks are exported as; imports also read QTI 2.0 and 2.2 items.
+const (
+	qtiVersion21 = "2.1"
+	qtiVersion30 = "3.0"
+)
+
+// Limits on imported QTI packages, on top of the upload size limit.
+const (
+	maxQTIItems       = 2000
+	maxQTIFileSize    = 2 << 20  // One XML file of a package
+	maxQTIPackageSize = 64 << 20 // All XML files read from a package, uncompressed
+)
+
+// qtiToolName marks the items the portal exported. Their text is taken as is on import, so
+// that exporting and importing a bank gives back the same questions.
+const qtiToolName = "student-portal"
+
+// Namespaces and resource types of the QTI versions the portal exports.
+var qtiFormats = map[string]struct {
+	itemNamespace, itemSchema         string
+	manifestNamespace, manifestSchema string
+	schema, schemaVersion             string
+	resourceType                      string
+}{
+	qtiVersion21: {
+		itemNamespace:     "http://www.imsglobal.org/xsd/imsqti_v2p1",
+		itemSchema:        "http://www.imsglobal.org/xsd/qti/qtiv2p1/imsqti_v2p1p2.xsd",
+		manifestNamespace: "http://www.imsglobal.org/xsd/imscp_v1p1",
+		manifestSchema:    "http://www.imsglobal.org/xsd/qti/qtiv2p1/qtiv2p1_imscpv1p2_v1p0.xsd",
+		schema:            "QTIv2.1 Package",
+		schemaVersion:     "1.0.0",
+		resourceType:      "imsqti_item_xmlv2p1",
+	},
+	qtiVersion30: {
+		itemNamespace:     "http://www.imsglobal.org/xsd/imsqtiasi_v3p0",
+		itemSchema:        "https://purl.imsglobal.org/spec/qti/v3p0/schema/xsd/imsqti_asiv3p0_v1p0.xsd",
+		manifestNamespace: "http://www.imsglobal.org/xsd/qti/qtiv3p0/imscp_v1p1",
+		manifestSchema:    "https://purl.imsglobal.org/spec/qti/v3p0/schema/xsd/imsqtiv3p0_imscpv1p2_v1p0.xsd",
+		schema:            "QTI Package",
+		schemaVersion:     "3.0.0",
+		resourceType:      "imsqti_item_xmlv3p0",
+	},
+}
+
+// qtiNode is an element of a QTI document, or a text node when Name is empty. Names of
+// elements and attributes are kept in their QTI 2.x form: QTI 3.0's "qti-choice-interaction"
+// and "response-identifier" read as "choiceInteraction" and "responseIdentifier".
+type qtiNode struct {
+	Name     string
+	Attrs    []xml.Attr
+	Text     string
+	Children []*qtiNode
+	HTML     bool // An XHTML element, which QTI 3.0 writes without the "qti-" prefix
+}
+
+// qtiName turns a QTI 3.0 element or attribute name into its QTI 2.x form.
+func qtiName(name string) string {
+	name = strings.TrimPrefix(name, "qti-")
+	parts := strings.Split(name, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// qtiKebab turns a QTI 2.x name into the QTI 3.0 spelling, without the element prefix.
+func qtiKebab(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsUpper(r) {
+			b.WriteByte('-')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (n *qtiNode) attr(name string) string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// child returns the first child element with the name.
+func (n *qtiNode) child(name string) *qtiNode {
+	if n == nil {
+		return nil
+	}
+	for _, c := range n.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// findAll returns the descendant elements the match accepts, in document order.
+func (n *qtiNode) findAll(match func(*qtiNode) bool) []*qtiNode {
+	var found []*qtiNode
+	if n == nil {
+		return found
+	}
+	for _, c := range n.Children {
+		if c.Name == "" {
+			continue
+		}
+		if match(c) {
+			found = append(found, c)
+		}
+		found = append(found, c.findAll(match)...)
+	}
+	return found
+}
+
+// find returns the first descendant element with the name.
+func (n *qtiNode) find(name string) *qtiNode {
+	found := n.findAll(func(c *qtiNode) bool { return c.Name == name })
+	if len(found) == 0 {
+		return nil
+	}
+	return found[0]
+}
+
+// contains reports whether other is n or one of its descendants.
+func (n *qtiNode) contains(other *qtiNode) bool {
+	if n == other {
+		return true
+	}
+	for _, c := range n.Children {
+		if c.contains(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// text returns all the text within n as is.
+func (n *qtiNode) text() string {
+	if n == nil {
+		return ""
+	}
+	if n.Name == "" {
+		return n.Text
+	}
+	var b strings.Builder
+	for _, c := range n.Children {
+		b.WriteString(c.text())
+	}
+	return b.String()
+}
+
+// qtiElement builds an element; attrs are name and value pairs.
+func qtiElement(name string, attrs []string, children ...*qtiNode) *qtiNode {
+	node := &qtiNode{Name: name, Children: children}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		node.Attrs = append(node.Attrs, xml.Attr{Name: xml.Name{Local: attrs[i]}, Value: attrs[i+1]})
+	}
+	return node
+}
+
+func qtiTextNode(text string) *qtiNode {
+	return &qtiNode{Text: text}
+}
+
+// parseQTIXML reads a QTI document or manifest. version is the QTI version the root element
+// is written in.
+func parseQTIXML(data []byte) (root *qtiNode, version string, err error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Entity = xml.HTMLEntity // Exports of other tools often use &nbsp; and the like
+	var stack []*qtiNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			node := &qtiNode{Name: qtiName(t.Name.Local)}
+			for _, a := range t.Attr {
+				if a.Name.Space == "xmlns" || a.Name.Local == "xmlns" {
+					continue
+				}
+				node.Attrs = append(node.Attrs, xml.Attr{Name: xml.Name{Local: qtiName(a.Name.Local)}, Value: a.Value})
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+			} else {
+				root = node
+				version = qtiRootVersion(t.Name)
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, qtiTextNode(string(t)))
+			}
+		}
+	}
+	if root == nil {
+		return nil, "", errors.New("document is empty")
+	}
+	return root, version, nil
+}
+
+// qtiRootVersion tells the QTI version from the root element of a document.
+func qtiRootVersion(name xml.Name) string {
+	switch {
+	case strings.HasPrefix(name.Local, "qti-"):
+		return qtiVersion30
+	case name.Local == "questestinterop":
+		return "1.2"
+	case strings.Contains(name.Space, "imsqti_v2p0"):
+		return "2.0"
+	case strings.Contains(name.Space, "imsqti_v2p2"):
+		return "2.2"
+	}
+	return qtiVersion21
+}
+
+// renderQTIXML writes the document; QTI 3.0 spells the names of QTI elements and attributes
+// in kebab case, with elements prefixed by "qti-".
+func renderQTIXML(root *qtiNode, v3 bool) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encodeQTINode(encoder, root, v3); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func encodeQTINode(encoder *xml.Encoder, node *qtiNode, v3 bool) error {
+	if node.Name == "" {
+		return encoder.EncodeToken(xml.CharData(node.Text))
+	}
+	start := xml.StartElement{Name: xml.Name{Local: node.Name}}
+	if v3 && !node.HTML {
+		start.Name.Local = "qti-" + qtiKebab(node.Name)
+	}
+	for _, a := range node.Attrs {
+		if v3 && !node.HTML && !strings.Contains(a.Name.Local, ":") {
+			a.Name.Local = qtiKebab(a.Name.Local)
+		}
+		start.Attr = append(start.Attr, a)
+	}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, c := range node.Children {
+		if err := encodeQTINode(encoder, c, v3); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// formatQTINumber writes a number so that it reads back exactly.
+func formatQTINumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// qtiChoiceID is the identifier of an option in exported items.
+func qtiChoiceID(optionID int64) string {
+	return "choice_" + strconv.FormatInt(optionID, 10)
+}
+
+// qtiTitle shortens the prompt into an item title.
+func qtiTitle(prompt string) string {
+	title := []rune(strings.Join(strings.Fields(prompt), " "))
+	if len(title) > 80 {
+		return string(title[:79]) + "…"
+	}
+	return string(title)
+}
+
+// qtiItem converts a question into an assessment item. Option questions become choice
+// interactions, true/false ones with the choices "true" and "false"; numeric questions a
+// float text entry compared within the tolerance; short answers an extended text entry
+// without a key. Multi-select questions map each option to its share of the points, which is
+// how the portal gives partial credit.
+func qtiItem(question *models.Question, version string) *qtiNode {
+	format := qtiFormats[version]
+	points := formatQTINumber(question.Points)
+
+	cardinality, baseType := "single", "identifier"
+	var correct []string
+	var interaction, mapping, processing *qtiNode
+	// Full points when the condition holds, none otherwise
+	allOrNothing := func(condition *qtiNode) *qtiNode {
+		return qtiElement("responseProcessing", nil,
+			qtiElement("responseCondition", nil,
+				qtiElement("responseIf", nil, condition,
+					qtiElement("setOutcomeValue", []string{"identifier", "SCORE"}, qtiElement("variable", []string{"identifier", "MAXSCORE"})),
+				),
+				qtiElement("responseElse", nil,
+					qtiElement("setOutcomeValue", []string{"identifier", "SCORE"}, qtiElement("baseValue", []string{"baseType", "float"}, qtiTextNode("0"))),
+				),
+			),
+		)
+	}
+	matchCorrect := func(name string, attrs ...string) *qtiNode {
+		return qtiElement(name, attrs, qtiElement("variable", []string{"identifier", "RESPONSE"}), qtiElement("correct", []string{"identifier", "RESPONSE"}))
+	}
+
+	switch question.Type {
+	case string(enums.QuestionTypeMultipleChoice), string(enums.QuestionTypeMultiSelect):
+		maxChoices := "1"
+		for _, id := range question.CorrectOptions {
+			correct = append(correct, qtiChoiceID(id))
+		}
+		if question.Type == string(enums.QuestionTypeMultiSelect) {
+			maxChoices = "0"
+			cardinality = "multiple"
+			share := question.Points / float64(len(question.CorrectOptions))
+			mapping = qtiElement("mapping", []string{"lowerBound", "0", "upperBound", points, "defaultValue", "0"})
+			for _, option := range question.Options {
+				value := -share
+				if slices.Contains(question.CorrectOptions, option.ID) {
+					value = share
+				}
+				mapping.Children = append(mapping.Children, qtiElement("mapEntry", []string{"mapKey", qtiChoiceID(option.ID), "mappedValue", formatQTINumber(value)}))
+			}
+			processing = qtiElement("responseProcessing", nil,
+				qtiElement("setOutcomeValue", []string{"identifier", "SCORE"}, qtiElement("mapResponse", []string{"identifier", "RESPONSE"})),
+			)
+		} else {
+			processing = allOrNothing(matchCorrect("match"))
+		}
+		interaction = qtiElement("choiceInteraction", []string{"responseIdentifier", "RESPONSE", "shuffle", "true", "maxChoices", maxChoices})
+		for _, option := range question.Options {
+			interaction.Children = append(interaction.Children, qtiElement("simpleChoice", []string{"identifier", qtiChoiceID(option.ID)}, qtiTextNode(option.Text)))
+		}
+	case string(enums.QuestionTypeTrueFalse):
+		correct = []string{strconv.FormatBool(question.CorrectBool != nil && *question.CorrectBool)}
+		processing = allOrNothing(matchCorrect("match"))
+		interaction = qtiElement("choiceInteraction", []string{"responseIdentifier", "RESPONSE", "shuffle", "false", "maxChoices", "1"},
+			qtiElement("simpleChoice", []string{"identifier", "true"}, qtiTextNode("True")),
+			qtiElement("simpleChoice", []string{"identifier", "false"}, qtiTextNode("False")),
+		)
+	case string(enums.QuestionTypeNumeric):
+		baseType = "float"
+		if question.NumericAnswer != nil {
+			correct = []string{formatQTINumber(*question.NumericAnswer)}
+		}
+		condition := matchCorrect("equal", "toleranceMode", "exact")
+		if question.Tolerance > 0 {
+			tolerance := formatQTINumber(question.Tolerance)
+			condition = matchCorrect("equal", "toleranceMode", "absolute", "tolerance", tolerance+" "+tolerance)
+		}
+		processing = allOrNothing(condition)
+		// Text entries are inline, so they sit in a paragraph of their own
+		interaction = qtiElement("textEntryInteraction", []string{"responseIdentifier", "RESPONSE"})
+		interaction = &qtiNode{Name: "p", HTML: true, Children: []*qtiNode{interaction}}
+	default:
+		baseType = "string"
+		interaction = qtiElement("extendedTextInteraction", []string{"responseIdentifier", "RESPONSE"})
+	}
+
+	declaration := qtiElement("responseDeclaration", []string{"identifier", "RESPONSE", "cardinality", cardinality, "baseType", baseType})
+	if len(correct) > 0 {
+		response := qtiElement("correctResponse", nil)
+		for _, value := range correct {
+			response.Children = append(response.Children, qtiElement("value", nil, qtiTextNode(value)))
+		}
+		declaration.Children = append(declaration.Children, response)
+	}
+	if mapping != nil {
+		declaration.Children = append(declaration.Children, mapping)
+	}
+
+	body := qtiElement("itemBody", nil)
+	for _, line := range strings.Split(question.Prompt, "\n") {
+		body.Children = append(body.Children, &qtiNode{Name: "p", HTML: true, Children: []*qtiNode{qtiTextNode(line)}})
+	}
+	body.Children = append(body.Children, interaction)
+
+	item := qtiElement("assessmentItem", []string{
+		"xmlns", format.itemNamespace,
+		"xmlns:xsi", "http://www.w3.org/2001/XMLSchema-instance",
+		"xsi:schemaLocation", format.itemNamespace + " " + format.itemSchema,
+		"identifier", fmt.Sprintf("q%d", question.ID),
+		"title", qtiTitle(question.Prompt),
+		"adaptive", "false",
+		"timeDependent", "false",
+		"toolName", qtiToolName,
+		"toolVersion", "1.0",
+	},
+		declaration,
+		qtiElement("outcomeDeclaration", []string{"identifier", "SCORE", "cardinality", "single", "baseType", "float", "normalMaximum", points},
+			qtiElement("defaultValue", nil, qtiElement("value", nil, qtiTextNode("0"))),
+		),
+		qtiElement("outcomeDeclaration", []string{"identifier", "MAXSCORE", "cardinality", "single", "baseType", "float"},
+			qtiElement("defaultValue", nil, qtiElement("value", nil, qtiTextNode(points))),
+		),
+		body,
+	)
+	if processing != nil {
+		item.Children = append(item.Children, processing)
+	}
+	return item
+}
+
+// buildQTIPackage writes the bank and its questions as a QTI content package: a zip of
+// imsmanifest.xml and one file per item, in the bank's order. The bank's name and description
+// are kept in the manifest's metadata.
+func buildQTIPackage(bank *models.QuestionBank, questions []models.Question, version string) ([]byte, error) {
+	format := qtiFormats[version]
+	lomString := func(name, value string) *qtiNode {
+		return qtiElement("imsmd:"+name, nil, qtiElement("imsmd:string", nil, qtiTextNode(value)))
+	}
+	general := qtiElement("imsmd:general", nil, lomString("title", bank.Name))
+	if bank.Description != nil {
+		general.Children = append(general.Children, lomString("description", *bank.Description))
+	}
+	resources := qtiElement("resources", nil)
+	manifest := qtiElement("manifest", []string{
+		"xmlns", format.manifestNamespace,
+		"xmlns:imsmd", "http://ltsc.ieee.org/xsd/LOM",
+		"xmlns:xsi", "http://www.w3.org/2001/XMLSchema-instance",
+		"xsi:schemaLocation", format.manifestNamespace + " " + format.manifestSchema,
+		"identifier", fmt.Sprintf("question-bank-%d", bank.ID),
+	},
+		qtiElement("metadata", nil,
+			qtiElement("schema", nil, qtiTextNode(format.schema)),
+			qtiElement("schemaversion", nil, qtiTextNode(format.schemaVersion)),
+			qtiElement("imsmd:lom", nil, general),
+		),
+		qtiElement("organizations", nil),
+		resources,
+	)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := range questions {
+		question := &questions[i]
+		item, err := renderQTIXML(qtiItem(question, version), version == qtiVersion30)
+		if err != nil {
+			return nil, err
+		}
+		identifier := fmt.Sprintf("q%d", question.ID)
+		href := "items/" + identifier + ".xml"
+		w, err := zw.Create(href)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(item); err != nil {
+			return nil, err
+		}
+		resources.Children = append(resources.Children, qtiElement("resource", []string{"identifier", identifier, "type", format.resourceType, "href", href},
+			qtiElement("file", []string{"href", href}),
+		))
+	}
+
+	content, err := renderQTIXML(manifest, false)
+	if err != nil {
+		return nil, err
+	}
+	w, err := zw.Create("imsmanifest.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(content); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// qtiEntry is an item file of an imported package, or the reason it cannot be read.
+type qtiEntry struct {
+	File    string
+	Root    *qtiNode
+	Version string
+	Err     string
+}
+
+// qtiPackage is what was read from an imported file.
+type qtiPackage struct {
+	Title       string
+	Description string
+	Entries     []qtiEntry
+	Skipped     []models.QTIItemResult // Resources in formats the import does not read
+}
+
+// readQTIPackage reads a QTI content package, or a single item file. Items are taken in the
+// order of the manifest's resources; without a manifest every XML file holding an item is.
+func readQTIPackage(filename string, data []byte) (*qtiPackage, error) {
+	pkg := &qtiPackage{}
+	if !bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		root, version, err := parseQTIXML(data)
+		if err != nil || (root.Name != "assessmentItem" && root.Name != "questestinterop") {
+			return nil, appErrors.ErrInvalidQTIPackage
+		}
+		pkg.Entries = append(pkg.Entries, qtiEntry{File: filename, Root: root, Version: version})
+		return pkg, nil
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, appErrors.ErrInvalidQTIPackage
+	}
+	files := make(map[string]*zip.File)
+	names := make([]string, 0)
+	manifestName := ""
+	for _, f := range reader.File {
+		name := path.Clean(f.Name)
+		if f.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX/") {
+			continue
+		}
+		files[name] = f
+		names = append(names, name)
+		if strings.EqualFold(path.Base(name), "imsmanifest.xml") &&
+			(manifestName == "" || strings.Count(name, "/") < strings.Count(manifestName, "/")) {
+			manifestName = name
+		}
+	}
+	slices.Sort(names)
+
+	budget := int64(maxQTIPackageSize)
+	read := func(name string) (*qtiNode, string, string, error) {
+		f, ok := files[name]
+		if !ok {
+			return nil, "", "File is missing from the package", nil
+		}
+		if f.UncompressedSize64 > maxQTIFileSize {
+			return nil, "", fmt.Sprintf("File is larger than %d MB", maxQTIFileSize>>20), nil
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, "", "File cannot be unpacked", nil
+		}
+		defer rc.Close()
+		content, err := io.ReadAll(io.LimitReader(rc, maxQTIFileSize+1))
+		if err != nil {
+			return nil, "", "File cannot be unpacked", nil
+		}
+		if len(content) > maxQTIFileSize {
+			return nil, "", fmt.Sprintf("File is larger than %d MB", maxQTIFileSize>>20), nil
+		}
+		if budget -= int64(len(content)); budget < 0 {
+			return nil, "", "", appErrors.New(http.StatusRequestEntityTooLarge, "Package unpacks to more than %d MB", maxQTIPackageSize>>20)
+		}
+		root, version, err := parseQTIXML(content)
+		if err != nil {
+			return nil, "", "File is not well-formed XML: " + err.Error(), nil
+		}
+		return root, version, "", nil
+	}
+	addEntry := func(entry qtiEntry) error {
+		if len(pkg.Entries) >= maxQTIItems {
+			return appErrors.New(http.StatusBadRequest, "Package has more than %d items", maxQTIItems)
+		}
+		pkg.Entries = append(pkg.Entries, entry)
+		return nil
+	}
+
+	if manifestName == "" {
+		for _, name := range names {
+			if !strings.EqualFold(path.Ext(name), ".xml") {
+				continue
+			}
+			root, version, reason, err := read(name)
+			if err != nil {
+				return nil, err
+			}
+			// Files that are not items, such as tests or metadata, are left alone
+			if reason == "" && root.Name != "assessmentItem" && root.Name != "questestinterop" {
+				continue
+			}
+			if err := addEntry(qtiEntry{File: name, Root: root, Version: version, Err: reason}); err != nil {
+				return nil, err
+			}
+		}
+		return pkg, nil
+	}
+
+	manifest, _, reason, err := read(manifestName)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" || manifest.Name != "manifest" {
+		return nil, appErrors.New(http.StatusBadRequest, "%s cannot be read", manifestName)
+	}
+	if general := manifest.find("lom").find("general"); general != nil {
+		pkg.Title = strings.TrimSpace(general.child("title").find("string").text())
+		if pkg.Title == "" {
+			pkg.Title = strings.TrimSpace(general.child("title").find("langstring").text())
+		}
+		pkg.Description = strings.TrimSpace(general.child("description").find("string").text())
+	}
+	for _, resource := range manifest.findAll(func(n *qtiNode) bool { return n.Name == "resource" }) {
+		href := resource.attr("href")
+		if href == "" {
+			href = resource.child("file").attr("href")
+		}
+		if unescaped, err := url.PathUnescape(href); err == nil {
+			href = unescaped
+		}
+		name := path.Join(path.Dir(manifestName), href)
+
+		resourceType := strings.ToLower(resource.attr("type"))
+		switch {
+		case strings.HasPrefix(resourceType, "imsqti_item_xmlv2p"), strings.HasPrefix(resourceType, "imsqti_item_xmlv3p"):
+			root, version, reason, err := read(name)
+			if err != nil {
+				return nil, err
+			}
+			if err := addEntry(qtiEntry{File: name, Root: root, Version: version, Err: reason}); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(resourceType, "imsqti_xmlv1p"):
+			pkg.Skipped = append(pkg.Skipped, models.QTIItemResult{
+				File:       name,
+				Identifier: resource.attr("identifier"),
+				Version:    "1.2",
+				Reason:     "QTI 1.x is not supported; export the questions as QTI 2.1 or 3.0",
+			})
+		}
+	}
+	return pkg, nil
+}
+
+// Elements within an item body that are not part of the question's text.
+var qtiHiddenElements = map[string]bool{
+	"feedbackBlock":  true,
+	"feedbackInline": true,
+	"rubricBlock":    true,
+	"templateBlock":  true,
+	"templateInline": true,
+	"stylesheet":     true,
+}
+
+// XHTML elements that start a new line of text.
+var qtiBlockElements = map[string]bool{
+	"p": true, "div": true, "li": true, "tr": true, "blockquote": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "table": true, "dl": true, "dt": true, "dd": true,
+}
+
+// Elements whose content a question cannot show.
+var qtiMediaElements = map[string]bool{
+	"img": true, "object": true, "video": true, "audio": true, "picture": true, "iframe": true,
+	"math": true, "svg": true, "include": true,
+}
+
+// writeQTIText writes the text of the node as HTML renders it: runs of white space become one
+// space and block elements their own lines. Of the interaction only its prompt is written.
+func writeQTIText(b *strings.Builder, node, interaction *qtiNode) {
+	for _, c := range node.Children {
+		switch {
+		case c.Name == "":
+			space := false
+			for _, r := range c.Text {
+				if unicode.IsSpace(r) {
+					space = true
+					continue
+				}
+				if space {
+					b.WriteByte(' ')
+					space = false
+				}
+				b.WriteRune(r)
+			}
+			if space {
+				b.WriteByte(' ')
+			}
+		case c == interaction:
+			if prompt := c.child("prompt"); prompt != nil {
+				b.WriteByte('\n')
+				writeQTIText(b, prompt, nil)
+				b.WriteByte('\n')
+			}
+		case qtiHiddenElements[c.Name]:
+		case c.Name == "br":
+			b.WriteByte('\n')
+		case qtiBlockElements[c.Name]:
+			b.WriteByte('\n')
+			writeQTIText(b, c, interaction)
+			b.WriteByte('\n')
+		default:
+			writeQTIText(b, c, interaction)
+		}
+	}
+}
+
+// qtiPlainText returns the text of the node with one line per block and no blank lines.
+func qtiPlainText(node, interaction *qtiNode) string {
+	var b strings.Builder
+	writeQTIText(&b, node, interaction)
+	lines := make([]string, 0)
+	for _, line := range strings.Split(b.String(), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// qtiValues returns the values of a correctResponse or defaultValue element.
+func qtiValues(node *qtiNode) []string {
+	values := make([]string, 0)
+	if node == nil {
+		return values
+	}
+	for _, c := range node.Children {
+		if c.Name == "value" {
+			values = append(values, strings.TrimSpace(c.text()))
+		}
+	}
+	return values
+}
+
+func parseQTINumber(value string) (float64, bool) {
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return number, err == nil && !math.IsNaN(number) && !math.IsInf(number, 0)
+}
+
+// qtiPoints returns what the item is worth: the default of its MAXSCORE outcome, the normal
+// maximum of its SCORE or the upper bound of its response's mapping, whichever it has.
+func qtiPoints(item, declaration *qtiNode) (float64, bool) {
+	for _, outcome := range item.Children {
+		if outcome.Name != "outcomeDeclaration" {
+			continue
+		}
+		if outcome.attr("identifier") == "MAXSCORE" {
+			if values := qtiValues(outcome.child("defaultValue")); len(values) == 1 {
+				if points, ok := parseQTINumber(values[0]); ok {
+					return points, true
+				}
+			}
+		}
+		if outcome.attr("identifier") == "SCORE" {
+			if points, ok := parseQTINumber(outcome.attr("normalMaximum")); ok {
+				return points, true
+			}
+		}
+	}
+	if mapping := declaration.child("mapping"); mapping != nil {
+		if points, ok := parseQTINumber(mapping.attr("upperBound")); ok {
+			return points, true
+		}
+	}
+	return 1, false
+}
+
+// qtiTolerance reads how far a numeric answer may be from the correct one, from the equal
+// comparison of the item's response processing.
+func qtiTolerance(item *qtiNode, answer float64) (float64, []string, error) {
+	equal := item.child("responseProcessing").find("equal")
+	if equal == nil {
+		return 0, nil, nil
+	}
+	mode := equal.attr("toleranceMode")
+	if mode == "" || mode == "exact" {
+		return 0, nil, nil
+	}
+	fields := strings.Fields(equal.attr("tolerance"))
+	bounds := make([]float64, 0, 2)
+	for _, field := range fields {
+		bound, ok := parseQTINumber(field)
+		if !ok || bound < 0 {
+			return 0, nil, fmt.Errorf("Tolerance %q is not valid", equal.attr("tolerance"))
+		}
+		bounds = append(bounds, bound)
+	}
+	if len(bounds) == 0 || len(bounds) > 2 {
+		return 0, nil, fmt.Errorf("Tolerance %q is not valid", equal.attr("tolerance"))
+	}
+	var warnings []string
+	tolerance := slices.Min(bounds)
+	if slices.Max(bounds) != tolerance {
+		warnings = append(warnings, "Lower and upper tolerances differ; the smaller one is used both ways")
+	}
+	switch mode {
+	case "absolute":
+	case "relative":
+		warnings = append(warnings, fmt.Sprintf("Relative tolerance of %s%% became an absolute one", formatQTINumber(tolerance)))
+		tolerance = math.Abs(answer) * tolerance / 100
+	default:
+		return 0, nil, fmt.Errorf("Tolerance mode %q is not supported", mode)
+	}
+	return tolerance, warnings, nil
+}
+
+// qtiQuestion converts an assessment item into a question request, with warnings about what
+// the conversion lost or changed. An error tells why the item cannot be converted. Items the
+// portal exported are read as written; the text of others is read as HTML renders it.
+func qtiQuestion(item *qtiNode) (*models.QuestionRequest, []string, error) {
+	if item.child("templateDeclaration") != nil {
+		return nil, nil, errors.New("Generates its values from templates, which questions cannot do")
+	}
+	body := item.child("itemBody")
+	if body == nil {
+		return nil, nil, errors.New("Has no item body")
+	}
+	if len(body.findAll(func(n *qtiNode) bool { return qtiMediaElements[n.Name] })) > 0 {
+		return nil, nil, errors.New("Contains images, media or formulas, which questions cannot show")
+	}
+	interactions := body.findAll(func(n *qtiNode) bool { return strings.HasSuffix(n.Name, "Interaction") })
+	if len(interactions) == 0 {
+		return nil, nil, errors.New("Has nothing to answer")
+	}
+	if len(interactions) > 1 {
+		return nil, nil, fmt.Errorf("Has %d interactions; only items with one can be imported", len(interactions))
+	}
+	interaction := interactions[0]
+	var declaration *qtiNode
+	for _, c := range item.Children {
+		if c.Name == "responseDeclaration" && c.attr("identifier") == interaction.attr("responseIdentifier") {
+			declaration = c
+		}
+	}
+	if declaration == nil && interaction.Name != "extendedTextInteraction" {
+		return nil, nil, errors.New("Has no response declaration for its interaction")
+	}
+
+	verbatim := item.attr("toolName") == qtiToolName
+	var warnings []string
+	req := &models.QuestionRequest{}
+	if verbatim {
+		lines := make([]string, 0)
+		for _, p := range body.Children {
+			if p.Name == "p" && !p.contains(interaction) {
+				lines = append(lines, p.text())
+			}
+		}
+		req.Prompt = strings.Join(lines, "\n")
+	} else {
+		req.Prompt = qtiPlainText(body, interaction)
+	}
+	points, ok := qtiPoints(item, declaration)
+	if !ok {
+		warnings = append(warnings, "Gives no maximum score; imported as worth 1 point")
+	}
+	if points != roundPoints(points) {
+		warnings = append(warnings, fmt.Sprintf("Points were rounded from %s to two decimals", formatQTINumber(points)))
+	}
+	req.Points = points
+	correct := qtiValues(declaration.child("correctResponse"))
+
+	switch interaction.Name {
+	case "choiceInteraction":
+		choices := interaction.findAll(func(n *qtiNode) bool { return n.Name == "simpleChoice" })
+		ids := make([]string, 0, len(choices))
+		for _, choice := range choices {
+			ids = append(ids, choice.attr("identifier"))
+			if verbatim {
+				req.Options = append(req.Options, choice.text())
+			} else {
+				req.Options = append(req.Options, strings.ReplaceAll(qtiPlainText(choice, nil), "\n", " "))
+			}
+		}
+		if len(correct) == 0 {
+			// Some tools only score the choices, without naming the correct ones
+			for _, entry := range declaration.child("mapping").findAll(func(n *qtiNode) bool { return n.Name == "mapEntry" }) {
+				if value, ok := parseQTINumber(entry.attr("mappedValue")); ok && value > 0 {
+					correct = append(correct, entry.attr("mapKey"))
+				}
+			}
+			if len(correct) > 0 {
+				warnings = append(warnings, "Correct choices were taken from the choices' scores")
+			}
+		}
+		if len(correct) == 0 {
+			return nil, nil, errors.New("Has no correct answer")
+		}
+
+		switch declaration.attr("cardinality") {
+		case "single":
+			if len(correct) > 1 {
+				return nil, nil, errors.New("Has more than one correct answer to a single choice")
+			}
+			if len(choices) == 2 && qtiIsTrueFalse(ids, req.Options) {
+				value := strings.EqualFold(correct[0], "true")
+				req.Type = string(enums.QuestionTypeTrueFalse)
+				req.CorrectBool = &value
+				req.Options = nil
+				return req, warnings, nil
+			}
+			req.Type = string(enums.QuestionTypeMultipleChoice)
+		case "multiple":
+			req.Type = string(enums.QuestionTypeMultiSelect)
+			if !verbatim && declaration.child("mapping") != nil {
+				warnings = append(warnings, "Scored with the portal's partial credit instead of the item's choice scores")
+			}
+		default:
+			return nil, nil, errors.New("Ordered choices are not supported")
+		}
+		for _, value := range correct {
+			index := slices.Index(ids, value)
+			if index < 0 {
+				return nil, nil, fmt.Errorf("Correct answer %q is not one of the choices", value)
+			}
+			req.CorrectOptions = append(req.CorrectOptions, int64(index+1))
+		}
+
+	case "textEntryInteraction":
+		switch declaration.attr("baseType") {
+		case "float", "integer":
+			if len(correct) != 1 {
+				return nil, nil, errors.New("Has no single correct number")
+			}
+			answer, ok := parseQTINumber(correct[0])
+			if !ok {
+				return nil, nil, fmt.Errorf("Correct answer %q is not a number", correct[0])
+			}
+			tolerance, toleranceWarnings, err := qtiTolerance(item, answer)
+			if err != nil {
+				return nil, nil, err
+			}
+			warnings = append(warnings, toleranceWarnings...)
+			if math.Round(answer*1e4) != answer*1e4 || math.Round(tolerance*1e4) != tolerance*1e4 {
+				warnings = append(warnings, "Answer and tolerance are kept to four decimals")
+			}
+			req.Type = string(enums.QuestionTypeNumeric)
+			req.NumericAnswer = &answer
+			req.Tolerance = tolerance
+		case "string":
+			req.Type = string(enums.QuestionTypeShortAnswer)
+			if len(correct) > 0 || declaration.child("mapping") != nil {
+				warnings = append(warnings, "Accepted answers are not kept; answers are graded by hand")
+			}
+		default:
+			return nil, nil, fmt.Errorf("Text entries of type %q are not supported", declaration.attr("baseType"))
+		}
+
+	case "extendedTextInteraction":
+		req.Type = string(enums.QuestionTypeShortAnswer)
+		if len(correct) > 0 {
+			warnings = append(warnings, "Accepted answers are not kept; answers are graded by hand")
+		}
+
+	default:
+		return nil, nil, fmt.Errorf("Items with a %s interaction are not supported", qtiKebab(strings.TrimSuffix(interaction.Name, "Interaction")))
+	}
+	return req, warnings, nil
+}
+
+// qtiIsTrueFalse reports whether the two choices are "true" and "false", in identifier and text.
+func qtiIsTrueFalse(ids, texts []string) bool {
+	for i, id := range ids {
+		if !strings.EqualFold(id, texts[i]) || (!strings.EqualFold(id, "true") && !strings.EqualFold(id, "false")) {
+			return false
+		}
+	}
+	return !strings.EqualFold(ids[0], ids[1])
+}
+
+// qtiImportItem converts an item file of a package into a question, or says why it cannot be.
+func qtiImportItem(entry qtiEntry) (*models.Question, models.QTIItemResult) {
+	result := models.QTIItemResult{File: entry.File, Version: entry.Version}
+	if entry.Err != "" {
+		result.Reason = entry.Err
+		return nil, result
+	}
+	result.Identifier = entry.Root.attr("identifier")
+	result.Title = entry.Root.attr("title")
+	switch {
+	case entry.Root.Name == "questestinterop":
+		result.Reason = "QTI 1.x is not supported; export the questions as QTI 2.1 or 3.0"
+		return nil, result
+	case entry.Root.Name != "assessmentItem":
+		result.Reason = "File is not an assessment item"
+		return nil, result
+	}
+	req, warnings, err := qtiQuestion(entry.Root)
+	if err != nil {
+		result.Reason = err.Error()
+		return nil, result
+	}
+	question, err := questionFromRequest(req)
+	if err != nil {
+		result.Reason = err.Error()
+		return nil, result
+	}
+	result.Type = question.Type
+	result.Warnings = warnings
+	return question, result
+}
@@ -0,0 +1,82 @@
+// internal/service/qti_test.go
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"student-portal/internal/commons/enums"
+	"student-portal/internal/models"
+)
+
+func TestQTIRoundTrip(t *testing.T) {
+	yes := true
+	number := func(v float64) *float64 { return &v }
+	requests := []models.QuestionRequest{
+		{Type: string(enums.QuestionTypeMultipleChoice), Prompt: "Which planet is closest to the Sun?", Points: 1,
+			Options: []string{"Venus", "Mercury", "Mars"}, CorrectOptions: []int64{2}},
+		{Type: string(enums.QuestionTypeMultiSelect), Prompt: "Which of these are prime? Pick all & only those < 10.", Points: 2.5,
+			Options: []string{"2", "4", "5", "7", "9"}, CorrectOptions: []int64{1, 3, 4}},
+		{Type: string(enums.QuestionTypeTrueFalse), Prompt: "Water boils at 100 °C at sea level.", Points: 1, CorrectBool: &yes},
+		{Type: string(enums.QuestionTypeNumeric), Prompt: "What is g in m/s²?", Points: 3, NumericAnswer: number(9.81), Tolerance: 0.05},
+		{Type: string(enums.QuestionTypeShortAnswer), Prompt: "Explain \"photosynthesis\" in one sentence.", Points: 4},
+	}
+	questions := make([]models.Question, 0, len(requests))
+	for i := range requests {
+		question, err := questionFromRequest(&requests[i])
+		if err != nil {
+			t.Fatalf("question %d: %v", i+1, err)
+		}
+		question.ID = int64(100 + i)
+		questions = append(questions, *question)
+	}
+	description := "Week 1 <review>"
+	bank := &models.QuestionBank{ID: 9, Name: "Physics & Chemistry", Description: &description}
+
+	for _, version := range []string{qtiVersion21, qtiVersion30} {
+		t.Run("QTI "+version, func(t *testing.T) {
+			data, err := buildQTIPackage(bank, questions, version)
+			if err != nil {
+				t.Fatalf("buildQTIPackage: %v", err)
+			}
+			pkg, err := readQTIPackage("bank.zip", data)
+			if err != nil {
+				t.Fatalf("readQTIPackage: %v", err)
+			}
+			if pkg.Title != bank.Name || pkg.Description != description {
+				t.Errorf("package is %q (%q), want %q (%q)", pkg.Title, pkg.Description, bank.Name, description)
+			}
+			if len(pkg.Entries) != len(questions) || len(pkg.Skipped) != 0 {
+				t.Fatalf("package has %d entries and %d skipped, want %d and none", len(pkg.Entries), len(pkg.Skipped), len(questions))
+			}
+
+			for i, entry := range pkg.Entries {
+				want := questions[i]
+				got, result := qtiImportItem(entry)
+				if got == nil {
+					t.Errorf("question %d not imported: %s", i+1, result.Reason)
+					continue
+				}
+				if result.Version != version || len(result.Warnings) != 0 {
+					t.Errorf("question %d read as QTI %s with warnings %v", i+1, result.Version, result.Warnings)
+				}
+				if got.Type != want.Type || got.Prompt != want.Prompt || got.Points != want.Points {
+					t.Errorf("question %d = %s %q (%v points), want %s %q (%v points)",
+						i+1, got.Type, got.Prompt, got.Points, want.Type, want.Prompt, want.Points)
+				}
+				if !slices.Equal(got.Options, want.Options) || !slices.Equal(got.CorrectOptions, want.CorrectOptions) {
+					t.Errorf("question %d options = %v correct %v, want %v correct %v",
+						i+1, got.Options, got.CorrectOptions, want.Options, want.CorrectOptions)
+				}
+				if (got.CorrectBool == nil) != (want.CorrectBool == nil) || (got.CorrectBool != nil && *got.CorrectBool != *want.CorrectBool) {
+					t.Errorf("question %d CorrectBool = %v, want %v", i+1, got.CorrectBool, want.CorrectBool)
+				}
+				if (got.NumericAnswer == nil) != (want.NumericAnswer == nil) ||
+					(got.NumericAnswer != nil && *got.NumericAnswer != *want.NumericAnswer) || got.Tolerance != want.Tolerance {
+					t.Errorf("question %d numeric answer = %v ± %v, want %v ± %v",
+						i+1, got.NumericAnswer, got.Tolerance, want.NumericAnswer, want.Tolerance)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// internal/service/quiz.go
+package service
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+)
+
+// Limits on question content.
+const (
+	maxPromptLength     = 5000
+	maxOptionLength     = 1000
+	maxQuestionOptions  = 20
+	maxShortAnswerChars = 10000
+	maxNumericAnswer    = 1e10 // NUMERIC(14, 4)
+)
+
+// validQuestionTypes are the types a question can have.
+var validQuestionTypes = map[string]bool{
+	string(enums.QuestionTypeMultipleChoice): true,
+	string(enums.QuestionTypeMultiSelect):    true,
+	string(enums.QuestionTypeTrueFalse):      true,
+	string(enums.QuestionTypeNumeric):        true,
+	string(enums.QuestionTypeShortAnswer):    true,
+}
+
+// hasOptions reports whether questions of the type are answered by picking options.
+func hasOptions(questionType string) bool {
+	return questionType == string(enums.QuestionTypeMultipleChoice) || questionType == string(enums.QuestionTypeMultiSelect)
+}
+
+// questionFromRequest validates the request into a question. Only the parts of the answer
+// key that belong to the question's type are kept.
+func questionFromRequest(req *models.QuestionRequest) (*models.Question, error) {
+	if !validQuestionTypes[req.Type] {
+		return nil, appErrors.New(http.StatusBadRequest, "Invalid question type '%s'", req.Type)
+	}
+	prompt := strings.TrimSpace(req.Prompt)
+	if prompt == "" || len([]rune(prompt)) > maxPromptLength {
+		return nil, appErrors.New(http.StatusBadRequest, "Prompt must be 1-%d characters", maxPromptLength)
+	}
+	if req.Points <= 0 || req.Points >= 1000 {
+		return nil, appErrors.New(http.StatusBadRequest, "Points must be greater than 0 and less than 1000")
+	}
+	question := &models.Question{
+		Type:           req.Type,
+		Prompt:         prompt,
+		Points:         roundPoints(req.Points),
+		Options:        make([]models.QuestionOption, 0),
+		CorrectOptions: make([]int64, 0),
+	}
+
+	if hasOptions(req.Type) {
+		if len(req.Options) < 2 || len(req.Options) > maxQuestionOptions {
+			return nil, appErrors.New(http.StatusBadRequest, "Give 2-%d options", maxQuestionOptions)
+		}
+		for i, text := range req.Options {
+			text = strings.TrimSpace(text)
+			if text == "" || len([]rune(text)) > maxOptionLength {
+				return nil, appErrors.New(http.StatusBadRequest, "Option %d must be 1-%d characters", i+1, maxOptionLength)
+			}
+			question.Options = append(question.Options, models.QuestionOption{ID: int64(i + 1), Text: text})
+		}
+		for _, id := range req.CorrectOptions {
+			if id < 1 || id > int64(len(req.Options)) {
+				return nil, appErrors.New(http.StatusBadRequest, "Correct option %d does not exist", id)
+			}
+			if slices.Contains(question.CorrectOptions, id) {
+				return nil, appErrors.New(http.StatusBadRequest, "Correct option %d is listed twice", id)
+			}
+			question.CorrectOptions = append(question.CorrectOptions, id)
+		}
+		slices.Sort(question.CorrectOptions)
+		if req.Type == string(enums.QuestionTypeMultipleChoice) && len(question.CorrectOptions) != 1 {
+			return nil, appErrors.New(http.StatusBadRequest, "A multiple-choice question has exactly one correct option")
+		}
+		if len(question.CorrectOptions) == 0 {
+			return nil, appErrors.New(http.StatusBadRequest, "Mark at least one option as correct")
+		}
+	} else if len(req.Options) > 0 || len(req.CorrectOptions) > 0 {
+		return nil, appErrors.New(http.StatusBadRequest, "Only multiple-choice and multi-select questions have options")
+	}
+
+	switch req.Type {
+	case string(enums.QuestionTypeTrueFalse):
+		if req.CorrectBool == nil {
+			return nil, appErrors.New(http.StatusBadRequest, "A true/false question needs its correct answer")
+		}
+		question.CorrectBool = req.CorrectBool
+	case string(enums.QuestionTypeNumeric):
+		if req.NumericAnswer == nil {
+			return nil, appErrors.New(http.StatusBadRequest, "A numeric question needs its correct answer")
+		}
+		if math.Abs(*req.NumericAnswer) >= maxNumericAnswer || req.Tolerance < 0 || req.Tolerance >= maxNumericAnswer {
+			return nil, appErrors.New(http.StatusBadRequest, "Answer and tolerance must be less than %g, and the tolerance not negative", maxNumericAnswer)
+		}
+		question.NumericAnswer = req.NumericAnswer
+		question.Tolerance = req.Tolerance
+	}
+	return question, nil
+}
+
+// buildLayout decides the order the questions of an attempt, and the options of each, are
+// shown in. Every attempt gets its own order when the quiz shuffles.
+func buildLayout(questions []models.Question, shuffleQuestions, shuffleOptions bool) []models.AttemptLayoutItem {
+	layout := make([]models.AttemptLayoutItem, 0, len(questions))
+	for _, question := range questions {
+		item := models.AttemptLayoutItem{QuestionID: question.ID}
+		for _, option := range question.Options {
+			item.Options = append(item.Options, option.ID)
+		}
+		if shuffleOptions {
+			rand.Shuffle(len(item.Options), func(i, j int) { item.Options[i], item.Options[j] = item.Options[j], item.Options[i] })
+		}
+		layout = append(layout, item)
+	}
+	if shuffleQuestions {
+		rand.Shuffle(len(layout), func(i, j int) { layout[i], layout[j] = layout[j], layout[i] })
+	}
+	return layout
+}
+
+// normalizeAnswer checks an answer against its question and keeps only the field that
+// belongs to the question's type.
+func normalizeAnswer(question *models.Question, answer models.QuizAnswer) (models.QuizAnswer, error) {
+	normalized := models.QuizAnswer{QuestionID: question.ID}
+	switch question.Type {
+	case string(enums.QuestionTypeMultipleChoice), string(enums.QuestionTypeMultiSelect):
+		normalized.Selected = make([]int64, 0, len(answer.Selected))
+		for _, id := range answer.Selected {
+			if !slices.ContainsFunc(question.Options, func(o models.QuestionOption) bool { return o.ID == id }) {
+				return normalized, appErrors.New(http.StatusBadRequest, "Question %d has no option %d", question.ID, id)
+			}
+			if !slices.Contains(normalized.Selected, id) {
+				normalized.Selected = append(normalized.Selected, id)
+			}
+		}
+		if question.Type == string(enums.QuestionTypeMultipleChoice) && len(normalized.Selected) > 1 {
+			return normalized, appErrors.New(http.StatusBadRequest, "Pick one option for question %d", question.ID)
+		}
+		slices.Sort(normalized.Selected)
+	case string(enums.QuestionTypeTrueFalse):
+		normalized.Value = answer.Value
+	case string(enums.QuestionTypeNumeric):
+		if answer.Number != nil && math.Abs(*answer.Number) >= maxNumericAnswer {
+			return normalized, appErrors.New(http.StatusBadRequest, "Answer to question %d is out of range", question.ID)
+		}
+		normalized.Number = answer.Number
+	case string(enums.QuestionTypeShortAnswer):
+		if answer.Text != nil && len([]rune(*answer.Text)) > maxShortAnswerChars {
+			return normalized, appErrors.New(http.StatusBadRequest, "Answer to question %d is longer than %d characters", question.ID, maxShortAnswerChars)
+		}
+		normalized.Text = answer.Text
+	}
+	return normalized, nil
+}
+
+// gradeAnswer scores an answer to an objective question; unanswered questions score zero.
+// Multi-select questions give partial credit: each correct pick earns its share of the
+// points and each wrong pick takes one share away, down to zero. Short answers return nil
+// for the teacher to grade, unless they were left blank.
+func gradeAnswer(question *models.Question, answer *models.QuizAnswer) *float64 {
+	var points float64
+	switch question.Type {
+	case string(enums.QuestionTypeMultipleChoice):
+		if answer != nil && len(answer.Selected) == 1 && slices.Contains(question.CorrectOptions, answer.Selected[0]) {
+			points = question.Points
+		}
+	case string(enums.QuestionTypeMultiSelect):
+		if answer != nil && len(question.CorrectOptions) > 0 {
+			var shares int
+			for _, id := range answer.Selected {
+				if slices.Contains(question.CorrectOptions, id) {
+					shares++
+				} else {
+					shares--
+				}
+			}
+			if shares > 0 {
+				points = roundPoints(question.Points * float64(shares) / float64(len(question.CorrectOptions)))
+			}
+		}
+	case string(enums.QuestionTypeTrueFalse):
+		if answer != nil && answer.Value != nil && question.CorrectBool != nil && *answer.Value == *question.CorrectBool {
+			points = question.Points
+		}
+	case string(enums.QuestionTypeNumeric):
+		// The epsilon keeps answers exactly at the tolerance from failing on rounding
+		if answer != nil && answer.Number != nil && question.NumericAnswer != nil &&
+			math.Abs(*answer.Number-*question.NumericAnswer) <= question.Tolerance+1e-9 {
+			points = question.Points
+		}
+	case string(enums.QuestionTypeShortAnswer):
+		if answer != nil && answer.Text != nil && strings.TrimSpace(*answer.Text) != "" {
+			return nil
+		}
+	}
+	return &points
+}
@@ -0,0 +1,1022 @@
+// internal/service/quiz_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/commons/logger"
+	"student-portal/internal/config"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// QuizService defines the methods for question banks, quizzes and attempts. Methods taking a
+// userID and isAdmin act on behalf of that user: teachers manage their own question banks,
+// the section's instructor and admins manage its quizzes, and the students enrolled in it
+// take them.
+type QuizService interface {
+	// ListBanks returns the user's question banks; admins see every bank.
+	ListBanks(ctx context.Context, userID int64, isAdmin bool) ([]models.QuestionBank, error)
+	CreateBank(ctx context.Context, req *models.QuestionBankRequest, userID int64) (*models.QuestionBank, error)
+	// GetBank returns a question bank with its questions and their answer keys.
+	GetBank(ctx context.Context, id, userID int64, isAdmin bool) (*models.QuestionBank, error)
+	UpdateBank(ctx context.Context, id int64, req *models.QuestionBankRequest, userID int64, isAdmin bool) (*models.QuestionBank, error)
+	// DeleteBank removes a bank none of whose questions are on a quiz.
+	DeleteBank(ctx context.Context, id, userID int64, isAdmin bool) error
+	AddQuestion(ctx context.Context, bankID int64, req *models.QuestionRequest, userID int64, isAdmin bool) (*models.Question, error)
+	// UpdateQuestion changes a question nobody has answered yet.
+	UpdateQuestion(ctx context.Context, bankID, questionID int64, req *models.QuestionRequest, userID int64, isAdmin bool) (*models.Question, error)
+	// DeleteQuestion removes a question that is not on any quiz.
+	DeleteQuestion(ctx context.Context, bankID, questionID, userID int64, isAdmin bool) error
+	// ExportBankQTI writes the bank as an IMS QTI content package of the version, "2.1" (the
+	// default) or "3.0".
+	ExportBankQTI(ctx context.Context, id int64, version string, userID int64, isAdmin bool) (filename string, content []byte, err error)
+	// ImportQTI adds the items of a QTI 2.x or 3.0 package, or of a single item file, to the
+	// bank, or to a new bank of the user when bankID is zero. Items that cannot be converted
+	// are skipped and reported.
+	ImportQTI(ctx context.Context, bankID int64, req *models.QTIImportRequest, file UploadedFile, userID int64, isAdmin bool) (*models.QTIImportReport, error)
+
+	CreateQuiz(ctx context.Context, sectionID int64, req *models.CreateQuizRequest, userID int64, isAdmin bool) (*models.Quiz, error)
+	ListQuizzes(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.Quiz, error)
+	// GetQuiz returns the quiz to its instructor, admins and the students of its section.
+	GetQuiz(ctx context.Context, id, userID int64, isAdmin bool) (*models.Quiz, error)
+	UpdateQuiz(ctx context.Context, id int64, req *models.UpdateQuizRequest, userID int64, isAdmin bool) (*models.Quiz, error)
+	// DeleteQuiz removes a quiz nobody has attempted yet.
+	DeleteQuiz(ctx context.Context, id, userID int64, isAdmin bool) error
+	GetQuizQuestions(ctx context.Context, id, userID int64, isAdmin bool) ([]models.Question, error)
+	// SetQuizQuestions replaces the questions of a quiz nobody has attempted yet. The questions
+	// must come from banks the user owns, unless they are an admin.
+	SetQuizQuestions(ctx context.Context, id int64, req *models.SetQuizQuestionsRequest, userID int64, isAdmin bool) ([]models.Question, error)
+	ListAttempts(ctx context.Context, quizID, userID int64, isAdmin bool) ([]models.QuizAttempt, error)
+	// GradingQueue returns the free-text answers of the quiz waiting to be graded.
+	GradingQueue(ctx context.Context, quizID, userID int64, isAdmin bool) ([]models.QueuedResponse, error)
+
+	// StartAttempt starts the student's next attempt, or returns the one in progress (started
+	// is false then).
+	StartAttempt(ctx context.Context, quizID, studentID int64) (detail *models.QuizAttemptDetail, started bool, err error)
+	// GetAttempt returns an attempt to its student, the section's instructor and admins.
+	GetAttempt(ctx context.Context, id, userID int64, isAdmin bool) (*models.QuizAttemptDetail, error)
+	// SaveAnswers stores the student's answers while the attempt is in progress and in time.
+	SaveAnswers(ctx context.Context, id, studentID int64, req *models.SaveAnswersRequest) (*models.QuizAttemptDetail, error)
+	// SubmitAttempt stores the final answers, if still in time, and grades the objective questions.
+	SubmitAttempt(ctx context.Context, id, studentID int64, req *models.SaveAnswersRequest) (*models.QuizAttemptDetail, error)
+	// GradeResponse sets the points of an answer by hand, free text or not.
+	GradeResponse(ctx context.Context, attemptID, questionID int64, req *models.GradeResponseRequest, userID int64, isAdmin bool) (*models.QuizAttemptDetail, error)
+	// GetStudentQuizzes returns a student's quizzes in the term (zero means the current term)
+	// with their attempts.
+	GetStudentQuizzes(ctx context.Context, studentID, termID int64) (*models.StudentQuizzes, error)
+}
+
+type quizService struct {
+	repo           repository.QuizRepository
+	assignmentRepo repository.AssignmentRepository
+	sectionRepo    repository.SectionRepository
+	termRepo       repository.TermRepository
+	userRepo       repository.UserRepository
+	transactor     repository.Transactor
+	historySvc     HistoryService
+	cfg            *config.Config
+}
+
+// NewQuizService creates a new QuizService instance.
+func NewQuizService(repo repository.QuizRepository, assignmentRepo repository.AssignmentRepository, sectionRepo repository.SectionRepository, termRepo repository.TermRepository, userRepo repository.UserRepository, transactor repository.Transactor, historySvc HistoryService, cfg *config.Config) QuizService {
+	return &quizService{repo: repo, assignmentRepo: assignmentRepo, sectionRepo: sectionRepo, termRepo: termRepo, userRepo: userRepo, transactor: transactor, historySvc: historySvc, cfg: cfg}
+}
+
+// requireBank loads the bank and checks that the user owns it.
+func (s *quizService) requireBank(ctx context.Context, id, userID int64, isAdmin bool) (*models.QuestionBank, error) {
+	bank, err := s.repo.GetBank(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && (bank.OwnerID == nil || *bank.OwnerID != userID) {
+		return nil, appErrors.New(http.StatusForbidden, "Only the owner of the question bank has access")
+	}
+	return bank, nil
+}
+
+// requireBankQuestion loads a question of the bank and checks that the user owns the bank.
+func (s *quizService) requireBankQuestion(ctx context.Context, bankID, questionID, userID int64, isAdmin bool) (*models.Question, error) {
+	if _, err := s.requireBank(ctx, bankID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	question, err := s.repo.GetQuestion(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if question.BankID != bankID {
+		return nil, appErrors.ErrNotFound
+	}
+	return question, nil
+}
+
+// bankFromRequest validates the request into the bank.
+func bankFromRequest(bank *models.QuestionBank, req *models.QuestionBankRequest) error {
+	bank.Name = strings.TrimSpace(req.Name)
+	if bank.Name == "" || len(bank.Name) > 200 {
+		return appErrors.New(http.StatusBadRequest, "Name must be 1-200 characters")
+	}
+	bank.Description = optionalString(req.Description)
+	bank.CourseID = req.CourseID
+	return nil
+}
+
+func (s *quizService) ListBanks(ctx context.Context, userID int64, isAdmin bool) ([]models.QuestionBank, error) {
+	if isAdmin {
+		return s.repo.ListBanks(ctx, nil)
+	}
+	return s.repo.ListBanks(ctx, &userID)
+}
+
+func (s *quizService) CreateBank(ctx context.Context, req *models.QuestionBankRequest, userID int64) (*models.QuestionBank, error) {
+	bank := &models.QuestionBank{OwnerID: &userID}
+	if err := bankFromRequest(bank, req); err != nil {
+		return nil, err
+	}
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateBank(ctx, bank); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityQuestionBank, bank.ID, enums.ActionCreate, nil, bank)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bank, nil
+}
+
+func (s *quizService) GetBank(ctx context.Context, id, userID int64, isAdmin bool) (*models.QuestionBank, error) {
+	bank, err := s.requireBank(ctx, id, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	bank.Questions, err = s.repo.ListBankQuestions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return bank, nil
+}
+
+func (s *quizService) UpdateBank(ctx context.Context, id int64, req *models.QuestionBankRequest, userID int64, isAdmin bool) (*models.QuestionBank, error) {
+	bank, err := s.requireBank(ctx, id, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	before := *bank
+	if err := bankFromRequest(bank, req); err != nil {
+		return nil, err
+	}
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateBank(ctx, bank); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityQuestionBank, bank.ID, enums.ActionUpdate, &before, bank)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bank, nil
+}
+
+func (s *quizService) DeleteBank(ctx context.Context, id, userID int64, isAdmin bool) error {
+	bank, err := s.requireBank(ctx, id, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteBank(ctx, id); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityQuestionBank, id, enums.ActionDelete, bank, nil)
+	})
+}
+
+func (s *quizService) AddQuestion(ctx context.Context, bankID int64, req *models.QuestionRequest, userID int64, isAdmin bool) (*models.Question, error) {
+	if _, err := s.requireBank(ctx, bankID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	question, err := questionFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	question.BankID = bankID
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateQuestion(ctx, question); err != nil {
+			return err
+		}
+		// Questions are recorded in the history of their bank
+		return s.historySvc.Record(ctx, enums.EntityQuestionBank, bankID, enums.ActionUpdate, nil, question)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+func (s *quizService) UpdateQuestion(ctx context.Context, bankID, questionID int64, req *models.QuestionRequest, userID int64, isAdmin bool) (*models.Question, error) {
+	before, err := s.requireBankQuestion(ctx, bankID, questionID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	// Changing a question would change what earlier answers to it mean
+	answered, err := s.repo.IsQuestionAnswered(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if answered {
+		return nil, appErrors.New(http.StatusConflict, "Question has already been answered; add a new question instead")
+	}
+	question, err := questionFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	question.ID = questionID
+	question.BankID = bankID
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateQuestion(ctx, question); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityQuestionBank, bankID, enums.ActionUpdate, before, question)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+func (s *quizService) DeleteQuestion(ctx context.Context, bankID, questionID, userID int64, isAdmin bool) error {
+	question, err := s.requireBankQuestion(ctx, bankID, questionID, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteQuestion(ctx, questionID); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityQuestionBank, bankID, enums.ActionUpdate, question, nil)
+	})
+}
+
+func (s *quizService) ExportBankQTI(ctx context.Context, id int64, version string, userID int64, isAdmin bool) (string, []byte, error) {
+	if version == "" {
+		version = qtiVersion21
+	}
+	if _, ok := qtiFormats[version]; !ok {
+		return "", nil, appErrors.New(http.StatusBadRequest, "QTI version must be %s or %s", qtiVersion21, qtiVersion30)
+	}
+	bank, err := s.GetBank(ctx, id, userID, isAdmin)
+	if err != nil {
+		return "", nil, err
+	}
+	content, err := buildQTIPackage(bank, bank.Questions, version)
+	if err != nil {
+		logger.Logger.Error("Failed to build QTI package", zap.Error(err), zap.Int64("bank_id", id))
+		return "", nil, appErrors.ErrInternalServerError
+	}
+	filename := fmt.Sprintf("question-bank-%d-qti%s.zip", id, strings.ReplaceAll(version, ".", ""))
+	return filename, content, nil
+}
+
+func (s *quizService) ImportQTI(ctx context.Context, bankID int64, req *models.QTIImportRequest, file UploadedFile, userID int64, isAdmin bool) (*models.QTIImportReport, error) {
+	bank := &models.QuestionBank{OwnerID: &userID}
+	if bankID != 0 {
+		existing, err := s.requireBank(ctx, bankID, userID, isAdmin)
+		if err != nil {
+			return nil, err
+		}
+		bank = existing
+	}
+
+	content, err := io.ReadAll(io.LimitReader(file.Content, s.cfg.MaxUploadSize+1))
+	if err != nil {
+		return nil, appErrors.ErrBadRequest
+	}
+	if int64(len(content)) > s.cfg.MaxUploadSize {
+		return nil, appErrors.ErrFileTooLarge
+	}
+	pkg, err := readQTIPackage(path.Base(file.Filename), content)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkg.Entries) == 0 && len(pkg.Skipped) == 0 {
+		return nil, appErrors.New(http.StatusBadRequest, "No QTI items were found in the file")
+	}
+
+	if bankID == 0 {
+		// A new bank is named after the package unless the teacher chose a name
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			name = pkg.Title
+		}
+		if name == "" {
+			name = strings.TrimSuffix(path.Base(file.Filename), path.Ext(file.Filename))
+		}
+		for len(name) > 200 {
+			runes := []rune(name)
+			name = string(runes[:len(runes)-1])
+		}
+		bankReq := &models.QuestionBankRequest{Name: name, Description: pkg.Description, CourseID: req.CourseID}
+		if err := bankFromRequest(bank, bankReq); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &models.QTIImportReport{Imported: make([]models.QTIItemResult, 0), Skipped: make([]models.QTIItemResult, 0)}
+	report.Skipped = append(report.Skipped, pkg.Skipped...)
+	questions := make([]models.Question, 0, len(pkg.Entries))
+	for _, entry := range pkg.Entries {
+		question, result := qtiImportItem(entry)
+		if question == nil {
+			report.Skipped = append(report.Skipped, result)
+			continue
+		}
+		questions = append(questions, *question)
+		report.Imported = append(report.Imported, result)
+	}
+	if len(questions) == 0 {
+		// Nothing to add; a new bank is not created empty
+		if bankID != 0 {
+			report.Bank = bank
+		}
+		return report, nil
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.ImportQuestions(ctx, bank, questions); err != nil {
+			return err
+		}
+		if bankID == 0 {
+			if err := s.historySvc.Record(ctx, enums.EntityQuestionBank, bank.ID, enums.ActionCreate, nil, bank); err != nil {
+				return err
+			}
+		}
+		return s.historySvc.Record(ctx, enums.EntityQuestionBank, bank.ID, enums.ActionUpdate, nil, questions)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range questions {
+		report.Imported[i].QuestionID = &questions[i].ID
+	}
+	logger.Logger.Info("Imported QTI package",
+		zap.Int64("bank_id", bank.ID), zap.Int("imported", len(report.Imported)), zap.Int("skipped", len(report.Skipped)),
+	)
+	report.Bank = bank
+	return report, nil
+}
+
+// validateQuiz normalizes the quiz's fields and checks them.
+func validateQuiz(quiz *models.Quiz) error {
+	quiz.Title = strings.TrimSpace(quiz.Title)
+	if quiz.Title == "" || len(quiz.Title) > 200 {
+		return appErrors.New(http.StatusBadRequest, "Title must be 1-200 characters")
+	}
+	if quiz.OpensAt.IsZero() || quiz.ClosesAt.IsZero() {
+		return appErrors.New(http.StatusBadRequest, "Opening and closing times are required")
+	}
+	if !quiz.ClosesAt.After(quiz.OpensAt) {
+		return appErrors.New(http.StatusBadRequest, "The quiz must close after it opens")
+	}
+	if quiz.TimeLimitMinutes != nil && (*quiz.TimeLimitMinutes < 1 || *quiz.TimeLimitMinutes > 24*60) {
+		return appErrors.New(http.StatusBadRequest, "Time limit must be 1-1440 minutes")
+	}
+	if quiz.MaxAttempts < 1 || quiz.MaxAttempts > 100 {
+		return appErrors.New(http.StatusBadRequest, "Attempts must be 1-100")
+	}
+	return nil
+}
+
+// requireQuizInstructor loads the quiz and checks that the user teaches its section.
+func (s *quizService) requireQuizInstructor(ctx context.Context, id, userID int64, isAdmin bool) (*models.Quiz, error) {
+	quiz, err := s.repo.GetQuiz(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, quiz.SectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return quiz, nil
+}
+
+func (s *quizService) CreateQuiz(ctx context.Context, sectionID int64, req *models.CreateQuizRequest, userID int64, isAdmin bool) (*models.Quiz, error) {
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, sectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	quiz := &models.Quiz{
+		SectionID:        sectionID,
+		Title:            req.Title,
+		Instructions:     optionalString(req.Instructions),
+		OpensAt:          req.OpensAt,
+		ClosesAt:         req.ClosesAt,
+		TimeLimitMinutes: req.TimeLimitMinutes,
+		MaxAttempts:      req.MaxAttempts,
+		ShuffleQuestions: req.ShuffleQuestions == nil || *req.ShuffleQuestions,
+		ShuffleOptions:   req.ShuffleOptions == nil || *req.ShuffleOptions,
+		CreatedBy:        &userID,
+	}
+	if quiz.MaxAttempts == 0 {
+		quiz.MaxAttempts = 1
+	}
+	if err := validateQuiz(quiz); err != nil {
+		return nil, err
+	}
+
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateQuiz(ctx, quiz); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityQuiz, quiz.ID, enums.ActionCreate, nil, quiz)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return quiz, nil
+}
+
+func (s *quizService) ListQuizzes(ctx context.Context, sectionID, userID int64, isAdmin bool) ([]models.Quiz, error) {
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, sectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return s.repo.ListForSection(ctx, sectionID)
+}
+
+func (s *quizService) GetQuiz(ctx context.Context, id, userID int64, isAdmin bool) (*models.Quiz, error) {
+	quiz, err := s.repo.GetQuiz(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if isAdmin {
+		return quiz, nil
+	}
+	enrolled, err := s.assignmentRepo.IsEnrolled(ctx, quiz.SectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		if _, err := requireSectionInstructor(ctx, s.sectionRepo, quiz.SectionID, userID, false); err != nil {
+			return nil, err
+		}
+	}
+	return quiz, nil
+}
+
+func (s *quizService) UpdateQuiz(ctx context.Context, id int64, req *models.UpdateQuizRequest, userID int64, isAdmin bool) (*models.Quiz, error) {
+	quiz, err := s.requireQuizInstructor(ctx, id, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	before := *quiz
+
+	if req.Title != nil {
+		quiz.Title = *req.Title
+	}
+	if req.Instructions != nil {
+		quiz.Instructions = optionalString(*req.Instructions)
+	}
+	if req.OpensAt != nil {
+		quiz.OpensAt = *req.OpensAt
+	}
+	if req.ClosesAt != nil {
+		quiz.ClosesAt = *req.ClosesAt
+	}
+	if req.TimeLimitMinutes != nil {
+		quiz.TimeLimitMinutes = req.TimeLimitMinutes
+	} else if req.ClearTimeLimit {
+		quiz.TimeLimitMinutes = nil
+	}
+	if req.MaxAttempts != nil {
+		quiz.MaxAttempts = *req.MaxAttempts
+	}
+	if req.ShuffleQuestions != nil {
+		quiz.ShuffleQuestions = *req.ShuffleQuestions
+	}
+	if req.ShuffleOptions != nil {
+		quiz.ShuffleOptions = *req.ShuffleOptions
+	}
+	if err := validateQuiz(quiz); err != nil {
+		return nil, err
+	}
+
+	// Attempts keep the deadline and layout they were started with
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateQuiz(ctx, quiz); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityQuiz, quiz.ID, enums.ActionUpdate, &before, quiz)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return quiz, nil
+}
+
+func (s *quizService) DeleteQuiz(ctx context.Context, id, userID int64, isAdmin bool) error {
+	quiz, err := s.requireQuizInstructor(ctx, id, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteQuiz(ctx, id); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityQuiz, id, enums.ActionDelete, quiz, nil)
+	})
+}
+
+func (s *quizService) GetQuizQuestions(ctx context.Context, id, userID int64, isAdmin bool) ([]models.Question, error) {
+	if _, err := s.requireQuizInstructor(ctx, id, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return s.repo.ListQuizQuestions(ctx, id)
+}
+
+func (s *quizService) SetQuizQuestions(ctx context.Context, id int64, req *models.SetQuizQuestionsRequest, userID int64, isAdmin bool) ([]models.Question, error) {
+	quiz, err := s.requireQuizInstructor(ctx, id, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.repo.ListQuizQuestions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]bool, len(req.QuestionIDs))
+	for _, questionID := range req.QuestionIDs {
+		if seen[questionID] {
+			return nil, appErrors.New(http.StatusBadRequest, "Question %d is listed twice", questionID)
+		}
+		seen[questionID] = true
+	}
+	questions, err := s.repo.GetQuestions(ctx, req.QuestionIDs)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[int64]bool, len(questions))
+	banks := make(map[int64]bool) // Bank IDs already checked
+	for _, question := range questions {
+		found[question.ID] = true
+		if banks[question.BankID] {
+			continue
+		}
+		if _, err := s.requireBank(ctx, question.BankID, userID, isAdmin); err != nil {
+			return nil, appErrors.New(http.StatusForbidden, "Question %d is in a question bank you do not own", question.ID)
+		}
+		banks[question.BankID] = true
+	}
+	for _, questionID := range req.QuestionIDs {
+		if !found[questionID] {
+			return nil, appErrors.New(http.StatusBadRequest, "Question %d does not exist", questionID)
+		}
+	}
+
+	var after []models.Question
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.SetQuizQuestions(ctx, id, req.QuestionIDs); err != nil {
+			return err
+		}
+		var err error
+		if after, err = s.repo.ListQuizQuestions(ctx, id); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityQuiz, quiz.ID, enums.ActionUpdate, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// finishAttempt submits an attempt whose time has run out and grades the objective questions
+// of a submitted attempt that has not been graded yet; other attempts are returned as they are.
+// Attempts are finished lazily, whenever they are looked at.
+func (s *quizService) finishAttempt(ctx context.Context, attempt *models.QuizAttempt) (*models.QuizAttempt, error) {
+	if attempt.Status == string(enums.QuizAttemptInProgress) {
+		if time.Now().Before(attempt.DeadlineAt.Add(s.cfg.QuizGracePeriod)) {
+			return attempt, nil
+		}
+		if err := s.repo.CloseAttempt(ctx, attempt.ID); err != nil && err != appErrors.ErrAttemptSubmitted {
+			return nil, err
+		}
+	} else if attempt.Score != nil {
+		return attempt, nil
+	}
+	if err := s.autoGrade(ctx, attempt); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAttempt(ctx, attempt.ID)
+}
+
+// finishUnfinished finishes the quiz's or the student's attempts that need it (zero for any).
+func (s *quizService) finishUnfinished(ctx context.Context, quizID, studentID int64) error {
+	ids, err := s.repo.ListUnfinished(ctx, quizID, studentID, s.cfg.QuizGracePeriod)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		attempt, err := s.repo.GetAttempt(ctx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := s.finishAttempt(ctx, attempt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// autoGrade grades the answers of a submitted attempt that need no teacher, including the
+// questions left unanswered.
+func (s *quizService) autoGrade(ctx context.Context, attempt *models.QuizAttempt) error {
+	questions, err := s.repo.ListQuizQuestions(ctx, attempt.QuizID)
+	if err != nil {
+		return err
+	}
+	responses, err := s.repo.ListResponses(ctx, attempt.ID)
+	if err != nil {
+		return err
+	}
+	answers := make(map[int64]*models.QuizAnswer, len(responses))
+	for i := range responses {
+		answers[responses[i].QuestionID] = &responses[i].QuizAnswer
+	}
+
+	graded := make([]models.QuizResponse, 0, len(questions))
+	for i := range questions {
+		question := &questions[i]
+		points := gradeAnswer(question, answers[question.ID])
+		if points == nil {
+			continue
+		}
+		graded = append(graded, models.QuizResponse{AttemptID: attempt.ID, QuizAnswer: models.QuizAnswer{QuestionID: question.ID}, Points: points})
+	}
+	return s.repo.RecordAutoGrades(ctx, attempt.ID, graded)
+}
+
+// forStudent hides the score of an attempt from its student until it is final.
+func forStudent(attempt models.QuizAttempt) models.QuizAttempt {
+	if attempt.Status != string(enums.QuizAttemptGraded) {
+		attempt.Score = nil
+	}
+	return attempt
+}
+
+// attemptDetail lays out the questions of an attempt with the answers given. Teachers also
+// see the answer key, and the points awarded so far; students see points and feedback once
+// the attempt is graded.
+func (s *quizService) attemptDetail(ctx context.Context, quiz *models.Quiz, attempt *models.QuizAttempt, teacher bool) (*models.QuizAttemptDetail, error) {
+	questions, err := s.repo.ListQuizQuestions(ctx, attempt.QuizID)
+	if err != nil {
+		return nil, err
+	}
+	responses, err := s.repo.ListResponses(ctx, attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*models.Question, len(questions))
+	for i := range questions {
+		byID[questions[i].ID] = &questions[i]
+	}
+	answers := make(map[int64]*models.QuizResponse, len(responses))
+	for i := range responses {
+		answers[responses[i].QuestionID] = &responses[i]
+	}
+
+	detail := &models.QuizAttemptDetail{Quiz: *quiz, Attempt: *attempt, Questions: make([]models.AttemptQuestion, 0, len(attempt.Layout))}
+	showGrades := teacher || attempt.Status == string(enums.QuizAttemptGraded)
+	if !teacher {
+		detail.Attempt = forStudent(*attempt)
+	}
+	if attempt.Status == string(enums.QuizAttemptInProgress) {
+		remaining := int64(time.Until(attempt.DeadlineAt).Seconds())
+		if remaining < 0 {
+			remaining = 0
+		}
+		detail.SecondsRemaining = &remaining
+	}
+
+	for i, item := range attempt.Layout {
+		question, ok := byID[item.QuestionID]
+		if !ok {
+			continue
+		}
+		entry := models.AttemptQuestion{
+			Number:     i + 1,
+			QuestionID: question.ID,
+			Type:       question.Type,
+			Prompt:     question.Prompt,
+			Points:     question.Points,
+			Options:    make([]models.QuestionOption, 0, len(item.Options)),
+		}
+		for _, optionID := range item.Options {
+			for _, option := range question.Options {
+				if option.ID == optionID {
+					entry.Options = append(entry.Options, option)
+				}
+			}
+		}
+		if response, ok := answers[question.ID]; ok {
+			if response.AnsweredAt != nil {
+				answer := response.QuizAnswer
+				entry.Answer = &answer
+			}
+			if showGrades {
+				entry.AwardedPoints = response.Points
+				entry.Feedback = response.Feedback
+			}
+		}
+		if teacher {
+			entry.Key = question
+		}
+		detail.Questions = append(detail.Questions, entry)
+	}
+	return detail, nil
+}
+
+func (s *quizService) ListAttempts(ctx context.Context, quizID, userID int64, isAdmin bool) ([]models.QuizAttempt, error) {
+	if _, err := s.requireQuizInstructor(ctx, quizID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	if err := s.finishUnfinished(ctx, quizID, 0); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAttempts(ctx, quizID)
+}
+
+func (s *quizService) GradingQueue(ctx context.Context, quizID, userID int64, isAdmin bool) ([]models.QueuedResponse, error) {
+	if _, err := s.requireQuizInstructor(ctx, quizID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	if err := s.finishUnfinished(ctx, quizID, 0); err != nil {
+		return nil, err
+	}
+	return s.repo.GradingQueue(ctx, quizID)
+}
+
+func (s *quizService) StartAttempt(ctx context.Context, quizID, studentID int64) (*models.QuizAttemptDetail, bool, error) {
+	// 1. Check the student can take the quiz now
+	quiz, err := s.repo.GetQuiz(ctx, quizID)
+	if err != nil {
+		return nil, false, err
+	}
+	enrolled, err := s.assignmentRepo.IsEnrolled(ctx, quiz.SectionID, studentID)
+	if err != nil {
+		return nil, false, err
+	}
+	if !enrolled {
+		return nil, false, appErrors.New(http.StatusForbidden, "Only students enrolled in the section can take the quiz")
+	}
+	now := time.Now()
+	if now.Before(quiz.OpensAt) || !now.Before(quiz.ClosesAt) {
+		return nil, false, appErrors.ErrQuizNotOpen
+	}
+	if quiz.QuestionCount == 0 {
+		return nil, false, appErrors.New(http.StatusConflict, "Quiz has no questions yet")
+	}
+
+	// 2. An attempt whose time ran out no longer counts as in progress
+	if err := s.finishUnfinished(ctx, quizID, studentID); err != nil {
+		return nil, false, err
+	}
+
+	// 3. Lay out the questions for this attempt; the database sets its deadline
+	questions, err := s.repo.ListQuizQuestions(ctx, quizID)
+	if err != nil {
+		return nil, false, err
+	}
+	attempt := &models.QuizAttempt{
+		QuizID:    quizID,
+		StudentID: studentID,
+		Layout:    buildLayout(questions, quiz.ShuffleQuestions, quiz.ShuffleOptions),
+	}
+	for _, question := range questions {
+		attempt.MaxScore += question.Points
+	}
+	started, err := s.repo.StartAttempt(ctx, attempt)
+	if err != nil {
+		return nil, false, err
+	}
+	if started {
+		logger.Logger.Info("Started quiz attempt", zap.Int64("quiz_id", quizID), zap.Int64("student_id", studentID), zap.Int64("attempt_id", attempt.ID))
+	}
+
+	detail, err := s.attemptDetail(ctx, quiz, attempt, false)
+	if err != nil {
+		return nil, false, err
+	}
+	return detail, started, nil
+}
+
+// loadAttempt loads an attempt, finished if due, with its quiz.
+func (s *quizService) loadAttempt(ctx context.Context, id int64) (*models.QuizAttempt, *models.Quiz, error) {
+	attempt, err := s.repo.GetAttempt(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	attempt, err = s.finishAttempt(ctx, attempt)
+	if err != nil {
+		return nil, nil, err
+	}
+	quiz, err := s.repo.GetQuiz(ctx, attempt.QuizID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attempt, quiz, nil
+}
+
+func (s *quizService) GetAttempt(ctx context.Context, id, userID int64, isAdmin bool) (*models.QuizAttemptDetail, error) {
+	attempt, quiz, err := s.loadAttempt(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.StudentID == userID && !isAdmin {
+		return s.attemptDetail(ctx, quiz, attempt, false)
+	}
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, quiz.SectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return s.attemptDetail(ctx, quiz, attempt, true)
+}
+
+// normalizeAnswers checks the answers against the questions of the attempt.
+func (s *quizService) normalizeAnswers(ctx context.Context, attempt *models.QuizAttempt, answers []models.QuizAnswer) ([]models.QuizAnswer, error) {
+	questions, err := s.repo.ListQuizQuestions(ctx, attempt.QuizID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*models.Question, len(questions))
+	for i := range questions {
+		byID[questions[i].ID] = &questions[i]
+	}
+
+	normalized := make([]models.QuizAnswer, 0, len(answers))
+	seen := make(map[int64]bool, len(answers))
+	for _, answer := range answers {
+		question, ok := byID[answer.QuestionID]
+		if !ok {
+			return nil, appErrors.New(http.StatusBadRequest, "Question %d is not part of this quiz", answer.QuestionID)
+		}
+		if seen[answer.QuestionID] {
+			return nil, appErrors.New(http.StatusBadRequest, "Question %d is answered twice", answer.QuestionID)
+		}
+		seen[answer.QuestionID] = true
+		answer, err := normalizeAnswer(question, answer)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, answer)
+	}
+	return normalized, nil
+}
+
+// requireOwnAttempt loads the student's attempt, finished if due.
+func (s *quizService) requireOwnAttempt(ctx context.Context, id, studentID int64) (*models.QuizAttempt, *models.Quiz, error) {
+	attempt, quiz, err := s.loadAttempt(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attempt.StudentID != studentID {
+		return nil, nil, appErrors.ErrNotFound
+	}
+	return attempt, quiz, nil
+}
+
+func (s *quizService) SaveAnswers(ctx context.Context, id, studentID int64, req *models.SaveAnswersRequest) (*models.QuizAttemptDetail, error) {
+	attempt, quiz, err := s.requireOwnAttempt(ctx, id, studentID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := s.normalizeAnswers(ctx, attempt, req.Answers)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveAnswers(ctx, attempt.ID, answers, s.cfg.QuizGracePeriod); err != nil {
+		return nil, err
+	}
+	return s.attemptDetail(ctx, quiz, attempt, false)
+}
+
+func (s *quizService) SubmitAttempt(ctx context.Context, id, studentID int64, req *models.SaveAnswersRequest) (*models.QuizAttemptDetail, error) {
+	attempt, quiz, err := s.requireOwnAttempt(ctx, id, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status != string(enums.QuizAttemptInProgress) {
+		return nil, appErrors.ErrAttemptSubmitted
+	}
+
+	// Final answers arriving after the deadline are dropped; what was saved in time counts
+	if len(req.Answers) > 0 {
+		answers, err := s.normalizeAnswers(ctx, attempt, req.Answers)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.repo.SaveAnswers(ctx, attempt.ID, answers, s.cfg.QuizGracePeriod); err != nil && err != appErrors.ErrQuizTimeUp {
+			return nil, err
+		}
+	}
+	if err := s.repo.CloseAttempt(ctx, attempt.ID); err != nil {
+		return nil, err
+	}
+
+	// The attempt has been submitted; if grading fails it is retried when the attempt is next looked at
+	if err := s.autoGrade(ctx, attempt); err != nil {
+		logger.Logger.Error("Failed to grade quiz attempt", zap.Error(err), zap.Int64("attempt_id", attempt.ID))
+	}
+	attempt, err = s.repo.GetAttempt(ctx, attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.attemptDetail(ctx, quiz, attempt, false)
+}
+
+func (s *quizService) GradeResponse(ctx context.Context, attemptID, questionID int64, req *models.GradeResponseRequest, userID int64, isAdmin bool) (*models.QuizAttemptDetail, error) {
+	attempt, quiz, err := s.loadAttempt(ctx, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, quiz.SectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	var question *models.Question
+	questions, err := s.repo.ListQuizQuestions(ctx, quiz.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range questions {
+		if questions[i].ID == questionID {
+			question = &questions[i]
+		}
+	}
+	if question == nil {
+		return nil, appErrors.ErrNotFound
+	}
+	if req.Points < 0 || req.Points > question.Points {
+		return nil, appErrors.New(http.StatusBadRequest, "Points must be between 0 and %g", question.Points)
+	}
+
+	points := roundPoints(req.Points)
+	response := &models.QuizResponse{
+		AttemptID:  attempt.ID,
+		QuizAnswer: models.QuizAnswer{QuestionID: questionID},
+		Points:     &points,
+		Feedback:   optionalString(req.Feedback),
+		GradedBy:   &userID,
+	}
+	if err := s.repo.GradeResponse(ctx, response); err != nil {
+		return nil, err
+	}
+	attempt, err = s.repo.GetAttempt(ctx, attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.attemptDetail(ctx, quiz, attempt, true)
+}
+
+func (s *quizService) GetStudentQuizzes(ctx context.Context, studentID, termID int64) (*models.StudentQuizzes, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, studentID); err != nil {
+		return nil, err
+	}
+	term, err := resolveTerm(ctx, s.termRepo, termID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.finishUnfinished(ctx, 0, studentID); err != nil {
+		return nil, err
+	}
+
+	quizzes, err := s.repo.ListForStudent(ctx, studentID, term.ID)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := s.repo.ListStudentAttempts(ctx, studentID, term.ID)
+	if err != nil {
+		return nil, err
+	}
+	byQuiz := make(map[int64][]models.QuizAttempt, len(quizzes))
+	for _, attempt := range attempts {
+		byQuiz[attempt.QuizID] = append(byQuiz[attempt.QuizID], forStudent(attempt))
+	}
+
+	result := &models.StudentQuizzes{Term: term, Quizzes: make([]models.StudentQuiz, 0, len(quizzes))}
+	for _, quiz := range quizzes {
+		entry := models.StudentQuiz{Quiz: quiz, Attempts: byQuiz[quiz.ID]}
+		if entry.Attempts == nil {
+			entry.Attempts = make([]models.QuizAttempt, 0)
+		}
+		entry.AttemptsLeft = max(quiz.MaxAttempts-len(entry.Attempts), 0)
+		result.Quizzes = append(result.Quizzes, entry)
+	}
+	return result, nil
+}
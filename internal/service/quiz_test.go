@@ -0,0 +1,67 @@
+// internal/service/quiz_test.go
+package service
+
+import (
+	"testing"
+
+	"student-portal/internal/commons/enums"
+	"student-portal/internal/models"
+)
+
+func TestGradeAnswer(t *testing.T) {
+	yes, no := true, false
+	number := func(v float64) *float64 { return &v }
+	text := func(s string) *string { return &s }
+
+	multipleChoice := &models.Question{Type: string(enums.QuestionTypeMultipleChoice), Points: 2, CorrectOptions: []int64{3}}
+	multiSelect := &models.Question{Type: string(enums.QuestionTypeMultiSelect), Points: 3, CorrectOptions: []int64{1, 2, 4}}
+	trueFalse := &models.Question{Type: string(enums.QuestionTypeTrueFalse), Points: 1, CorrectBool: &yes}
+	numeric := &models.Question{Type: string(enums.QuestionTypeNumeric), Points: 4, NumericAnswer: number(9.81), Tolerance: 0.05}
+	shortAnswer := &models.Question{Type: string(enums.QuestionTypeShortAnswer), Points: 5}
+
+	tests := []struct {
+		name     string
+		question *models.Question
+		answer   *models.QuizAnswer
+		want     float64 // Negative when the answer is left for the teacher
+	}{
+		{"multiple choice correct", multipleChoice, &models.QuizAnswer{Selected: []int64{3}}, 2},
+		{"multiple choice wrong", multipleChoice, &models.QuizAnswer{Selected: []int64{1}}, 0},
+		{"multiple choice with two picks", multipleChoice, &models.QuizAnswer{Selected: []int64{3, 1}}, 0},
+		{"multiple choice unanswered", multipleChoice, nil, 0},
+
+		{"multi-select all correct", multiSelect, &models.QuizAnswer{Selected: []int64{1, 2, 4}}, 3},
+		{"multi-select partly correct", multiSelect, &models.QuizAnswer{Selected: []int64{1, 4}}, 2},
+		{"multi-select wrong pick takes a share", multiSelect, &models.QuizAnswer{Selected: []int64{1, 2, 3}}, 1},
+		{"multi-select never below zero", multiSelect, &models.QuizAnswer{Selected: []int64{1, 3, 5}}, 0},
+		{"multi-select rounded", &models.Question{Type: string(enums.QuestionTypeMultiSelect), Points: 1, CorrectOptions: []int64{1, 2, 3}}, &models.QuizAnswer{Selected: []int64{1}}, 0.33},
+		{"multi-select nothing picked", multiSelect, &models.QuizAnswer{}, 0},
+
+		{"true/false correct", trueFalse, &models.QuizAnswer{Value: &yes}, 1},
+		{"true/false wrong", trueFalse, &models.QuizAnswer{Value: &no}, 0},
+		{"true/false unanswered", trueFalse, &models.QuizAnswer{}, 0},
+
+		{"numeric exact", numeric, &models.QuizAnswer{Number: number(9.81)}, 4},
+		{"numeric within tolerance", numeric, &models.QuizAnswer{Number: number(9.78)}, 4},
+		{"numeric at the tolerance", numeric, &models.QuizAnswer{Number: number(9.86)}, 4},
+		{"numeric outside tolerance", numeric, &models.QuizAnswer{Number: number(9.87)}, 0},
+		{"numeric unanswered", numeric, &models.QuizAnswer{}, 0},
+
+		{"short answer left for the teacher", shortAnswer, &models.QuizAnswer{Text: text("Photosynthesis")}, -1},
+		{"short answer blank", shortAnswer, &models.QuizAnswer{Text: text("  ")}, 0},
+		{"short answer unanswered", shortAnswer, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := gradeAnswer(tt.question, tt.answer)
+			switch {
+			case tt.want < 0 && got != nil:
+				t.Errorf("gradeAnswer = %v, want nil", *got)
+			case tt.want >= 0 && got == nil:
+				t.Errorf("gradeAnswer = nil, want %v", tt.want)
+			case tt.want >= 0 && *got != tt.want:
+				t.Errorf("gradeAnswer = %v, want %v", *got, tt.want)
+			}
+		})
+	}
+}
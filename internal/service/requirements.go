@@ -0,0 +1,231 @@
+// internal/service/requirements.go
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+)
+
+// gradeScale lists the letter grades from best to worst.
+var gradeScale = []string{"A+", "A", "A-", "B+", "B", "B-", "C+", "C", "C-", "D+", "D", "D-", "F"}
+
+// failingGrade is the only grade that does not complete a course.
+const failingGrade = "F"
+
+// Limits keep prerequisite expressions readable and cheap to evaluate.
+const (
+	maxRequirementDepth = 5
+	maxRequirementNodes = 50
+)
+
+// gradeRank returns the position of the grade on the scale (0 is best) and false for unknown grades.
+func gradeRank(grade string) (int, bool) {
+	for i, g := range gradeScale {
+		if g == grade {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// normalizeGrade upper-cases and validates a letter grade.
+func normalizeGrade(grade string) (string, error) {
+	grade = strings.ToUpper(strings.TrimSpace(grade))
+	if _, ok := gradeRank(grade); !ok {
+		return "", appErrors.New(http.StatusBadRequest, "Invalid grade '%s'", grade)
+	}
+	return grade, nil
+}
+
+// meetsGrade reports whether a grade is at least the minimum; an empty minimum accepts any passing grade.
+func meetsGrade(grade, minGrade string) bool {
+	if minGrade == "" {
+		return grade != failingGrade
+	}
+	rank, ok := gradeRank(grade)
+	minRank, minOK := gradeRank(minGrade)
+	return ok && minOK && rank <= minRank
+}
+
+// bestGrades returns the best grade per course from a student's completions.
+func bestGrades(completions []models.CourseCompletion) map[int64]string {
+	best := make(map[int64]string, len(completions))
+	for _, c := range completions {
+		current, seen := best[c.CourseID]
+		if !seen || meetsGrade(c.Grade, current) {
+			best[c.CourseID] = c.Grade
+		}
+	}
+	return best
+}
+
+// validateRequirementNode checks the shape of a prerequisite expression, normalizes its
+// grades and collects the referenced course IDs.
+func validateRequirementNode(node *models.RequirementNode, depth int, count *int, courseIDs map[int64]bool) error {
+	*count++
+	if depth > maxRequirementDepth || *count > maxRequirementNodes {
+		return appErrors.New(http.StatusBadRequest, "Prerequisites may nest at most %d levels and have at most %d requirements", maxRequirementDepth, maxRequirementNodes)
+	}
+
+	kinds := 0
+	if len(node.All) > 0 {
+		kinds++
+	}
+	if len(node.Any) > 0 {
+		kinds++
+	}
+	if node.CourseID != 0 {
+		kinds++
+	}
+	if kinds != 1 {
+		return appErrors.New(http.StatusBadRequest, "Each prerequisite must have exactly one of a non-empty 'all', a non-empty 'any' or a 'course_id'")
+	}
+
+	if node.CourseID != 0 {
+		if node.MinGrade != "" {
+			grade, err := normalizeGrade(node.MinGrade)
+			if err != nil {
+				return err
+			}
+			node.MinGrade = grade
+		}
+		courseIDs[node.CourseID] = true
+		return nil
+	}
+	if node.MinGrade != "" {
+		return appErrors.New(http.StatusBadRequest, "'min_grade' only applies to course requirements")
+	}
+
+	for i := range node.All {
+		if err := validateRequirementNode(&node.All[i], depth+1, count, courseIDs); err != nil {
+			return err
+		}
+	}
+	for i := range node.Any {
+		if err := validateRequirementNode(&node.Any[i], depth+1, count, courseIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requirementContext is what a student's requirements are evaluated against.
+type requirementContext struct {
+	grades   map[int64]string // Best grade per completed course
+	enrolled map[int64]bool   // Courses the student is enrolled or waitlisted in
+	codes    map[int64]string // Course codes for the explanation
+}
+
+// evaluatePrerequisite checks a prerequisite expression. Unmet course requirements are
+// appended to unmet.
+func evaluatePrerequisite(node models.RequirementNode, rc requirementContext, unmet *[]models.RequirementCheck) models.RequirementCheck {
+	switch {
+	case len(node.All) > 0, len(node.Any) > 0:
+		check := models.RequirementCheck{Type: "all"}
+		children := node.All
+		if len(node.Any) > 0 {
+			check.Type, children = "any", node.Any
+		}
+		// Collect the unmet requirements of an "any" group only if none of its options is met.
+		var groupUnmet []models.RequirementCheck
+		metCount := 0
+		for _, child := range children {
+			result := evaluatePrerequisite(child, rc, &groupUnmet)
+			if result.Met {
+				metCount++
+			}
+			check.Requirements = append(check.Requirements, result)
+		}
+		if check.Type == "all" {
+			check.Met = metCount == len(children)
+		} else {
+			check.Met = metCount > 0
+		}
+		if !check.Met {
+			*unmet = append(*unmet, groupUnmet...)
+		}
+		return check
+
+	default:
+		check := models.RequirementCheck{
+			Type:       "course",
+			CourseID:   node.CourseID,
+			CourseCode: rc.codes[node.CourseID],
+			MinGrade:   node.MinGrade,
+		}
+		grade, completed := rc.grades[node.CourseID]
+		check.BestGrade = grade
+		switch {
+		case !completed:
+			check.Reason = "not completed"
+		case !meetsGrade(grade, node.MinGrade):
+			if node.MinGrade == "" {
+				check.Reason = "not passed"
+			} else {
+				check.Reason = fmt.Sprintf("grade %s is below the required %s", grade, node.MinGrade)
+			}
+		default:
+			check.Met = true
+		}
+		if !check.Met {
+			*unmet = append(*unmet, check)
+		}
+		return check
+	}
+}
+
+// evaluateCorequisite checks that a co-requisite is passed or taken at the same time.
+func evaluateCorequisite(courseID int64, rc requirementContext) models.RequirementCheck {
+	check := models.RequirementCheck{Type: "corequisite", CourseID: courseID, CourseCode: rc.codes[courseID]}
+	grade, completed := rc.grades[courseID]
+	check.BestGrade = grade
+	if (completed && meetsGrade(grade, "")) || rc.enrolled[courseID] {
+		check.Met = true
+	} else {
+		check.Reason = "not completed and not enrolled at the same time"
+	}
+	return check
+}
+
+// requirementCourseIDs returns every course referenced by a course's requirements.
+func requirementCourseIDs(course *models.Course) []int64 {
+	ids := append([]int64{}, course.CorequisiteIDs...)
+	var walk func(node models.RequirementNode)
+	walk = func(node models.RequirementNode) {
+		if node.CourseID != 0 {
+			ids = append(ids, node.CourseID)
+		}
+		for _, child := range node.All {
+			walk(child)
+		}
+		for _, child := range node.Any {
+			walk(child)
+		}
+	}
+	if course.Prerequisites != nil {
+		walk(*course.Prerequisites)
+	}
+	return ids
+}
+
+// checkRequirements evaluates a course's prerequisites and co-requisites for a student.
+func checkRequirements(course *models.Course, rc requirementContext) *models.EnrollmentEligibility {
+	eligibility := &models.EnrollmentEligibility{CourseID: course.ID, Unmet: make([]models.RequirementCheck, 0)}
+	if course.Prerequisites != nil {
+		check := evaluatePrerequisite(*course.Prerequisites, rc, &eligibility.Unmet)
+		eligibility.Prerequisites = &check
+	}
+	for _, id := range course.CorequisiteIDs {
+		check := evaluateCorequisite(id, rc)
+		if !check.Met {
+			eligibility.Unmet = append(eligibility.Unmet, check)
+		}
+		eligibility.Corequisites = append(eligibility.Corequisites, check)
+	}
+	eligibility.Eligible = len(eligibility.Unmet) == 0
+	return eligibility
+}
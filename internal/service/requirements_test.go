@@ -0,0 +1,131 @@
+// internal/service/requirements_test.go
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"student-portal/internal/models"
+)
+
+func TestCheckRequirements(t *testing.T) {
+	course := func(id int64, minGrade string) models.RequirementNode {
+		return models.RequirementNode{CourseID: id, MinGrade: minGrade}
+	}
+	all := func(nodes ...models.RequirementNode) *models.RequirementNode {
+		return &models.RequirementNode{All: nodes}
+	}
+	anyOf := func(nodes ...models.RequirementNode) models.RequirementNode {
+		return models.RequirementNode{Any: nodes}
+	}
+
+	tests := []struct {
+		name          string
+		prerequisites *models.RequirementNode
+		corequisites  []int64
+		grades        map[int64]string
+		enrolled      map[int64]bool
+		wantEligible  bool
+		wantUnmet     []int64
+	}{
+		{
+			name:          "all met",
+			prerequisites: all(course(1, ""), course(2, "")),
+			grades:        map[int64]string{1: "B", 2: "C-"},
+			wantEligible:  true,
+		},
+		{
+			name:          "all with one missing",
+			prerequisites: all(course(1, ""), course(2, "")),
+			grades:        map[int64]string{1: "B"},
+			wantUnmet:     []int64{2},
+		},
+		{
+			name:          "failed course does not count",
+			prerequisites: all(course(1, "")),
+			grades:        map[int64]string{1: "F"},
+			wantUnmet:     []int64{1},
+		},
+		{
+			name:          "grade below minimum",
+			prerequisites: all(course(1, "B")),
+			grades:        map[int64]string{1: "B-"},
+			wantUnmet:     []int64{1},
+		},
+		{
+			name:          "grade at minimum",
+			prerequisites: all(course(1, "B")),
+			grades:        map[int64]string{1: "B"},
+			wantEligible:  true,
+		},
+		{
+			name:          "any with one option met",
+			prerequisites: all(anyOf(course(1, ""), course(2, ""))),
+			grades:        map[int64]string{2: "A"},
+			wantEligible:  true,
+		},
+		{
+			name:          "any with no option met reports every option",
+			prerequisites: all(anyOf(course(1, ""), course(2, "A"))),
+			grades:        map[int64]string{2: "B"},
+			wantUnmet:     []int64{1, 2},
+		},
+		{
+			name:          "nested and inside or",
+			prerequisites: all(course(1, ""), anyOf(*all(course(2, ""), course(3, "")), course(4, "B+"))),
+			grades:        map[int64]string{1: "C", 2: "A", 3: "A-"},
+			wantEligible:  true,
+		},
+		{
+			name:          "nested and inside or partly met",
+			prerequisites: all(course(1, ""), anyOf(*all(course(2, ""), course(3, "")), course(4, "B+"))),
+			grades:        map[int64]string{1: "C", 2: "A", 4: "B"},
+			wantUnmet:     []int64{3, 4},
+		},
+		{
+			name:         "corequisite taken at the same time",
+			corequisites: []int64{5},
+			enrolled:     map[int64]bool{5: true},
+			wantEligible: true,
+		},
+		{
+			name:         "corequisite missing",
+			corequisites: []int64{5},
+			grades:       map[int64]string{5: "F"},
+			wantUnmet:    []int64{5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &models.Course{ID: 100, Prerequisites: tt.prerequisites, CorequisiteIDs: tt.corequisites}
+			rc := requirementContext{grades: tt.grades, enrolled: tt.enrolled, codes: map[int64]string{}}
+
+			got := checkRequirements(c, rc)
+			if got.Eligible != tt.wantEligible {
+				t.Errorf("Eligible = %t, want %t", got.Eligible, tt.wantEligible)
+			}
+			unmet := make([]int64, 0, len(got.Unmet))
+			for _, check := range got.Unmet {
+				unmet = append(unmet, check.CourseID)
+			}
+			if !slices.Equal(unmet, tt.wantUnmet) {
+				t.Errorf("Unmet = %v, want %v", unmet, tt.wantUnmet)
+			}
+		})
+	}
+}
+
+func TestBestGrades(t *testing.T) {
+	completions := []models.CourseCompletion{
+		{CourseID: 1, Grade: "F"},
+		{CourseID: 1, Grade: "C+"},
+		{CourseID: 1, Grade: "B-"},
+		{CourseID: 2, Grade: "A"},
+		{CourseID: 2, Grade: "D"},
+	}
+	got := bestGrades(completions)
+	if got[1] != "B-" || got[2] != "A" {
+		t.Errorf("bestGrades = %v, want map[1:B- 2:A]", got)
+	}
+}
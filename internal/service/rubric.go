@@ -0,0 +1,167 @@
+// internal/service/rubric.go
+package service
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+)
+
+// Limits on rubric content.
+const (
+	maxRubricCriteria   = 50
+	maxCriterionLevels  = 10
+	maxLevelPoints      = 1000 // NUMERIC(7, 2) leaves room for sums of many criteria
+	maxRubricTextLength = 2000
+	maxScoreComment     = 5000
+)
+
+// rubricFromRequest validates the request into the rubric, replacing its criteria.
+func rubricFromRequest(rubric *models.Rubric, req *models.RubricRequest) error {
+	rubric.Title = strings.TrimSpace(req.Title)
+	if rubric.Title == "" || len(rubric.Title) > 200 {
+		return appErrors.New(http.StatusBadRequest, "Title must be 1-200 characters")
+	}
+	if len(req.Description) > maxRubricTextLength {
+		return appErrors.New(http.StatusBadRequest, "Description must be at most %d characters", maxRubricTextLength)
+	}
+	rubric.Description = optionalString(req.Description)
+	if len(req.Criteria) == 0 || len(req.Criteria) > maxRubricCriteria {
+		return appErrors.New(http.StatusBadRequest, "A rubric needs 1-%d criteria", maxRubricCriteria)
+	}
+
+	rubric.Criteria = make([]models.RubricCriterion, 0, len(req.Criteria))
+	rubric.MaxPoints = 0
+	for i, criterionReq := range req.Criteria {
+		criterion := models.RubricCriterion{
+			Title:       strings.TrimSpace(criterionReq.Title),
+			Description: optionalString(criterionReq.Description),
+		}
+		if criterion.Title == "" || len(criterion.Title) > 200 {
+			return appErrors.New(http.StatusBadRequest, "Title of criterion %d must be 1-200 characters", i+1)
+		}
+		if len(criterionReq.Description) > maxRubricTextLength {
+			return appErrors.New(http.StatusBadRequest, "Description of criterion %d must be at most %d characters", i+1, maxRubricTextLength)
+		}
+		if len(criterionReq.Levels) == 0 || len(criterionReq.Levels) > maxCriterionLevels {
+			return appErrors.New(http.StatusBadRequest, "Criterion %d needs 1-%d levels", i+1, maxCriterionLevels)
+		}
+		criterion.Levels = make([]models.RubricLevel, 0, len(criterionReq.Levels))
+		for j, levelReq := range criterionReq.Levels {
+			level := models.RubricLevel{
+				Title:       strings.TrimSpace(levelReq.Title),
+				Description: optionalString(levelReq.Description),
+				Points:      roundPoints(levelReq.Points),
+			}
+			if level.Title == "" || len(level.Title) > 200 {
+				return appErrors.New(http.StatusBadRequest, "Title of level %d of criterion %d must be 1-200 characters", j+1, i+1)
+			}
+			if len(levelReq.Description) > maxRubricTextLength {
+				return appErrors.New(http.StatusBadRequest, "Description of level %d of criterion %d must be at most %d characters", j+1, i+1, maxRubricTextLength)
+			}
+			if math.IsNaN(level.Points) || level.Points < 0 || level.Points >= maxLevelPoints {
+				return appErrors.New(http.StatusBadRequest, "Points of level %d of criterion %d must be between 0 and %d", j+1, i+1, maxLevelPoints)
+			}
+			criterion.Levels = append(criterion.Levels, level)
+			criterion.MaxPoints = math.Max(criterion.MaxPoints, level.Points)
+		}
+		rubric.Criteria = append(rubric.Criteria, criterion)
+		rubric.MaxPoints += criterion.MaxPoints
+	}
+	if rubric.MaxPoints <= 0 {
+		return appErrors.New(http.StatusBadRequest, "A rubric must be worth more than 0 points")
+	}
+	return nil
+}
+
+// scoreRubric validates the scores of one piece of work against the rubric. Every criterion
+// must be scored exactly once, by one of its levels or by points up to its best level. The
+// scores come back in the rubric's order with their total.
+func scoreRubric(rubric *models.Rubric, entries []models.CriterionScoreEntry) ([]models.RubricScore, float64, error) {
+	byCriterion := make(map[int64]*models.CriterionScoreEntry, len(entries))
+	for i := range entries {
+		entry := &entries[i]
+		if !rubricHasCriterion(rubric, entry.CriterionID) {
+			return nil, 0, appErrors.New(http.StatusBadRequest, "Criterion %d is not part of the rubric", entry.CriterionID)
+		}
+		if byCriterion[entry.CriterionID] != nil {
+			return nil, 0, appErrors.New(http.StatusBadRequest, "Criterion %d is scored more than once", entry.CriterionID)
+		}
+		byCriterion[entry.CriterionID] = entry
+	}
+
+	scores := make([]models.RubricScore, 0, len(rubric.Criteria))
+	total := 0.0
+	for _, criterion := range rubric.Criteria {
+		entry := byCriterion[criterion.ID]
+		if entry == nil {
+			return nil, 0, appErrors.New(http.StatusBadRequest, "Criterion %q has not been scored", criterion.Title)
+		}
+		if len(entry.Comment) > maxScoreComment {
+			return nil, 0, appErrors.New(http.StatusBadRequest, "Comment on criterion %q must be at most %d characters", criterion.Title, maxScoreComment)
+		}
+		score := models.RubricScore{CriterionID: criterion.ID, LevelID: entry.LevelID, Comment: optionalString(entry.Comment)}
+		switch {
+		case entry.LevelID != nil:
+			level := criterionLevel(&criterion, *entry.LevelID)
+			if level == nil {
+				return nil, 0, appErrors.New(http.StatusBadRequest, "Level %d is not a level of criterion %q", *entry.LevelID, criterion.Title)
+			}
+			score.Points = level.Points
+			if entry.Points != nil && roundPoints(*entry.Points) != level.Points {
+				return nil, 0, appErrors.New(http.StatusBadRequest, "Give either a level or points for criterion %q", criterion.Title)
+			}
+		case entry.Points != nil:
+			score.Points = roundPoints(*entry.Points)
+			if math.IsNaN(score.Points) || score.Points < 0 || score.Points > criterion.MaxPoints {
+				return nil, 0, appErrors.New(http.StatusBadRequest, "Points for criterion %q must be between 0 and %s", criterion.Title, strconv.FormatFloat(criterion.MaxPoints, 'f', -1, 64))
+			}
+		default:
+			return nil, 0, appErrors.New(http.StatusBadRequest, "Criterion %q needs a level or points", criterion.Title)
+		}
+		scores = append(scores, score)
+		total += score.Points
+	}
+	return scores, roundPoints(total), nil
+}
+
+// rubricHasCriterion reports whether the criterion is part of the rubric.
+func rubricHasCriterion(rubric *models.Rubric, criterionID int64) bool {
+	for _, criterion := range rubric.Criteria {
+		if criterion.ID == criterionID {
+			return true
+		}
+	}
+	return false
+}
+
+// criterionLevel returns the level of the criterion with the ID, or nil.
+func criterionLevel(criterion *models.RubricCriterion, levelID int64) *models.RubricLevel {
+	for i := range criterion.Levels {
+		if criterion.Levels[i].ID == levelID {
+			return &criterion.Levels[i]
+		}
+	}
+	return nil
+}
+
+// rubricTotal sums up the points of the scores.
+func rubricTotal(scores []models.RubricScore) float64 {
+	total := 0.0
+	for _, score := range scores {
+		total += score.Points
+	}
+	return roundPoints(total)
+}
+
+// scaleRubricPoints converts rubric points into points of an assignment worth maxPoints.
+func scaleRubricPoints(total, rubricMax, maxPoints float64) float64 {
+	if rubricMax <= 0 {
+		return 0
+	}
+	return roundPoints(math.Min(total/rubricMax, 1) * maxPoints)
+}
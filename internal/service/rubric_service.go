@@ -0,0 +1,317 @@
+// internal/service/rubric_service.go
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+)
+
+// RubricService defines the methods for rubrics and rubric-based grading. Methods taking a
+// userID and isAdmin act on behalf of that user: teachers manage their own rubrics, and the
+// section's instructor and admins attach them to assignments and score with them.
+type RubricService interface {
+	// ListRubrics returns the user's rubrics; admins see every rubric.
+	ListRubrics(ctx context.Context, userID int64, isAdmin bool) ([]models.Rubric, error)
+	CreateRubric(ctx context.Context, req *models.RubricRequest, userID int64) (*models.Rubric, error)
+	GetRubric(ctx context.Context, id, userID int64, isAdmin bool) (*models.Rubric, error)
+	// UpdateRubric replaces the rubric's criteria and levels, as long as nobody has been
+	// scored with it.
+	UpdateRubric(ctx context.Context, id int64, req *models.RubricRequest, userID int64, isAdmin bool) (*models.Rubric, error)
+	// DeleteRubric removes a rubric that is not attached to any assignment.
+	DeleteRubric(ctx context.Context, id, userID int64, isAdmin bool) error
+
+	// GetAssignmentRubric returns the rubric of the assignment to its instructor, admins and
+	// the students of its section.
+	GetAssignmentRubric(ctx context.Context, assignmentID, userID int64, isAdmin bool) (*models.Rubric, error)
+	// SetAssignmentRubric attaches one of the user's rubrics to the assignment, or detaches it,
+	// until the first student has been scored or peer reviewed.
+	SetAssignmentRubric(ctx context.Context, assignmentID int64, req *models.SetAssignmentRubricRequest, userID int64, isAdmin bool) (*models.Assignment, error)
+	// GetAssessment returns a student's rubric scores on the assignment to its instructor.
+	GetAssessment(ctx context.Context, assignmentID, studentID, userID int64, isAdmin bool) (*models.RubricAssessment, error)
+	// ScoreRubric scores every criterion of the assignment's rubric for a student. The total,
+	// scaled to the assignment's points, becomes the student's grade.
+	ScoreRubric(ctx context.Context, assignmentID, studentID int64, req *models.ScoreRubricRequest, userID int64, isAdmin bool) (*models.RubricAssessment, error)
+	// GetOwnFeedback returns the student's rubric scores on the assignment once their grade
+	// has been released.
+	GetOwnFeedback(ctx context.Context, assignmentID, studentID int64) (*models.RubricAssessment, error)
+}
+
+type rubricService struct {
+	repo           repository.RubricRepository
+	assignmentRepo repository.AssignmentRepository
+	sectionRepo    repository.SectionRepository
+	gradebookRepo  repository.GradebookRepository
+	transactor     repository.Transactor
+	gradebookSvc   GradebookService
+	historySvc     HistoryService
+}
+
+// NewRubricService creates a new RubricService instance.
+func NewRubricService(repo repository.RubricRepository, assignmentRepo repository.AssignmentRepository, sectionRepo repository.SectionRepository, gradebookRepo repository.GradebookRepository, transactor repository.Transactor, gradebookSvc GradebookService, historySvc HistoryService) RubricService {
+	return &rubricService{repo: repo, assignmentRepo: assignmentRepo, sectionRepo: sectionRepo, gradebookRepo: gradebookRepo, transactor: transactor, gradebookSvc: gradebookSvc, historySvc: historySvc}
+}
+
+// requireRubric loads the rubric and checks that the user owns it.
+func (s *rubricService) requireRubric(ctx context.Context, id, userID int64, isAdmin bool) (*models.Rubric, error) {
+	rubric, err := s.repo.GetRubric(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && (rubric.OwnerID == nil || *rubric.OwnerID != userID) {
+		return nil, appErrors.New(http.StatusForbidden, "Only the owner of the rubric has access")
+	}
+	return rubric, nil
+}
+
+// requireScoringAssignment loads the assignment with its rubric and checks that the user
+// teaches its section.
+func (s *rubricService) requireScoringAssignment(ctx context.Context, assignmentID, userID int64, isAdmin bool) (*models.Assignment, *models.Rubric, error) {
+	assignment, err := s.assignmentRepo.GetAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, assignment.SectionID, userID, isAdmin); err != nil {
+		return nil, nil, err
+	}
+	rubric, err := s.assignmentRubric(ctx, assignment)
+	if err != nil {
+		return nil, nil, err
+	}
+	return assignment, rubric, nil
+}
+
+// assignmentRubric returns the rubric the assignment is graded against.
+func (s *rubricService) assignmentRubric(ctx context.Context, assignment *models.Assignment) (*models.Rubric, error) {
+	if assignment.RubricID == nil {
+		return nil, appErrors.New(http.StatusNotFound, "Assignment is not graded with a rubric")
+	}
+	return s.repo.GetRubric(ctx, *assignment.RubricID)
+}
+
+// studentGrade returns the student's grade on the assignment, or nil if not graded yet.
+func (s *rubricService) studentGrade(ctx context.Context, assignment *models.Assignment, studentID int64) (*models.AssignmentGrade, error) {
+	grades, err := s.gradebookRepo.ListStudentGrades(ctx, assignment.SectionID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range grades {
+		if grades[i].AssignmentID == assignment.ID {
+			return &grades[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// assessment gathers the student's scores on the assignment.
+func (s *rubricService) assessment(ctx context.Context, assignment *models.Assignment, rubric *models.Rubric, studentID int64) (*models.RubricAssessment, error) {
+	scores, err := s.repo.ListScores(ctx, assignment.ID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	grade, err := s.studentGrade(ctx, assignment, studentID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RubricAssessment{
+		AssignmentID: assignment.ID,
+		StudentID:    studentID,
+		Rubric:       rubric,
+		Scores:       scores,
+		Total:        rubricTotal(scores),
+		Grade:        grade,
+	}, nil
+}
+
+func (s *rubricService) ListRubrics(ctx context.Context, userID int64, isAdmin bool) ([]models.Rubric, error) {
+	if isAdmin {
+		return s.repo.ListRubrics(ctx, nil)
+	}
+	return s.repo.ListRubrics(ctx, &userID)
+}
+
+func (s *rubricService) CreateRubric(ctx context.Context, req *models.RubricRequest, userID int64) (*models.Rubric, error) {
+	rubric := &models.Rubric{OwnerID: &userID}
+	if err := rubricFromRequest(rubric, req); err != nil {
+		return nil, err
+	}
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateRubric(ctx, rubric); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityRubric, rubric.ID, enums.ActionCreate, nil, rubric)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rubric, nil
+}
+
+func (s *rubricService) GetRubric(ctx context.Context, id, userID int64, isAdmin bool) (*models.Rubric, error) {
+	return s.requireRubric(ctx, id, userID, isAdmin)
+}
+
+func (s *rubricService) UpdateRubric(ctx context.Context, id int64, req *models.RubricRequest, userID int64, isAdmin bool) (*models.Rubric, error) {
+	rubric, err := s.requireRubric(ctx, id, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	before := *rubric
+	if err := rubricFromRequest(rubric, req); err != nil {
+		return nil, err
+	}
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateRubric(ctx, rubric); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityRubric, rubric.ID, enums.ActionUpdate, &before, rubric)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rubric, nil
+}
+
+func (s *rubricService) DeleteRubric(ctx context.Context, id, userID int64, isAdmin bool) error {
+	rubric, err := s.requireRubric(ctx, id, userID, isAdmin)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteRubric(ctx, id); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityRubric, id, enums.ActionDelete, rubric, nil)
+	})
+}
+
+func (s *rubricService) GetAssignmentRubric(ctx context.Context, assignmentID, userID int64, isAdmin bool) (*models.Rubric, error) {
+	assignment, err := s.assignmentRepo.GetAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		enrolled, err := s.assignmentRepo.IsEnrolled(ctx, assignment.SectionID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !enrolled {
+			if _, err := requireSectionInstructor(ctx, s.sectionRepo, assignment.SectionID, userID, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s.assignmentRubric(ctx, assignment)
+}
+
+func (s *rubricService) SetAssignmentRubric(ctx context.Context, assignmentID int64, req *models.SetAssignmentRubricRequest, userID int64, isAdmin bool) (*models.Assignment, error) {
+	assignment, err := s.assignmentRepo.GetAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := requireSectionInstructor(ctx, s.sectionRepo, assignment.SectionID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	if req.RubricID != nil {
+		if _, err := s.requireRubric(ctx, *req.RubricID, userID, isAdmin); err != nil {
+			return nil, err
+		}
+	}
+	before := *assignment
+
+	var updated *models.Assignment
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.SetAssignmentRubric(ctx, assignment.ID, req.RubricID); err != nil {
+			return err
+		}
+		var err error
+		if updated, err = s.assignmentRepo.GetAssignment(ctx, assignment.ID); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityAssignment, assignment.ID, enums.ActionUpdate, &before, updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (s *rubricService) GetAssessment(ctx context.Context, assignmentID, studentID, userID int64, isAdmin bool) (*models.RubricAssessment, error) {
+	assignment, rubric, err := s.requireScoringAssignment(ctx, assignmentID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return s.assessment(ctx, assignment, rubric, studentID)
+}
+
+func (s *rubricService) ScoreRubric(ctx context.Context, assignmentID, studentID int64, req *models.ScoreRubricRequest, userID int64, isAdmin bool) (*models.RubricAssessment, error) {
+	assignment, rubric, err := s.requireScoringAssignment(ctx, assignmentID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	scores, total, err := scoreRubric(rubric, req.Scores)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the roster first so that no scores are kept for a student who cannot be graded
+	statuses, err := s.assignmentRepo.SubmissionStatuses(ctx, assignment)
+	if err != nil {
+		return nil, err
+	}
+	onRoster := false
+	for _, status := range statuses {
+		onRoster = onRoster || status.StudentID == studentID
+	}
+	if !onRoster {
+		return nil, appErrors.New(http.StatusBadRequest, "Student %d is not in this section", studentID)
+	}
+
+	for i := range scores {
+		scores[i].ScoredBy = &userID
+	}
+	if err := s.repo.SaveScores(ctx, assignment.ID, studentID, rubric.ID, scores); err != nil {
+		return nil, err
+	}
+
+	points := scaleRubricPoints(total, rubric.MaxPoints, assignment.MaxPoints)
+	grades, err := s.gradebookSvc.SetGrades(ctx, assignment.ID, &models.SetGradesRequest{
+		Grades:  []models.GradeEntry{{StudentID: studentID, Points: &points, Feedback: req.Feedback}},
+		Release: req.Release,
+	}, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RubricAssessment{
+		AssignmentID: assignment.ID,
+		StudentID:    studentID,
+		Rubric:       rubric,
+		Scores:       scores,
+		Total:        total,
+		Grade:        &grades[0],
+	}, nil
+}
+
+func (s *rubricService) GetOwnFeedback(ctx context.Context, assignmentID, studentID int64) (*models.RubricAssessment, error) {
+	assignment, err := s.assignmentRepo.GetAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	grade, err := s.studentGrade(ctx, assignment, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if grade == nil || grade.ReleasedAt == nil {
+		return nil, appErrors.New(http.StatusNotFound, "No feedback has been released for this assignment yet")
+	}
+	rubric, err := s.assignmentRubric(ctx, assignment)
+	if err != nil {
+		return nil, err
+	}
+	return s.assessment(ctx, assignment, rubric, studentID)
+}
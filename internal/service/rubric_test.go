@@ -0,0 +1,83 @@
+// internal/service/rubric_test.go
+package service
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"student-portal/internal/models"
+)
+
+func TestScoreRubric(t *testing.T) {
+	rubric := &models.Rubric{
+		MaxPoints: 15,
+		Criteria: []models.RubricCriterion{
+			{ID: 1, Title: "Content", MaxPoints: 10, Levels: []models.RubricLevel{{ID: 11, Points: 10}, {ID: 12, Points: 6}, {ID: 13, Points: 0}}},
+			{ID: 2, Title: "Style", MaxPoints: 5, Levels: []models.RubricLevel{{ID: 21, Points: 5}, {ID: 22, Points: 2.5}}},
+		},
+	}
+	level := func(criterionID, levelID int64) models.CriterionScoreEntry {
+		return models.CriterionScoreEntry{CriterionID: criterionID, LevelID: &levelID}
+	}
+	points := func(criterionID int64, p float64) models.CriterionScoreEntry {
+		return models.CriterionScoreEntry{CriterionID: criterionID, Points: &p}
+	}
+	both := func(criterionID, levelID int64, p float64) models.CriterionScoreEntry {
+		entry := level(criterionID, levelID)
+		entry.Points = &p
+		return entry
+	}
+
+	tests := []struct {
+		name       string
+		entries    []models.CriterionScoreEntry
+		wantPoints []float64 // Per criterion, in the rubric's order
+		wantTotal  float64
+		wantErr    string
+	}{
+		{name: "levels", entries: []models.CriterionScoreEntry{level(1, 12), level(2, 22)}, wantPoints: []float64{6, 2.5}, wantTotal: 8.5},
+		{name: "rubric order kept", entries: []models.CriterionScoreEntry{level(2, 21), level(1, 11)}, wantPoints: []float64{10, 5}, wantTotal: 15},
+		{name: "points between levels", entries: []models.CriterionScoreEntry{points(1, 7.5), level(2, 21)}, wantPoints: []float64{7.5, 5}, wantTotal: 12.5},
+		{name: "points rounded", entries: []models.CriterionScoreEntry{points(1, 3.333), points(2, 0)}, wantPoints: []float64{3.33, 0}, wantTotal: 3.33},
+		{name: "level with matching points", entries: []models.CriterionScoreEntry{both(1, 12, 6), level(2, 21)}, wantPoints: []float64{6, 5}, wantTotal: 11},
+		{name: "level with other points", entries: []models.CriterionScoreEntry{both(1, 12, 7), level(2, 21)}, wantErr: "either a level or points"},
+		{name: "criterion missing", entries: []models.CriterionScoreEntry{level(1, 11)}, wantErr: `"Style" has not been scored`},
+		{name: "criterion scored twice", entries: []models.CriterionScoreEntry{level(1, 11), level(1, 12), level(2, 21)}, wantErr: "more than once"},
+		{name: "unknown criterion", entries: []models.CriterionScoreEntry{level(1, 11), level(2, 21), level(3, 31)}, wantErr: "not part of the rubric"},
+		{name: "level of another criterion", entries: []models.CriterionScoreEntry{level(1, 21), level(2, 21)}, wantErr: "not a level"},
+		{name: "points above the best level", entries: []models.CriterionScoreEntry{points(1, 10.5), level(2, 21)}, wantErr: "between 0 and 10"},
+		{name: "negative points", entries: []models.CriterionScoreEntry{points(1, -1), level(2, 21)}, wantErr: "between 0 and 10"},
+		{name: "neither level nor points", entries: []models.CriterionScoreEntry{{CriterionID: 1}, level(2, 21)}, wantErr: "needs a level or points"},
+		{
+			name:    "comment too long",
+			entries: []models.CriterionScoreEntry{{CriterionID: 1, LevelID: level(1, 11).LevelID, Comment: strings.Repeat("x", maxScoreComment+1)}, level(2, 21)},
+			wantErr: "at most",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scores, total, err := scoreRubric(rubric, tt.entries)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("scoreRubric error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("scoreRubric: %v", err)
+			}
+			got := make([]float64, 0, len(scores))
+			for i, score := range scores {
+				if score.CriterionID != rubric.Criteria[i].ID {
+					t.Errorf("score %d is for criterion %d, want %d", i+1, score.CriterionID, rubric.Criteria[i].ID)
+				}
+				got = append(got, score.Points)
+			}
+			if !slices.Equal(got, tt.wantPoints) || total != tt.wantTotal {
+				t.Errorf("scores = %v totalling %v, want %v totalling %v", got, total, tt.wantPoints, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// internal/service/section_service.go
+package service
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+)
+
+// SectionService defines the methods for course sections, timetables and scheduling clashes.
+type SectionService interface {
+	CreateSection(ctx context.Context, termID, courseID int64, req *models.CreateSectionRequest) (*models.Section, error)
+	GetSection(ctx context.Context, id int64) (*models.Section, error)
+	UpdateSection(ctx context.Context, id int64, req *models.UpdateSectionRequest) (*models.Section, error)
+	DeleteSection(ctx context.Context, id int64) error
+	ListSections(ctx context.Context, termID, courseID int64) ([]models.Section, error)
+	// GetTimetable returns the user's weekly schedule in the term (zero means the current
+	// term): the sections a student attends or a teacher teaches.
+	GetTimetable(ctx context.Context, userID, termID int64) (*models.Timetable, error)
+	// ListClashes reports rooms and instructors booked twice at the same time. clashType
+	// limits the report to "room" or "instructor" clashes.
+	ListClashes(ctx context.Context, termID int64, clashType string) ([]models.ScheduleClash, error)
+}
+
+// sectionCodePattern matches codes like "001", "L02" or "LAB-A" after upper-casing.
+var sectionCodePattern = regexp.MustCompile(`^[A-Z0-9]+(-[A-Z0-9]+)*$`)
+
+type sectionService struct {
+	repo         repository.SectionRepository
+	offeringRepo repository.OfferingRepository
+	termRepo     repository.TermRepository
+	userRepo     repository.UserRepository
+	transactor   repository.Transactor
+	historySvc   HistoryService
+	cfg          *config.Config
+}
+
+// NewSectionService creates a new SectionService instance.
+func NewSectionService(repo repository.SectionRepository, offeringRepo repository.OfferingRepository, termRepo repository.TermRepository, userRepo repository.UserRepository, transactor repository.Transactor, historySvc HistoryService, cfg *config.Config) SectionService {
+	return &sectionService{repo: repo, offeringRepo: offeringRepo, termRepo: termRepo, userRepo: userRepo, transactor: transactor, historySvc: historySvc, cfg: cfg}
+}
+
+// normalizeSectionCode trims and upper-cases a section code and validates it.
+func normalizeSectionCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) == 0 || len(code) > 10 || !sectionCodePattern.MatchString(code) {
+		return "", appErrors.New(http.StatusBadRequest, "Section code must be 1-10 letters or digits, optionally separated by hyphens")
+	}
+	return code, nil
+}
+
+// requireInstructor checks that the user can teach a section.
+func (s *sectionService) requireInstructor(ctx context.Context, id int64) error {
+	user, err := s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.Role != string(enums.RoleTeacher) && user.Role != string(enums.RoleAdmin) {
+		return appErrors.New(http.StatusBadRequest, "User %d is not a teacher", id)
+	}
+	return nil
+}
+
+func (s *sectionService) CreateSection(ctx context.Context, termID, courseID int64, req *models.CreateSectionRequest) (*models.Section, error) {
+	if _, err := s.offeringRepo.GetOffering(ctx, courseID, termID); err != nil {
+		if err == appErrors.ErrNotFound {
+			return nil, appErrors.ErrOfferingNotFound
+		}
+		return nil, err
+	}
+
+	section := &models.Section{CourseID: courseID, TermID: termID, InstructorID: req.InstructorID}
+	var err error
+	if section.Code, err = normalizeSectionCode(req.Code); err != nil {
+		return nil, err
+	}
+	if section.InstructorID != nil {
+		if err := s.requireInstructor(ctx, *section.InstructorID); err != nil {
+			return nil, err
+		}
+	}
+	if section.Meetings, err = buildMeetings(req.Meetings); err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateSection(ctx, section); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntitySection, section.ID, enums.ActionCreate, nil, section)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return section, nil
+}
+
+func (s *sectionService) GetSection(ctx context.Context, id int64) (*models.Section, error) {
+	return s.repo.GetSection(ctx, id)
+}
+
+func (s *sectionService) UpdateSection(ctx context.Context, id int64, req *models.UpdateSectionRequest) (*models.Section, error) {
+	section, err := s.repo.GetSection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *section
+
+	if req.Code != nil {
+		if section.Code, err = normalizeSectionCode(*req.Code); err != nil {
+			return nil, err
+		}
+	}
+	if req.InstructorID != nil {
+		if err := s.requireInstructor(ctx, *req.InstructorID); err != nil {
+			return nil, err
+		}
+		section.InstructorID = req.InstructorID
+	} else if req.ClearInstructor {
+		section.InstructorID = nil
+	}
+	if req.Meetings != nil {
+		if section.Meetings, err = buildMeetings(*req.Meetings); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateSection(ctx, section); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntitySection, section.ID, enums.ActionUpdate, &before, section)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return section, nil
+}
+
+func (s *sectionService) DeleteSection(ctx context.Context, id int64) error {
+	section, err := s.repo.GetSection(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteSection(ctx, id); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntitySection, section.ID, enums.ActionDelete, section, nil)
+	})
+}
+
+func (s *sectionService) ListSections(ctx context.Context, termID, courseID int64) ([]models.Section, error) {
+	if _, err := s.offeringRepo.GetOffering(ctx, courseID, termID); err != nil {
+		if err == appErrors.ErrNotFound {
+			return nil, appErrors.ErrOfferingNotFound
+		}
+		return nil, err
+	}
+	return s.repo.ListForOffering(ctx, courseID, termID)
+}
+
+func (s *sectionService) GetTimetable(ctx context.Context, userID, termID int64) (*models.Timetable, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	term, err := resolveTerm(ctx, s.termRepo, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.TimetableEntry
+	switch enums.Role(user.Role) {
+	case enums.RoleStudent:
+		entries, err = s.repo.StudentTimetable(ctx, userID, term.ID)
+	case enums.RoleTeacher, enums.RoleAdmin:
+		entries, err = s.repo.InstructorTimetable(ctx, userID, term.ID)
+	default:
+		return nil, appErrors.New(http.StatusBadRequest, "Only students and teachers have a timetable")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &models.Timetable{Term: term, Entries: entries}, nil
+}
+
+func (s *sectionService) ListClashes(ctx context.Context, termID int64, clashType string) ([]models.ScheduleClash, error) {
+	if clashType != "" && clashType != clashTypeRoom && clashType != clashTypeInstructor {
+		return nil, appErrors.New(http.StatusBadRequest, "Invalid clash type '%s'", clashType)
+	}
+	if _, err := s.termRepo.GetTermByID(ctx, termID); err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.ListTermMeetings(ctx, termID)
+	if err != nil {
+		return nil, err
+	}
+
+	clashes := make([]models.ScheduleClash, 0)
+	if clashType == "" || clashType == clashTypeRoom {
+		// Rooms are compared case-insensitively, since they are typed in by hand
+		clashes = append(clashes, resourceClashes(entries, clashTypeRoom, func(e models.TimetableEntry) string {
+			if e.Room == nil {
+				return ""
+			}
+			return strings.ToUpper(*e.Room)
+		})...)
+	}
+	if clashType == "" || clashType == clashTypeInstructor {
+		clashes = append(clashes, resourceClashes(entries, clashTypeInstructor, func(e models.TimetableEntry) string {
+			if e.InstructorID == nil {
+				return ""
+			}
+			return strconv.FormatInt(*e.InstructorID, 10)
+		})...)
+	}
+	return clashes, nil
+}
@@ -0,0 +1,305 @@
+// internal/service/term_service.go
+package service
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/config"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+)
+
+// TermService defines the methods for the academic calendar.
+type TermService interface {
+	CreateTerm(ctx context.Context, req *models.CreateTermRequest, createdBy int64) (*models.Term, error)
+	GetTerm(ctx context.Context, id int64) (*models.Term, error)
+	GetCurrentTerm(ctx context.Context) (*models.Term, error)
+	UpdateTerm(ctx context.Context, id int64, req *models.UpdateTermRequest) (*models.Term, error)
+	DeleteTerm(ctx context.Context, id int64) error
+	ListTerms(ctx context.Context, limit, offset int) ([]models.Term, int64, error)
+	AddHoliday(ctx context.Context, termID int64, req *models.CreateHolidayRequest) (*models.TermHoliday, error)
+	DeleteHoliday(ctx context.Context, termID, id int64) error
+}
+
+// termCodePattern matches codes like "2025FA", "2026-SPRING" or "SU26" after upper-casing.
+var termCodePattern = regexp.MustCompile(`^[A-Z0-9]+([ -]?[A-Z0-9]+)*$`)
+
+type termService struct {
+	repo       repository.TermRepository
+	transactor repository.Transactor
+	historySvc HistoryService
+	cfg        *config.Config
+}
+
+// NewTermService creates a new TermService instance.
+func NewTermService(repo repository.TermRepository, transactor repository.Transactor, historySvc HistoryService, cfg *config.Config) TermService {
+	return &termService{repo: repo, transactor: transactor, historySvc: historySvc, cfg: cfg}
+}
+
+// resolveTerm loads the given term, or the one currently running when termID is zero.
+func resolveTerm(ctx context.Context, repo repository.TermRepository, termID int64) (*models.Term, error) {
+	if termID != 0 {
+		return repo.GetTermByID(ctx, termID)
+	}
+	term, err := repo.GetTermAt(ctx, time.Now())
+	if err == appErrors.ErrNotFound {
+		return nil, appErrors.ErrNoCurrentTerm
+	}
+	return term, err
+}
+
+// parseTermDate parses a required YYYY-MM-DD date of a term.
+func parseTermDate(value, field string) (time.Time, error) {
+	date, err := parseOptionalDate(value)
+	if err != nil || date == nil {
+		return time.Time{}, appErrors.New(http.StatusBadRequest, "'%s' must be a date formatted as YYYY-MM-DD", field)
+	}
+	return *date, nil
+}
+
+// parseOptionalTermDate parses an optional YYYY-MM-DD date of a term; empty clears it.
+func parseOptionalTermDate(value, field string) (*time.Time, error) {
+	date, err := parseOptionalDate(value)
+	if err != nil {
+		return nil, appErrors.New(http.StatusBadRequest, "'%s' must be a date formatted as YYYY-MM-DD", field)
+	}
+	return date, nil
+}
+
+// validateTerm checks that the term's code, name and calendar are consistent.
+func validateTerm(term *models.Term) error {
+	term.Code = strings.ToUpper(strings.TrimSpace(term.Code))
+	if len(term.Code) < 2 || len(term.Code) > 20 || !termCodePattern.MatchString(term.Code) {
+		return appErrors.New(http.StatusBadRequest, "Term code must be 2-20 letters or digits, optionally separated by spaces or hyphens")
+	}
+	term.Name = strings.TrimSpace(term.Name)
+	if term.Name == "" {
+		return appErrors.New(http.StatusBadRequest, "Term name is required")
+	}
+	if term.EndDate.Before(term.StartDate) {
+		return appErrors.New(http.StatusBadRequest, "Term cannot end before it starts")
+	}
+
+	// Deadlines are instants; the term ends at the close of its last day.
+	termEnd := term.EndDate.AddDate(0, 0, 1)
+	if term.AddDeadline != nil && !term.AddDeadline.Before(termEnd) {
+		return appErrors.New(http.StatusBadRequest, "Add deadline must fall before the end of the term")
+	}
+	if term.DropDeadline != nil && !term.DropDeadline.Before(termEnd) {
+		return appErrors.New(http.StatusBadRequest, "Drop deadline must fall before the end of the term")
+	}
+	if term.AddDeadline != nil && term.DropDeadline != nil && term.DropDeadline.Before(*term.AddDeadline) {
+		return appErrors.New(http.StatusBadRequest, "Drop deadline cannot be before the add deadline")
+	}
+
+	if (term.ExamStart == nil) != (term.ExamEnd == nil) {
+		return appErrors.New(http.StatusBadRequest, "Exam period needs both a start and an end date")
+	}
+	if term.ExamStart != nil {
+		if term.ExamEnd.Before(*term.ExamStart) {
+			return appErrors.New(http.StatusBadRequest, "Exam period cannot end before it starts")
+		}
+		if term.ExamStart.Before(term.StartDate) || term.ExamEnd.After(term.EndDate) {
+			return appErrors.New(http.StatusBadRequest, "Exam period must fall within the term")
+		}
+	}
+	return nil
+}
+
+func (s *termService) CreateTerm(ctx context.Context, req *models.CreateTermRequest, createdBy int64) (*models.Term, error) {
+	term := &models.Term{
+		Code:         req.Code,
+		Name:         req.Name,
+		AddDeadline:  req.AddDeadline,
+		DropDeadline: req.DropDeadline,
+		CreatedBy:    &createdBy,
+	}
+	var err error
+	if term.StartDate, err = parseTermDate(req.StartDate, "start_date"); err != nil {
+		return nil, err
+	}
+	if term.EndDate, err = parseTermDate(req.EndDate, "end_date"); err != nil {
+		return nil, err
+	}
+	if req.ExamStart != nil {
+		if term.ExamStart, err = parseOptionalTermDate(*req.ExamStart, "exam_start"); err != nil {
+			return nil, err
+		}
+	}
+	if req.ExamEnd != nil {
+		if term.ExamEnd, err = parseOptionalTermDate(*req.ExamEnd, "exam_end"); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateTerm(term); err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateTerm(ctx, term); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityTerm, term.ID, enums.ActionCreate, nil, term)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+func (s *termService) GetTerm(ctx context.Context, id int64) (*models.Term, error) {
+	return s.repo.GetTermByID(ctx, id)
+}
+
+func (s *termService) GetCurrentTerm(ctx context.Context) (*models.Term, error) {
+	return resolveTerm(ctx, s.repo, 0)
+}
+
+func (s *termService) UpdateTerm(ctx context.Context, id int64, req *models.UpdateTermRequest) (*models.Term, error) {
+	term, err := s.repo.GetTermByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *term
+
+	if req.Code != nil {
+		term.Code = *req.Code
+	}
+	if req.Name != nil {
+		term.Name = *req.Name
+	}
+	if req.StartDate != nil {
+		if term.StartDate, err = parseTermDate(*req.StartDate, "start_date"); err != nil {
+			return nil, err
+		}
+	}
+	if req.EndDate != nil {
+		if term.EndDate, err = parseTermDate(*req.EndDate, "end_date"); err != nil {
+			return nil, err
+		}
+	}
+	if req.AddDeadline != nil {
+		term.AddDeadline = req.AddDeadline
+	} else if req.ClearAddDeadline {
+		term.AddDeadline = nil
+	}
+	if req.DropDeadline != nil {
+		term.DropDeadline = req.DropDeadline
+	} else if req.ClearDropDeadline {
+		term.DropDeadline = nil
+	}
+	if req.ExamStart != nil {
+		if term.ExamStart, err = parseOptionalTermDate(*req.ExamStart, "exam_start"); err != nil {
+			return nil, err
+		}
+	}
+	if req.ExamEnd != nil {
+		if term.ExamEnd, err = parseOptionalTermDate(*req.ExamEnd, "exam_end"); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateTerm(term); err != nil {
+		return nil, err
+	}
+	for _, holiday := range term.Holidays {
+		if holiday.StartDate.Before(term.StartDate) || holiday.EndDate.After(term.EndDate) {
+			return nil, appErrors.New(http.StatusBadRequest, "Holiday '%s' would fall outside the term", holiday.Name)
+		}
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateTerm(ctx, term); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityTerm, term.ID, enums.ActionUpdate, &before, term)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+func (s *termService) DeleteTerm(ctx context.Context, id int64) error {
+	term, err := s.repo.GetTermByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteTerm(ctx, id); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityTerm, term.ID, enums.ActionDelete, term, nil)
+	})
+}
+
+func (s *termService) ListTerms(ctx context.Context, limit, offset int) ([]models.Term, int64, error) {
+	return s.repo.ListTerms(ctx, limit, offset)
+}
+
+func (s *termService) AddHoliday(ctx context.Context, termID int64, req *models.CreateHolidayRequest) (*models.TermHoliday, error) {
+	term, err := s.repo.GetTermByID(ctx, termID)
+	if err != nil {
+		return nil, err
+	}
+	before := *term
+
+	holiday := &models.TermHoliday{TermID: termID, Name: strings.TrimSpace(req.Name)}
+	if holiday.Name == "" {
+		return nil, appErrors.New(http.StatusBadRequest, "Holiday name is required")
+	}
+	if holiday.StartDate, err = parseTermDate(req.StartDate, "start_date"); err != nil {
+		return nil, err
+	}
+	holiday.EndDate = holiday.StartDate
+	if strings.TrimSpace(req.EndDate) != "" {
+		if holiday.EndDate, err = parseTermDate(req.EndDate, "end_date"); err != nil {
+			return nil, err
+		}
+	}
+	if holiday.EndDate.Before(holiday.StartDate) {
+		return nil, appErrors.New(http.StatusBadRequest, "Holiday cannot end before it starts")
+	}
+	if holiday.StartDate.Before(term.StartDate) || holiday.EndDate.After(term.EndDate) {
+		return nil, appErrors.New(http.StatusBadRequest, "Holiday must fall within the term")
+	}
+
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateHoliday(ctx, holiday); err != nil {
+			return err
+		}
+		term.Holidays = append(append([]models.TermHoliday{}, term.Holidays...), *holiday)
+		return s.historySvc.Record(ctx, enums.EntityTerm, term.ID, enums.ActionUpdate, &before, term)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+func (s *termService) DeleteHoliday(ctx context.Context, termID, id int64) error {
+	term, err := s.repo.GetTermByID(ctx, termID)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		holiday, err := s.repo.DeleteHoliday(ctx, termID, id)
+		if err != nil {
+			return err
+		}
+
+		after := *term
+		after.Holidays = make([]models.TermHoliday, 0, len(term.Holidays))
+		for _, h := range term.Holidays {
+			if h.ID != holiday.ID {
+				after.Holidays = append(after.Holidays, h)
+			}
+		}
+		return s.historySvc.Record(ctx, enums.EntityTerm, term.ID, enums.ActionUpdate, term, &after)
+	})
+}
@@ -0,0 +1,180 @@
+// internal/service/timetable.go
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+)
+
+// clockLayout is the layout of meeting start and end times.
+const clockLayout = "15:04"
+
+// maxSectionMeetings bounds the weekly meetings of a single section.
+const maxSectionMeetings = 14
+
+// Clash types reported by ScheduleClash.
+const (
+	clashTypeStudent    = "student"
+	clashTypeRoom       = "room"
+	clashTypeInstructor = "instructor"
+)
+
+// clockMinutes returns the minutes since midnight of an HH:MM time.
+func clockMinutes(value string) (int, bool) {
+	t, err := time.Parse(clockLayout, value)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// formatClock formats minutes since midnight as HH:MM.
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// calendarDay returns midnight in loc of the calendar date of a DATE column.
+func calendarDay(date time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+}
+
+// localToday returns today's date in loc the way DATE columns are scanned: at midnight UTC.
+func localToday(loc *time.Location) time.Time {
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// onHoliday reports whether the day falls in one of the term's holidays.
+func onHoliday(term *models.Term, day time.Time) bool {
+	for _, holiday := range term.Holidays {
+		if !day.Before(calendarDay(holiday.StartDate, day.Location())) && !day.After(calendarDay(holiday.EndDate, day.Location())) {
+			return true
+		}
+	}
+	return false
+}
+
+// meetingsOverlap reports whether two weekly meetings share some time. Meetings that
+// merely touch (one ends when the other starts) do not overlap.
+func meetingsOverlap(a, b models.TimetableEntry) bool {
+	if a.DayOfWeek != b.DayOfWeek {
+		return false
+	}
+	aStart, _ := clockMinutes(a.StartTime)
+	aEnd, _ := clockMinutes(a.EndTime)
+	bStart, _ := clockMinutes(b.StartTime)
+	bEnd, _ := clockMinutes(b.EndTime)
+	return aStart < bEnd && bStart < aEnd
+}
+
+// buildMeetings validates the requested meetings of a section and converts them.
+func buildMeetings(reqs []models.MeetingRequest) ([]models.SectionMeeting, error) {
+	if len(reqs) > maxSectionMeetings {
+		return nil, appErrors.New(http.StatusBadRequest, "A section can have at most %d weekly meetings", maxSectionMeetings)
+	}
+	meetings := make([]models.SectionMeeting, 0, len(reqs))
+	entries := make([]models.TimetableEntry, 0, len(reqs))
+	for i, req := range reqs {
+		if req.DayOfWeek < 1 || req.DayOfWeek > 7 {
+			return nil, appErrors.New(http.StatusBadRequest, "Meeting %d: day_of_week must be 1 (Monday) to 7 (Sunday)", i+1)
+		}
+		start, startOK := clockMinutes(strings.TrimSpace(req.StartTime))
+		end, endOK := clockMinutes(strings.TrimSpace(req.EndTime))
+		if !startOK || !endOK {
+			return nil, appErrors.New(http.StatusBadRequest, "Meeting %d: times must be formatted as HH:MM", i+1)
+		}
+		if end <= start {
+			return nil, appErrors.New(http.StatusBadRequest, "Meeting %d: must end after it starts", i+1)
+		}
+		room := optionalString(req.Room)
+		if room != nil && len(*room) > 50 {
+			return nil, appErrors.New(http.StatusBadRequest, "Meeting %d: room must be at most 50 characters", i+1)
+		}
+
+		meeting := models.SectionMeeting{
+			DayOfWeek: req.DayOfWeek,
+			StartTime: formatClock(start),
+			EndTime:   formatClock(end),
+			Room:      room,
+		}
+		entry := models.TimetableEntry{DayOfWeek: meeting.DayOfWeek, StartTime: meeting.StartTime, EndTime: meeting.EndTime}
+		for _, other := range entries {
+			if meetingsOverlap(entry, other) {
+				return nil, appErrors.New(http.StatusBadRequest, "Meeting %d overlaps another meeting of the section", i+1)
+			}
+		}
+		meetings = append(meetings, meeting)
+		entries = append(entries, entry)
+	}
+	return meetings, nil
+}
+
+// sectionEntries turns a section's meetings into timetable entries.
+func sectionEntries(section *models.Section, courseTitle string) []models.TimetableEntry {
+	entries := make([]models.TimetableEntry, 0, len(section.Meetings))
+	for _, m := range section.Meetings {
+		entries = append(entries, models.TimetableEntry{
+			CourseID:       section.CourseID,
+			CourseCode:     section.CourseCode,
+			CourseTitle:    courseTitle,
+			SectionID:      section.ID,
+			SectionCode:    section.Code,
+			MeetingID:      m.ID,
+			DayOfWeek:      m.DayOfWeek,
+			StartTime:      m.StartTime,
+			EndTime:        m.EndTime,
+			Room:           m.Room,
+			InstructorID:   section.InstructorID,
+			InstructorName: section.InstructorName,
+		})
+	}
+	return entries
+}
+
+// findClashes returns every pair of a new meeting and an existing one that overlap.
+func findClashes(newEntries, existing []models.TimetableEntry) []models.ScheduleClash {
+	clashes := make([]models.ScheduleClash, 0)
+	for _, n := range newEntries {
+		for _, e := range existing {
+			if meetingsOverlap(n, e) {
+				clashes = append(clashes, models.ScheduleClash{Type: clashTypeStudent, First: n, Second: e})
+			}
+		}
+	}
+	return clashes
+}
+
+// resourceClashes returns the overlapping meetings of different sections that share a
+// resource, e.g. a room or an instructor. Meetings without the resource are ignored.
+func resourceClashes(entries []models.TimetableEntry, clashType string, resource func(models.TimetableEntry) string) []models.ScheduleClash {
+	byResource := make(map[string][]models.TimetableEntry)
+	for _, e := range entries {
+		if key := resource(e); key != "" {
+			byResource[key] = append(byResource[key], e)
+		}
+	}
+	keys := make([]string, 0, len(byResource))
+	for key := range byResource {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	clashes := make([]models.ScheduleClash, 0)
+	for _, key := range keys {
+		group := byResource[key]
+		for i := range group {
+			for j := i + 1; j < len(group); j++ {
+				if group[i].SectionID != group[j].SectionID && meetingsOverlap(group[i], group[j]) {
+					clashes = append(clashes, models.ScheduleClash{Type: clashType, First: group[i], Second: group[j]})
+				}
+			}
+		}
+	}
+	return clashes
+}
@@ -0,0 +1,153 @@
+// internal/service/timetable_test.go
+package service
+
+import (
+	"testing"
+
+	"student-portal/internal/models"
+)
+
+// meeting returns a timetable entry of the given section.
+func meeting(sectionID int64, day int, start, end string) models.TimetableEntry {
+	return models.TimetableEntry{SectionID: sectionID, DayOfWeek: day, StartTime: start, EndTime: end}
+}
+
+func TestMeetingsOverlap(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b models.TimetableEntry
+		want bool
+	}{
+		{"same time", meeting(1, 1, "09:00", "10:30"), meeting(2, 1, "09:00", "10:30"), true},
+		{"partial overlap", meeting(1, 1, "09:00", "10:30"), meeting(2, 1, "10:00", "11:00"), true},
+		{"one inside the other", meeting(1, 3, "08:00", "12:00"), meeting(2, 3, "09:00", "10:00"), true},
+		{"back to back", meeting(1, 1, "09:00", "10:00"), meeting(2, 1, "10:00", "11:00"), false},
+		{"apart", meeting(1, 1, "09:00", "10:00"), meeting(2, 1, "13:00", "14:00"), false},
+		{"different days", meeting(1, 1, "09:00", "10:30"), meeting(2, 2, "09:00", "10:30"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := meetingsOverlap(tt.a, tt.b); got != tt.want {
+				t.Errorf("meetingsOverlap(a, b) = %t, want %t", got, tt.want)
+			}
+			if got := meetingsOverlap(tt.b, tt.a); got != tt.want {
+				t.Errorf("meetingsOverlap(b, a) = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindClashes(t *testing.T) {
+	existing := []models.TimetableEntry{
+		meeting(10, 1, "09:00", "10:30"),
+		meeting(10, 3, "09:00", "10:30"),
+		meeting(11, 2, "14:00", "16:00"),
+	}
+
+	tests := []struct {
+		name       string
+		newEntries []models.TimetableEntry
+		want       int
+	}{
+		{"no clash", []models.TimetableEntry{meeting(20, 1, "10:30", "12:00"), meeting(20, 2, "16:00", "17:00")}, 0},
+		{"one clash", []models.TimetableEntry{meeting(20, 2, "15:00", "17:00")}, 1},
+		{"clash with two meetings", []models.TimetableEntry{meeting(20, 1, "10:00", "11:00"), meeting(20, 3, "08:00", "09:30")}, 2},
+		{"nothing new", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clashes := findClashes(tt.newEntries, existing)
+			if len(clashes) != tt.want {
+				t.Fatalf("findClashes returned %d clashes, want %d", len(clashes), tt.want)
+			}
+			for _, clash := range clashes {
+				if clash.Type != clashTypeStudent || clash.First.SectionID != 20 {
+					t.Errorf("unexpected clash %+v", clash)
+				}
+			}
+		})
+	}
+}
+
+func TestResourceClashes(t *testing.T) {
+	room := func(e models.TimetableEntry, name string) models.TimetableEntry {
+		e.Room = &name
+		return e
+	}
+	byRoom := func(e models.TimetableEntry) string {
+		if e.Room == nil {
+			return ""
+		}
+		return *e.Room
+	}
+
+	tests := []struct {
+		name    string
+		entries []models.TimetableEntry
+		want    int
+	}{
+		{
+			name:    "same room at the same time",
+			entries: []models.TimetableEntry{room(meeting(1, 1, "09:00", "10:00"), "A1"), room(meeting(2, 1, "09:30", "11:00"), "A1")},
+			want:    1,
+		},
+		{
+			name:    "different rooms",
+			entries: []models.TimetableEntry{room(meeting(1, 1, "09:00", "10:00"), "A1"), room(meeting(2, 1, "09:00", "10:00"), "B2")},
+			want:    0,
+		},
+		{
+			name:    "meetings of the same section",
+			entries: []models.TimetableEntry{room(meeting(1, 1, "09:00", "10:00"), "A1"), room(meeting(1, 1, "09:30", "10:30"), "A1")},
+			want:    0,
+		},
+		{
+			name:    "meetings without a room",
+			entries: []models.TimetableEntry{meeting(1, 1, "09:00", "10:00"), meeting(2, 1, "09:00", "10:00")},
+			want:    0,
+		},
+		{
+			name: "three sections in one room",
+			entries: []models.TimetableEntry{
+				room(meeting(1, 4, "09:00", "11:00"), "A1"),
+				room(meeting(2, 4, "10:00", "12:00"), "A1"),
+				room(meeting(3, 4, "10:30", "11:30"), "A1"),
+			},
+			want: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clashes := resourceClashes(tt.entries, clashTypeRoom, byRoom)
+			if len(clashes) != tt.want {
+				t.Errorf("resourceClashes returned %d clashes, want %d", len(clashes), tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildMeetings(t *testing.T) {
+	tests := []struct {
+		name    string
+		reqs    []models.MeetingRequest
+		wantErr bool
+	}{
+		{"valid", []models.MeetingRequest{{DayOfWeek: 1, StartTime: "09:00", EndTime: "10:30", Room: "A1"}, {DayOfWeek: 3, StartTime: "09:00", EndTime: "10:30"}}, false},
+		{"back to back", []models.MeetingRequest{{DayOfWeek: 1, StartTime: "09:00", EndTime: "10:00"}, {DayOfWeek: 1, StartTime: "10:00", EndTime: "11:00"}}, false},
+		{"overlapping", []models.MeetingRequest{{DayOfWeek: 1, StartTime: "09:00", EndTime: "10:30"}, {DayOfWeek: 1, StartTime: "10:00", EndTime: "11:00"}}, true},
+		{"bad day", []models.MeetingRequest{{DayOfWeek: 8, StartTime: "09:00", EndTime: "10:00"}}, true},
+		{"bad time", []models.MeetingRequest{{DayOfWeek: 1, StartTime: "9am", EndTime: "10:00"}}, true},
+		{"ends before it starts", []models.MeetingRequest{{DayOfWeek: 1, StartTime: "10:00", EndTime: "09:00"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meetings, err := buildMeetings(tt.reqs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildMeetings error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if err == nil && len(meetings) != len(tt.reqs) {
+				t.Errorf("buildMeetings returned %d meetings, want %d", len(meetings), len(tt.reqs))
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+// internal/service/transcript.go
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+)
+
+// verificationAlphabet leaves out characters that are easily misread on paper (0/O, 1/I).
+const verificationAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// verificationGroups and verificationGroupSize shape codes like ABCD-EFGH-JKLM-NPQR (80 bits).
+const (
+	verificationGroups    = 4
+	verificationGroupSize = 4
+)
+
+// transcriptTitleLength is where course titles are cut off on the PDF.
+const transcriptTitleLength = 48
+
+// validateGradePoints checks that the scale gives points to every letter grade and nothing else.
+func validateGradePoints(points map[string]float64) (map[string]float64, error) {
+	normalized := make(map[string]float64, len(points))
+	for letter, value := range points {
+		grade, err := normalizeGrade(letter)
+		if err != nil {
+			return nil, err
+		}
+		if value < 0 || value >= 100 {
+			return nil, appErrors.New(http.StatusBadRequest, "Points for '%s' must be between 0 and 99.99", grade)
+		}
+		normalized[grade] = roundPoints(value)
+	}
+	for _, grade := range gradeScale {
+		if _, ok := normalized[grade]; !ok {
+			return nil, appErrors.New(http.StatusBadRequest, "Points for '%s' are missing", grade)
+		}
+	}
+	return normalized, nil
+}
+
+// newVerificationCode returns a random transcript verification code.
+func newVerificationCode() (string, error) {
+	groups := make([]string, 0, verificationGroups)
+	max := big.NewInt(int64(len(verificationAlphabet)))
+	for g := 0; g < verificationGroups; g++ {
+		var b strings.Builder
+		for i := 0; i < verificationGroupSize; i++ {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return "", err
+			}
+			b.WriteByte(verificationAlphabet[n.Int64()])
+		}
+		groups = append(groups, b.String())
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// normalizeVerificationCode accepts codes typed in lower case, with spaces or without dashes.
+func normalizeVerificationCode(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if strings.ContainsRune(verificationAlphabet, r) {
+			b.WriteRune(r)
+		}
+	}
+	compact := b.String()
+	if len(compact) != verificationGroups*verificationGroupSize {
+		return ""
+	}
+	groups := make([]string, 0, verificationGroups)
+	for i := 0; i < len(compact); i += verificationGroupSize {
+		groups = append(groups, compact[i:i+verificationGroupSize])
+	}
+	return strings.Join(groups, "-")
+}
+
+// gpaOf divides quality points by the credits they were earned over; nil without credits.
+func gpaOf(quality, credits float64) *float64 {
+	if credits <= 0 {
+		return nil
+	}
+	gpa := roundPoints(quality / credits)
+	return &gpa
+}
+
+// buildTranscript groups the completions by term and computes the GPAs. Every attempt counts
+// towards the GPA of its term; when a course was repeated, only the latest attempt counts
+// towards the cumulative GPA and credits.
+func buildTranscript(student *models.User, scale *models.GradePointScale, completions []models.TranscriptCompletion, now time.Time) *models.Transcript {
+	transcript := &models.Transcript{
+		StudentID:    student.ID,
+		StudentName:  student.Name,
+		StudentEmail: student.Email,
+		Scale:        scale.Name,
+		Terms:        make([]models.TranscriptTerm, 0),
+		GeneratedAt:  now,
+	}
+
+	latest := make(map[int64]int64, len(completions)) // Course ID to the completion that counts
+	for _, c := range completions {
+		latest[c.CourseID] = c.CompletionID
+	}
+
+	type attempt struct {
+		credits, quality float64
+		passed           bool
+	}
+	counting := make(map[int64]attempt, len(latest))
+
+	for i := 0; i < len(completions); {
+		// Completions arrive in term order; take the run of the same term
+		j := i
+		for j < len(completions) && sameTerm(completions[i].TermID, completions[j].TermID) {
+			j++
+		}
+
+		first := completions[i]
+		term := models.TranscriptTerm{TermID: first.TermID, TermCode: first.TermCode, Courses: make([]models.TranscriptCourse, 0, j-i)}
+		term.TermName = "Credit without a term"
+		if first.TermName != nil {
+			term.TermName = *first.TermName
+		}
+
+		var gpaCredits, quality float64
+		for _, c := range completions[i:j] {
+			course := c.TranscriptCourse
+			course.GradePoints = scale.Points[course.Grade]
+			course.Repeated = latest[course.CourseID] != course.CompletionID
+			passed := course.Grade != failingGrade
+
+			term.AttemptedCredits += course.Credits
+			if passed {
+				term.EarnedCredits += course.Credits
+			}
+			gpaCredits += course.Credits
+			quality += course.Credits * course.GradePoints
+			counting[course.CourseID] = attempt{credits: course.Credits, quality: course.Credits * course.GradePoints, passed: passed}
+			transcript.AttemptedCredits += course.Credits
+			term.Courses = append(term.Courses, course)
+		}
+		term.GPA = gpaOf(quality, gpaCredits)
+
+		var cumulativeCredits, cumulativeQuality float64
+		for _, a := range counting {
+			cumulativeCredits += a.credits
+			cumulativeQuality += a.quality
+		}
+		term.CumulativeGPA = gpaOf(cumulativeQuality, cumulativeCredits)
+
+		transcript.Terms = append(transcript.Terms, term)
+		i = j
+	}
+
+	var credits, quality float64
+	for _, a := range counting {
+		credits += a.credits
+		quality += a.quality
+		if a.passed {
+			transcript.EarnedCredits += a.credits
+		}
+	}
+	transcript.CumulativeGPA = gpaOf(quality, credits)
+	return transcript
+}
+
+// sameTerm compares optional term IDs.
+func sameTerm(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// formatCredits prints credits without trailing zeros.
+func formatCredits(credits float64) string {
+	return strconv.FormatFloat(credits, 'f', -1, 64)
+}
+
+// formatGPA prints a GPA with two decimals, or a dash without one.
+func formatGPA(gpa *float64) string {
+	if gpa == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", *gpa)
+}
+
+// shorten cuts text to at most n characters, marking the cut.
+func shorten(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:n-3])) + "..."
+}
+
+// renderTranscriptPDF lays out an official transcript. Every page carries the verification
+// code and where to check it.
+func renderTranscriptPDF(transcript *models.Transcript, institution, code, verifyURL string, issuedAt time.Time) []byte {
+	footer := func(page, pages int) string {
+		return fmt.Sprintf("Verification code %s - verify at %s - Page %d of %d", code, verifyURL, page, pages)
+	}
+	doc := newPDFDocument("Official transcript of "+transcript.StudentName, footer)
+
+	doc.text(pdfFontBold, 16, institution)
+	doc.text(pdfFontBold, 12, "Official Transcript")
+	doc.space(6)
+	doc.row(pdfFontRegular, 10, pdfCell{Text: "Student: " + transcript.StudentName}, pdfCell{X: 300, Text: "Issued: " + issuedAt.UTC().Format("2006-01-02")})
+	doc.row(pdfFontRegular, 10, pdfCell{Text: "Student ID: " + strconv.FormatInt(transcript.StudentID, 10)}, pdfCell{X: 300, Text: "Grade-point scale: " + transcript.Scale})
+	doc.row(pdfFontRegular, 10, pdfCell{Text: "Email: " + transcript.StudentEmail}, pdfCell{X: 300, Text: "Verification code: " + code})
+	doc.rule()
+
+	if len(transcript.Terms) == 0 {
+		doc.text(pdfFontRegular, 10, "No completed courses.")
+	}
+	for _, term := range transcript.Terms {
+		heading := term.TermName
+		if term.TermCode != nil {
+			heading += " (" + *term.TermCode + ")"
+		}
+		doc.space(6)
+		doc.text(pdfFontBold, 11, heading)
+		doc.row(pdfFontBold, 9, pdfCell{Text: "Course"}, pdfCell{X: 70, Text: "Title"}, pdfCell{X: 330, Text: "Credits"},
+			pdfCell{X: 385, Text: "Grade"}, pdfCell{X: 435, Text: "Points"})
+		for _, course := range term.Courses {
+			grade := course.Grade
+			if course.Repeated {
+				grade += " (R)"
+			}
+			doc.row(pdfFontRegular, 9, pdfCell{Text: course.CourseCode}, pdfCell{X: 70, Text: shorten(course.CourseTitle, transcriptTitleLength)},
+				pdfCell{X: 330, Text: formatCredits(course.Credits)}, pdfCell{X: 385, Text: grade}, pdfCell{X: 435, Text: fmt.Sprintf("%.2f", course.GradePoints)})
+		}
+		doc.row(pdfFontRegular, 9,
+			pdfCell{X: 70, Text: "Credits earned: " + formatCredits(term.EarnedCredits) + " of " + formatCredits(term.AttemptedCredits)},
+			pdfCell{X: 250, Text: "Term GPA: " + formatGPA(term.GPA)},
+			pdfCell{X: 385, Text: "Cumulative GPA: " + formatGPA(term.CumulativeGPA)})
+	}
+
+	doc.space(6)
+	doc.rule()
+	doc.row(pdfFontBold, 10, pdfCell{Text: "Credits attempted: " + formatCredits(transcript.AttemptedCredits)},
+		pdfCell{X: 170, Text: "Credits earned: " + formatCredits(transcript.EarnedCredits)},
+		pdfCell{X: 330, Text: "Cumulative GPA: " + formatGPA(transcript.CumulativeGPA)})
+	doc.space(6)
+	doc.text(pdfFontRegular, 8, "(R) Repeated course: a later attempt replaces it in the cumulative GPA and credits.")
+	doc.text(pdfFontRegular, 8, "This transcript is official only if the verification code confirms it and the checksum of this file matches.")
+
+	return doc.bytes(issuedAt)
+}
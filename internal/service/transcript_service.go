@@ -0,0 +1,236 @@
+// internal/service/transcript_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/commons/logger"
+	"student-portal/internal/config"
+	"student-portal/internal/models"
+	"student-portal/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// TranscriptService defines the methods for grade-point scales and transcripts.
+type TranscriptService interface {
+	ListScales(ctx context.Context) ([]models.GradePointScale, error)
+	CreateScale(ctx context.Context, req *models.GradePointScaleRequest) (*models.GradePointScale, error)
+	UpdateScale(ctx context.Context, id int64, req *models.GradePointScaleRequest) (*models.GradePointScale, error)
+	// DeleteScale removes a scale other than the default.
+	DeleteScale(ctx context.Context, id int64) error
+
+	// GetTranscript returns a student's academic record computed on the given scale (zero
+	// means the default scale). It is not an official document.
+	GetTranscript(ctx context.Context, studentID, scaleID int64) (*models.Transcript, error)
+	// IssueTranscript renders the official transcript as PDF with a new verification code.
+	IssueTranscript(ctx context.Context, studentID int64, req *models.IssueTranscriptRequest, issuedBy int64) (*models.IssuedTranscript, error)
+	ListIssued(ctx context.Context, studentID int64) ([]models.IssuedTranscript, error)
+	// GetIssuedFileURL returns a signed download URL for an issued transcript, to its student and admins.
+	GetIssuedFileURL(ctx context.Context, id, userID int64, isAdmin bool) (*models.FileURLResponse, error)
+	// Verify confirms a verification code without revealing the transcript's contents.
+	Verify(ctx context.Context, code string) (*models.TranscriptVerification, error)
+}
+
+type transcriptService struct {
+	repo       repository.TranscriptRepository
+	userRepo   repository.UserRepository
+	transactor repository.Transactor
+	fileSvc    FileService
+	historySvc HistoryService
+	cfg        *config.Config
+}
+
+// NewTranscriptService creates a new TranscriptService instance.
+func NewTranscriptService(repo repository.TranscriptRepository, userRepo repository.UserRepository, transactor repository.Transactor, fileSvc FileService, historySvc HistoryService, cfg *config.Config) TranscriptService {
+	return &transcriptService{repo: repo, userRepo: userRepo, transactor: transactor, fileSvc: fileSvc, historySvc: historySvc, cfg: cfg}
+}
+
+func (s *transcriptService) ListScales(ctx context.Context) ([]models.GradePointScale, error) {
+	return s.repo.ListScales(ctx)
+}
+
+// scaleFromRequest validates the request into a scale.
+func scaleFromRequest(req *models.GradePointScaleRequest) (*models.GradePointScale, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, appErrors.New(http.StatusBadRequest, "Name must be 1-100 characters")
+	}
+	points, err := validateGradePoints(req.Points)
+	if err != nil {
+		return nil, err
+	}
+	return &models.GradePointScale{Name: name, IsDefault: req.IsDefault, Points: points}, nil
+}
+
+func (s *transcriptService) CreateScale(ctx context.Context, req *models.GradePointScaleRequest) (*models.GradePointScale, error) {
+	scale, err := scaleFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateScale(ctx, scale); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityGradePointScale, scale.ID, enums.ActionCreate, nil, scale)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+func (s *transcriptService) UpdateScale(ctx context.Context, id int64, req *models.GradePointScaleRequest) (*models.GradePointScale, error) {
+	before, err := s.repo.GetScale(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	scale, err := scaleFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	// There is always a default; it changes by making another scale the default
+	if before.IsDefault && !scale.IsDefault {
+		return nil, appErrors.New(http.StatusConflict, "Make another scale the default instead")
+	}
+	scale.ID = id
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateScale(ctx, scale); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityGradePointScale, scale.ID, enums.ActionUpdate, before, scale)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+func (s *transcriptService) DeleteScale(ctx context.Context, id int64) error {
+	scale, err := s.repo.GetScale(ctx, id)
+	if err != nil {
+		return err
+	}
+	if scale.IsDefault {
+		return appErrors.New(http.StatusConflict, "The default scale cannot be deleted")
+	}
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteScale(ctx, id); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityGradePointScale, id, enums.ActionDelete, scale, nil)
+	})
+}
+
+// resolveScale returns the scale with the given ID, or the default scale for zero.
+func (s *transcriptService) resolveScale(ctx context.Context, scaleID int64) (*models.GradePointScale, error) {
+	if scaleID == 0 {
+		return s.repo.GetDefaultScale(ctx)
+	}
+	scale, err := s.repo.GetScale(ctx, scaleID)
+	if err == appErrors.ErrNotFound {
+		return nil, appErrors.New(http.StatusBadRequest, "Grade-point scale %d does not exist", scaleID)
+	}
+	return scale, err
+}
+
+// buildFor loads a student's completions and builds their transcript on the scale.
+func (s *transcriptService) buildFor(ctx context.Context, studentID, scaleID int64, now time.Time) (*models.Transcript, error) {
+	student, err := s.userRepo.GetUserByID(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	scale, err := s.resolveScale(ctx, scaleID)
+	if err != nil {
+		return nil, err
+	}
+	completions, err := s.repo.ListCompletions(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	return buildTranscript(student, scale, completions, now), nil
+}
+
+func (s *transcriptService) GetTranscript(ctx context.Context, studentID, scaleID int64) (*models.Transcript, error) {
+	return s.buildFor(ctx, studentID, scaleID, time.Now())
+}
+
+func (s *transcriptService) IssueTranscript(ctx context.Context, studentID int64, req *models.IssueTranscriptRequest, issuedBy int64) (*models.IssuedTranscript, error) {
+	var scaleID int64
+	if req.ScaleID != nil {
+		scaleID = *req.ScaleID
+	}
+	issuedAt := time.Now().Truncate(time.Second)
+	transcript, err := s.buildFor(ctx, studentID, scaleID, issuedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := newVerificationCode()
+	if err != nil {
+		return nil, appErrors.ErrInternalServerError
+	}
+	verifyURL := strings.TrimRight(s.cfg.PublicBaseURL, "/") + "/api/transcripts/verify/" + code
+	pdf := renderTranscriptPDF(transcript, s.cfg.InstitutionName, code, verifyURL, issuedAt)
+
+	filename := fmt.Sprintf("transcript-%d-%s.pdf", studentID, issuedAt.UTC().Format("20060102"))
+	file, err := s.fileSvc.StoreGenerated(ctx, studentID, enums.FilePurposeTranscript, filename, "application/pdf", pdf)
+	if err != nil {
+		return nil, err
+	}
+
+	issued := &models.IssuedTranscript{
+		StudentID:        studentID,
+		VerificationCode: code,
+		ScaleName:        transcript.Scale,
+		CumulativeGPA:    transcript.CumulativeGPA,
+		EarnedCredits:    transcript.EarnedCredits,
+		FileID:           file.ID,
+		IssuedBy:         &issuedBy,
+		IssuedAt:         issuedAt,
+	}
+	if err := s.repo.CreateTranscript(ctx, issued); err != nil {
+		if delErr := s.fileSvc.DeleteFile(ctx, file.ID); delErr != nil {
+			logger.Logger.Error("Failed to remove file of a failed transcript", zap.Error(delErr), zap.Int64("file_id", file.ID))
+		}
+		return nil, err
+	}
+	logger.Logger.Info("Issued transcript", zap.Int64("student_id", studentID), zap.Int64("transcript_id", issued.ID), zap.Int64("issued_by", issuedBy))
+	return issued, nil
+}
+
+func (s *transcriptService) ListIssued(ctx context.Context, studentID int64) ([]models.IssuedTranscript, error) {
+	if _, err := s.userRepo.GetUserByID(ctx, studentID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTranscripts(ctx, studentID)
+}
+
+func (s *transcriptService) GetIssuedFileURL(ctx context.Context, id, userID int64, isAdmin bool) (*models.FileURLResponse, error) {
+	issued, err := s.repo.GetTranscript(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin && issued.StudentID != userID {
+		return nil, appErrors.ErrNotFound
+	}
+	file, err := s.fileSvc.GetFile(ctx, issued.FileID)
+	if err != nil {
+		return nil, err
+	}
+	return s.fileSvc.SignedURL(ctx, file)
+}
+
+func (s *transcriptService) Verify(ctx context.Context, code string) (*models.TranscriptVerification, error) {
+	normalized := normalizeVerificationCode(code)
+	if normalized == "" {
+		return nil, appErrors.ErrInvalidTranscript
+	}
+	return s.repo.Verify(ctx, normalized)
+}
@@ -0,0 +1,128 @@
+// internal/service/transcript_test.go
+package service
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"student-portal/internal/models"
+)
+
+func TestBuildTranscript(t *testing.T) {
+	scale := &models.GradePointScale{Name: "Standard", Points: map[string]float64{"A": 4, "B": 3, "C": 2, "D": 1, "F": 0}}
+	student := &models.User{ID: 7, Name: "Sam Student", Email: "sam@example.com"}
+
+	// completion records an attempt at a course; term 0 is credit without a term.
+	completion := func(id, term, courseID int64, credits float64, grade string) models.TranscriptCompletion {
+		c := models.TranscriptCompletion{TranscriptCourse: models.TranscriptCourse{CompletionID: id, CourseID: courseID, Credits: credits, Grade: grade}}
+		if term != 0 {
+			name := "Term"
+			c.TermID, c.TermName = &term, &name
+		}
+		return c
+	}
+
+	tests := []struct {
+		name           string
+		completions    []models.TranscriptCompletion
+		wantTermGPAs   []float64
+		wantCumulative []float64 // Cumulative GPA after each term
+		wantAttempted  float64
+		wantEarned     float64
+		wantGPA        float64
+		wantRepeated   []int64 // Completion IDs replaced by a later attempt
+	}{
+		{
+			name:           "no repeats",
+			completions:    []models.TranscriptCompletion{completion(1, 1, 10, 3, "A"), completion(2, 1, 11, 4, "B")},
+			wantTermGPAs:   []float64{3.43},
+			wantCumulative: []float64{3.43},
+			wantAttempted:  7,
+			wantEarned:     7,
+			wantGPA:        3.43,
+		},
+		{
+			name:           "failed course passed on repeat",
+			completions:    []models.TranscriptCompletion{completion(1, 1, 10, 3, "F"), completion(2, 2, 10, 3, "B")},
+			wantTermGPAs:   []float64{0, 3},
+			wantCumulative: []float64{0, 3},
+			wantAttempted:  6,
+			wantEarned:     3,
+			wantGPA:        3,
+			wantRepeated:   []int64{1},
+		},
+		{
+			name: "latest attempt counts even when lower",
+			completions: []models.TranscriptCompletion{
+				completion(1, 1, 10, 3, "A"), completion(2, 1, 11, 3, "C"),
+				completion(3, 2, 10, 3, "C"),
+			},
+			wantTermGPAs:   []float64{3, 2},
+			wantCumulative: []float64{3, 2},
+			wantAttempted:  9,
+			wantEarned:     6,
+			wantGPA:        2,
+			wantRepeated:   []int64{1},
+		},
+		{
+			name: "passed course failed on repeat",
+			completions: []models.TranscriptCompletion{
+				completion(1, 1, 10, 4, "D"), completion(2, 1, 11, 2, "A"),
+				completion(3, 2, 10, 4, "F"),
+			},
+			wantTermGPAs:   []float64{2, 0},
+			wantCumulative: []float64{2, 1.33},
+			wantAttempted:  10,
+			wantEarned:     2,
+			wantGPA:        1.33,
+			wantRepeated:   []int64{1},
+		},
+		{
+			name:           "credit without a term",
+			completions:    []models.TranscriptCompletion{completion(1, 0, 12, 3, "B"), completion(2, 1, 10, 3, "A")},
+			wantTermGPAs:   []float64{3, 4},
+			wantCumulative: []float64{3, 3.5},
+			wantAttempted:  6,
+			wantEarned:     6,
+			wantGPA:        3.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transcript := buildTranscript(student, scale, tt.completions, time.Now())
+
+			var termGPAs, cumulative []float64
+			var repeated []int64
+			for _, term := range transcript.Terms {
+				if term.GPA == nil || term.CumulativeGPA == nil {
+					t.Fatalf("term %q has no GPA", term.TermName)
+				}
+				termGPAs = append(termGPAs, *term.GPA)
+				cumulative = append(cumulative, *term.CumulativeGPA)
+				for _, course := range term.Courses {
+					if course.Repeated {
+						repeated = append(repeated, course.CompletionID)
+					}
+				}
+			}
+			if !slices.Equal(termGPAs, tt.wantTermGPAs) {
+				t.Errorf("term GPAs = %v, want %v", termGPAs, tt.wantTermGPAs)
+			}
+			if !slices.Equal(cumulative, tt.wantCumulative) {
+				t.Errorf("cumulative GPAs = %v, want %v", cumulative, tt.wantCumulative)
+			}
+			if !slices.Equal(repeated, tt.wantRepeated) {
+				t.Errorf("repeated completions = %v, want %v", repeated, tt.wantRepeated)
+			}
+			if transcript.AttemptedCredits != tt.wantAttempted || transcript.EarnedCredits != tt.wantEarned {
+				t.Errorf("credits = %v attempted, %v earned, want %v and %v",
+					transcript.AttemptedCredits, transcript.EarnedCredits, tt.wantAttempted, tt.wantEarned)
+			}
+			if transcript.CumulativeGPA == nil || *transcript.CumulativeGPA != tt.wantGPA {
+				t.Errorf("CumulativeGPA = %v, want %v", transcript.CumulativeGPA, tt.wantGPA)
+			}
+		})
+	}
+}
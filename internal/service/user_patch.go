@@ -0,0 +1,290 @@
+// internal/service/user_patch.go
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+	"student-portal/internal/utils"
+
+	"go.uber.org/zap"
+)
+
+// patchableFields is the set of members of the document PATCH requests operate on.
+var patchableFields = []string{
+	fieldName, fieldEmail, fieldRole, fieldPhone, fieldDateOfBirth, fieldAddress,
+	fieldStudentNumber, fieldProgram, fieldEnrollmentYear, fieldCustomFields,
+}
+
+// patchDocument builds the JSON document PATCH requests are applied to.
+// Values use the types produced by encoding/json so that patched values compare cleanly.
+func patchDocument(user *models.User, profile *models.Profile) map[string]interface{} {
+	resp := profile.ToResponse()
+
+	doc := map[string]interface{}{
+		fieldName:           user.Name,
+		fieldEmail:          user.Email,
+		fieldRole:           user.Role,
+		fieldPhone:          nil,
+		fieldDateOfBirth:    nil,
+		fieldAddress:        nil,
+		fieldStudentNumber:  nil,
+		fieldProgram:        nil,
+		fieldEnrollmentYear: nil,
+		fieldCustomFields:   resp.CustomFields,
+	}
+	for field, value := range map[string]*string{
+		fieldPhone: resp.Phone, fieldDateOfBirth: resp.DateOfBirth, fieldAddress: resp.Address,
+		fieldStudentNumber: resp.StudentNumber, fieldProgram: resp.Program,
+	} {
+		if value != nil {
+			doc[field] = *value
+		}
+	}
+	if resp.EnrollmentYear != nil {
+		doc[fieldEnrollmentYear] = float64(*resp.EnrollmentYear)
+	}
+	return doc
+}
+
+func (s *userService) PatchProfile(ctx context.Context, id int64, patch *utils.Patch, ifMatch *utils.IfMatch) (*models.UserResponse, error) {
+	return s.applyPatch(ctx, id, patch, ifMatch, true)
+}
+
+func (s *userService) PatchUser(ctx context.Context, id int64, patch *utils.Patch, ifMatch *utils.IfMatch) (*models.UserResponse, error) {
+	return s.applyPatch(ctx, id, patch, ifMatch, false)
+}
+
+// applyPatch applies a merge patch or JSON patch to the user's document, checks the
+// changed fields against the caller's permissions and persists only the changed columns.
+func (s *userService) applyPatch(ctx context.Context, id int64, patch *utils.Patch, ifMatch *utils.IfMatch, selfService bool) (*models.UserResponse, error) {
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVersion(user, ifMatch); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.loadProfile(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Apply the patch to the current representation
+	original := patchDocument(user, profile)
+	patched, err := patch.Apply(original)
+	if errors.Is(err, utils.ErrPatchTestFailed) {
+		return nil, appErrors.New(http.StatusConflict, "Patch test operation failed")
+	}
+	if err != nil {
+		return nil, appErrors.New(http.StatusBadRequest, "Invalid patch: %v", err)
+	}
+
+	// 2. Work out which fields changed and reject unknown ones
+	changed, err := changedFields(original, patched)
+	if err != nil {
+		return nil, err
+	}
+	if len(changed) == 0 {
+		return toResponseWithProfile(user, profile), nil
+	}
+
+	// 3. Enforce field-level permissions
+	if selfService {
+		allowed, ok := selfEditableFields[user.Role]
+		if !ok {
+			allowed = selfEditableFields[string(enums.RoleStudent)]
+		}
+		for _, f := range changed {
+			if !allowed[f] {
+				return nil, appErrors.New(http.StatusForbidden, "You are not allowed to change '%s'", f)
+			}
+		}
+	}
+
+	// 4. Validate and convert the changed values to column updates
+	// Any profile change is checked against the required custom fields
+	var defs []models.ProfileFieldDefinition
+	for _, f := range changed {
+		if f != fieldName && f != fieldEmail && f != fieldRole {
+			if defs, err = s.profileRepo.ListFieldDefinitions(ctx); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	restrictCustom := selfService && user.Role == string(enums.RoleStudent)
+	userColumns, profileColumns, err := columnUpdates(changed, patched, profile, defs, restrictCustom)
+	if err != nil {
+		return nil, err
+	}
+	if email, ok := userColumns[fieldEmail].(string); ok {
+		userColumns[fieldEmail] = s.normalizeEmail(email)
+	}
+
+	// 5. Persist only the changed columns in one transaction; the user update also checks and
+	// bumps the version
+	before := userSnapshot(user, profile)
+	var updated *models.User
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if updated, err = s.repo.PatchUser(ctx, user.ID, user.Version, userColumns); err != nil {
+			return err
+		}
+		if err := s.profileRepo.PatchProfile(ctx, user.ID, profileColumns); err != nil {
+			return err
+		}
+		if profile, err = s.loadProfile(ctx, user.ID); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityUser, user.ID, enums.ActionUpdate, before, userSnapshot(updated, profile))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eventName := "user_updated_admin"
+	if selfService {
+		eventName = "user_updated"
+	}
+	publishAsync(
+		func(ctx context.Context) error {
+			return s.kafka.PublishUpdateEvent(ctx, updated.ID, updated.Email, updated.Name, string(updated.Role))
+		},
+		eventName,
+		zap.Int64("user_id", updated.ID),
+	)
+
+	return toResponseWithProfile(updated, profile), nil
+}
+
+// changedFields returns the patchable fields whose values differ between the documents.
+// A removed member is treated as null.
+func changedFields(original, patched map[string]interface{}) ([]string, error) {
+	for key := range patched {
+		if _, ok := original[key]; !ok {
+			return nil, appErrors.New(http.StatusBadRequest, "Unknown field '%s'", key)
+		}
+	}
+
+	changed := make([]string, 0)
+	for _, f := range patchableFields {
+		if !reflect.DeepEqual(original[f], patched[f]) {
+			changed = append(changed, f)
+		}
+	}
+	return changed, nil
+}
+
+// columnUpdates validates the changed values and splits them into users and user_profiles columns.
+func columnUpdates(changed []string, patched map[string]interface{}, profile *models.Profile, defs []models.ProfileFieldDefinition, restrictCustom bool) (map[string]interface{}, map[string]interface{}, error) {
+	userColumns := map[string]interface{}{}
+	profileColumns := map[string]interface{}{}
+
+	for _, f := range changed {
+		value := patched[f]
+
+		switch f {
+		case fieldName, fieldEmail, fieldRole:
+			str, ok := value.(string)
+			if !ok {
+				return nil, nil, appErrors.New(http.StatusBadRequest, "Field '%s' must be a non-null string", f)
+			}
+			str = strings.TrimSpace(str)
+			switch {
+			case f == fieldName && str == "":
+				return nil, nil, appErrors.New(http.StatusBadRequest, "Name cannot be empty")
+			case f == fieldEmail && !strings.Contains(str, "@"):
+				return nil, nil, appErrors.New(http.StatusBadRequest, "Invalid email address")
+			case f == fieldRole && !validRoles[str]:
+				return nil, nil, appErrors.New(http.StatusBadRequest, "Invalid role '%s'", str)
+			}
+			userColumns[f] = str
+
+		case fieldPhone, fieldAddress, fieldStudentNumber, fieldProgram, fieldDateOfBirth:
+			var str string
+			if value != nil {
+				v, ok := value.(string)
+				if !ok {
+					return nil, nil, appErrors.New(http.StatusBadRequest, "Field '%s' must be a string or null", f)
+				}
+				str = v
+			}
+
+			switch f {
+			case fieldDateOfBirth:
+				dob, err := parseDateOfBirth(str)
+				if err != nil {
+					return nil, nil, err
+				}
+				profileColumns[f] = dob
+			case fieldPhone:
+				phone := optionalString(str)
+				if phone != nil && !phonePattern.MatchString(*phone) {
+					return nil, nil, appErrors.New(http.StatusBadRequest, "Invalid phone number")
+				}
+				profileColumns[f] = phone
+			default:
+				profileColumns[f] = optionalString(str)
+			}
+
+		case fieldEnrollmentYear:
+			if value == nil {
+				profileColumns[f] = nil
+				continue
+			}
+			year, ok := value.(float64)
+			if !ok || year != math.Trunc(year) || year < 1900 || year > 2200 {
+				return nil, nil, appErrors.New(http.StatusBadRequest, "Invalid enrollment_year")
+			}
+			profileColumns[f] = int(year)
+
+		case fieldCustomFields:
+			values := map[string]interface{}{}
+			if value != nil {
+				obj, ok := value.(map[string]interface{})
+				if !ok {
+					return nil, nil, appErrors.New(http.StatusBadRequest, "Field 'custom_fields' must be an object")
+				}
+				values = obj
+			}
+
+			// Express the new object as per-key changes (null = removed) and reuse the merge validation.
+			delta := map[string]interface{}{}
+			for key, v := range values {
+				if !reflect.DeepEqual(profile.CustomFields[key], v) {
+					delta[key] = v
+				}
+			}
+			for key := range profile.CustomFields {
+				if _, ok := values[key]; !ok {
+					delta[key] = nil
+				}
+			}
+
+			working := &models.Profile{CustomFields: map[string]interface{}{}}
+			for key, v := range profile.CustomFields {
+				working.CustomFields[key] = v
+			}
+			if err := applyCustomFields(working, delta, defs, restrictCustom); err != nil {
+				return nil, nil, err
+			}
+			profileColumns[f] = working.CustomFields
+		}
+	}
+
+	if _, ok := profileColumns[fieldCustomFields]; !ok && len(profileColumns) > 0 {
+		if err := checkRequiredFields(profile.CustomFields, defs, restrictCustom); err != nil {
+			return nil, nil, err
+		}
+	}
+	return userColumns, profileColumns, nil
+}
@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"io"
+
+	"student-portal/internal/commons/enums"
 	// Needed for Login event timestamp
 	appErrors "student-portal/internal/commons/errors"
 	"student-portal/internal/commons/logger" // Imported for structured logging
@@ -19,37 +22,122 @@ type UserService interface {
 	RegisterUser(ctx context.Context, req *models.RegisterRequest) (*models.UserResponse, error)
 	LoginUser(ctx context.Context, req *models.LoginRequest) (*models.LoginResponse, error)
 	GetUserByID(ctx context.Context, id int64) (*models.UserResponse, error)
-	UpdateProfile(ctx context.Context, id int64, req *models.UpdateProfileRequest) (*models.UserResponse, error)
-	UpdateUser(ctx context.Context, id int64, req *models.UpdateUserRequest) (*models.UserResponse, error)
+	UpdateProfile(ctx context.Context, id int64, req *models.UpdateProfileRequest, ifMatch *utils.IfMatch) (*models.UserResponse, error)
+	UpdateUser(ctx context.Context, id int64, req *models.UpdateUserRequest, ifMatch *utils.IfMatch) (*models.UserResponse, error)
+	PatchProfile(ctx context.Context, id int64, patch *utils.Patch, ifMatch *utils.IfMatch) (*models.UserResponse, error)
+	PatchUser(ctx context.Context, id int64, patch *utils.Patch, ifMatch *utils.IfMatch) (*models.UserResponse, error)
 	DeleteUser(ctx context.Context, id int64) error
 	ListUsers(ctx context.Context, limit, offset int) ([]models.UserResponse, int64, error)
+	ListProfileFields(ctx context.Context) ([]models.ProfileFieldDefinition, error)
+	CreateProfileField(ctx context.Context, req *models.CreateProfileFieldRequest) (*models.ProfileFieldDefinition, error)
+	DeleteProfileField(ctx context.Context, key string) error
+	UploadAvatar(ctx context.Context, userID int64, filename string, r io.Reader) (*models.FileURLResponse, error)
+	GetAvatarURL(ctx context.Context, userID int64) (*models.FileURLResponse, error)
+	DeleteAvatar(ctx context.Context, userID int64) error
+	// CheckAccountActive fails unless the user's account status allows using the API.
+	CheckAccountActive(ctx context.Context, userID int64) error
+	// ChangeStatus moves the account to another status. Erased accounts cannot change status.
+	ChangeStatus(ctx context.Context, id, changedBy int64, req *models.ChangeStatusRequest) (*models.UserResponse, error)
+	ListStatusChanges(ctx context.Context, id int64) ([]models.UserStatusChange, error)
 }
 
 type userService struct {
-	repo  repository.UserRepository
-	cfg   *config.Config
-	kafka *kafka.KafkaProducer
+	repo        repository.UserRepository
+	profileRepo repository.ProfileRepository
+	transactor  repository.Transactor
+	fileSvc     FileService
+	historySvc  HistoryService
+	cfg         *config.Config
+	kafka       *kafka.KafkaProducer
 }
 
 // NewUserService creates a new UserService instance.
-func NewUserService(repo repository.UserRepository, cfg *config.Config, kafka *kafka.KafkaProducer) UserService {
-	return &userService{repo: repo, cfg: cfg, kafka: kafka}
-}
-
-// publishAsync handles the non-blocking publication and logs any failure.
-func (s *userService) publishAsync(eventFunc func(context.Context) error, eventName string, userID int64) {
-	go func() {
-		// Use a background context as the original request context may expire.
-		if err := eventFunc(context.Background()); err != nil {
-			logger.Logger.Error("Failed to publish Kafka event",
-				zap.Error(err),
-				zap.String("event", eventName),
-				zap.Int64("user_id", userID),
-			)
-		} else {
-			logger.Logger.Info("Successfully published Kafka event", zap.String("event", eventName), zap.Int64("user_id", userID))
+func NewUserService(repo repository.UserRepository, profileRepo repository.ProfileRepository, transactor repository.Transactor, fileSvc FileService, historySvc HistoryService, cfg *config.Config, kafka *kafka.KafkaProducer) UserService {
+	return &userService{repo: repo, profileRepo: profileRepo, transactor: transactor, fileSvc: fileSvc, historySvc: historySvc, cfg: cfg, kafka: kafka}
+}
+
+// userSnapshot captures the audited state of a user and their profile for change history.
+// The snapshot is a fresh map, so later changes to user or profile do not affect it.
+func userSnapshot(user *models.User, profile *models.Profile) map[string]interface{} {
+	snapshot := patchDocument(user, profile)
+	snapshot["status"] = user.Status
+	snapshot["avatar_file_id"] = nil
+	if profile.AvatarFileID != nil {
+		snapshot["avatar_file_id"] = float64(*profile.AvatarFileID)
+	}
+	if custom, ok := snapshot[fieldCustomFields].(map[string]interface{}); ok {
+		copied := make(map[string]interface{}, len(custom))
+		for key, value := range custom {
+			copied[key] = value
 		}
-	}()
+		snapshot[fieldCustomFields] = copied
+	}
+	return snapshot
+}
+
+// normalizeEmail returns the form in which an email address is stored and looked up.
+func (s *userService) normalizeEmail(email string) string {
+	return utils.NormalizeEmail(email, s.cfg.EmailLowercaseLocalPart)
+}
+
+// loadProfile returns the user's profile, or an empty one if it has not been created yet.
+func (s *userService) loadProfile(ctx context.Context, userID int64) (*models.Profile, error) {
+	profile, err := s.profileRepo.GetProfile(ctx, userID)
+	if err == appErrors.ErrNotFound {
+		return models.NewProfile(userID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// saveChanges validates and persists a change set for the user and their profile.
+func (s *userService) saveChanges(ctx context.Context, user *models.User, changes *userChanges, selfService bool) (*models.Profile, error) {
+	profile, err := s.loadProfile(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Any profile change is checked against the required custom fields
+	var defs []models.ProfileFieldDefinition
+	if changes.touchesProfile() {
+		if defs, err = s.profileRepo.ListFieldDefinitions(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	before := userSnapshot(user, profile)
+	if err := applyChanges(user, profile, changes, defs, selfService); err != nil {
+		return nil, err
+	}
+	user.Email = s.normalizeEmail(user.Email)
+
+	// The repository update method will handle the actual update, the version check and email
+	// conflicts. The profile is written in the same transaction, so a failure leaves both untouched.
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.UpdateUser(ctx, user); err != nil {
+			return err
+		}
+		if changes.touchesProfile() {
+			if err := s.profileRepo.UpsertProfile(ctx, profile); err != nil {
+				return err
+			}
+		}
+		return s.historySvc.Record(ctx, enums.EntityUser, user.ID, enums.ActionUpdate, before, userSnapshot(user, profile))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// toResponseWithProfile builds a UserResponse that embeds the user's profile.
+func toResponseWithProfile(user *models.User, profile *models.Profile) *models.UserResponse {
+	resp := user.ToResponsePtr()
+	profileResp := profile.ToResponse()
+	resp.Profile = &profileResp
+	return resp
 }
 
 func (s *userService) RegisterUser(ctx context.Context, req *models.RegisterRequest) (*models.UserResponse, error) {
@@ -62,23 +150,29 @@ func (s *userService) RegisterUser(ctx context.Context, req *models.RegisterRequ
 	// 2. Create the User model
 	user := &models.User{
 		Name:     req.Name,
-		Email:    req.Email,
+		Email:    s.normalizeEmail(req.Email),
 		Password: hashedPassword,
 		Role:     req.Role,
 	}
 
 	// 3. Save to repository
-	if err := s.repo.CreateUser(ctx, user); err != nil {
-		return nil, err // Returns ErrEmailExists if unique constraint violated
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.CreateUser(ctx, user); err != nil {
+			return err // Returns ErrEmailExists if unique constraint violated
+		}
+		return s.historySvc.Record(ctx, enums.EntityUser, user.ID, enums.ActionCreate, nil, userSnapshot(user, models.NewProfile(user.ID)))
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// 4. Publish register event to Kafka asynchronously and with error logging
-	s.publishAsync(
+	publishAsync(
 		func(ctx context.Context) error {
 			return s.kafka.PublishRegisterEvent(ctx, user.ID, user.Email, user.Name, string(user.Role))
 		},
 		"user_registered",
-		user.ID,
+		zap.Int64("user_id", user.ID),
 	)
 
 	return user.ToResponsePtr(), nil
@@ -86,7 +180,7 @@ func (s *userService) RegisterUser(ctx context.Context, req *models.RegisterRequ
 
 func (s *userService) LoginUser(ctx context.Context, req *models.LoginRequest) (*models.LoginResponse, error) {
 	// 1. Get user by email
-	user, err := s.repo.GetUserByEmail(ctx, req.Email)
+	user, err := s.repo.GetUserByEmail(ctx, s.normalizeEmail(req.Email))
 	if err != nil {
 		if err == appErrors.ErrNotFound {
 			return nil, appErrors.ErrInvalidCredentials
@@ -99,19 +193,25 @@ func (s *userService) LoginUser(ctx context.Context, req *models.LoginRequest) (
 		return nil, appErrors.ErrInvalidCredentials
 	}
 
-	// 3. Generate JWT token
+	// 3. Only active accounts may log in. This is checked after the password so that the
+	//    status of an account is not revealed to someone who does not know it.
+	if err := checkCanLogin(user); err != nil {
+		return nil, err
+	}
+
+	// 4. Generate JWT token
 	token, err := utils.GenerateToken(s.cfg, user.ID, user.Email, user.Role)
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. KAFKA: Publish Login Event
-	s.publishAsync(
+	// 5. KAFKA: Publish Login Event
+	publishAsync(
 		func(ctx context.Context) error {
 			return s.kafka.PublishLoginEvent(ctx, user.ID, user.Email, user.Name, user.Role)
 		},
 		"user_logged_in",
-		user.ID,
+		zap.Int64("user_id", user.ID),
 	)
 
 	return &models.LoginResponse{
@@ -120,66 +220,73 @@ func (s *userService) LoginUser(ctx context.Context, req *models.LoginRequest) (
 	}, nil
 }
 
-func (s *userService) UpdateProfile(ctx context.Context, id int64, req *models.UpdateProfileRequest) (*models.UserResponse, error) {
+// checkVersion fails fast when none of the client's If-Match versions is the current one.
+// A nil ifMatch means the client did not make the update conditional.
+func checkVersion(user *models.User, ifMatch *utils.IfMatch) error {
+	if ifMatch != nil && !ifMatch.Matches(user.Version) {
+		return appErrors.ErrPreconditionFailed
+	}
+	return nil
+}
+
+func (s *userService) UpdateProfile(ctx context.Context, id int64, req *models.UpdateProfileRequest, ifMatch *utils.IfMatch) (*models.UserResponse, error) {
 	user, err := s.repo.GetUserByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-
-	if req.Name != nil {
-		user.Name = *req.Name
+	if err := checkVersion(user, ifMatch); err != nil {
+		return nil, err
 	}
-	if req.Email != nil {
-		user.Email = *req.Email
+
+	// Field-level permissions depend on the role stored for the user, not on the request.
+	changes := changesFromProfileRequest(req)
+	if err := checkSelfEditable(user.Role, changes); err != nil {
+		return nil, err
 	}
 
-	// The repository update method will handle the actual update and check for email conflicts.
-	if err := s.repo.UpdateUser(ctx, user); err != nil {
+	profile, err := s.saveChanges(ctx, user, changes, true)
+	if err != nil {
 		return nil, err
 	}
 
 	// Publish update event to Kafka asynchronously
-	s.publishAsync(
+	publishAsync(
 		func(ctx context.Context) error {
 			return s.kafka.PublishUpdateEvent(ctx, user.ID, user.Email, user.Name, string(user.Role))
 		},
 		"user_updated",
-		user.ID,
+		zap.Int64("user_id", user.ID),
 	)
 
-	return user.ToResponsePtr(), nil
+	return toResponseWithProfile(user, profile), nil
 }
 
-func (s *userService) UpdateUser(ctx context.Context, id int64, req *models.UpdateUserRequest) (*models.UserResponse, error) {
+func (s *userService) UpdateUser(ctx context.Context, id int64, req *models.UpdateUserRequest, ifMatch *utils.IfMatch) (*models.UserResponse, error) {
 	user, err := s.repo.GetUserByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-
-	if req.Name != nil {
-		user.Name = *req.Name
-	}
-	if req.Email != nil {
-		user.Email = *req.Email
-	}
-	if req.Role != nil {
-		user.Role = *req.Role
+	// The repository update is conditional on the version read here, which also
+	// catches modifications made between this read and the write.
+	if err := checkVersion(user, ifMatch); err != nil {
+		return nil, err
 	}
 
-	if err := s.repo.UpdateUser(ctx, user); err != nil {
+	profile, err := s.saveChanges(ctx, user, changesFromUserRequest(req), false)
+	if err != nil {
 		return nil, err
 	}
 
 	// Since this is a core UpdateUser (potentially by admin), we should also publish an event.
-	s.publishAsync(
+	publishAsync(
 		func(ctx context.Context) error {
 			return s.kafka.PublishUpdateEvent(ctx, user.ID, user.Email, user.Name, string(user.Role))
 		},
 		"user_updated_admin",
-		user.ID,
+		zap.Int64("user_id", user.ID),
 	)
 
-	return user.ToResponsePtr(), nil
+	return toResponseWithProfile(user, profile), nil
 }
 
 func (s *userService) GetUserByID(ctx context.Context, id int64) (*models.UserResponse, error) {
@@ -187,12 +294,31 @@ func (s *userService) GetUserByID(ctx context.Context, id int64) (*models.UserRe
 	if err != nil {
 		return nil, err
 	}
-	return user.ToResponsePtr(), nil
+
+	profile, err := s.loadProfile(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toResponseWithProfile(user, profile), nil
 }
 
 func (s *userService) DeleteUser(ctx context.Context, id int64) error {
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	profile, err := s.loadProfile(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	// You might want to publish a Delete event here as well!
-	return s.repo.DeleteUser(ctx, id)
+	return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteUser(ctx, id); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityUser, id, enums.ActionDelete, userSnapshot(user, profile), nil)
+	})
 }
 
 func (s *userService) ListUsers(ctx context.Context, limit, offset int) ([]models.UserResponse, int64, error) {
@@ -208,3 +334,102 @@ func (s *userService) ListUsers(ctx context.Context, limit, offset int) ([]model
 
 	return userResponses, totalCount, nil
 }
+
+func (s *userService) ListProfileFields(ctx context.Context) ([]models.ProfileFieldDefinition, error) {
+	return s.profileRepo.ListFieldDefinitions(ctx)
+}
+
+func (s *userService) CreateProfileField(ctx context.Context, req *models.CreateProfileFieldRequest) (*models.ProfileFieldDefinition, error) {
+	if err := validateFieldDefinition(req); err != nil {
+		return nil, err
+	}
+
+	def := &models.ProfileFieldDefinition{
+		Key:             req.Key,
+		Label:           req.Label,
+		FieldType:       req.FieldType,
+		Options:         req.Options,
+		Required:        req.Required,
+		StudentEditable: req.StudentEditable,
+	}
+	if err := s.profileRepo.CreateFieldDefinition(ctx, def); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+func (s *userService) DeleteProfileField(ctx context.Context, key string) error {
+	return s.profileRepo.DeleteFieldDefinition(ctx, key)
+}
+
+func (s *userService) UploadAvatar(ctx context.Context, userID int64, filename string, r io.Reader) (*models.FileURLResponse, error) {
+	// The file service enforces the avatar size limit, sniffs the type and resizes the image.
+	file, err := s.fileSvc.Upload(ctx, userID, enums.FilePurposeAvatar, filename, r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the avatar column is written, so the version bump cannot hide a lost update
+	var previous *int64
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.repo.BumpVersion(ctx, userID); err != nil {
+			return err
+		}
+		var err error
+		if previous, err = s.profileRepo.SetAvatar(ctx, userID, &file.ID); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityUser, userID, enums.ActionUpdate,
+			map[string]interface{}{"avatar_file_id": previous}, map[string]interface{}{"avatar_file_id": file.ID})
+	})
+	if err != nil {
+		// nolint:errcheck
+		s.fileSvc.DeleteFile(ctx, file.ID)
+		return nil, err
+	}
+
+	if previous != nil {
+		if err := s.fileSvc.DeleteFile(ctx, *previous); err != nil && err != appErrors.ErrNotFound {
+			logger.Logger.Error("Failed to delete previous avatar", zap.Error(err), zap.Int64("user_id", userID))
+		}
+	}
+
+	return s.fileSvc.SignedURL(ctx, file)
+}
+
+func (s *userService) GetAvatarURL(ctx context.Context, userID int64) (*models.FileURLResponse, error) {
+	profile, err := s.loadProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile.AvatarFileID == nil {
+		return nil, appErrors.ErrNotFound
+	}
+
+	file, err := s.fileSvc.GetFile(ctx, *profile.AvatarFileID)
+	if err != nil {
+		return nil, err
+	}
+	return s.fileSvc.SignedURL(ctx, file)
+}
+
+func (s *userService) DeleteAvatar(ctx context.Context, userID int64) error {
+	var previous *int64
+	err := s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.repo.BumpVersion(ctx, userID); err != nil {
+			return err
+		}
+		var err error
+		if previous, err = s.profileRepo.SetAvatar(ctx, userID, nil); err != nil {
+			return err
+		}
+		if previous == nil {
+			return appErrors.ErrNotFound
+		}
+		return s.historySvc.Record(ctx, enums.EntityUser, userID, enums.ActionUpdate, map[string]interface{}{"avatar_file_id": *previous}, map[string]interface{}{"avatar_file_id": nil})
+	})
+	if err != nil {
+		return err
+	}
+	return s.fileSvc.DeleteFile(ctx, *previous)
+}
@@ -0,0 +1,134 @@
+// internal/service/user_status.go
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"student-portal/internal/commons/enums"
+	appErrors "student-portal/internal/commons/errors"
+	"student-portal/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// statusTransitions lists the statuses each account status may move to. Erased accounts are
+// archived for good: the repository refuses to change their status.
+var statusTransitions = map[enums.AccountStatus][]enums.AccountStatus{
+	enums.AccountStatusPending:   {enums.AccountStatusActive, enums.AccountStatusArchived},
+	enums.AccountStatusActive:    {enums.AccountStatusSuspended, enums.AccountStatusGraduated, enums.AccountStatusArchived},
+	enums.AccountStatusSuspended: {enums.AccountStatusActive, enums.AccountStatusArchived},
+	enums.AccountStatusGraduated: {enums.AccountStatusActive, enums.AccountStatusArchived}, // Re-enrollment
+	enums.AccountStatusArchived:  {enums.AccountStatusActive},                              // Restore
+}
+
+// loginStatusErrors are the errors LoginUser returns for accounts that are not active.
+var loginStatusErrors = map[enums.AccountStatus]error{
+	enums.AccountStatusPending:   appErrors.ErrAccountPending,
+	enums.AccountStatusSuspended: appErrors.ErrAccountSuspended,
+	enums.AccountStatusGraduated: appErrors.ErrAccountGraduated,
+	enums.AccountStatusArchived:  appErrors.ErrAccountArchived,
+}
+
+// checkCanLogin returns the error for an account whose status does not allow logging in.
+func checkCanLogin(user *models.User) error {
+	return checkStatusCanLogin(enums.AccountStatus(user.Status))
+}
+
+// checkStatusCanLogin returns the error for an account status that does not allow logging in.
+func checkStatusCanLogin(status enums.AccountStatus) error {
+	if status == enums.AccountStatusActive {
+		return nil
+	}
+	if err, ok := loginStatusErrors[status]; ok {
+		return err
+	}
+	return appErrors.ErrForbidden
+}
+
+// checkStatusTransition fails unless the account may move from one status to the other.
+func checkStatusTransition(from, to enums.AccountStatus) error {
+	if from == to {
+		return appErrors.New(http.StatusConflict, "Account is already %s", to)
+	}
+	if _, known := statusTransitions[to]; !known {
+		return appErrors.New(http.StatusBadRequest, "Invalid status '%s'", to)
+	}
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return appErrors.New(http.StatusConflict, "Cannot change account status from '%s' to '%s'", from, to)
+}
+
+func (s *userService) CheckAccountActive(ctx context.Context, userID int64) error {
+	status, err := s.repo.GetStatus(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return checkStatusCanLogin(enums.AccountStatus(status))
+}
+
+func (s *userService) ChangeStatus(ctx context.Context, id, changedBy int64, req *models.ChangeStatusRequest) (*models.UserResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, appErrors.New(http.StatusBadRequest, "A reason is required to change the account status")
+	}
+	if id == changedBy {
+		return nil, appErrors.New(http.StatusBadRequest, "You cannot change the status of your own account")
+	}
+
+	user, err := s.repo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	to := enums.AccountStatus(strings.TrimSpace(req.Status))
+	if err := checkStatusTransition(enums.AccountStatus(user.Status), to); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.loadProfile(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The repository only applies the change if the status is still the one checked here.
+	change := &models.UserStatusChange{
+		UserID:     user.ID,
+		FromStatus: user.Status,
+		ToStatus:   string(to),
+		Reason:     reason,
+		ChangedBy:  &changedBy,
+	}
+	var updated *models.User
+	err = s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if updated, err = s.repo.ChangeStatus(ctx, change); err != nil {
+			return err
+		}
+		return s.historySvc.Record(ctx, enums.EntityUser, user.ID, enums.ActionUpdate, userSnapshot(user, profile), userSnapshot(updated, profile))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishAsync(
+		func(ctx context.Context) error {
+			return s.kafka.PublishStatusChangedEvent(ctx, updated.ID, change.FromStatus, change.ToStatus, reason, changedBy)
+		},
+		"user_status_changed",
+		zap.Int64("user_id", updated.ID),
+		zap.String("status", change.ToStatus),
+	)
+
+	return toResponseWithProfile(updated, profile), nil
+}
+
+func (s *userService) ListStatusChanges(ctx context.Context, id int64) ([]models.UserStatusChange, error) {
+	if _, err := s.repo.GetUserByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStatusChanges(ctx, id)
+}
@@ -0,0 +1,25 @@
+// internal/storage/scanner.go
+package storage
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInfected is returned by a Scanner that detected malicious content.
+var ErrInfected = errors.New("storage: file rejected by virus scan")
+
+// Scanner is a hook point for virus scanning uploaded content before it is stored.
+// Implementations should return ErrInfected for malicious content and any other
+// error if the scan itself could not be performed.
+type Scanner interface {
+	Scan(ctx context.Context, filename string, content []byte) error
+}
+
+// ScannerFunc adapts an ordinary function to the Scanner interface.
+type ScannerFunc func(ctx context.Context, filename string, content []byte) error
+
+// Scan calls f(ctx, filename, content).
+func (f ScannerFunc) Scan(ctx context.Context, filename string, content []byte) error {
+	return f(ctx, filename, content)
+}
@@ -0,0 +1,55 @@
+// internal/storage/storage.go
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"student-portal/internal/config"
+)
+
+// ErrObjectNotFound is returned when a key does not exist in the storage backend.
+var ErrObjectNotFound = errors.New("storage: object not found")
+
+// ErrInvalidSignature is returned when a signed URL is malformed, tampered with or expired.
+var ErrInvalidSignature = errors.New("storage: invalid or expired signature")
+
+// Storage is a pluggable blob store.
+type Storage interface {
+	// Put stores the content under the given key, replacing any existing object.
+	Put(ctx context.Context, key string, content []byte, contentType string) error
+	// Get opens the object for reading. The caller must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that allows downloading the object until it expires.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// URLVerifier is implemented by backends whose signed URLs are served by this API
+// (rather than by the backend itself) and must therefore be verified here.
+type URLVerifier interface {
+	VerifySignedURL(key, expires, signature string) error
+}
+
+// NewStorage creates the storage backend selected by the configuration.
+func NewStorage(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageBackend {
+	case "local":
+		return NewLocalStorage(cfg.StorageLocalDir, cfg.PublicBaseURL, cfg.FileSigningKey)
+	case "s3":
+		return NewS3Storage(S3Options{
+			Endpoint:     cfg.S3Endpoint,
+			Region:       cfg.S3Region,
+			Bucket:       cfg.S3Bucket,
+			AccessKey:    cfg.S3AccessKey,
+			SecretKey:    cfg.S3SecretKey,
+			UsePathStyle: cfg.S3UsePathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.StorageBackend)
+	}
+}
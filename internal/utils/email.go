@@ -0,0 +1,23 @@
+// internal/utils/email.go
+package utils
+
+import (
+	"strings"
+)
+
+// NormalizeEmail trims an email address and lowercases its domain, which is case-insensitive.
+// The local part is case-sensitive by the RFCs but not in practice at virtually any provider,
+// so it is lowercased as well when lowercaseLocal is set.
+// Lookups and uniqueness are case-insensitive either way.
+func NormalizeEmail(email string, lowercaseLocal bool) string {
+	email = strings.TrimSpace(email)
+	if lowercaseLocal {
+		return strings.ToLower(email)
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	return email[:at] + strings.ToLower(email[at:])
+}
@@ -0,0 +1,101 @@
+// internal/utils/etag.go
+package utils
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	appErrors "student-portal/internal/commons/errors"
+)
+
+// ETag formats a resource version as a strong entity tag, e.g. "7".
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// SetETag sets the ETag response header for the given resource version.
+func SetETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", ETag(version))
+}
+
+// IfMatch is a parsed If-Match request header (RFC 9110 §13.1.1).
+type IfMatch struct {
+	Any      bool    // "*": any current representation matches
+	Versions []int64 // The versions of the listed strong entity tags produced by ETag
+}
+
+// Matches reports whether the current version of the resource satisfies the precondition.
+func (m *IfMatch) Matches(version int64) bool {
+	if m.Any {
+		return true
+	}
+	for _, v := range m.Versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseIfMatch reads the If-Match request header, either "*" or a comma-separated list of
+// entity tags. It returns nil when the header is absent and ErrBadRequest when it is
+// malformed. If-Match uses the strong comparison, so weak tags (W/"7") and tags this API
+// never produces are parsed but match no version.
+func ParseIfMatch(r *http.Request) (*IfMatch, error) {
+	header := strings.TrimSpace(strings.Join(r.Header.Values("If-Match"), ","))
+	if header == "" {
+		return nil, nil
+	}
+	if header == "*" {
+		return &IfMatch{Any: true}, nil
+	}
+
+	m := &IfMatch{Versions: make([]int64, 0)}
+	rest := header
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		weak := strings.HasPrefix(rest, "W/")
+		if weak {
+			rest = rest[2:]
+		}
+		if !strings.HasPrefix(rest, `"`) {
+			return nil, appErrors.ErrBadRequest
+		}
+		end := strings.IndexByte(rest[1:], '"')
+		if end < 0 {
+			return nil, appErrors.ErrBadRequest
+		}
+		opaque := rest[1 : end+1]
+		rest = strings.TrimLeft(rest[end+2:], " \t")
+
+		if version, err := strconv.ParseInt(opaque, 10, 64); err == nil && !weak {
+			m.Versions = append(m.Versions, version)
+		}
+
+		if rest == "" {
+			return m, nil
+		}
+		if rest[0] != ',' {
+			return nil, appErrors.ErrBadRequest
+		}
+		// Empty list elements are allowed, e.g. "7", , "8"
+		rest = strings.TrimLeft(rest[1:], " \t,")
+		if rest == "" {
+			return m, nil
+		}
+	}
+}
+
+// RequireIfMatch reads the If-Match header of a request that must be conditional on a
+// specific version. It fails with ErrIfMatchRequired when the header is absent or "*".
+func RequireIfMatch(r *http.Request) (*IfMatch, error) {
+	m, err := ParseIfMatch(r)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil || m.Any {
+		return nil, appErrors.ErrIfMatchRequired
+	}
+	return m, nil
+}
@@ -0,0 +1,89 @@
+// internal/utils/image.go
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif" // Register GIF decoder
+	"image/jpeg"
+	"image/png"
+)
+
+// ErrImageTooLarge is returned when an image has more pixels than allowed.
+var ErrImageTooLarge = errors.New("image: too many pixels")
+
+// ResizeImage decodes an image, scales it down so that neither side exceeds maxDim
+// (preserving the aspect ratio) and re-encodes it. Re-encoding also strips metadata
+// such as EXIF location data. JPEG input stays JPEG; everything else becomes PNG.
+// It returns the encoded bytes and their content type.
+//
+// Images of more than maxPixels pixels are rejected with ErrImageTooLarge before they are
+// decoded, as a small compressed file can otherwise expand to gigabytes in memory.
+func ResizeImage(data []byte, maxDim int, maxPixels int64) ([]byte, string, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > maxPixels {
+		return nil, "", ErrImageTooLarge
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+
+	dst := scaleDown(src, maxDim)
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	}
+
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// scaleDown resizes src with a box filter so that it fits into maxDim x maxDim.
+func scaleDown(src image.Image, maxDim int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= maxDim && h <= maxDim {
+		return src
+	}
+
+	scale := float64(maxDim) / float64(max(w, h))
+	nw := max(1, int(float64(w)*scale+0.5))
+	nh := max(1, int(float64(h)*scale+0.5))
+
+	dst := image.NewRGBA(image.Rect(0, 0, nw, nh))
+	for y := 0; y < nh; y++ {
+		sy0 := bounds.Min.Y + y*h/nh
+		sy1 := max(sy0+1, bounds.Min.Y+(y+1)*h/nh)
+		for x := 0; x < nw; x++ {
+			sx0 := bounds.Min.X + x*w/nw
+			sx1 := max(sx0+1, bounds.Min.X+(x+1)*w/nw)
+
+			// Average all source pixels covered by this destination pixel.
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,353 @@
+// internal/utils/patch.go
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	appErrors "student-portal/internal/commons/errors"
+)
+
+// Content types accepted by PATCH endpoints.
+const (
+	ContentTypeMergePatch = "application/merge-patch+json" // RFC 7396
+	ContentTypeJSONPatch  = "application/json-patch+json"  // RFC 6902
+)
+
+// ErrPatchTestFailed is returned when a JSON Patch "test" operation does not match.
+var ErrPatchTestFailed = errors.New("patch test operation failed")
+
+// PatchOperation is a single RFC 6902 JSON Patch operation.
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Patch is a parsed JSON Merge Patch or JSON Patch document.
+type Patch struct {
+	ContentType string
+	merge       interface{}
+	ops         []PatchOperation
+}
+
+// ParsePatch reads a PATCH request body according to its Content-Type.
+// It returns ErrUnsupportedMedia for any other content type.
+func ParsePatch(r *http.Request) (*Patch, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, appErrors.ErrUnsupportedMedia
+	}
+
+	patch := &Patch{ContentType: mediaType}
+	switch mediaType {
+	case ContentTypeMergePatch:
+		if err := json.NewDecoder(r.Body).Decode(&patch.merge); err != nil {
+			return nil, appErrors.ErrBadRequest
+		}
+	case ContentTypeJSONPatch:
+		if err := json.NewDecoder(r.Body).Decode(&patch.ops); err != nil {
+			return nil, appErrors.ErrBadRequest
+		}
+	default:
+		return nil, appErrors.ErrUnsupportedMedia
+	}
+	return patch, nil
+}
+
+// Apply applies the patch to a JSON object and returns the patched object.
+// The input document is not modified.
+func (p *Patch) Apply(doc map[string]interface{}) (map[string]interface{}, error) {
+	var result interface{}
+	var err error
+
+	switch p.ContentType {
+	case ContentTypeMergePatch:
+		result = MergePatch(deepCopy(doc), p.merge)
+	case ContentTypeJSONPatch:
+		result, err = ApplyJSONPatch(deepCopy(doc), p.ops)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported patch type %q", p.ContentType)
+	}
+
+	obj, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("patch result must be a JSON object")
+	}
+	return obj, nil
+}
+
+// MergePatch applies an RFC 7396 JSON Merge Patch to target.
+// A null member in the patch removes the corresponding member from the target.
+func MergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = MergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
+
+// ApplyJSONPatch applies a sequence of RFC 6902 operations to doc.
+// Operations are applied in order; the first failure aborts the whole patch.
+func ApplyJSONPatch(doc interface{}, ops []PatchOperation) (interface{}, error) {
+	var err error
+	for i, op := range ops {
+		path, perr := parsePointer(op.Path)
+		if perr != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, perr)
+		}
+
+		switch op.Op {
+		case "add":
+			doc, err = addAt(doc, path, deepCopy(op.Value), false)
+		case "replace":
+			doc, err = addAt(doc, path, deepCopy(op.Value), true)
+		case "remove":
+			doc, _, err = removeAt(doc, path)
+		case "move", "copy":
+			from, ferr := parsePointer(op.From)
+			if ferr != nil {
+				return nil, fmt.Errorf("operation %d: %w", i, ferr)
+			}
+			var value interface{}
+			if op.Op == "move" {
+				if isPrefix(from, path) && len(from) < len(path) {
+					return nil, fmt.Errorf("operation %d: cannot move a value into itself", i)
+				}
+				doc, value, err = removeAt(doc, from)
+			} else {
+				value, err = getAt(doc, from)
+				value = deepCopy(value)
+			}
+			if err == nil {
+				doc, err = addAt(doc, path, value, false)
+			}
+		case "test":
+			var value interface{}
+			value, err = getAt(doc, path)
+			if err == nil && !reflect.DeepEqual(value, op.Value) {
+				return nil, ErrPatchTestFailed
+			}
+		default:
+			return nil, fmt.Errorf("operation %d: unknown op %q", i, op.Op)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayIndex parses an array reference token; limit is the largest allowed index + 1.
+func arrayIndex(token string, limit int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 || idx >= limit {
+		return 0, fmt.Errorf("array index %q out of range", token)
+	}
+	return idx, nil
+}
+
+func getAt(node interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("path member %q not found", token)
+			}
+			node = child
+		case []interface{}:
+			idx, err := arrayIndex(token, len(n))
+			if err != nil {
+				return nil, err
+			}
+			node = n[idx]
+		default:
+			return nil, fmt.Errorf("cannot traverse into a scalar at %q", token)
+		}
+	}
+	return node, nil
+}
+
+// addAt implements "add" (and "replace" when mustExist is true) and returns the updated node.
+func addAt(node interface{}, path []string, value interface{}, mustExist bool) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	token, rest := path[0], path[1:]
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if len(rest) == 0 {
+			if _, ok := n[token]; mustExist && !ok {
+				return nil, fmt.Errorf("path member %q not found", token)
+			}
+			n[token] = value
+			return n, nil
+		}
+		child, ok := n[token]
+		if !ok {
+			return nil, fmt.Errorf("path member %q not found", token)
+		}
+		updated, err := addAt(child, rest, value, mustExist)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = updated
+		return n, nil
+
+	case []interface{}:
+		if len(rest) == 0 {
+			if mustExist {
+				idx, err := arrayIndex(token, len(n))
+				if err != nil {
+					return nil, err
+				}
+				n[idx] = value
+				return n, nil
+			}
+			idx := len(n)
+			if token != "-" {
+				var err error
+				if idx, err = arrayIndex(token, len(n)+1); err != nil {
+					return nil, err
+				}
+			}
+			result := make([]interface{}, 0, len(n)+1)
+			result = append(result, n[:idx]...)
+			result = append(result, value)
+			return append(result, n[idx:]...), nil
+		}
+		idx, err := arrayIndex(token, len(n))
+		if err != nil {
+			return nil, err
+		}
+		updated, err := addAt(n[idx], rest, value, mustExist)
+		if err != nil {
+			return nil, err
+		}
+		n[idx] = updated
+		return n, nil
+	}
+	return nil, fmt.Errorf("cannot traverse into a scalar at %q", token)
+}
+
+// removeAt implements "remove" and returns the updated node and the removed value.
+func removeAt(node interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("cannot remove the document root")
+	}
+	token, rest := path[0], path[1:]
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("path member %q not found", token)
+		}
+		if len(rest) == 0 {
+			delete(n, token)
+			return n, child, nil
+		}
+		updated, removed, err := removeAt(child, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		n[token] = updated
+		return n, removed, nil
+
+	case []interface{}:
+		idx, err := arrayIndex(token, len(n))
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rest) == 0 {
+			removed := n[idx]
+			result := make([]interface{}, 0, len(n)-1)
+			result = append(result, n[:idx]...)
+			return append(result, n[idx+1:]...), removed, nil
+		}
+		updated, removed, err := removeAt(n[idx], rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		n[idx] = updated
+		return n, removed, nil
+	}
+	return nil, nil, fmt.Errorf("cannot traverse into a scalar at %q", token)
+}
+
+// deepCopy copies decoded JSON values (maps, slices and scalars).
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			out[key] = deepCopy(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, child := range v {
+			out[i] = deepCopy(child)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -11,6 +11,7 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
 	Message string      `json:"message,omitempty"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 func WriteJSON(w http.ResponseWriter, statusCode int, data interface{}) {
@@ -40,6 +41,7 @@ func SendError(w http.ResponseWriter, err error) {
 			Success: false,
 			Error:   http.StatusText(appErr.Code),
 			Message: appErr.Message,
+			Details: appErr.Details,
 		})
 	} else {
 		WriteError(w, http.StatusInternalServerError, "An unexpected error occurred")
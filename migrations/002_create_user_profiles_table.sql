@@ -0,0 +1,29 @@
+-- migrations/002_create_user_profiles_table.sql
+
+-- Create the user_profiles table (one row per user, created lazily on first update)
+CREATE TABLE IF NOT EXISTS user_profiles (
+    user_id INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    phone VARCHAR(32),
+    date_of_birth DATE,
+    address TEXT,
+    student_number VARCHAR(64) UNIQUE,
+    program VARCHAR(255),
+    enrollment_year INTEGER CHECK (enrollment_year BETWEEN 1900 AND 2200),
+    custom_fields JSONB NOT NULL DEFAULT '{}'::jsonb, -- Values for admin-defined fields
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_profiles_program ON user_profiles (program);
+
+-- Create the profile_field_definitions table for admin-defined custom fields
+CREATE TABLE IF NOT EXISTS profile_field_definitions (
+    id SERIAL PRIMARY KEY,
+    key VARCHAR(64) UNIQUE NOT NULL,          -- Key used inside user_profiles.custom_fields
+    label VARCHAR(255) NOT NULL,
+    field_type VARCHAR(20) NOT NULL CHECK (field_type IN ('string', 'number', 'boolean', 'date', 'select')),
+    options JSONB NOT NULL DEFAULT '[]'::jsonb, -- Allowed values for 'select' fields
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    student_editable BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
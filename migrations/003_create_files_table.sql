@@ -0,0 +1,19 @@
+-- migrations/003_create_files_table.sql
+
+-- Create the files table holding metadata for blobs kept in the configured storage backend
+CREATE TABLE IF NOT EXISTS files (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    storage_key VARCHAR(512) UNIQUE NOT NULL, -- Object key in the storage backend
+    filename VARCHAR(255) NOT NULL,           -- Original filename supplied by the uploader
+    content_type VARCHAR(255) NOT NULL,       -- Sniffed from the content, not trusted from the client
+    size_bytes BIGINT NOT NULL,
+    checksum CHAR(64) NOT NULL,               -- Hex-encoded SHA-256 of the stored content
+    purpose VARCHAR(50) NOT NULL,             -- 'avatar', 'document', ...
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_files_owner_id ON files (owner_id);
+
+-- Link avatars to user profiles
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS avatar_file_id INTEGER REFERENCES files (id) ON DELETE SET NULL;
@@ -0,0 +1,5 @@
+-- migrations/004_add_users_version.sql
+
+-- Add a version column for optimistic concurrency control.
+-- Every update increments it; clients send it back in If-Match (as the ETag) to detect lost updates.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
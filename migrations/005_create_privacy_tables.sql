@@ -0,0 +1,27 @@
+-- migrations/005_create_privacy_tables.sql
+
+-- Self-service personal data export jobs (GDPR/FERPA right of access)
+CREATE TABLE IF NOT EXISTS data_export_jobs (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    requested_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    file_id INTEGER REFERENCES files (id) ON DELETE SET NULL, -- The generated ZIP archive
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_export_jobs_user_id ON data_export_jobs (user_id);
+
+-- Audit log of erasures (right to be forgotten). The users row itself is kept, anonymized,
+-- so that academic records that must be retained by law keep their references.
+CREATE TABLE IF NOT EXISTS user_erasures (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id),
+    performed_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    reason TEXT NOT NULL,
+    erased_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP WITH TIME ZONE;
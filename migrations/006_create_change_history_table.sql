@@ -0,0 +1,17 @@
+-- migrations/006_create_change_history_table.sql
+
+-- Generic change history for any entity: who changed what, when, and through which request
+CREATE TABLE IF NOT EXISTS change_history (
+    id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(50) NOT NULL,        -- 'user', ...
+    entity_id BIGINT NOT NULL,
+    action VARCHAR(50) NOT NULL,             -- 'create', 'update', 'delete', ...
+    actor_id INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    actor_role VARCHAR(50),
+    request_id VARCHAR(100),
+    changes JSONB NOT NULL DEFAULT '{}'::jsonb, -- {"field": {"old": ..., "new": ...}}
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_change_history_entity ON change_history (entity_type, entity_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_change_history_actor_id ON change_history (actor_id);
@@ -0,0 +1,18 @@
+-- migrations/007_create_user_merges_table.sql
+
+-- Audit log of duplicate account merges. The source users row is deleted by the merge,
+-- so its identifying details are copied here.
+CREATE TABLE IF NOT EXISTS user_merges (
+    id SERIAL PRIMARY KEY,
+    source_user_id INTEGER NOT NULL,                -- No foreign key: the row no longer exists
+    source_name VARCHAR(255) NOT NULL,
+    source_email VARCHAR(255),
+    target_user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    performed_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    reason TEXT NOT NULL,
+    records JSONB NOT NULL DEFAULT '{}'::jsonb,     -- Number of moved records per table and column
+    merged_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_merges_target_user_id ON user_merges (target_user_id);
+CREATE INDEX IF NOT EXISTS idx_user_merges_source_user_id ON user_merges (source_user_id);
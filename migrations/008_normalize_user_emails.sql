@@ -0,0 +1,57 @@
+-- migrations/008_normalize_user_emails.sql
+
+-- Email addresses are matched case-insensitively. Existing accounts whose addresses only
+-- differ in case cannot be merged automatically, so they are reported here instead.
+CREATE TABLE IF NOT EXISTS email_collisions (
+    id SERIAL PRIMARY KEY,
+    normalized_email VARCHAR(255) UNIQUE NOT NULL,
+    user_ids INTEGER[] NOT NULL,
+    emails TEXT[] NOT NULL,
+    detected_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 1. Record the collisions (re-running the migration refreshes the report)
+DELETE FROM email_collisions;
+INSERT INTO email_collisions (normalized_email, user_ids, emails)
+SELECT lower(trim(email)), array_agg(id ORDER BY id), array_agg(email ORDER BY id)
+FROM users
+GROUP BY lower(trim(email))
+HAVING COUNT(*) > 1;
+
+-- 2. Store addresses the way the application normalizes them (utils.NormalizeEmail); safe for
+--    every account without a collision. With the default EMAIL_LOWERCASE_LOCAL_PART=true the
+--    whole address is trimmed and lowercased. Deployments that set it to false run this
+--    migration after "SET app.email_lowercase_local_part = 'false';" so that only the domain
+--    is lowercased.
+UPDATE users u
+SET email = n.email, updated_at = NOW(), version = u.version + 1
+FROM (
+    SELECT id,
+           CASE WHEN COALESCE(NULLIF(current_setting('app.email_lowercase_local_part', true), ''), 'true') = 'false'
+                THEN trim(substring(email FROM '^(.*)@')) || '@' || lower(trim(substring(email FROM '@([^@]*)$')))
+                ELSE lower(trim(email))
+           END AS email
+    FROM users
+    WHERE email LIKE '%@%'
+) n
+WHERE n.id = u.id
+  AND u.email <> n.email
+  AND lower(trim(u.email)) NOT IN (SELECT normalized_email FROM email_collisions);
+
+-- 3. Enforce case-insensitive uniqueness once no collisions are left. Otherwise warn and keep a
+--    plain index for lookups; resolve the reported accounts (GET /api/users/duplicates lists them,
+--    POST /api/users/merge merges them) and run this migration again.
+DO $$
+DECLARE
+    collisions INTEGER;
+BEGIN
+    SELECT COUNT(*) INTO collisions FROM email_collisions;
+    IF collisions = 0 THEN
+        DROP INDEX IF EXISTS idx_users_email_lower_lookup;
+        CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (lower(email));
+    ELSE
+        RAISE WARNING '% email address(es) are used by several accounts that differ only in case; see the email_collisions table. The case-insensitive unique index was not created.', collisions;
+        CREATE INDEX IF NOT EXISTS idx_users_email_lower_lookup ON users (lower(email));
+    END IF;
+END
+$$;
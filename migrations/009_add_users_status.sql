@@ -0,0 +1,23 @@
+-- migrations/009_add_users_status.sql
+
+-- Account lifecycle status; only 'active' accounts can log in
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('pending', 'active', 'suspended', 'graduated', 'archived'));
+
+CREATE INDEX IF NOT EXISTS idx_users_status ON users (status);
+
+-- Erased accounts are closed
+UPDATE users SET status = 'archived' WHERE erased_at IS NOT NULL AND status <> 'archived';
+
+-- Audit log of status transitions and the reasons given for them
+CREATE TABLE IF NOT EXISTS user_status_changes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    changed_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_status_changes_user_id ON user_status_changes (user_id);
@@ -0,0 +1,38 @@
+-- migrations/010_create_guardian_tables.sql
+
+-- Links between students and their parents/guardians. The consent flags record what the
+-- student (or an admin on their behalf) agreed to share with the guardian.
+CREATE TABLE IF NOT EXISTS student_guardians (
+    id SERIAL PRIMARY KEY,
+    student_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    guardian_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    relationship VARCHAR(30) NOT NULL,           -- 'parent', 'legal_guardian', ...
+    consent_profile BOOLEAN NOT NULL DEFAULT TRUE,  -- Guardian may view the student's profile
+    consent_academic BOOLEAN NOT NULL DEFAULT FALSE, -- Guardian may view academic records
+    created_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (student_id, guardian_id),
+    CHECK (student_id <> guardian_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_student_guardians_guardian_id ON student_guardians (guardian_id);
+
+-- Invitations sent to parents by email; accepting one creates (or reuses) a guardian account
+-- and links it to the student. Only a hash of the token is stored.
+CREATE TABLE IF NOT EXISTS guardian_invitations (
+    id SERIAL PRIMARY KEY,
+    student_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    relationship VARCHAR(30) NOT NULL,
+    consent_profile BOOLEAN NOT NULL DEFAULT TRUE,
+    consent_academic BOOLEAN NOT NULL DEFAULT FALSE,
+    token_hash CHAR(64) UNIQUE NOT NULL,         -- Hex-encoded SHA-256 of the invitation token
+    invited_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    accepted_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_guardian_invitations_student_id ON guardian_invitations (student_id);
@@ -0,0 +1,23 @@
+-- migrations/012_create_enrollments_table.sql
+
+-- Seat limits and drop deadlines; NULL means unlimited seats / no deadline
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS capacity INTEGER CHECK (capacity > 0);
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS drop_deadline TIMESTAMP WITH TIME ZONE;
+
+-- One row per student and course; re-enrolling after a drop reuses the row
+CREATE TABLE IF NOT EXISTS enrollments (
+    id SERIAL PRIMARY KEY,
+    course_id INTEGER NOT NULL REFERENCES courses (id), -- Courses with enrollments cannot be deleted
+    student_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('enrolled', 'waitlisted', 'dropped')),
+    enrolled_at TIMESTAMP WITH TIME ZONE,
+    waitlisted_at TIMESTAMP WITH TIME ZONE,            -- Orders the waitlist, first come first served
+    dropped_at TIMESTAMP WITH TIME ZONE,
+    dropped_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (course_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_enrollments_student_id ON enrollments (student_id);
+CREATE INDEX IF NOT EXISTS idx_enrollments_waitlist ON enrollments (course_id, waitlisted_at, id) WHERE status = 'waitlisted';
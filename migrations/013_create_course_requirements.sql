@@ -0,0 +1,25 @@
+-- migrations/013_create_course_requirements.sql
+
+-- Prerequisites are an expression tree, e.g.
+--   {"all": [{"course_id": 1, "min_grade": "C"}, {"any": [{"course_id": 2}, {"course_id": 3}]}]}
+-- Co-requisites must be completed or taken at the same time.
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS prerequisites JSONB;
+ALTER TABLE courses ADD COLUMN IF NOT EXISTS corequisite_ids INTEGER[] NOT NULL DEFAULT '{}';
+
+-- Courses a student has completed, with the final letter grade. Retakes add another row;
+-- requirement checks use the best grade.
+CREATE TABLE IF NOT EXISTS course_completions (
+    id SERIAL PRIMARY KEY,
+    student_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    course_id INTEGER NOT NULL REFERENCES courses (id),
+    grade VARCHAR(2) NOT NULL CHECK (grade IN ('A+', 'A', 'A-', 'B+', 'B', 'B-', 'C+', 'C', 'C-', 'D+', 'D', 'D-', 'F')),
+    completed_at DATE NOT NULL DEFAULT CURRENT_DATE,
+    recorded_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_completions_student_id ON course_completions (student_id);
+
+-- Admins can enroll students who do not meet the requirements, with a justification
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS override_by INTEGER REFERENCES users (id) ON DELETE SET NULL;
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS override_justification TEXT;
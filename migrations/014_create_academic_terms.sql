@@ -0,0 +1,103 @@
+-- migrations/014_create_academic_terms.sql
+
+-- Academic terms (semesters). Dates are calendar days; deadlines are exact instants.
+CREATE TABLE IF NOT EXISTS terms (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) UNIQUE NOT NULL,            -- e.g. '2026-FALL'
+    name VARCHAR(100) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    add_deadline TIMESTAMP WITH TIME ZONE,       -- Last moment students can enroll themselves
+    drop_deadline TIMESTAMP WITH TIME ZONE,      -- Last moment students can give up a seat themselves
+    exam_start DATE,
+    exam_end DATE,
+    created_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_date <= end_date),
+    CHECK ((exam_start IS NULL) = (exam_end IS NULL) AND exam_start <= exam_end)
+);
+
+CREATE INDEX IF NOT EXISTS idx_terms_dates ON terms (start_date, end_date);
+
+-- Terms must not overlap, so that every day belongs to at most one term. The LEGACY term
+-- created below only holds enrollments from before terms existed and is exempt.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'terms_no_overlap') THEN
+        ALTER TABLE terms ADD CONSTRAINT terms_no_overlap
+            EXCLUDE USING gist (daterange(start_date, end_date, '[]') WITH &&) WHERE (code <> 'LEGACY');
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS term_holidays (
+    id SERIAL PRIMARY KEY,
+    term_id INTEGER NOT NULL REFERENCES terms (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    CHECK (start_date <= end_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_term_holidays_term_id ON term_holidays (term_id);
+
+-- A course offered in a term. Seats are counted per offering.
+CREATE TABLE IF NOT EXISTS course_offerings (
+    id SERIAL PRIMARY KEY,
+    course_id INTEGER NOT NULL REFERENCES courses (id),
+    term_id INTEGER NOT NULL REFERENCES terms (id),
+    capacity INTEGER CHECK (capacity > 0),       -- NULL means unlimited
+    drop_deadline TIMESTAMP WITH TIME ZONE,      -- Overrides the term's drop deadline
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (course_id, term_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_offerings_term_id ON course_offerings (term_id);
+
+-- Enrollments belong to an offering. Enrollments made before terms existed are moved to a
+-- 'LEGACY' term spanning them, with an offering per course that keeps the course's settings.
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS term_id INTEGER REFERENCES terms (id);
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM enrollments WHERE term_id IS NULL) THEN
+        INSERT INTO terms (code, name, start_date, end_date)
+        SELECT 'LEGACY', 'Before academic terms', MIN(created_at)::date, MAX(COALESCE(updated_at, created_at))::date
+        FROM enrollments
+        ON CONFLICT (code) DO NOTHING;
+
+        INSERT INTO course_offerings (course_id, term_id, capacity, drop_deadline)
+        SELECT c.id, t.id, c.capacity, c.drop_deadline
+        FROM courses c, terms t
+        WHERE t.code = 'LEGACY' AND c.id IN (SELECT course_id FROM enrollments WHERE term_id IS NULL)
+        ON CONFLICT (course_id, term_id) DO NOTHING;
+
+        UPDATE enrollments SET term_id = (SELECT id FROM terms WHERE code = 'LEGACY') WHERE term_id IS NULL;
+    END IF;
+END $$;
+
+ALTER TABLE enrollments ALTER COLUMN term_id SET NOT NULL;
+ALTER TABLE enrollments DROP CONSTRAINT IF EXISTS enrollments_course_id_student_id_key;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'enrollments_course_id_term_id_student_id_key') THEN
+        ALTER TABLE enrollments ADD CONSTRAINT enrollments_course_id_term_id_student_id_key UNIQUE (course_id, term_id, student_id);
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'enrollments_offering_fkey') THEN
+        ALTER TABLE enrollments ADD CONSTRAINT enrollments_offering_fkey
+            FOREIGN KEY (course_id, term_id) REFERENCES course_offerings (course_id, term_id);
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS idx_enrollments_waitlist;
+CREATE INDEX IF NOT EXISTS idx_enrollments_waitlist ON enrollments (course_id, term_id, waitlisted_at, id) WHERE status = 'waitlisted';
+
+-- Completions record the term they were earned in; transfer credit has none
+ALTER TABLE course_completions ADD COLUMN IF NOT EXISTS term_id INTEGER REFERENCES terms (id);
+
+-- Drop deadlines now come from the term or the offering; the course capacity is the
+-- default for new offerings
+ALTER TABLE courses DROP COLUMN IF EXISTS drop_deadline;
@@ -0,0 +1,36 @@
+-- migrations/015_create_course_sections.sql
+
+-- Sections of a course offering, each with its own instructor and weekly meetings.
+-- Seats are still counted per offering; the section only decides when and where a student meets.
+CREATE TABLE IF NOT EXISTS course_sections (
+    id SERIAL PRIMARY KEY,
+    course_id INTEGER NOT NULL,
+    term_id INTEGER NOT NULL,
+    code VARCHAR(10) NOT NULL,                   -- e.g. '001', 'L02'
+    instructor_id INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (course_id, term_id, code),
+    FOREIGN KEY (course_id, term_id) REFERENCES course_offerings (course_id, term_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_sections_term_id ON course_sections (term_id);
+CREATE INDEX IF NOT EXISTS idx_course_sections_instructor_id ON course_sections (instructor_id);
+
+-- Weekly meetings of a section. Days follow ISO 8601: 1 is Monday, 7 is Sunday.
+CREATE TABLE IF NOT EXISTS section_meetings (
+    id SERIAL PRIMARY KEY,
+    section_id INTEGER NOT NULL REFERENCES course_sections (id) ON DELETE CASCADE,
+    day_of_week SMALLINT NOT NULL CHECK (day_of_week BETWEEN 1 AND 7),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    room VARCHAR(50),
+    CHECK (start_time < end_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_section_meetings_section_id ON section_meetings (section_id);
+
+-- The section a student attends; sections with enrollments cannot be deleted
+ALTER TABLE enrollments ADD COLUMN IF NOT EXISTS section_id INTEGER REFERENCES course_sections (id);
+
+CREATE INDEX IF NOT EXISTS idx_enrollments_section_id ON enrollments (section_id);
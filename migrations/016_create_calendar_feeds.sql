@@ -0,0 +1,12 @@
+-- migrations/016_create_calendar_feeds.sql
+
+-- Secret iCalendar subscription feeds of personal timetables and deadlines, at most one per
+-- user. Calendar apps cannot send credentials, so the token in the feed URL is the only
+-- authentication; only a hash of it is stored and regenerating it breaks the old URL.
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    user_id INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    token_hash CHAR(64) UNIQUE NOT NULL,         -- Hex-encoded SHA-256 of the feed token
+    created_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_accessed_at TIMESTAMP WITH TIME ZONE    -- Last time a calendar app fetched the feed
+);
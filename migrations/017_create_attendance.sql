@@ -0,0 +1,30 @@
+-- migrations/017_create_attendance.sql
+
+-- Individual class sessions, generated from the weekly meetings of a section over its term
+-- (holidays excluded). Sessions keep their own times so that later changes to the meetings
+-- do not rewrite attendance that was already taken.
+CREATE TABLE IF NOT EXISTS class_sessions (
+    id SERIAL PRIMARY KEY,
+    section_id INTEGER NOT NULL REFERENCES course_sections (id) ON DELETE CASCADE,
+    meeting_id INTEGER REFERENCES section_meetings (id) ON DELETE SET NULL,
+    session_date DATE NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    room VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (section_id, session_date, start_time),
+    CHECK (start_time < end_time)
+);
+
+CREATE TABLE IF NOT EXISTS attendance_records (
+    id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL REFERENCES class_sessions (id) ON DELETE CASCADE,
+    student_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('present', 'absent', 'late', 'excused')),
+    note VARCHAR(255),
+    marked_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    marked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (session_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_records_student_id ON attendance_records (student_id);
@@ -0,0 +1,34 @@
+-- migrations/018_create_check_in.sql
+
+-- Self check-in windows for class sessions. While a window is open, its instructor shows a
+-- rotating code (derived from the window and the server key, never stored) that students
+-- submit to be marked present. The optional restrictions limit where they can do so from.
+CREATE TABLE IF NOT EXISTS check_in_windows (
+    id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL REFERENCES class_sessions (id) ON DELETE CASCADE,
+    opened_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    opened_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    closes_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    allowed_networks TEXT[] NOT NULL DEFAULT '{}', -- CIDR ranges; empty allows any address
+    latitude DOUBLE PRECISION,                     -- Geofence centre and radius, all or none
+    longitude DOUBLE PRECISION,
+    radius_meters INTEGER,
+    CHECK ((latitude IS NULL) = (longitude IS NULL) AND (latitude IS NULL) = (radius_meters IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_check_in_windows_session_id ON check_in_windows (session_id);
+
+-- Every check-in attempt, kept for auditing and to limit guessing. A student can only
+-- succeed once per window, so a code cannot be replayed.
+CREATE TABLE IF NOT EXISTS check_in_attempts (
+    id SERIAL PRIMARY KEY,
+    window_id INTEGER NOT NULL REFERENCES check_in_windows (id) ON DELETE CASCADE,
+    student_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    succeeded BOOLEAN NOT NULL,
+    reason VARCHAR(50),                            -- Why a failed attempt was rejected
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_check_in_attempts_success ON check_in_attempts (window_id, student_id) WHERE succeeded;
+CREATE INDEX IF NOT EXISTS idx_check_in_attempts_student_id ON check_in_attempts (student_id);
@@ -0,0 +1,48 @@
+-- migrations/019_create_assignments.sql
+
+-- Coursework set for a section. The late policy decides what happens to work handed in after
+-- due_at: 'reject' refuses it, 'accept' takes it flagged as late, and 'penalty' also deducts
+-- late_penalty_percent of the points for every started day. Late work is never accepted
+-- after late_until, when set.
+CREATE TABLE IF NOT EXISTS assignments (
+    id SERIAL PRIMARY KEY,
+    section_id INTEGER NOT NULL REFERENCES course_sections (id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    instructions TEXT,
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    late_policy VARCHAR(10) NOT NULL DEFAULT 'accept' CHECK (late_policy IN ('reject', 'accept', 'penalty')),
+    late_penalty_percent NUMERIC(5, 2) NOT NULL DEFAULT 0 CHECK (late_penalty_percent BETWEEN 0 AND 100),
+    late_until TIMESTAMP WITH TIME ZONE,
+    max_points NUMERIC(7, 2) NOT NULL CHECK (max_points > 0),
+    created_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (late_until IS NULL OR late_until >= due_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignments_section_id ON assignments (section_id);
+
+-- Every version a student handed in. Resubmitting adds a version instead of replacing the
+-- previous one; the latest version is the one that counts. submitted_at is always set by the
+-- database, and late records whether it was after the due date at that moment.
+-- Submissions are academic records, so assignments that have any cannot be deleted.
+CREATE TABLE IF NOT EXISTS assignment_submissions (
+    id SERIAL PRIMARY KEY,
+    assignment_id INTEGER NOT NULL REFERENCES assignments (id) ON DELETE RESTRICT,
+    student_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    version INTEGER NOT NULL CHECK (version > 0),
+    comment TEXT,
+    submitted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    late BOOLEAN NOT NULL,
+    UNIQUE (assignment_id, student_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_submissions_student_id ON assignment_submissions (student_id);
+
+-- Files attached to a submission version, in upload order
+CREATE TABLE IF NOT EXISTS submission_files (
+    submission_id INTEGER NOT NULL REFERENCES assignment_submissions (id) ON DELETE CASCADE,
+    file_id INTEGER NOT NULL REFERENCES files (id) ON DELETE RESTRICT,
+    position SMALLINT NOT NULL,
+    PRIMARY KEY (submission_id, file_id)
+);
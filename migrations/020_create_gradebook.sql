@@ -0,0 +1,67 @@
+-- migrations/020_create_gradebook.sql
+
+-- Letter-grade scale of a course: a percentage earns the best letter whose min_percent it
+-- reaches. Courses without rows use the default scale.
+CREATE TABLE IF NOT EXISTS grade_scales (
+    course_id INTEGER NOT NULL REFERENCES courses (id) ON DELETE CASCADE,
+    letter VARCHAR(2) NOT NULL,
+    min_percent NUMERIC(5, 2) NOT NULL CHECK (min_percent >= 0),
+    PRIMARY KEY (course_id, letter),
+    UNIQUE (course_id, min_percent)
+);
+
+-- Weighted grade categories of a section (e.g. homework 40, exams 60). Weights add up to 100.
+-- The lowest drop_lowest assignments of a category, by percentage, do not count.
+CREATE TABLE IF NOT EXISTS grade_categories (
+    id SERIAL PRIMARY KEY,
+    section_id INTEGER NOT NULL REFERENCES course_sections (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    weight NUMERIC(5, 2) NOT NULL CHECK (weight > 0 AND weight <= 100),
+    drop_lowest SMALLINT NOT NULL DEFAULT 0 CHECK (drop_lowest >= 0),
+    position SMALLINT NOT NULL,
+    UNIQUE (section_id, name)
+);
+
+-- Extra-credit assignments add their points to the category without adding to what can be earned
+ALTER TABLE assignments ADD COLUMN IF NOT EXISTS category_id INTEGER REFERENCES grade_categories (id) ON DELETE SET NULL;
+ALTER TABLE assignments ADD COLUMN IF NOT EXISTS extra_credit BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- A student's grade for an assignment. late_penalty is the number of points deducted under the
+-- assignment's late policy for the submission that was graded. Students only see released grades.
+CREATE TABLE IF NOT EXISTS assignment_grades (
+    id SERIAL PRIMARY KEY,
+    assignment_id INTEGER NOT NULL REFERENCES assignments (id) ON DELETE RESTRICT,
+    student_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    submission_id INTEGER REFERENCES assignment_submissions (id) ON DELETE SET NULL,
+    points NUMERIC(7, 2) CHECK (points >= 0),                   -- NULL when excused
+    late_penalty NUMERIC(7, 2) NOT NULL DEFAULT 0 CHECK (late_penalty >= 0),
+    excused BOOLEAN NOT NULL DEFAULT FALSE,
+    feedback TEXT,
+    graded_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    graded_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    released_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (assignment_id, student_id),
+    CHECK (excused = (points IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_grades_student_id ON assignment_grades (student_id);
+
+-- Course grades of the students of a section, recomputed whenever anything they depend on
+-- changes. The running grade only counts graded work; the final grade counts missing work as
+-- zero. Posting the final grade records it as a course completion.
+CREATE TABLE IF NOT EXISTS section_grades (
+    section_id INTEGER NOT NULL REFERENCES course_sections (id) ON DELETE CASCADE,
+    student_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    running_percent NUMERIC(6, 2),
+    running_letter VARCHAR(2),
+    final_percent NUMERIC(6, 2),
+    final_letter VARCHAR(2),
+    computed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    posted_letter VARCHAR(2),                                   -- The final grade as last posted
+    posted_at TIMESTAMP WITH TIME ZONE,
+    posted_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    completion_id INTEGER REFERENCES course_completions (id) ON DELETE SET NULL,
+    PRIMARY KEY (section_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_section_grades_student_id ON section_grades (student_id);
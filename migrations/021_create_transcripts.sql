@@ -0,0 +1,46 @@
+-- migrations/021_create_transcripts.sql
+
+-- Grade-point scales map letter grades to the points a GPA averages. Exactly one scale is the
+-- default used when a transcript does not name one.
+CREATE TABLE IF NOT EXISTS grade_point_scales (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_grade_point_scales_default ON grade_point_scales (is_default) WHERE is_default;
+
+CREATE TABLE IF NOT EXISTS grade_point_values (
+    scale_id INTEGER NOT NULL REFERENCES grade_point_scales (id) ON DELETE CASCADE,
+    letter VARCHAR(2) NOT NULL CHECK (letter IN ('A+', 'A', 'A-', 'B+', 'B', 'B-', 'C+', 'C', 'C-', 'D+', 'D', 'D-', 'F')),
+    points NUMERIC(4, 2) NOT NULL CHECK (points >= 0),
+    PRIMARY KEY (scale_id, letter)
+);
+
+INSERT INTO grade_point_scales (name, is_default) VALUES ('4.0', TRUE) ON CONFLICT (name) DO NOTHING;
+INSERT INTO grade_point_values (scale_id, letter, points)
+SELECT s.id, v.letter, v.points
+FROM grade_point_scales s
+CROSS JOIN (VALUES ('A+', 4.0), ('A', 4.0), ('A-', 3.7), ('B+', 3.3), ('B', 3.0), ('B-', 2.7), ('C+', 2.3),
+                   ('C', 2.0), ('C-', 1.7), ('D+', 1.3), ('D', 1.0), ('D-', 0.7), ('F', 0.0)) AS v (letter, points)
+WHERE s.name = '4.0'
+ON CONFLICT DO NOTHING;
+
+-- Official transcripts issued as PDF. The verification code printed on the document lets
+-- anyone confirm it was issued here; the stored file's checksum shows it was not altered.
+-- The GPA and scale are kept as printed, since grades and scales can change later.
+CREATE TABLE IF NOT EXISTS transcripts (
+    id SERIAL PRIMARY KEY,
+    student_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    verification_code VARCHAR(32) NOT NULL UNIQUE,
+    scale_name VARCHAR(100) NOT NULL,
+    cumulative_gpa NUMERIC(4, 2),                               -- NULL without graded credits
+    earned_credits NUMERIC(7, 2) NOT NULL DEFAULT 0,
+    file_id INTEGER NOT NULL REFERENCES files (id) ON DELETE RESTRICT,
+    issued_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    issued_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_transcripts_student_id ON transcripts (student_id);
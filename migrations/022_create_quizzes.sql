@@ -0,0 +1,109 @@
+-- migrations/022_create_quizzes.sql
+
+-- Reusable questions, grouped into banks. A bank belongs to the teacher who created it; the
+-- course only files it.
+CREATE TABLE IF NOT EXISTS question_banks (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    course_id INTEGER REFERENCES courses (id) ON DELETE SET NULL,
+    owner_id INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_question_banks_owner_id ON question_banks (owner_id);
+
+-- options lists the choices of multiple-choice and multi-select questions as
+-- [{"id": 1, "text": "..."}]; correct_options holds the IDs of the right ones. True/false
+-- questions use correct_bool, numeric ones numeric_answer within tolerance. Short answers
+-- have no key and are graded by hand.
+CREATE TABLE IF NOT EXISTS questions (
+    id SERIAL PRIMARY KEY,
+    bank_id INTEGER NOT NULL REFERENCES question_banks (id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('multiple_choice', 'multi_select', 'true_false', 'numeric', 'short_answer')),
+    prompt TEXT NOT NULL,
+    points NUMERIC(7, 2) NOT NULL CHECK (points > 0),
+    options JSONB NOT NULL DEFAULT '[]'::jsonb,
+    correct_options INTEGER[] NOT NULL DEFAULT '{}',
+    correct_bool BOOLEAN,
+    numeric_answer NUMERIC(14, 4),
+    tolerance NUMERIC(14, 4) NOT NULL DEFAULT 0 CHECK (tolerance >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_questions_bank_id ON questions (bank_id);
+
+-- A quiz of a section, open between opens_at and closes_at. Attempts last at most
+-- time_limit_minutes (no limit when NULL) and never beyond closes_at.
+CREATE TABLE IF NOT EXISTS quizzes (
+    id SERIAL PRIMARY KEY,
+    section_id INTEGER NOT NULL REFERENCES course_sections (id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    instructions TEXT,
+    opens_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    closes_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    time_limit_minutes INTEGER CHECK (time_limit_minutes > 0),
+    max_attempts INTEGER NOT NULL DEFAULT 1 CHECK (max_attempts > 0),
+    shuffle_questions BOOLEAN NOT NULL DEFAULT TRUE,
+    shuffle_options BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (closes_at > opens_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quizzes_section_id ON quizzes (section_id);
+
+-- The questions of a quiz, in the order they are shown when not shuffled. Questions on a
+-- quiz cannot be deleted from their bank.
+CREATE TABLE IF NOT EXISTS quiz_questions (
+    quiz_id INTEGER NOT NULL REFERENCES quizzes (id) ON DELETE CASCADE,
+    question_id INTEGER NOT NULL REFERENCES questions (id) ON DELETE RESTRICT,
+    position SMALLINT NOT NULL,
+    PRIMARY KEY (quiz_id, question_id)
+);
+
+-- A student's attempt at a quiz. layout fixes the order of the questions and their options
+-- the student sees, as [{"question_id": 1, "options": [3, 1, 2]}]. The database sets
+-- started_at and deadline_at; answers are refused after the deadline. score is NULL until
+-- the objective questions have been graded, and final once status is 'graded'.
+-- Attempts are academic records, so quizzes that have any cannot be deleted.
+CREATE TABLE IF NOT EXISTS quiz_attempts (
+    id SERIAL PRIMARY KEY,
+    quiz_id INTEGER NOT NULL REFERENCES quizzes (id) ON DELETE RESTRICT,
+    student_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    attempt_number INTEGER NOT NULL CHECK (attempt_number > 0),
+    layout JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'in_progress' CHECK (status IN ('in_progress', 'submitted', 'graded')),
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deadline_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    score NUMERIC(9, 2),
+    max_score NUMERIC(9, 2) NOT NULL,
+    UNIQUE (quiz_id, student_id, attempt_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_quiz_attempts_student_id ON quiz_attempts (student_id);
+
+-- A student works on one attempt of a quiz at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_quiz_attempts_in_progress ON quiz_attempts (quiz_id, student_id) WHERE status = 'in_progress';
+
+-- A student's answer to one question of an attempt. points stays NULL until the answer is
+-- graded: objective questions when the attempt is submitted, short answers by the teacher.
+CREATE TABLE IF NOT EXISTS quiz_responses (
+    attempt_id INTEGER NOT NULL REFERENCES quiz_attempts (id) ON DELETE CASCADE,
+    question_id INTEGER NOT NULL REFERENCES questions (id) ON DELETE RESTRICT,
+    selected_options INTEGER[],
+    bool_answer BOOLEAN,
+    numeric_answer NUMERIC(14, 4),
+    text_answer TEXT,
+    answered_at TIMESTAMP WITH TIME ZONE,
+    points NUMERIC(7, 2) CHECK (points >= 0),
+    auto_graded BOOLEAN NOT NULL DEFAULT FALSE,
+    feedback TEXT,
+    graded_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    graded_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (attempt_id, question_id)
+);
@@ -0,0 +1,55 @@
+-- migrations/023_create_rubrics.sql
+
+-- Reusable grading rubrics. Like a question bank, a rubric belongs to the teacher who created
+-- it and can be attached to any number of assignments.
+CREATE TABLE IF NOT EXISTS rubrics (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    owner_id INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rubrics_owner_id ON rubrics (owner_id);
+
+-- A criterion is scored by picking one of its performance levels, and is worth the points of
+-- its best level.
+CREATE TABLE IF NOT EXISTS rubric_criteria (
+    id SERIAL PRIMARY KEY,
+    rubric_id INTEGER NOT NULL REFERENCES rubrics (id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    position SMALLINT NOT NULL,
+    UNIQUE (rubric_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS rubric_levels (
+    id SERIAL PRIMARY KEY,
+    criterion_id INTEGER NOT NULL REFERENCES rubric_criteria (id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    points NUMERIC(7, 2) NOT NULL CHECK (points >= 0),
+    position SMALLINT NOT NULL,
+    UNIQUE (criterion_id, position)
+);
+
+-- The rubric an assignment is graded against
+ALTER TABLE assignments ADD COLUMN IF NOT EXISTS rubric_id INTEGER REFERENCES rubrics (id) ON DELETE RESTRICT;
+
+-- A student's score on one criterion of the rubric of an assignment. The total goes into the
+-- student's assignment grade; students see the scores once that grade is released. Criteria
+-- and levels that were scored with cannot be removed, so rubrics freeze once used.
+CREATE TABLE IF NOT EXISTS rubric_scores (
+    assignment_id INTEGER NOT NULL REFERENCES assignments (id) ON DELETE CASCADE,
+    student_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    criterion_id INTEGER NOT NULL REFERENCES rubric_criteria (id) ON DELETE RESTRICT,
+    level_id INTEGER REFERENCES rubric_levels (id) ON DELETE RESTRICT, -- NULL when points were given directly
+    points NUMERIC(7, 2) NOT NULL CHECK (points >= 0),
+    comment TEXT,
+    scored_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    scored_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (assignment_id, student_id, criterion_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_rubric_scores_student_id ON rubric_scores (student_id);
@@ -0,0 +1,42 @@
+-- migrations/024_create_peer_reviews.sql
+
+-- Anonymous peer review of an assignment. Once the assignment is due, the teacher has every
+-- submission assigned to reviewers_per_submission other students who handed in work.
+CREATE TABLE IF NOT EXISTS peer_review_settings (
+    assignment_id INTEGER PRIMARY KEY REFERENCES assignments (id) ON DELETE CASCADE,
+    reviewers_per_submission SMALLINT NOT NULL CHECK (reviewers_per_submission BETWEEN 1 AND 10),
+    review_due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    allocated_at TIMESTAMP WITH TIME ZONE, -- NULL until reviews have been assigned
+    created_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One student reviewing another's submission. Points are the rubric total when the assignment
+-- has a rubric, otherwise optional points out of the assignment's.
+CREATE TABLE IF NOT EXISTS peer_reviews (
+    id SERIAL PRIMARY KEY,
+    assignment_id INTEGER NOT NULL REFERENCES assignments (id) ON DELETE CASCADE,
+    submission_id INTEGER NOT NULL REFERENCES assignment_submissions (id) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    reviewer_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    points NUMERIC(7, 2) CHECK (points >= 0),
+    comment TEXT,
+    assigned_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    submitted_at TIMESTAMP WITH TIME ZONE, -- NULL until the reviewer hands the review in
+    UNIQUE (assignment_id, author_id, reviewer_id),
+    CHECK (author_id <> reviewer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_peer_reviews_reviewer_id ON peer_reviews (reviewer_id);
+CREATE INDEX IF NOT EXISTS idx_peer_reviews_author_id ON peer_reviews (author_id);
+
+-- A reviewer's score on one criterion of the assignment's rubric
+CREATE TABLE IF NOT EXISTS peer_review_scores (
+    review_id INTEGER NOT NULL REFERENCES peer_reviews (id) ON DELETE CASCADE,
+    criterion_id INTEGER NOT NULL REFERENCES rubric_criteria (id) ON DELETE RESTRICT,
+    level_id INTEGER REFERENCES rubric_levels (id) ON DELETE RESTRICT,
+    points NUMERIC(7, 2) NOT NULL CHECK (points >= 0),
+    comment TEXT,
+    PRIMARY KEY (review_id, criterion_id)
+);
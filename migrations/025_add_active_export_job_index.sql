@@ -0,0 +1,14 @@
+-- migrations/025_add_active_export_job_index.sql
+
+-- A user has at most one export job pending or running at a time. Jobs left behind by a
+-- process that stopped mid-export are failed first so that the index can be built.
+UPDATE data_export_jobs j
+SET status = 'failed', error = 'export abandoned', completed_at = CURRENT_TIMESTAMP
+WHERE j.status IN ('pending', 'running')
+  AND EXISTS (
+      SELECT 1 FROM data_export_jobs newer
+      WHERE newer.user_id = j.user_id AND newer.status IN ('pending', 'running') AND newer.id > j.id
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_data_export_jobs_active_user_id ON data_export_jobs (user_id)
+    WHERE status IN ('pending', 'running');
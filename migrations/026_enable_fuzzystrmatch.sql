@@ -0,0 +1,5 @@
+-- migrations/026_enable_fuzzystrmatch.sql
+
+-- soundex() gives the phonetic name keys used to find candidate duplicate accounts without
+-- comparing every pair of users.
+CREATE EXTENSION IF NOT EXISTS fuzzystrmatch;